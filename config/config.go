@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/timeutil"
 	env "github.com/caarlos0/env/v10"
@@ -12,6 +13,7 @@ import (
 const (
 	CountersTtlHours                    = 48
 	ProjectName                         = "User Votes Storage"
+	ServiceName                         = "recs-votes-storage"
 	ProjectVersion                      = "1.0.0"
 	DynamoDbVersionConflictRetriesCount = 3
 )
@@ -24,6 +26,10 @@ type RomancesConfig struct {
 
 type CountersConfig struct {
 	TtlSeconds int64
+	// DailyRetention and WeeklyRetention are how long the daily and ISO week rollups of the hourly
+	// counters are kept after their period ended, a zero retention disables the rollup
+	DailyRetention  time.Duration `env:"COUNTERS_DAILY_RETENTION" envDefault:"2160h"`
+	WeeklyRetention time.Duration `env:"COUNTERS_WEEKLY_RETENTION" envDefault:"8760h"`
 }
 
 type PipelineConfig struct {
@@ -42,12 +48,106 @@ type AWSConfig struct {
 	SnsEndpoint      string `env:"SNS_ENDPOINT"`
 }
 
+type MessagingConfig struct {
+	// TopicEncodings selects the codec per topic, e.g. "delete-romances.fifo:protobuf,delete-romances-group.fifo:cloudevents".
+	// Topics which are not listed are published as JSON envelopes.
+	TopicEncodings map[string]string `env:"MESSAGING_TOPIC_ENCODINGS"`
+	// VoteSignalsTopic receives trust & safety signals about voting behaviour
+	VoteSignalsTopic string `env:"MESSAGING_VOTE_SIGNALS_TOPIC" envDefault:"vote-signals.fifo"`
+}
+
+type StreamsConfig struct {
+	PollInterval          time.Duration `env:"DYNAMO_DB_STREAMS_POLL_INTERVAL" envDefault:"1s"`
+	ShardsRefreshInterval time.Duration `env:"DYNAMO_DB_STREAMS_SHARDS_REFRESH_INTERVAL" envDefault:"1m"`
+	RetryInterval         time.Duration `env:"DYNAMO_DB_STREAMS_RETRY_INTERVAL" envDefault:"5s"`
+	BatchSize             int32         `env:"DYNAMO_DB_STREAMS_BATCH_SIZE" envDefault:"100"`
+	// LeaseDuration is how long a shard stays with a reader which stopped renewing its lease
+	LeaseDuration time.Duration `env:"DYNAMO_DB_STREAMS_LEASE_DURATION" envDefault:"30s"`
+}
+
+type IdempotencyConfig struct {
+	Ttl time.Duration `env:"IDEMPOTENCY_KEY_TTL" envDefault:"24h"`
+	// LockTimeout is how long a key stays locked by a request which never completed
+	LockTimeout time.Duration `env:"IDEMPOTENCY_KEY_LOCK_TIMEOUT" envDefault:"30s"`
+}
+
+type VotingConfig struct {
+	// Transitions lists the vote types every vote type can be changed to, e.g. "empty:yes|no,no:yes,yes:".
+	// Vote types which are not listed are final. Built-in rules are used when empty.
+	Transitions map[string]string `env:"VOTE_TRANSITIONS"`
+	// TransitionOverrides replaces the targets of a vote type in one country, e.g. "11/yes:no|crush|compliment"
+	TransitionOverrides map[string]string `env:"VOTE_TRANSITION_OVERRIDES"`
+	// MaxClockSkew is how far ahead of server time voted_at may be
+	MaxClockSkew time.Duration `env:"VOTE_MAX_CLOCK_SKEW" envDefault:"1m"`
+	// StaleVoteWindow is how old voted_at may be, e.g. for votes replayed by offline clients
+	StaleVoteWindow time.Duration `env:"VOTE_STALE_WINDOW" envDefault:"72h"`
+	// HourlyLimits caps outgoing votes per UTC hour by counter, e.g. "yes:300,no:1000".
+	// Crush and compliment votes count as yes, missing counters are not limited.
+	HourlyLimits map[string]uint32 `env:"VOTE_HOURLY_LIMITS"`
+	// DailyLimits caps outgoing votes per UTC day by counter, e.g. "yes:1000,no:5000"
+	DailyLimits map[string]uint32 `env:"VOTE_DAILY_LIMITS"`
+	// LimitOverrides replaces a single limit in one country, a zero limit lifts it, e.g. "11/yes/hour:100,11/no/day:0"
+	LimitOverrides map[string]uint32 `env:"VOTE_LIMIT_OVERRIDES"`
+	// PeerCountryRequired rejects requests without the peer country instead of assuming the country of
+	// the active user, it has to be set once countries are served from more than one region
+	PeerCountryRequired bool `env:"VOTE_PEER_COUNTRY_REQUIRED" envDefault:"false"`
+	// DailyQuotas caps premium votes per day in the user profile time zone, e.g. "crush:1,compliment:3".
+	// Users can have overrides, vote types without a quota are not limited.
+	DailyQuotas map[string]uint32 `env:"VOTE_DAILY_QUOTAS"`
+	// UnmatchRevoteBan is how long both users of an unmatched romance can not vote on each other
+	UnmatchRevoteBan time.Duration `env:"UNMATCH_REVOTE_BAN" envDefault:"720h"`
+	// RewindWindow is how long after it was accepted a vote can still be rewound
+	RewindWindow time.Duration `env:"VOTE_REWIND_WINDOW" envDefault:"5m"`
+	// RewindHistorySize is how many of the last votes of a user are kept for consecutive rewinds
+	RewindHistorySize int `env:"VOTE_REWIND_HISTORY_SIZE" envDefault:"5"`
+	// NoVoteCoolingPeriod is how long a no keeps the peer out of the recommendations, a zero period
+	// keeps the peer out until the romance expires. Expired no votes are still counted and can be renewed.
+	NoVoteCoolingPeriod time.Duration `env:"NO_VOTE_COOLING_PERIOD" envDefault:"2160h"`
+	// HistoryRetention is how long vote history entries are kept, independently of their romance
+	HistoryRetention time.Duration `env:"VOTE_HISTORY_RETENTION" envDefault:"8760h"`
+}
+
+type ModerationConfig struct {
+	// BlockedKeywords rejects compliments containing any of the words or phrases, case-insensitive
+	BlockedKeywords []string `env:"COMPLIMENT_BLOCKED_KEYWORDS" envSeparator:","`
+	// AutoApprove approves compliments without blocked keywords right away, otherwise they stay
+	// pending until a moderation callback decides
+	AutoApprove bool `env:"COMPLIMENT_AUTO_APPROVE" envDefault:"false"`
+}
+
+type ExclusionFilterConfig struct {
+	// FalsePositiveRate is the share of not excluded peers the filter still reports as excluded
+	FalsePositiveRate float64 `env:"EXCLUSION_FILTER_FALSE_POSITIVE_RATE" envDefault:"0.01"`
+	// CacheTtl is how long a built filter is served, filters are shared by the instances and see the
+	// votes written through any of them
+	CacheTtl time.Duration `env:"EXCLUSION_FILTER_CACHE_TTL" envDefault:"5m"`
+}
+
+type UserServiceConfig struct {
+	// Url of the user service answering status, entitlement and time zone checks, in-memory providers
+	// treating every user as active, entitled and in UTC are used when empty
+	Url      string        `env:"USER_SERVICE_URL"`
+	Timeout  time.Duration `env:"USER_SERVICE_TIMEOUT" envDefault:"300ms"`
+	CacheTtl time.Duration `env:"USER_SERVICE_CACHE_TTL" envDefault:"30s"`
+	// StatusFailOpen lets votes through while the user status can not be checked
+	StatusFailOpen bool `env:"USER_STATUS_FAIL_OPEN" envDefault:"true"`
+	// EntitlementFailOpen lets premium votes through while the entitlements can not be checked
+	EntitlementFailOpen bool `env:"USER_ENTITLEMENT_FAIL_OPEN" envDefault:"false"`
+}
+
 type Config struct {
-	LogLevel string `env:"LOG_LEVEL"`
-	Aws      AWSConfig
-	Counters CountersConfig
-	Romances RomancesConfig
-	Pipeline PipelineConfig
+	LogLevel    string `env:"LOG_LEVEL"`
+	Aws         AWSConfig
+	Counters    CountersConfig
+	Romances    RomancesConfig
+	Pipeline    PipelineConfig
+	Messaging   MessagingConfig
+	Streams     StreamsConfig
+	Idempotency IdempotencyConfig
+	Voting      VotingConfig
+	UserService UserServiceConfig
+	Moderation  ModerationConfig
+	Exclusion   ExclusionFilterConfig
 }
 
 type ServerOptions struct {
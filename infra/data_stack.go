@@ -8,12 +8,17 @@ import (
 	awssqs "github.com/aws/aws-cdk-go/awscdk/v2/awssqs"
 	"github.com/aws/constructs-go/constructs/v10"
 	"github.com/aws/jsii-runtime-go"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/stream"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/idempotency"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb_streams"
 )
 
 type DataStackProps struct {
 	awscdk.StackProps
 	GrantRwToRole awsiam.IGrantable
+	Messaging     config.MessagingConfig
 }
 
 type DataOutputs struct {
@@ -23,6 +28,14 @@ type DataOutputs struct {
 	DeleteRomancesFifoQueue      awssqs.IQueue
 	DeleteRomancesGroupFifoTopic awssns.ITopic
 	DeleteRomancesGroupFifoQueue awssqs.IQueue
+	StreamCheckpoints            awsdynamodb.ITable
+	IdempotencyKeys              awsdynamodb.ITable
+	RomanceEventsFifoTopic       awssns.ITopic
+	VoteSignalsFifoTopic         awssns.ITopic
+	VoteQuotas                   awsdynamodb.ITable
+	LastVotes                    awsdynamodb.ITable
+	VoteHistory                  awsdynamodb.ITable
+	ExclusionFilters             awsdynamodb.ITable
 }
 
 func DataStack(scope constructs.Construct, id string, props *DataStackProps) *DataOutputs {
@@ -47,6 +60,7 @@ func DataStack(scope constructs.Construct, id string, props *DataStackProps) *Da
 		PartitionKey: &awsdynamodb.Attribute{Name: jsii.String(persistence.PkUserIdAttrName), Type: awsdynamodb.AttributeType_STRING},
 		SortKey:      &awsdynamodb.Attribute{Name: jsii.String(persistence.SkUserIdAttrName), Type: awsdynamodb.AttributeType_STRING},
 		BillingMode:  awsdynamodb.BillingMode_PAY_PER_REQUEST,
+		Stream:       awsdynamodb.StreamViewType_OLD_IMAGE,
 	})
 	cfnRomances := romancesTbl.Node().DefaultChild().(awscdk.CfnResource)
 	cfnRomances.AddOverride(jsii.String("Properties.TimeToLiveSpecification"),
@@ -59,6 +73,63 @@ func DataStack(scope constructs.Construct, id string, props *DataStackProps) *Da
 	})
 	romances = romancesTbl
 
+	checkpointsTbl := awsdynamodb.NewTable(parent, jsii.String(dynamodb_streams.CheckpointsTableName), &awsdynamodb.TableProps{
+		TableName:    jsii.String(dynamodb_streams.CheckpointsTableName),
+		PartitionKey: &awsdynamodb.Attribute{Name: jsii.String(dynamodb_streams.StreamArnAttrName), Type: awsdynamodb.AttributeType_STRING},
+		SortKey:      &awsdynamodb.Attribute{Name: jsii.String(dynamodb_streams.ShardIdAttrName), Type: awsdynamodb.AttributeType_STRING},
+		BillingMode:  awsdynamodb.BillingMode_PAY_PER_REQUEST,
+	})
+	cfnCheckpoints := checkpointsTbl.Node().DefaultChild().(awscdk.CfnResource)
+	cfnCheckpoints.AddOverride(jsii.String("Properties.TimeToLiveSpecification"),
+		map[string]interface{}{"Enabled": true, "AttributeName": "ttl"})
+
+	idempotencyKeysTbl := awsdynamodb.NewTable(parent, jsii.String(idempotency.KeysTableName), &awsdynamodb.TableProps{
+		TableName:    jsii.String(idempotency.KeysTableName),
+		PartitionKey: &awsdynamodb.Attribute{Name: jsii.String(idempotency.KeyAttrName), Type: awsdynamodb.AttributeType_STRING},
+		BillingMode:  awsdynamodb.BillingMode_PAY_PER_REQUEST,
+	})
+	cfnIdempotencyKeys := idempotencyKeysTbl.Node().DefaultChild().(awscdk.CfnResource)
+	cfnIdempotencyKeys.AddOverride(jsii.String("Properties.TimeToLiveSpecification"),
+		map[string]interface{}{"Enabled": true, "AttributeName": "ttl"})
+
+	quotasTbl := awsdynamodb.NewTable(parent, jsii.String(persistence.QuotasTableName), &awsdynamodb.TableProps{
+		TableName:    jsii.String(persistence.QuotasTableName),
+		PartitionKey: &awsdynamodb.Attribute{Name: jsii.String(persistence.QuotaUserIdAttrName), Type: awsdynamodb.AttributeType_STRING},
+		SortKey:      &awsdynamodb.Attribute{Name: jsii.String(persistence.QuotaDayAttrName), Type: awsdynamodb.AttributeType_NUMBER},
+		BillingMode:  awsdynamodb.BillingMode_PAY_PER_REQUEST,
+	})
+	cfnQuotas := quotasTbl.Node().DefaultChild().(awscdk.CfnResource)
+	cfnQuotas.AddOverride(jsii.String("Properties.TimeToLiveSpecification"),
+		map[string]interface{}{"Enabled": true, "AttributeName": "ttl"})
+
+	lastVotesTbl := awsdynamodb.NewTable(parent, jsii.String(persistence.LastVotesTableName), &awsdynamodb.TableProps{
+		TableName:    jsii.String(persistence.LastVotesTableName),
+		PartitionKey: &awsdynamodb.Attribute{Name: jsii.String(persistence.LastVotesUserIdAttrName), Type: awsdynamodb.AttributeType_STRING},
+		BillingMode:  awsdynamodb.BillingMode_PAY_PER_REQUEST,
+	})
+	cfnLastVotes := lastVotesTbl.Node().DefaultChild().(awscdk.CfnResource)
+	cfnLastVotes.AddOverride(jsii.String("Properties.TimeToLiveSpecification"),
+		map[string]interface{}{"Enabled": true, "AttributeName": "ttl"})
+
+	voteHistoryTbl := awsdynamodb.NewTable(parent, jsii.String(persistence.VoteHistoryTableName), &awsdynamodb.TableProps{
+		TableName:    jsii.String(persistence.VoteHistoryTableName),
+		PartitionKey: &awsdynamodb.Attribute{Name: jsii.String(persistence.VoteHistoryRomanceAttrName), Type: awsdynamodb.AttributeType_STRING},
+		SortKey:      &awsdynamodb.Attribute{Name: jsii.String(persistence.VoteHistorySortAttrName), Type: awsdynamodb.AttributeType_STRING},
+		BillingMode:  awsdynamodb.BillingMode_PAY_PER_REQUEST,
+	})
+	cfnVoteHistory := voteHistoryTbl.Node().DefaultChild().(awscdk.CfnResource)
+	cfnVoteHistory.AddOverride(jsii.String("Properties.TimeToLiveSpecification"),
+		map[string]interface{}{"Enabled": true, "AttributeName": "ttl"})
+
+	exclusionFiltersTbl := awsdynamodb.NewTable(parent, jsii.String(persistence.ExclusionFiltersTableName), &awsdynamodb.TableProps{
+		TableName:    jsii.String(persistence.ExclusionFiltersTableName),
+		PartitionKey: &awsdynamodb.Attribute{Name: jsii.String(persistence.ExclusionFiltersUserAttrName), Type: awsdynamodb.AttributeType_STRING},
+		BillingMode:  awsdynamodb.BillingMode_PAY_PER_REQUEST,
+	})
+	cfnExclusionFilters := exclusionFiltersTbl.Node().DefaultChild().(awscdk.CfnResource)
+	cfnExclusionFilters.AddOverride(jsii.String("Properties.TimeToLiveSpecification"),
+		map[string]interface{}{"Enabled": true, "AttributeName": "ttl"})
+
 	if props != nil && props.GrantRwToRole != nil {
 		counters.GrantReadWriteData(props.GrantRwToRole)
 		romances.GrantReadWriteData(props.GrantRwToRole)
@@ -85,6 +156,16 @@ func DataStack(scope constructs.Construct, id string, props *DataStackProps) *Da
 		Fifo:      jsii.Bool(true),
 	})
 
+	topic3 := awssns.NewTopic(parent, jsii.String("RomanceEventsFifoTopic"), &awssns.TopicProps{
+		TopicName: jsii.String(string(stream.RomanceEventsTopic)),
+		Fifo:      jsii.Bool(true),
+	})
+
+	topic4 := awssns.NewTopic(parent, jsii.String("VoteSignalsFifoTopic"), &awssns.TopicProps{
+		TopicName: jsii.String(props.Messaging.VoteSignalsTopic),
+		Fifo:      jsii.Bool(true),
+	})
+
 	return &DataOutputs{
 		Counters:                     counters,
 		Romances:                     romances,
@@ -92,5 +173,13 @@ func DataStack(scope constructs.Construct, id string, props *DataStackProps) *Da
 		DeleteRomancesFifoQueue:      queue1,
 		DeleteRomancesGroupFifoTopic: topic2,
 		DeleteRomancesGroupFifoQueue: queue2,
+		StreamCheckpoints:            checkpointsTbl,
+		IdempotencyKeys:              idempotencyKeysTbl,
+		RomanceEventsFifoTopic:       topic3,
+		VoteQuotas:                   quotasTbl,
+		LastVotes:                    lastVotesTbl,
+		VoteHistory:                  voteHistoryTbl,
+		VoteSignalsFifoTopic:         topic4,
+		ExclusionFilters:             exclusionFiltersTbl,
 	}
 }
@@ -32,6 +32,7 @@ func main() {
 
 	data := DataStack(stack, "Data", &DataStackProps{
 		StackProps: awscdk.StackProps{Env: env},
+		Messaging:  cfg.Messaging,
 	})
 
 	if envType != "local" {
@@ -63,6 +64,15 @@ func main() {
 		data.DeleteRomancesGroupFifoTopic.GrantPublish(taskRole)
 		data.DeleteRomancesFifoQueue.GrantConsumeMessages(taskRole)
 		data.DeleteRomancesGroupFifoQueue.GrantConsumeMessages(taskRole)
+		data.Romances.GrantStreamRead(taskRole)
+		data.StreamCheckpoints.GrantReadWriteData(taskRole)
+		data.IdempotencyKeys.GrantReadWriteData(taskRole)
+		data.RomanceEventsFifoTopic.GrantPublish(taskRole)
+		data.VoteSignalsFifoTopic.GrantPublish(taskRole)
+		data.VoteQuotas.GrantReadWriteData(taskRole)
+		data.LastVotes.GrantReadWriteData(taskRole)
+		data.VoteHistory.GrantReadWriteData(taskRole)
+		data.ExclusionFilters.GrantReadWriteData(taskRole)
 
 		dg := NewEcsDeployment(stack, "CD", svc, prodListener, testListener, blueTG, greenTG)
 
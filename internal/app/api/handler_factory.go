@@ -5,6 +5,9 @@ import (
 	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/app/api/response"
 	votingV1 "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/callerscope"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/requestid"
 	huma "github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
 	"net/http"
@@ -27,9 +30,13 @@ func (s HandlerFactory) NewHumaApiServerHandler() http.Handler {
 	api := humago.New(handler, huma.DefaultConfig(config.ProjectName, config.ProjectVersion))
 	grp := huma.NewGroup(api, "/v1")
 
+	api.UseMiddleware(s.requestIdMiddleware)
+	api.UseMiddleware(s.traceContextMiddleware)
+	api.UseMiddleware(s.callerScopeMiddleware)
+
 	s.registerHealthCheck(api)
 	s.setApiErrorSchema()
-	s.registerDefaultOpenApiErrorsResponses(grp, 400, 422, 500)
+	s.registerDefaultOpenApiErrorsResponses(grp, 400, 422, 429, 500, 503)
 
 	s.votesStorageRoutesRegister.RegisterV1Routes(grp)
 
@@ -47,6 +54,33 @@ func (s HandlerFactory) registerHealthCheck(api huma.API) {
 	})
 }
 
+// requestIdMiddleware ties the writes of a request together, e.g. in the vote history,
+// the id is echoed back so clients can refer to it.
+func (s HandlerFactory) requestIdMiddleware(ctx huma.Context, next func(huma.Context)) {
+	requestId := requestid.FromHeader(ctx.Header(requestid.Header))
+	ctx.SetHeader(requestid.Header, requestId)
+	next(huma.WithContext(ctx, requestid.ContextWithRequestId(ctx.Context(), requestId)))
+}
+
+// callerScopeMiddleware decides how much of a romance the caller may see, e.g. the vote of the peer
+func (s HandlerFactory) callerScopeMiddleware(ctx huma.Context, next func(huma.Context)) {
+	scope := callerscope.FromHeader(ctx.Header(callerscope.Header))
+	next(huma.WithContext(ctx, callerscope.ContextWithScope(ctx.Context(), scope)))
+}
+
+func (s HandlerFactory) traceContextMiddleware(ctx huma.Context, next func(huma.Context)) {
+	traceContext := messaging.NewTraceContext(
+		ctx.Header(messaging.TraceParentHeader),
+		ctx.Header(messaging.TraceStateHeader),
+	)
+	if traceContext.IsEmpty() {
+		next(ctx)
+		return
+	}
+
+	next(huma.WithContext(ctx, messaging.ContextWithTraceContext(ctx.Context(), traceContext)))
+}
+
 func (s HandlerFactory) registerDefaultOpenApiErrorsResponses(grp *huma.Group, codes ...int) {
 	grp.UseSimpleModifier(func(op *huma.Operation) {
 		for code, resp := range response.GenerateErrorResponsesGroup(grp, codes...) {
@@ -70,10 +104,8 @@ func (s HandlerFactory) setApiErrorSchema() {
 			}
 		}
 
-		return &response.HumaApiError{
-			Status:  status,
-			Message: message,
-			Errors:  details,
-		}
+		apiErr := response.NewHumaApiError(status, response.CodeForStatus(status), message)
+		apiErr.Errors = details
+		return apiErr
 	}
 }
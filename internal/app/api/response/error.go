@@ -5,12 +5,63 @@ import (
 	"net/http"
 	"reflect"
 	"strconv"
+	"time"
 )
 
+const ProblemJsonContentType = "application/problem+json"
+
+const (
+	CodeBadRequest         = "bad_request"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodePreconditionFailed = "precondition_failed"
+	CodeValidationFailed   = "validation_failed"
+	CodeTooManyRequests    = "too_many_requests"
+	CodeInternalError      = "internal_error"
+	CodeServiceUnavailable = "service_unavailable"
+)
+
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          CodeBadRequest,
+	http.StatusForbidden:           CodeForbidden,
+	http.StatusNotFound:            CodeNotFound,
+	http.StatusConflict:            CodeConflict,
+	http.StatusPreconditionFailed:  CodePreconditionFailed,
+	http.StatusUnprocessableEntity: CodeValidationFailed,
+	http.StatusTooManyRequests:     CodeTooManyRequests,
+	http.StatusInternalServerError: CodeInternalError,
+	http.StatusServiceUnavailable:  CodeServiceUnavailable,
+}
+
+// HumaApiError is an RFC 9457 problem details body, Code and Message are extension members
 type HumaApiError struct {
+	Title   string              `json:"title" example:"Bad Request" doc:"A short, human-readable summary of the problem type."`
 	Status  int                 `json:"status" example:"400" doc:"HTTP status code"`
+	Code    string              `json:"code" example:"bad_request" doc:"Stable machine-readable error code"`
 	Message string              `json:"message" example:"Property foo is required but is missing." doc:"A human-readable explanation specific to this occurrence of the problem."`
 	Errors  []*huma.ErrorDetail `json:"errors,omitempty" doc:"Optional list of individual error details"`
+	headers http.Header
+}
+
+func NewHumaApiError(status int, code string, message string) *HumaApiError {
+	return &HumaApiError{
+		Title:   http.StatusText(status),
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// CodeForStatus is used for errors raised by huma itself, e.g. request validation
+func CodeForStatus(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternalError
+	}
+	return CodeBadRequest
 }
 
 func (e *HumaApiError) Error() string {
@@ -21,6 +72,27 @@ func (e *HumaApiError) GetStatus() int {
 	return e.Status
 }
 
+func (e *HumaApiError) GetHeaders() http.Header {
+	return e.headers
+}
+
+func (e *HumaApiError) ContentType(ct string) string {
+	if ct == "application/json" {
+		return ProblemJsonContentType
+	}
+	return ct
+}
+
+// WithRetryAfter never advertises less than a second, a reset which has just passed
+// would otherwise tell the client to retry immediately or in the past
+func (e *HumaApiError) WithRetryAfter(after time.Duration) *HumaApiError {
+	if e.headers == nil {
+		e.headers = http.Header{}
+	}
+	e.headers.Set("Retry-After", strconv.Itoa(max(int(after.Round(time.Second).Seconds()), 1)))
+	return e
+}
+
 func GenerateErrorResponsesGroup(grp *huma.Group, codes ...int) map[string]*huma.Response {
 	responses := map[string]*huma.Response{}
 	for _, code := range codes {
@@ -32,12 +104,21 @@ func GenerateErrorResponsesGroup(grp *huma.Group, codes ...int) map[string]*huma
 func GenerateErrorResponse(grp *huma.Group, code int) *huma.Response {
 	reg := grp.OpenAPI().Components.Schemas
 	errSchema := huma.SchemaFromType(reg, reflect.TypeOf(HumaApiError{}))
-	return &huma.Response{
+	resp := &huma.Response{
 		Description: http.StatusText(code),
 		Content: map[string]*huma.MediaType{
-			"application/json": {
+			ProblemJsonContentType: {
 				Schema: errSchema,
 			},
 		},
 	}
+	if code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable {
+		resp.Headers = map[string]*huma.Header{
+			"Retry-After": {
+				Description: "Seconds to wait before retrying the request",
+				Schema:      &huma.Schema{Type: huma.TypeInteger},
+			},
+		}
+	}
+	return resp
 }
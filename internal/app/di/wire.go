@@ -10,14 +10,25 @@ import (
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/handler"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/operation"
+	counterDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
 	countersRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/repository"
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	quotasRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/repository"
+	rewindDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
+	lastVotesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/repository"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
 	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/moderation"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/stream"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/userservice"
 	storageV1 "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/idempotency"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/amazon_sns"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb_streams"
 	"github.com/google/wire"
 )
 
@@ -29,11 +40,42 @@ var ReposSet = wire.NewSet(
 	dynamodb.NewDynamoDbClient,
 	persistence.NewRomancesRepository,
 	persistence.NewCountersRepository,
+	persistence.NewQuotasRepository,
+	persistence.NewLastVotesRepository,
+	persistence.NewExclusionFiltersRepository,
 	wire.Bind(new(romancesRepo.RomancesRepository), new(*persistence.RomancesRepository)),
 	wire.Bind(new(countersRepo.CountersRepository), new(*persistence.CountersRepository)),
+	wire.Bind(new(quotasRepo.QuotasRepository), new(*persistence.QuotasRepository)),
+	wire.Bind(new(lastVotesRepo.LastVotesRepository), new(*persistence.LastVotesRepository)),
+	wire.Bind(new(romancesRepo.ExclusionFiltersRepository), new(*persistence.ExclusionFiltersRepository)),
+	userservice.NewUserStatusProvider,
+	userservice.NewTimeZoneProvider,
+	userservice.NewEntitlementProvider,
+	moderation.NewModerationProvider,
+)
+
+var StreamsSet = wire.NewSet(
+	dynamodb_streams.NewDynamoDbStreamsClient,
+	dynamodb_streams.NewDynamoDbCheckpointStore,
+	dynamodb_streams.NewStreamReader,
+	stream.NewRomancesStreamHandler,
+	wire.Bind(new(dynamodb_streams.CheckpointStore), new(*dynamodb_streams.DynamoDbCheckpointStore)),
+)
+
+var IdempotencySet = wire.NewSet(
+	idempotency.NewDynamoDbStore,
+	idempotency.NewGuard,
+	wire.Bind(new(idempotency.Store), new(*idempotency.DynamoDbStore)),
 )
 
 var OperationsSet = wire.NewSet(
+	romanceDomain.NewVoteTransitionPolicy,
+	romanceDomain.NewVotedAtPolicy,
+	romanceDomain.NewPeerVoteProjectionPolicy,
+	romanceDomain.NewEligibilityPolicy,
+	counterDomain.NewVotePolicy,
+	quotaDomain.NewQuotaPolicy,
+	rewindDomain.NewRewindPolicy,
 	operation.NewGetRomanceOperation,
 	operation.NewDeleteRomanceOperation,
 	operation.NewGetUserVoteOperation,
@@ -42,9 +84,26 @@ var OperationsSet = wire.NewSet(
 	operation.NewDeleteUserVoteOperation,
 	operation.NewGetLifetimeCountersOperation,
 	operation.NewGetHourlyCountersOperation,
+	operation.NewGetCountersSeriesOperation,
 	operation.NewDeleteRomancesRequestOperation,
 	operation.NewDeleteRomancesOperation,
 	operation.NewDeleteRomancesGroupOperation,
+	operation.NewCheckVoterOperation,
+	operation.NewConsumeVoteQuotaOperation,
+	operation.NewGetVoteQuotasOperation,
+	operation.NewSetVoteQuotaOverridesOperation,
+	operation.NewBlockPeerOperation,
+	operation.NewUnblockPeerOperation,
+	operation.NewUnmatchOperation,
+	operation.NewRecordLastVoteOperation,
+	operation.NewRewindVoteOperation,
+	operation.NewGetVoteHistoryOperation,
+	operation.NewSubmitComplimentOperation,
+	operation.NewModerateComplimentOperation,
+	operation.NewListRomancesOperation,
+	operation.NewExportRomancesOperation,
+	operation.NewCheckEligibilityOperation,
+	operation.NewGetExclusionFilterOperation,
 	application.NewVotingService,
 )
 
@@ -55,6 +114,7 @@ func InitializeApiWebServer(config config.Config) (*app.ApiWebServer, error) {
 		amazon_sns.NewSnsPublisher,
 		wire.Bind(new(messaging.Publisher), new(*amazon_sns.SnsPublisher)),
 		OperationsSet,
+		IdempotencySet,
 		storageV1.NewVotesStorageRoutesRegister,
 		api.NewHandlerFactory,
 		app.NewApiWebServer,
@@ -72,6 +132,7 @@ func InitializeMessageProcessor(config config.Config) (*app.MessageProcessor, er
 		wire.Bind(new(messaging.Publisher), new(*amazon_sns.SnsPublisher)),
 		handler.NewDeleteRomancesHandler,
 		handler.NewDeleteRomancesGroupHandler,
+		StreamsSet,
 		OperationsSet,
 		bootstrap.NewPreparedTopicHandler,
 		app.NewTopicListener,
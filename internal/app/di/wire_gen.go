@@ -14,13 +14,24 @@ import (
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/handler"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/operation"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
 	repository2 "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/repository"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	repository3 "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/repository"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
+	repository4 "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/repository"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/moderation"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/stream"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/userservice"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/idempotency"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/amazon_sns"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb_streams"
 	"github.com/google/wire"
 )
 
@@ -30,21 +41,66 @@ func InitializeApiWebServer(config2 config.Config) (*app.ApiWebServer, error) {
 	logger := platform.NewLogger(config2)
 	client := dynamodb.NewDynamoDbClient(config2, logger)
 	romancesRepository := persistence.NewRomancesRepository(client, config2, logger)
+	exclusionFiltersRepository := persistence.NewExclusionFiltersRepository(client, config2, logger)
 	countersRepository := persistence.NewCountersRepository(client, config2, logger)
-	addUserVoteOperation := operation.NewAddUserVoteOperation(romancesRepository, countersRepository, logger)
+	voteTransitionPolicy, err := romance.NewVoteTransitionPolicy(config2)
+	if err != nil {
+		return nil, err
+	}
+	votedAtPolicy := romance.NewVotedAtPolicy(config2)
+	eligibilityPolicy := romance.NewEligibilityPolicy(config2)
+	votePolicy, err := counter.NewVotePolicy(config2)
+	if err != nil {
+		return nil, err
+	}
+	userStatusProvider := userservice.NewUserStatusProvider(config2, logger)
+	entitlementProvider := userservice.NewEntitlementProvider(config2, logger)
+	checkVoterOperation := operation.NewCheckVoterOperation(userStatusProvider, entitlementProvider)
+	quotasRepository := persistence.NewQuotasRepository(client, config2, logger)
+	quotaPolicy, err := quota.NewQuotaPolicy(config2)
+	if err != nil {
+		return nil, err
+	}
+	timeZoneProvider := userservice.NewTimeZoneProvider(config2, logger)
+	consumeVoteQuotaOperation := operation.NewConsumeVoteQuotaOperation(quotasRepository, quotaPolicy, timeZoneProvider, logger)
+	lastVotesRepository := persistence.NewLastVotesRepository(client, config2, logger)
+	rewindPolicy := rewind.NewRewindPolicy(config2)
+	recordLastVoteOperation := operation.NewRecordLastVoteOperation(lastVotesRepository, rewindPolicy, logger)
+	moderationProvider := moderation.NewModerationProvider(config2)
+	submitComplimentOperation := operation.NewSubmitComplimentOperation(moderationProvider, logger)
+	snsPublisher := amazon_sns.NewSnsPublisher(config2, logger)
+	addUserVoteOperation := operation.NewAddUserVoteOperation(romancesRepository, exclusionFiltersRepository, countersRepository, voteTransitionPolicy, votedAtPolicy, eligibilityPolicy, votePolicy, checkVoterOperation, consumeVoteQuotaOperation, recordLastVoteOperation, submitComplimentOperation, snsPublisher, config2, logger)
 	getUserVoteOperation := operation.NewGetUserVoteOperation(romancesRepository)
-	deleteUserVoteOperation := operation.NewDeleteUserVoteOperation(romancesRepository, countersRepository, logger)
-	changeUserVoteOperation := operation.NewChangeUserVoteOperation(romancesRepository, countersRepository, logger)
+	deleteUserVoteOperation := operation.NewDeleteUserVoteOperation(romancesRepository, exclusionFiltersRepository, countersRepository, logger)
+	changeUserVoteOperation := operation.NewChangeUserVoteOperation(romancesRepository, exclusionFiltersRepository, countersRepository, voteTransitionPolicy, votedAtPolicy, checkVoterOperation, consumeVoteQuotaOperation, recordLastVoteOperation, submitComplimentOperation, logger)
 	getRomanceOperation := operation.NewGetRomanceOperation(romancesRepository)
-	deleteRomanceOperation := operation.NewDeleteRomanceOperation(romancesRepository)
-	snsPublisher := amazon_sns.NewSnsPublisher(config2, logger)
+	deleteRomanceOperation := operation.NewDeleteRomanceOperation(romancesRepository, exclusionFiltersRepository)
 	deleteRomancesRequestOperation := operation.NewDeleteRomancesRequestOperation(snsPublisher, logger)
 	deleteRomancesOperation := operation.NewDeleteRomancesOperation(romancesRepository, snsPublisher, logger)
 	deleteRomancesGroupOperation := operation.NewDeleteRomancesGroupOperation(romancesRepository, logger)
 	getLifetimeCountersOperation := operation.NewGetLifetimeCountersOperation(countersRepository)
 	getHourlyCountersOperation := operation.NewGetHourlyCountersOperation(countersRepository)
-	votingService := application.NewVotingService(addUserVoteOperation, getUserVoteOperation, deleteUserVoteOperation, changeUserVoteOperation, getRomanceOperation, deleteRomanceOperation, deleteRomancesRequestOperation, deleteRomancesOperation, deleteRomancesGroupOperation, getLifetimeCountersOperation, getHourlyCountersOperation)
-	votesStorageRoutesRegister := v1.NewVotesStorageRoutesRegister(votingService)
+	getCountersSeriesOperation := operation.NewGetCountersSeriesOperation(countersRepository)
+	getVoteQuotasOperation := operation.NewGetVoteQuotasOperation(quotasRepository, quotaPolicy, timeZoneProvider)
+	setVoteQuotaOverridesOperation := operation.NewSetVoteQuotaOverridesOperation(quotasRepository)
+	blockPeerOperation := operation.NewBlockPeerOperation(romancesRepository, exclusionFiltersRepository, logger)
+	unblockPeerOperation := operation.NewUnblockPeerOperation(romancesRepository, exclusionFiltersRepository, logger)
+	unmatchOperation := operation.NewUnmatchOperation(romancesRepository, countersRepository, config2, logger)
+	rewindVoteOperation := operation.NewRewindVoteOperation(romancesRepository, exclusionFiltersRepository, countersRepository, lastVotesRepository, rewindPolicy, consumeVoteQuotaOperation, logger)
+	peerVoteProjectionPolicy := romance.NewPeerVoteProjectionPolicy()
+	getVoteHistoryOperation := operation.NewGetVoteHistoryOperation(romancesRepository, peerVoteProjectionPolicy)
+	moderateComplimentOperation := operation.NewModerateComplimentOperation(romancesRepository, logger)
+	listRomancesOperation := operation.NewListRomancesOperation(romancesRepository, peerVoteProjectionPolicy)
+	exportRomancesOperation := operation.NewExportRomancesOperation(romancesRepository, peerVoteProjectionPolicy)
+	checkEligibilityOperation := operation.NewCheckEligibilityOperation(romancesRepository, eligibilityPolicy)
+	getExclusionFilterOperation, err := operation.NewGetExclusionFilterOperation(romancesRepository, exclusionFiltersRepository, eligibilityPolicy, config2)
+	if err != nil {
+		return nil, err
+	}
+	votingService := application.NewVotingService(addUserVoteOperation, getUserVoteOperation, deleteUserVoteOperation, changeUserVoteOperation, getRomanceOperation, deleteRomanceOperation, deleteRomancesRequestOperation, deleteRomancesOperation, deleteRomancesGroupOperation, getLifetimeCountersOperation, getHourlyCountersOperation, getCountersSeriesOperation, getVoteQuotasOperation, setVoteQuotaOverridesOperation, blockPeerOperation, unblockPeerOperation, unmatchOperation, rewindVoteOperation, getVoteHistoryOperation, moderateComplimentOperation, listRomancesOperation, exportRomancesOperation, checkEligibilityOperation, getExclusionFilterOperation, peerVoteProjectionPolicy, config2)
+	dynamoDbStore := idempotency.NewDynamoDbStore(client, logger)
+	guard := idempotency.NewGuard(dynamoDbStore, config2, logger)
+	votesStorageRoutesRegister := v1.NewVotesStorageRoutesRegister(votingService, guard, voteTransitionPolicy, logger)
 	handlerFactory := api.NewHandlerFactory(votesStorageRoutesRegister)
 	apiWebServer := app.NewApiWebServer(handlerFactory, config2, logger)
 	return apiWebServer, nil
@@ -55,25 +111,72 @@ func InitializeMessageProcessor(config2 config.Config) (*app.MessageProcessor, e
 	snsSubscriber := amazon_sns.NewSnsSubscriber(config2, logger)
 	client := dynamodb.NewDynamoDbClient(config2, logger)
 	romancesRepository := persistence.NewRomancesRepository(client, config2, logger)
+	exclusionFiltersRepository := persistence.NewExclusionFiltersRepository(client, config2, logger)
 	countersRepository := persistence.NewCountersRepository(client, config2, logger)
-	addUserVoteOperation := operation.NewAddUserVoteOperation(romancesRepository, countersRepository, logger)
+	voteTransitionPolicy, err := romance.NewVoteTransitionPolicy(config2)
+	if err != nil {
+		return nil, err
+	}
+	votedAtPolicy := romance.NewVotedAtPolicy(config2)
+	eligibilityPolicy := romance.NewEligibilityPolicy(config2)
+	votePolicy, err := counter.NewVotePolicy(config2)
+	if err != nil {
+		return nil, err
+	}
+	userStatusProvider := userservice.NewUserStatusProvider(config2, logger)
+	entitlementProvider := userservice.NewEntitlementProvider(config2, logger)
+	checkVoterOperation := operation.NewCheckVoterOperation(userStatusProvider, entitlementProvider)
+	quotasRepository := persistence.NewQuotasRepository(client, config2, logger)
+	quotaPolicy, err := quota.NewQuotaPolicy(config2)
+	if err != nil {
+		return nil, err
+	}
+	timeZoneProvider := userservice.NewTimeZoneProvider(config2, logger)
+	consumeVoteQuotaOperation := operation.NewConsumeVoteQuotaOperation(quotasRepository, quotaPolicy, timeZoneProvider, logger)
+	lastVotesRepository := persistence.NewLastVotesRepository(client, config2, logger)
+	rewindPolicy := rewind.NewRewindPolicy(config2)
+	recordLastVoteOperation := operation.NewRecordLastVoteOperation(lastVotesRepository, rewindPolicy, logger)
+	moderationProvider := moderation.NewModerationProvider(config2)
+	submitComplimentOperation := operation.NewSubmitComplimentOperation(moderationProvider, logger)
+	snsPublisher := amazon_sns.NewSnsPublisher(config2, logger)
+	addUserVoteOperation := operation.NewAddUserVoteOperation(romancesRepository, exclusionFiltersRepository, countersRepository, voteTransitionPolicy, votedAtPolicy, eligibilityPolicy, votePolicy, checkVoterOperation, consumeVoteQuotaOperation, recordLastVoteOperation, submitComplimentOperation, snsPublisher, config2, logger)
 	getUserVoteOperation := operation.NewGetUserVoteOperation(romancesRepository)
-	deleteUserVoteOperation := operation.NewDeleteUserVoteOperation(romancesRepository, countersRepository, logger)
-	changeUserVoteOperation := operation.NewChangeUserVoteOperation(romancesRepository, countersRepository, logger)
+	deleteUserVoteOperation := operation.NewDeleteUserVoteOperation(romancesRepository, exclusionFiltersRepository, countersRepository, logger)
+	changeUserVoteOperation := operation.NewChangeUserVoteOperation(romancesRepository, exclusionFiltersRepository, countersRepository, voteTransitionPolicy, votedAtPolicy, checkVoterOperation, consumeVoteQuotaOperation, recordLastVoteOperation, submitComplimentOperation, logger)
 	getRomanceOperation := operation.NewGetRomanceOperation(romancesRepository)
-	deleteRomanceOperation := operation.NewDeleteRomanceOperation(romancesRepository)
-	snsPublisher := amazon_sns.NewSnsPublisher(config2, logger)
+	deleteRomanceOperation := operation.NewDeleteRomanceOperation(romancesRepository, exclusionFiltersRepository)
 	deleteRomancesRequestOperation := operation.NewDeleteRomancesRequestOperation(snsPublisher, logger)
 	deleteRomancesOperation := operation.NewDeleteRomancesOperation(romancesRepository, snsPublisher, logger)
 	deleteRomancesGroupOperation := operation.NewDeleteRomancesGroupOperation(romancesRepository, logger)
 	getLifetimeCountersOperation := operation.NewGetLifetimeCountersOperation(countersRepository)
 	getHourlyCountersOperation := operation.NewGetHourlyCountersOperation(countersRepository)
-	votingService := application.NewVotingService(addUserVoteOperation, getUserVoteOperation, deleteUserVoteOperation, changeUserVoteOperation, getRomanceOperation, deleteRomanceOperation, deleteRomancesRequestOperation, deleteRomancesOperation, deleteRomancesGroupOperation, getLifetimeCountersOperation, getHourlyCountersOperation)
+	getCountersSeriesOperation := operation.NewGetCountersSeriesOperation(countersRepository)
+	getVoteQuotasOperation := operation.NewGetVoteQuotasOperation(quotasRepository, quotaPolicy, timeZoneProvider)
+	setVoteQuotaOverridesOperation := operation.NewSetVoteQuotaOverridesOperation(quotasRepository)
+	blockPeerOperation := operation.NewBlockPeerOperation(romancesRepository, exclusionFiltersRepository, logger)
+	unblockPeerOperation := operation.NewUnblockPeerOperation(romancesRepository, exclusionFiltersRepository, logger)
+	unmatchOperation := operation.NewUnmatchOperation(romancesRepository, countersRepository, config2, logger)
+	rewindVoteOperation := operation.NewRewindVoteOperation(romancesRepository, exclusionFiltersRepository, countersRepository, lastVotesRepository, rewindPolicy, consumeVoteQuotaOperation, logger)
+	peerVoteProjectionPolicy := romance.NewPeerVoteProjectionPolicy()
+	getVoteHistoryOperation := operation.NewGetVoteHistoryOperation(romancesRepository, peerVoteProjectionPolicy)
+	moderateComplimentOperation := operation.NewModerateComplimentOperation(romancesRepository, logger)
+	listRomancesOperation := operation.NewListRomancesOperation(romancesRepository, peerVoteProjectionPolicy)
+	exportRomancesOperation := operation.NewExportRomancesOperation(romancesRepository, peerVoteProjectionPolicy)
+	checkEligibilityOperation := operation.NewCheckEligibilityOperation(romancesRepository, eligibilityPolicy)
+	getExclusionFilterOperation, err := operation.NewGetExclusionFilterOperation(romancesRepository, exclusionFiltersRepository, eligibilityPolicy, config2)
+	if err != nil {
+		return nil, err
+	}
+	votingService := application.NewVotingService(addUserVoteOperation, getUserVoteOperation, deleteUserVoteOperation, changeUserVoteOperation, getRomanceOperation, deleteRomanceOperation, deleteRomancesRequestOperation, deleteRomancesOperation, deleteRomancesGroupOperation, getLifetimeCountersOperation, getHourlyCountersOperation, getCountersSeriesOperation, getVoteQuotasOperation, setVoteQuotaOverridesOperation, blockPeerOperation, unblockPeerOperation, unmatchOperation, rewindVoteOperation, getVoteHistoryOperation, moderateComplimentOperation, listRomancesOperation, exportRomancesOperation, checkEligibilityOperation, getExclusionFilterOperation, peerVoteProjectionPolicy, config2)
 	deleteRomancesHandler := handler.NewDeleteRomancesHandler(votingService, logger)
 	deleteRomancesGroupHandler := handler.NewDeleteRomancesGroupHandler(votingService, logger)
 	topicHandler := bootstrap.NewPreparedTopicHandler(deleteRomancesHandler, deleteRomancesGroupHandler, logger)
 	topicListener := app.NewTopicListener(snsSubscriber, topicHandler, logger)
-	messageProcessor := app.NewMessageProcessor(topicListener, logger)
+	streamsClient := dynamodb_streams.NewDynamoDbStreamsClient(config2, logger)
+	dynamoDbCheckpointStore := dynamodb_streams.NewDynamoDbCheckpointStore(client, logger)
+	streamReader := dynamodb_streams.NewStreamReader(streamsClient, client, dynamoDbCheckpointStore, config2, logger)
+	romancesStreamHandler := stream.NewRomancesStreamHandler(snsPublisher, logger)
+	messageProcessor := app.NewMessageProcessor(topicListener, streamReader, romancesStreamHandler, logger)
 	return messageProcessor, nil
 }
 
@@ -81,6 +184,10 @@ func InitializeMessageProcessor(config2 config.Config) (*app.MessageProcessor, e
 
 var PlatformSet = wire.NewSet(platform.NewLogger)
 
-var ReposSet = wire.NewSet(dynamodb.NewDynamoDbClient, persistence.NewRomancesRepository, persistence.NewCountersRepository, wire.Bind(new(repository.RomancesRepository), new(*persistence.RomancesRepository)), wire.Bind(new(repository2.CountersRepository), new(*persistence.CountersRepository)))
+var ReposSet = wire.NewSet(dynamodb.NewDynamoDbClient, persistence.NewRomancesRepository, persistence.NewCountersRepository, persistence.NewQuotasRepository, persistence.NewLastVotesRepository, persistence.NewExclusionFiltersRepository, wire.Bind(new(repository.RomancesRepository), new(*persistence.RomancesRepository)), wire.Bind(new(repository2.CountersRepository), new(*persistence.CountersRepository)), wire.Bind(new(repository3.QuotasRepository), new(*persistence.QuotasRepository)), wire.Bind(new(repository4.LastVotesRepository), new(*persistence.LastVotesRepository)), wire.Bind(new(repository.ExclusionFiltersRepository), new(*persistence.ExclusionFiltersRepository)), userservice.NewUserStatusProvider, userservice.NewTimeZoneProvider, userservice.NewEntitlementProvider, moderation.NewModerationProvider)
+
+var StreamsSet = wire.NewSet(dynamodb_streams.NewDynamoDbStreamsClient, dynamodb_streams.NewDynamoDbCheckpointStore, dynamodb_streams.NewStreamReader, stream.NewRomancesStreamHandler, wire.Bind(new(dynamodb_streams.CheckpointStore), new(*dynamodb_streams.DynamoDbCheckpointStore)))
+
+var IdempotencySet = wire.NewSet(idempotency.NewDynamoDbStore, idempotency.NewGuard, wire.Bind(new(idempotency.Store), new(*idempotency.DynamoDbStore)))
 
-var OperationsSet = wire.NewSet(operation.NewGetRomanceOperation, operation.NewDeleteRomanceOperation, operation.NewGetUserVoteOperation, operation.NewAddUserVoteOperation, operation.NewChangeUserVoteOperation, operation.NewDeleteUserVoteOperation, operation.NewGetLifetimeCountersOperation, operation.NewGetHourlyCountersOperation, operation.NewDeleteRomancesRequestOperation, operation.NewDeleteRomancesOperation, operation.NewDeleteRomancesGroupOperation, application.NewVotingService)
+var OperationsSet = wire.NewSet(romance.NewVoteTransitionPolicy, romance.NewVotedAtPolicy, romance.NewPeerVoteProjectionPolicy, romance.NewEligibilityPolicy, counter.NewVotePolicy, quota.NewQuotaPolicy, rewind.NewRewindPolicy, operation.NewGetRomanceOperation, operation.NewDeleteRomanceOperation, operation.NewGetUserVoteOperation, operation.NewAddUserVoteOperation, operation.NewChangeUserVoteOperation, operation.NewDeleteUserVoteOperation, operation.NewGetLifetimeCountersOperation, operation.NewGetHourlyCountersOperation, operation.NewGetCountersSeriesOperation, operation.NewDeleteRomancesRequestOperation, operation.NewDeleteRomancesOperation, operation.NewDeleteRomancesGroupOperation, operation.NewCheckVoterOperation, operation.NewConsumeVoteQuotaOperation, operation.NewGetVoteQuotasOperation, operation.NewSetVoteQuotaOverridesOperation, operation.NewBlockPeerOperation, operation.NewUnblockPeerOperation, operation.NewUnmatchOperation, operation.NewRecordLastVoteOperation, operation.NewRewindVoteOperation, operation.NewGetVoteHistoryOperation, operation.NewSubmitComplimentOperation, operation.NewModerateComplimentOperation, operation.NewListRomancesOperation, operation.NewExportRomancesOperation, operation.NewCheckEligibilityOperation, operation.NewGetExclusionFilterOperation, application.NewVotingService)
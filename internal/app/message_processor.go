@@ -2,27 +2,38 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/operation"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/stream"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb_streams"
 	"os"
 	"sync"
+	"time"
 )
 
 type MessageProcessor struct {
-	topicListener *TopicListener
-	logger        platform.Logger
+	topicListener         *TopicListener
+	streamReader          *dynamodb_streams.StreamReader
+	romancesStreamHandler *stream.RomancesStreamHandler
+	logger                platform.Logger
 }
 
 func NewMessageProcessor(
 	topicListener *TopicListener,
+	streamReader *dynamodb_streams.StreamReader,
+	romancesStreamHandler *stream.RomancesStreamHandler,
 	logger platform.Logger,
 ) *MessageProcessor {
 
 	return &MessageProcessor{
-		topicListener: topicListener,
-		logger:        logger,
+		topicListener:         topicListener,
+		streamReader:          streamReader,
+		romancesStreamHandler: romancesStreamHandler,
+		logger:                logger,
 	}
 }
 
@@ -43,9 +54,27 @@ func (s *MessageProcessor) Start(ctx context.Context) {
 		}()
 	}
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.readRomancesStream(ctx)
+	}()
+
 	wg.Wait()
 }
 
+func (s *MessageProcessor) readRomancesStream(ctx context.Context) {
+	err := s.streamReader.Read(ctx, persistence.RomancesTableName, s.romancesStreamHandler)
+	if errors.Is(err, dynamodb_streams.ErrStreamNotEnabled) {
+		s.logger.Warn(fmt.Sprintf("Romance removal events are not published: %v", err))
+		return
+	}
+	if err != nil {
+		s.logger.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
 type TopicListener struct {
 	subscriber   messaging.Subscriber
 	topicHandler *messaging.TopicHandler
@@ -105,6 +134,18 @@ func (t TopicListener) safeProcessMessage(ctx context.Context, topic messaging.T
 		}
 	}()
 
+	metadata := m.GetMetadata()
+	t.logger.Debug(
+		fmt.Sprintf(
+			"Topic `%s`: processing message `%s` (producer: `%s`, schema: v%d, age: %s)",
+			topic,
+			metadata.Id,
+			metadata.Producer,
+			metadata.SchemaVersion,
+			metadata.Age(time.Now()),
+		),
+	)
+
 	err := t.topicHandler.Dispatch(ctx, topic, m)
 	if err != nil {
 		t.logger.Error(err.Error())
@@ -4,14 +4,21 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/message/messagepb"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
 )
 
-const delRomancesGroupMessageName = "del_romances_group"
+const (
+	delRomancesGroupMessageName          = "del_romances_group"
+	delRomancesGroupMessageSchemaVersion = 1
+)
 
 type DeleteRomancesGroupMessage struct {
+	Headers
 	ActiveUserId uuid.UUID   `json:"active_user_id"`
 	CountryId    uint16      `json:"country_id"`
 	PeerIds      []uuid.UUID `json:"peer_ids"`
@@ -31,13 +38,17 @@ func (m *DeleteRomancesGroupMessage) GetDeduplicationId() string {
 }
 
 func (m *DeleteRomancesGroupMessage) GetPayload() messaging.Payload {
-	payload, err := MarshalMessage(delRomancesGroupMessageName, m)
+	payload, err := m.Encode(codec.Json)
 	if err != nil {
 		return nil
 	}
 	return payload
 }
 
+func (m *DeleteRomancesGroupMessage) Encode(c codec.Codec) (messaging.Payload, error) {
+	return MarshalMessage(c, delRomancesGroupMessageName, delRomancesGroupMessageSchemaVersion, m)
+}
+
 func (m *DeleteRomancesGroupMessage) Load(payload messaging.Payload) error {
 	tmp, err := UnmarshalMessage[*DeleteRomancesGroupMessage](payload, delRomancesGroupMessageName)
 	if err != nil {
@@ -46,3 +57,41 @@ func (m *DeleteRomancesGroupMessage) Load(payload messaging.Payload) error {
 	*m = *tmp
 	return nil
 }
+
+func (m *DeleteRomancesGroupMessage) MarshalProto() ([]byte, error) {
+	peerIds := make([]string, len(m.PeerIds))
+	for i, peerId := range m.PeerIds {
+		peerIds[i] = peerId.String()
+	}
+
+	return codec.MarshalProto(&messagepb.DeleteRomancesGroup{
+		ActiveUserId: m.ActiveUserId.String(),
+		CountryId:    uint32(m.CountryId),
+		PeerIds:      peerIds,
+	})
+}
+
+func (m *DeleteRomancesGroupMessage) UnmarshalProto(data []byte) error {
+	pb := &messagepb.DeleteRomancesGroup{}
+	if err := proto.Unmarshal(data, pb); err != nil {
+		return err
+	}
+
+	activeUserId, countryId, err := parseProtoUserKey(pb.ActiveUserId, pb.CountryId)
+	if err != nil {
+		return err
+	}
+
+	peerIds := make([]uuid.UUID, len(pb.PeerIds))
+	for i, peerId := range pb.PeerIds {
+		peerIds[i], err = uuid.Parse(peerId)
+		if err != nil {
+			return fmt.Errorf("invalid peer id %q: %w", peerId, err)
+		}
+	}
+
+	m.ActiveUserId = activeUserId
+	m.CountryId = countryId
+	m.PeerIds = peerIds
+	return nil
+}
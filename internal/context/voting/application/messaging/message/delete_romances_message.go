@@ -2,14 +2,21 @@ package message
 
 import (
 	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/message/messagepb"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
 )
 
-const delRomancesMessageName = "del_romances"
+const (
+	delRomancesMessageName          = "del_romances"
+	delRomancesMessageSchemaVersion = 1
+)
 
 type DeleteRomancesMessage struct {
+	Headers
 	ActiveUserId uuid.UUID `json:"active_user_id"`
 	CountryId    uint16    `json:"country_id"`
 }
@@ -26,13 +33,17 @@ func (m *DeleteRomancesMessage) GetDeduplicationId() string {
 }
 
 func (m *DeleteRomancesMessage) GetPayload() messaging.Payload {
-	payload, err := MarshalMessage(delRomancesMessageName, m)
+	payload, err := m.Encode(codec.Json)
 	if err != nil {
 		return nil
 	}
 	return payload
 }
 
+func (m *DeleteRomancesMessage) Encode(c codec.Codec) (messaging.Payload, error) {
+	return MarshalMessage(c, delRomancesMessageName, delRomancesMessageSchemaVersion, m)
+}
+
 func (m *DeleteRomancesMessage) Load(payload messaging.Payload) error {
 	tmp, err := UnmarshalMessage[*DeleteRomancesMessage](payload, delRomancesMessageName)
 	if err != nil {
@@ -42,3 +53,26 @@ func (m *DeleteRomancesMessage) Load(payload messaging.Payload) error {
 	*m = *tmp
 	return nil
 }
+
+func (m *DeleteRomancesMessage) MarshalProto() ([]byte, error) {
+	return codec.MarshalProto(&messagepb.DeleteRomances{
+		ActiveUserId: m.ActiveUserId.String(),
+		CountryId:    uint32(m.CountryId),
+	})
+}
+
+func (m *DeleteRomancesMessage) UnmarshalProto(data []byte) error {
+	pb := &messagepb.DeleteRomances{}
+	if err := proto.Unmarshal(data, pb); err != nil {
+		return err
+	}
+
+	activeUserId, countryId, err := parseProtoUserKey(pb.ActiveUserId, pb.CountryId)
+	if err != nil {
+		return err
+	}
+
+	m.ActiveUserId = activeUserId
+	m.CountryId = countryId
+	return nil
+}
@@ -0,0 +1,209 @@
+package message
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+type goldenCase struct {
+	name string
+	// fixture names the golden files when a message has several cases, it defaults to the name
+	fixture  string
+	message  func() codec.Encodable
+	schema   uint16
+	loadInto func() messaging.Message
+}
+
+type GoldenUnitTestSuite struct {
+	suite.Suite
+	metadata messaging.Metadata
+	cases    []goldenCase
+}
+
+func TestGoldenUnitSuite(t *testing.T) {
+	suite.Run(t, new(GoldenUnitTestSuite))
+}
+
+func (s *GoldenUnitTestSuite) SetupSuite() {
+	producedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.metadata = messaging.Metadata{
+		Id:            "b7b7d3c4-4f4e-4d8c-9d6c-0b6f6f0e2a11",
+		ProducedAt:    &producedAt,
+		Producer:      "recs-votes-storage",
+		SchemaVersion: 1,
+		TraceContext:  messaging.NewTraceContext(traceParent, "vendor=value"),
+	}
+
+	activeUserId := uuid.MustParse("0b6f1c2e-3a4d-4e5f-8a9b-0c1d2e3f4a5b")
+	peerIds := []uuid.UUID{
+		uuid.MustParse("1c2d3e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f"),
+		uuid.MustParse("2d3e4f5a-6b7c-4d8e-9f0a-1b2c3d4e5f6a"),
+	}
+
+	removal := RomanceRemoval{
+		StreamEventId:   "4b7a1f2c9e3d4a5b8c6d7e8f9a0b1c2d",
+		MinUserId:       activeUserId,
+		MaxUserId:       peerIds[0],
+		MinUserVoteType: 2,
+		MaxUserVoteType: 1,
+		Version:         3,
+		RemovedAt:       time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC),
+	}
+
+	position := uint32(4)
+	voteContext := &VoteContext{
+		Surface:          "deck",
+		RecommendationId: "rec-42",
+		Position:         &position,
+		ExperimentBucket: "control",
+	}
+	removalWithContext := removal
+	removalWithContext.MinUserVoteContext = voteContext
+
+	voteRateLimited := VoteRateLimitedMessage{
+		ActiveUserId: activeUserId,
+		PeerId:       peerIds[0],
+		CountryId:    11,
+		VoteType:     1,
+		Counter:      "yes",
+		Window:       "hour",
+		Limit:        300,
+		ResetAt:      time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC),
+		LimitedAt:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	s.cases = []goldenCase{
+		{
+			name: delRomancesMessageName,
+			message: func() codec.Encodable {
+				return &DeleteRomancesMessage{ActiveUserId: activeUserId, CountryId: 11}
+			},
+			schema:   delRomancesMessageSchemaVersion,
+			loadInto: func() messaging.Message { return &DeleteRomancesMessage{} },
+		},
+		{
+			name: delRomancesGroupMessageName,
+			message: func() codec.Encodable {
+				return &DeleteRomancesGroupMessage{ActiveUserId: activeUserId, CountryId: 11, PeerIds: peerIds}
+			},
+			schema:   delRomancesGroupMessageSchemaVersion,
+			loadInto: func() messaging.Message { return &DeleteRomancesGroupMessage{} },
+		},
+		{
+			name: romanceExpiredMessageName,
+			message: func() codec.Encodable {
+				return NewRomanceExpiredMessage(removal)
+			},
+			schema:   romanceExpiredMessageSchemaVersion,
+			loadInto: func() messaging.Message { return &RomanceExpiredMessage{} },
+		},
+		{
+			name: romanceDeletedMessageName,
+			message: func() codec.Encodable {
+				return NewRomanceDeletedMessage(removal)
+			},
+			schema:   romanceDeletedMessageSchemaVersion,
+			loadInto: func() messaging.Message { return &RomanceDeletedMessage{} },
+		},
+		{
+			name:    romanceExpiredMessageName,
+			fixture: romanceExpiredMessageName + "_with_context",
+			message: func() codec.Encodable {
+				return NewRomanceExpiredMessage(removalWithContext)
+			},
+			schema:   romanceExpiredMessageSchemaVersion,
+			loadInto: func() messaging.Message { return &RomanceExpiredMessage{} },
+		},
+		{
+			name:    romanceDeletedMessageName,
+			fixture: romanceDeletedMessageName + "_with_context",
+			message: func() codec.Encodable {
+				return NewRomanceDeletedMessage(removalWithContext)
+			},
+			schema:   romanceDeletedMessageSchemaVersion,
+			loadInto: func() messaging.Message { return &RomanceDeletedMessage{} },
+		},
+		{
+			name: voteRateLimitedMessageName,
+			message: func() codec.Encodable {
+				m := voteRateLimited
+				return &m
+			},
+			schema:   voteRateLimitedMessageSchemaVersion,
+			loadInto: func() messaging.Message { return &VoteRateLimitedMessage{} },
+		},
+		{
+			name:    voteRateLimitedMessageName,
+			fixture: voteRateLimitedMessageName + "_with_context",
+			message: func() codec.Encodable {
+				m := voteRateLimited
+				m.VoteContext = voteContext
+				return &m
+			},
+			schema:   voteRateLimitedMessageSchemaVersion,
+			loadInto: func() messaging.Message { return &VoteRateLimitedMessage{} },
+		},
+	}
+}
+
+func (s *GoldenUnitTestSuite) TestEncodeMatchesGolden() {
+	for _, tc := range s.cases {
+		for _, c := range []codec.Codec{codec.Json, codec.CloudEvents, codec.Protobuf} {
+			s.Run(tc.fixtureName()+"/"+string(c.Encoding()), func() {
+				metadata := s.metadata
+				metadata.SchemaVersion = tc.schema
+
+				payload, err := marshalMessageWithMetadata(c, tc.name, metadata, tc.message())
+				s.Require().NoError(err)
+
+				path := goldenPath(tc.fixtureName(), c)
+				if *updateGolden {
+					s.Require().NoError(os.WriteFile(path, payload, 0o644))
+				}
+
+				golden, err := os.ReadFile(path)
+				s.Require().NoError(err)
+				s.Require().Equal(string(golden), string(payload))
+			})
+		}
+	}
+}
+
+func (s *GoldenUnitTestSuite) TestLoadGolden() {
+	for _, tc := range s.cases {
+		for _, c := range []codec.Codec{codec.Json, codec.CloudEvents, codec.Protobuf} {
+			s.Run(tc.fixtureName()+"/"+string(c.Encoding()), func() {
+				golden, err := os.ReadFile(goldenPath(tc.fixtureName(), c))
+				s.Require().NoError(err)
+
+				loaded := tc.loadInto()
+				s.Require().NoError(loaded.Load(golden))
+
+				expected := tc.message()
+				expected.(headersCarrier).setMetadata(s.metadata)
+				s.Require().Equal(expected, loaded)
+			})
+		}
+	}
+}
+
+func (tc goldenCase) fixtureName() string {
+	if tc.fixture != "" {
+		return tc.fixture
+	}
+	return tc.name
+}
+
+func goldenPath(name string, c codec.Codec) string {
+	return filepath.Join("testdata", name+"."+string(c.Encoding())+".golden")
+}
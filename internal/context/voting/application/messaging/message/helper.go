@@ -1,32 +1,96 @@
 package message
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec"
+	"github.com/google/uuid"
+	"math"
+	"reflect"
+	"time"
 )
 
-type Envelope[T messaging.Message] struct {
-	Name    string `json:"name"`
-	Message T      `json:"message"`
+// Headers keeps the envelope metadata of a message. Producers attach the trace context
+// before publishing, consumers get the whole metadata back after the message is loaded.
+type Headers struct {
+	metadata messaging.Metadata
 }
 
-func MarshalMessage[T messaging.Message](name string, message T) (messaging.Payload, error) {
-	env := Envelope[T]{
-		Name:    name,
-		Message: message,
+type headersCarrier interface {
+	GetMetadata() messaging.Metadata
+	setMetadata(metadata messaging.Metadata)
+}
+
+func (h *Headers) GetMetadata() messaging.Metadata {
+	return h.metadata
+}
+
+func (h *Headers) SetTraceContext(traceContext messaging.TraceContext) {
+	h.metadata.TraceContext = traceContext
+}
+
+func (h *Headers) setMetadata(metadata messaging.Metadata) {
+	h.metadata = metadata
+}
+
+func MarshalMessage[T messaging.Message](
+	c codec.Codec,
+	name string,
+	schemaVersion uint16,
+	message T,
+) (messaging.Payload, error) {
+	producedAt := time.Now().UTC()
+	metadata := messaging.Metadata{
+		Id:            uuid.NewString(),
+		ProducedAt:    &producedAt,
+		Producer:      config.ServiceName,
+		SchemaVersion: schemaVersion,
 	}
-	return json.Marshal(env)
+	if carrier, ok := any(message).(headersCarrier); ok {
+		metadata.TraceContext = carrier.GetMetadata().TraceContext
+	}
+
+	return marshalMessageWithMetadata(c, name, metadata, message)
 }
 
+func marshalMessageWithMetadata[T messaging.Message](
+	c codec.Codec,
+	name string,
+	metadata messaging.Metadata,
+	message T,
+) (messaging.Payload, error) {
+	body, err := c.MarshalBody(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Encode(codec.Envelope{
+		Metadata: metadata,
+		Name:     name,
+		Body:     body,
+	})
+}
+
+// UnmarshalMessage detects the encoding of the payload, so consumers accept every codec
+// regardless of what the topic is currently configured to publish.
 func UnmarshalMessage[T messaging.Message](
 	p messaging.Payload,
 	expectName string,
 ) (T, error) {
-	var env Envelope[T]
 	var zero T
 
-	if err := json.Unmarshal(p, &env); err != nil {
+	c, err := codec.Detect(p)
+	if err != nil {
+		return zero, err
+	}
+
+	env, err := c.Decode(p)
+	if errors.Is(err, codec.ErrEmptyBody) {
+		return zero, fmt.Errorf("empty message body for %q", expectName)
+	}
+	if err != nil {
 		return zero, err
 	}
 
@@ -35,5 +99,30 @@ func UnmarshalMessage[T messaging.Message](
 		return zero, fmt.Errorf("wrong message name: have %q, want %q", gotName, expectName)
 	}
 
-	return env.Message, nil
+	messageType := reflect.TypeOf(zero)
+	if messageType == nil || messageType.Kind() != reflect.Ptr {
+		return zero, fmt.Errorf("message %q must be unmarshaled into a pointer", expectName)
+	}
+
+	message := reflect.New(messageType.Elem()).Interface().(T)
+	if err := c.UnmarshalBody(env.Body, message); err != nil {
+		return zero, err
+	}
+
+	if carrier, ok := any(message).(headersCarrier); ok {
+		carrier.setMetadata(env.Metadata.WithDefaults())
+	}
+
+	return message, nil
+}
+
+func parseProtoUserKey(activeUserId string, countryId uint32) (uuid.UUID, uint16, error) {
+	id, err := uuid.Parse(activeUserId)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("invalid active user id %q: %w", activeUserId, err)
+	}
+	if countryId > math.MaxUint16 {
+		return uuid.Nil, 0, fmt.Errorf("country id %d out of range", countryId)
+	}
+	return id, uint16(countryId), nil
 }
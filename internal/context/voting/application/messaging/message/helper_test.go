@@ -0,0 +1,104 @@
+package message
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+type EnvelopeUnitTestSuite struct {
+	suite.Suite
+	activeUserKey valueobject.ActiveUserKey
+}
+
+func TestEnvelopeUnitSuite(t *testing.T) {
+	suite.Run(t, new(EnvelopeUnitTestSuite))
+}
+
+func (s *EnvelopeUnitTestSuite) SetupSuite() {
+	activeUserKey, err := valueobject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.activeUserKey = activeUserKey
+}
+
+func (s *EnvelopeUnitTestSuite) TestMarshalMessagePopulatesMetadata() {
+	m := NewDeleteRomancesMessage(s.activeUserKey)
+	m.SetTraceContext(messaging.NewTraceContext(traceParent, "vendor=value"))
+
+	payload := m.GetPayload()
+	s.Require().NotNil(payload)
+
+	metadata, err := codec.DecodeMetadata(payload)
+	s.Require().NoError(err)
+	s.Require().NoError(uuid.Validate(metadata.Id))
+	s.Require().NotNil(metadata.ProducedAt)
+	s.Require().Equal(config.ServiceName, metadata.Producer)
+	s.Require().Equal(uint16(delRomancesMessageSchemaVersion), metadata.SchemaVersion)
+	s.Require().Equal(traceParent, metadata.TraceParent)
+	s.Require().Equal("vendor=value", metadata.TraceState)
+
+	var raw map[string]any
+	s.Require().NoError(json.Unmarshal(payload, &raw))
+	s.Require().Equal(delRomancesMessageName, raw["name"])
+	s.Require().Equal(
+		map[string]any{
+			"active_user_id": s.activeUserKey.ActiveUserId().String(),
+			"country_id":     float64(s.activeUserKey.CountryId()),
+		},
+		raw["message"],
+	)
+}
+
+func (s *EnvelopeUnitTestSuite) TestLoadExposesMetadata() {
+	peerIds := []uuid.UUID{uuidhelper.NewUUID(s.T())}
+	original := NewDeleteRomancesGroupMessage(s.activeUserKey, peerIds)
+	original.SetTraceContext(messaging.NewTraceContext(traceParent, ""))
+
+	loaded := &DeleteRomancesGroupMessage{}
+	s.Require().NoError(loaded.Load(original.GetPayload()))
+
+	s.Require().Equal(original.ActiveUserId, loaded.ActiveUserId)
+	s.Require().Equal(original.CountryId, loaded.CountryId)
+	s.Require().Equal(original.PeerIds, loaded.PeerIds)
+
+	metadata := loaded.GetMetadata()
+	s.Require().NotEmpty(metadata.Id)
+	s.Require().Equal(config.ServiceName, metadata.Producer)
+	s.Require().Equal(uint16(delRomancesGroupMessageSchemaVersion), metadata.SchemaVersion)
+	s.Require().Equal(traceParent, metadata.TraceParent)
+}
+
+func (s *EnvelopeUnitTestSuite) TestLoadLegacyPayload() {
+	payload := messaging.Payload(`{"name":"del_romances","message":{"active_user_id":"` +
+		s.activeUserKey.ActiveUserId().String() + `","country_id":11}}`)
+
+	loaded := &DeleteRomancesMessage{}
+	s.Require().NoError(loaded.Load(payload))
+
+	s.Require().Equal(s.activeUserKey.ActiveUserId(), loaded.ActiveUserId)
+	s.Require().Equal(uint16(11), loaded.CountryId)
+	s.Require().Equal(messaging.Metadata{SchemaVersion: messaging.DefaultSchemaVersion}, loaded.GetMetadata())
+}
+
+func (s *EnvelopeUnitTestSuite) TestLoadWrongName() {
+	payload := NewDeleteRomancesMessage(s.activeUserKey).GetPayload()
+
+	err := (&DeleteRomancesGroupMessage{}).Load(payload)
+	s.Require().ErrorContains(err, "wrong message name")
+}
+
+func (s *EnvelopeUnitTestSuite) TestLoadEmptyMessageBody() {
+	payload := messaging.Payload(`{"name":"del_romances","message":null}`)
+
+	err := (&DeleteRomancesMessage{}).Load(payload)
+	s.Require().ErrorContains(err, "empty message body")
+}
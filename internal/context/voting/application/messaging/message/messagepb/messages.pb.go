@@ -0,0 +1,540 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: internal/context/voting/application/messaging/message/messagepb/messages.proto
+
+package messagepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DeleteRomances is published as "del_romances".
+type DeleteRomances struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActiveUserId  string                 `protobuf:"bytes,1,opt,name=active_user_id,json=activeUserId,proto3" json:"active_user_id,omitempty"`
+	CountryId     uint32                 `protobuf:"varint,2,opt,name=country_id,json=countryId,proto3" json:"country_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRomances) Reset() {
+	*x = DeleteRomances{}
+	mi := &file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRomances) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRomances) ProtoMessage() {}
+
+func (x *DeleteRomances) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRomances.ProtoReflect.Descriptor instead.
+func (*DeleteRomances) Descriptor() ([]byte, []int) {
+	return file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DeleteRomances) GetActiveUserId() string {
+	if x != nil {
+		return x.ActiveUserId
+	}
+	return ""
+}
+
+func (x *DeleteRomances) GetCountryId() uint32 {
+	if x != nil {
+		return x.CountryId
+	}
+	return 0
+}
+
+// DeleteRomancesGroup is published as "del_romances_group".
+type DeleteRomancesGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActiveUserId  string                 `protobuf:"bytes,1,opt,name=active_user_id,json=activeUserId,proto3" json:"active_user_id,omitempty"`
+	CountryId     uint32                 `protobuf:"varint,2,opt,name=country_id,json=countryId,proto3" json:"country_id,omitempty"`
+	PeerIds       []string               `protobuf:"bytes,3,rep,name=peer_ids,json=peerIds,proto3" json:"peer_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRomancesGroup) Reset() {
+	*x = DeleteRomancesGroup{}
+	mi := &file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRomancesGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRomancesGroup) ProtoMessage() {}
+
+func (x *DeleteRomancesGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRomancesGroup.ProtoReflect.Descriptor instead.
+func (*DeleteRomancesGroup) Descriptor() ([]byte, []int) {
+	return file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DeleteRomancesGroup) GetActiveUserId() string {
+	if x != nil {
+		return x.ActiveUserId
+	}
+	return ""
+}
+
+func (x *DeleteRomancesGroup) GetCountryId() uint32 {
+	if x != nil {
+		return x.CountryId
+	}
+	return 0
+}
+
+func (x *DeleteRomancesGroup) GetPeerIds() []string {
+	if x != nil {
+		return x.PeerIds
+	}
+	return nil
+}
+
+// RomanceRemoved is published as "romance_expired" and "romance_deleted".
+type RomanceRemoved struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	StreamEventId      string                 `protobuf:"bytes,1,opt,name=stream_event_id,json=streamEventId,proto3" json:"stream_event_id,omitempty"`
+	MinUserId          string                 `protobuf:"bytes,2,opt,name=min_user_id,json=minUserId,proto3" json:"min_user_id,omitempty"`
+	MaxUserId          string                 `protobuf:"bytes,3,opt,name=max_user_id,json=maxUserId,proto3" json:"max_user_id,omitempty"`
+	MinUserVoteType    uint32                 `protobuf:"varint,4,opt,name=min_user_vote_type,json=minUserVoteType,proto3" json:"min_user_vote_type,omitempty"`
+	MaxUserVoteType    uint32                 `protobuf:"varint,5,opt,name=max_user_vote_type,json=maxUserVoteType,proto3" json:"max_user_vote_type,omitempty"`
+	Version            uint32                 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	RemovedAt          *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=removed_at,json=removedAt,proto3" json:"removed_at,omitempty"`
+	MinUserVoteContext *VoteContext           `protobuf:"bytes,8,opt,name=min_user_vote_context,json=minUserVoteContext,proto3" json:"min_user_vote_context,omitempty"`
+	MaxUserVoteContext *VoteContext           `protobuf:"bytes,9,opt,name=max_user_vote_context,json=maxUserVoteContext,proto3" json:"max_user_vote_context,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *RomanceRemoved) Reset() {
+	*x = RomanceRemoved{}
+	mi := &file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RomanceRemoved) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RomanceRemoved) ProtoMessage() {}
+
+func (x *RomanceRemoved) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RomanceRemoved.ProtoReflect.Descriptor instead.
+func (*RomanceRemoved) Descriptor() ([]byte, []int) {
+	return file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RomanceRemoved) GetStreamEventId() string {
+	if x != nil {
+		return x.StreamEventId
+	}
+	return ""
+}
+
+func (x *RomanceRemoved) GetMinUserId() string {
+	if x != nil {
+		return x.MinUserId
+	}
+	return ""
+}
+
+func (x *RomanceRemoved) GetMaxUserId() string {
+	if x != nil {
+		return x.MaxUserId
+	}
+	return ""
+}
+
+func (x *RomanceRemoved) GetMinUserVoteType() uint32 {
+	if x != nil {
+		return x.MinUserVoteType
+	}
+	return 0
+}
+
+func (x *RomanceRemoved) GetMaxUserVoteType() uint32 {
+	if x != nil {
+		return x.MaxUserVoteType
+	}
+	return 0
+}
+
+func (x *RomanceRemoved) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RomanceRemoved) GetRemovedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RemovedAt
+	}
+	return nil
+}
+
+func (x *RomanceRemoved) GetMinUserVoteContext() *VoteContext {
+	if x != nil {
+		return x.MinUserVoteContext
+	}
+	return nil
+}
+
+func (x *RomanceRemoved) GetMaxUserVoteContext() *VoteContext {
+	if x != nil {
+		return x.MaxUserVoteContext
+	}
+	return nil
+}
+
+// VoteContext attributes a vote to the recommendation it was cast on.
+type VoteContext struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Surface          string                 `protobuf:"bytes,1,opt,name=surface,proto3" json:"surface,omitempty"`
+	RecommendationId string                 `protobuf:"bytes,2,opt,name=recommendation_id,json=recommendationId,proto3" json:"recommendation_id,omitempty"`
+	Position         *uint32                `protobuf:"varint,3,opt,name=position,proto3,oneof" json:"position,omitempty"`
+	ExperimentBucket string                 `protobuf:"bytes,4,opt,name=experiment_bucket,json=experimentBucket,proto3" json:"experiment_bucket,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *VoteContext) Reset() {
+	*x = VoteContext{}
+	mi := &file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoteContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteContext) ProtoMessage() {}
+
+func (x *VoteContext) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteContext.ProtoReflect.Descriptor instead.
+func (*VoteContext) Descriptor() ([]byte, []int) {
+	return file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VoteContext) GetSurface() string {
+	if x != nil {
+		return x.Surface
+	}
+	return ""
+}
+
+func (x *VoteContext) GetRecommendationId() string {
+	if x != nil {
+		return x.RecommendationId
+	}
+	return ""
+}
+
+func (x *VoteContext) GetPosition() uint32 {
+	if x != nil && x.Position != nil {
+		return *x.Position
+	}
+	return 0
+}
+
+func (x *VoteContext) GetExperimentBucket() string {
+	if x != nil {
+		return x.ExperimentBucket
+	}
+	return ""
+}
+
+// VoteRateLimited is published as "vote_rate_limited".
+type VoteRateLimited struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActiveUserId  string                 `protobuf:"bytes,1,opt,name=active_user_id,json=activeUserId,proto3" json:"active_user_id,omitempty"`
+	PeerId        string                 `protobuf:"bytes,2,opt,name=peer_id,json=peerId,proto3" json:"peer_id,omitempty"`
+	CountryId     uint32                 `protobuf:"varint,3,opt,name=country_id,json=countryId,proto3" json:"country_id,omitempty"`
+	VoteType      uint32                 `protobuf:"varint,4,opt,name=vote_type,json=voteType,proto3" json:"vote_type,omitempty"`
+	Counter       string                 `protobuf:"bytes,5,opt,name=counter,proto3" json:"counter,omitempty"`
+	Window        string                 `protobuf:"bytes,6,opt,name=window,proto3" json:"window,omitempty"`
+	Limit         uint32                 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	ResetAt       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=reset_at,json=resetAt,proto3" json:"reset_at,omitempty"`
+	LimitedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=limited_at,json=limitedAt,proto3" json:"limited_at,omitempty"`
+	VoteContext   *VoteContext           `protobuf:"bytes,10,opt,name=vote_context,json=voteContext,proto3" json:"vote_context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoteRateLimited) Reset() {
+	*x = VoteRateLimited{}
+	mi := &file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoteRateLimited) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteRateLimited) ProtoMessage() {}
+
+func (x *VoteRateLimited) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteRateLimited.ProtoReflect.Descriptor instead.
+func (*VoteRateLimited) Descriptor() ([]byte, []int) {
+	return file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VoteRateLimited) GetActiveUserId() string {
+	if x != nil {
+		return x.ActiveUserId
+	}
+	return ""
+}
+
+func (x *VoteRateLimited) GetPeerId() string {
+	if x != nil {
+		return x.PeerId
+	}
+	return ""
+}
+
+func (x *VoteRateLimited) GetCountryId() uint32 {
+	if x != nil {
+		return x.CountryId
+	}
+	return 0
+}
+
+func (x *VoteRateLimited) GetVoteType() uint32 {
+	if x != nil {
+		return x.VoteType
+	}
+	return 0
+}
+
+func (x *VoteRateLimited) GetCounter() string {
+	if x != nil {
+		return x.Counter
+	}
+	return ""
+}
+
+func (x *VoteRateLimited) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *VoteRateLimited) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *VoteRateLimited) GetResetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResetAt
+	}
+	return nil
+}
+
+func (x *VoteRateLimited) GetLimitedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LimitedAt
+	}
+	return nil
+}
+
+func (x *VoteRateLimited) GetVoteContext() *VoteContext {
+	if x != nil {
+		return x.VoteContext
+	}
+	return nil
+}
+
+var File_internal_context_voting_application_messaging_message_messagepb_messages_proto protoreflect.FileDescriptor
+
+const file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDesc = "" +
+	"\n" +
+	"Ninternal/context/voting/application/messaging/message/messagepb/messages.proto\x12\x1crecs.votes_storage.voting.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"U\n" +
+	"\x0eDeleteRomances\x12$\n" +
+	"\x0eactive_user_id\x18\x01 \x01(\tR\factiveUserId\x12\x1d\n" +
+	"\n" +
+	"country_id\x18\x02 \x01(\rR\tcountryId\"u\n" +
+	"\x13DeleteRomancesGroup\x12$\n" +
+	"\x0eactive_user_id\x18\x01 \x01(\tR\factiveUserId\x12\x1d\n" +
+	"\n" +
+	"country_id\x18\x02 \x01(\rR\tcountryId\x12\x19\n" +
+	"\bpeer_ids\x18\x03 \x03(\tR\apeerIds\"\xe3\x03\n" +
+	"\x0eRomanceRemoved\x12&\n" +
+	"\x0fstream_event_id\x18\x01 \x01(\tR\rstreamEventId\x12\x1e\n" +
+	"\vmin_user_id\x18\x02 \x01(\tR\tminUserId\x12\x1e\n" +
+	"\vmax_user_id\x18\x03 \x01(\tR\tmaxUserId\x12+\n" +
+	"\x12min_user_vote_type\x18\x04 \x01(\rR\x0fminUserVoteType\x12+\n" +
+	"\x12max_user_vote_type\x18\x05 \x01(\rR\x0fmaxUserVoteType\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\rR\aversion\x129\n" +
+	"\n" +
+	"removed_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tremovedAt\x12\\\n" +
+	"\x15min_user_vote_context\x18\b \x01(\v2).recs.votes_storage.voting.v1.VoteContextR\x12minUserVoteContext\x12\\\n" +
+	"\x15max_user_vote_context\x18\t \x01(\v2).recs.votes_storage.voting.v1.VoteContextR\x12maxUserVoteContext\"\xaf\x01\n" +
+	"\vVoteContext\x12\x18\n" +
+	"\asurface\x18\x01 \x01(\tR\asurface\x12+\n" +
+	"\x11recommendation_id\x18\x02 \x01(\tR\x10recommendationId\x12\x1f\n" +
+	"\bposition\x18\x03 \x01(\rH\x00R\bposition\x88\x01\x01\x12+\n" +
+	"\x11experiment_bucket\x18\x04 \x01(\tR\x10experimentBucketB\v\n" +
+	"\t_position\"\x94\x03\n" +
+	"\x0fVoteRateLimited\x12$\n" +
+	"\x0eactive_user_id\x18\x01 \x01(\tR\factiveUserId\x12\x17\n" +
+	"\apeer_id\x18\x02 \x01(\tR\x06peerId\x12\x1d\n" +
+	"\n" +
+	"country_id\x18\x03 \x01(\rR\tcountryId\x12\x1b\n" +
+	"\tvote_type\x18\x04 \x01(\rR\bvoteType\x12\x18\n" +
+	"\acounter\x18\x05 \x01(\tR\acounter\x12\x16\n" +
+	"\x06window\x18\x06 \x01(\tR\x06window\x12\x14\n" +
+	"\x05limit\x18\a \x01(\rR\x05limit\x125\n" +
+	"\breset_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\aresetAt\x129\n" +
+	"\n" +
+	"limited_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tlimitedAt\x12L\n" +
+	"\fvote_context\x18\n" +
+	" \x01(\v2).recs.votes_storage.voting.v1.VoteContextR\vvoteContextBlZjgithub.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/message/messagepbb\x06proto3"
+
+var (
+	file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDescOnce sync.Once
+	file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDescData []byte
+)
+
+func file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDescGZIP() []byte {
+	file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDescOnce.Do(func() {
+		file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDesc), len(file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDesc)))
+	})
+	return file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDescData
+}
+
+var file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_internal_context_voting_application_messaging_message_messagepb_messages_proto_goTypes = []any{
+	(*DeleteRomances)(nil),        // 0: recs.votes_storage.voting.v1.DeleteRomances
+	(*DeleteRomancesGroup)(nil),   // 1: recs.votes_storage.voting.v1.DeleteRomancesGroup
+	(*RomanceRemoved)(nil),        // 2: recs.votes_storage.voting.v1.RomanceRemoved
+	(*VoteContext)(nil),           // 3: recs.votes_storage.voting.v1.VoteContext
+	(*VoteRateLimited)(nil),       // 4: recs.votes_storage.voting.v1.VoteRateLimited
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+}
+var file_internal_context_voting_application_messaging_message_messagepb_messages_proto_depIdxs = []int32{
+	5, // 0: recs.votes_storage.voting.v1.RomanceRemoved.removed_at:type_name -> google.protobuf.Timestamp
+	3, // 1: recs.votes_storage.voting.v1.RomanceRemoved.min_user_vote_context:type_name -> recs.votes_storage.voting.v1.VoteContext
+	3, // 2: recs.votes_storage.voting.v1.RomanceRemoved.max_user_vote_context:type_name -> recs.votes_storage.voting.v1.VoteContext
+	5, // 3: recs.votes_storage.voting.v1.VoteRateLimited.reset_at:type_name -> google.protobuf.Timestamp
+	5, // 4: recs.votes_storage.voting.v1.VoteRateLimited.limited_at:type_name -> google.protobuf.Timestamp
+	3, // 5: recs.votes_storage.voting.v1.VoteRateLimited.vote_context:type_name -> recs.votes_storage.voting.v1.VoteContext
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() {
+	file_internal_context_voting_application_messaging_message_messagepb_messages_proto_init()
+}
+func file_internal_context_voting_application_messaging_message_messagepb_messages_proto_init() {
+	if File_internal_context_voting_application_messaging_message_messagepb_messages_proto != nil {
+		return
+	}
+	file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes[3].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDesc), len(file_internal_context_voting_application_messaging_message_messagepb_messages_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_internal_context_voting_application_messaging_message_messagepb_messages_proto_goTypes,
+		DependencyIndexes: file_internal_context_voting_application_messaging_message_messagepb_messages_proto_depIdxs,
+		MessageInfos:      file_internal_context_voting_application_messaging_message_messagepb_messages_proto_msgTypes,
+	}.Build()
+	File_internal_context_voting_application_messaging_message_messagepb_messages_proto = out.File
+	file_internal_context_voting_application_messaging_message_messagepb_messages_proto_goTypes = nil
+	file_internal_context_voting_application_messaging_message_messagepb_messages_proto_depIdxs = nil
+}
@@ -0,0 +1,44 @@
+package message
+
+import (
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec"
+)
+
+const (
+	romanceDeletedMessageName          = "romance_deleted"
+	romanceDeletedMessageSchemaVersion = 1
+)
+
+type RomanceDeletedMessage struct {
+	Headers
+	RomanceRemoval
+}
+
+func NewRomanceDeletedMessage(removal RomanceRemoval) *RomanceDeletedMessage {
+	return &RomanceDeletedMessage{
+		RomanceRemoval: removal,
+	}
+}
+
+func (m *RomanceDeletedMessage) GetPayload() messaging.Payload {
+	payload, err := m.Encode(codec.Json)
+	if err != nil {
+		return nil
+	}
+	return payload
+}
+
+func (m *RomanceDeletedMessage) Encode(c codec.Codec) (messaging.Payload, error) {
+	return MarshalMessage(c, romanceDeletedMessageName, romanceDeletedMessageSchemaVersion, m)
+}
+
+func (m *RomanceDeletedMessage) Load(payload messaging.Payload) error {
+	tmp, err := UnmarshalMessage[*RomanceDeletedMessage](payload, romanceDeletedMessageName)
+	if err != nil {
+		return err
+	}
+
+	*m = *tmp
+	return nil
+}
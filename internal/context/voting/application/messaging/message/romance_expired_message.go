@@ -0,0 +1,44 @@
+package message
+
+import (
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec"
+)
+
+const (
+	romanceExpiredMessageName          = "romance_expired"
+	romanceExpiredMessageSchemaVersion = 1
+)
+
+type RomanceExpiredMessage struct {
+	Headers
+	RomanceRemoval
+}
+
+func NewRomanceExpiredMessage(removal RomanceRemoval) *RomanceExpiredMessage {
+	return &RomanceExpiredMessage{
+		RomanceRemoval: removal,
+	}
+}
+
+func (m *RomanceExpiredMessage) GetPayload() messaging.Payload {
+	payload, err := m.Encode(codec.Json)
+	if err != nil {
+		return nil
+	}
+	return payload
+}
+
+func (m *RomanceExpiredMessage) Encode(c codec.Codec) (messaging.Payload, error) {
+	return MarshalMessage(c, romanceExpiredMessageName, romanceExpiredMessageSchemaVersion, m)
+}
+
+func (m *RomanceExpiredMessage) Load(payload messaging.Payload) error {
+	tmp, err := UnmarshalMessage[*RomanceExpiredMessage](payload, romanceExpiredMessageName)
+	if err != nil {
+		return err
+	}
+
+	*m = *tmp
+	return nil
+}
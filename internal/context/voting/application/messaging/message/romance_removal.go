@@ -0,0 +1,108 @@
+package message
+
+import (
+	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/message/messagepb"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"math"
+	"time"
+)
+
+// RomanceRemoval describes a romance removed from the storage by TTL or by a user request,
+// vote types are the ones the romance had right before the removal.
+type RomanceRemoval struct {
+	StreamEventId      string       `json:"stream_event_id"`
+	MinUserId          uuid.UUID    `json:"min_user_id"`
+	MaxUserId          uuid.UUID    `json:"max_user_id"`
+	MinUserVoteType    uint8        `json:"min_user_vote_type"`
+	MaxUserVoteType    uint8        `json:"max_user_vote_type"`
+	Version            uint32       `json:"version"`
+	RemovedAt          time.Time    `json:"removed_at"`
+	MinUserVoteContext *VoteContext `json:"min_user_vote_context,omitempty"`
+	MaxUserVoteContext *VoteContext `json:"max_user_vote_context,omitempty"`
+}
+
+// VoteContext attributes a vote to the recommendation it was cast on
+type VoteContext struct {
+	Surface          string  `json:"surface"`
+	RecommendationId string  `json:"recommendation_id,omitempty"`
+	Position         *uint32 `json:"position,omitempty"`
+	ExperimentBucket string  `json:"experiment_bucket,omitempty"`
+}
+
+func (c *VoteContext) toProto() *messagepb.VoteContext {
+	if c == nil {
+		return nil
+	}
+	return &messagepb.VoteContext{
+		Surface:          c.Surface,
+		RecommendationId: c.RecommendationId,
+		Position:         c.Position,
+		ExperimentBucket: c.ExperimentBucket,
+	}
+}
+
+func newVoteContextFromProto(pb *messagepb.VoteContext) *VoteContext {
+	if pb == nil {
+		return nil
+	}
+	return &VoteContext{
+		Surface:          pb.Surface,
+		RecommendationId: pb.RecommendationId,
+		Position:         pb.Position,
+		ExperimentBucket: pb.ExperimentBucket,
+	}
+}
+
+func (r *RomanceRemoval) GetDeduplicationId() string {
+	return r.StreamEventId
+}
+
+func (r *RomanceRemoval) MarshalProto() ([]byte, error) {
+	return codec.MarshalProto(&messagepb.RomanceRemoved{
+		StreamEventId:      r.StreamEventId,
+		MinUserId:          r.MinUserId.String(),
+		MaxUserId:          r.MaxUserId.String(),
+		MinUserVoteType:    uint32(r.MinUserVoteType),
+		MaxUserVoteType:    uint32(r.MaxUserVoteType),
+		Version:            r.Version,
+		RemovedAt:          timestamppb.New(r.RemovedAt),
+		MinUserVoteContext: r.MinUserVoteContext.toProto(),
+		MaxUserVoteContext: r.MaxUserVoteContext.toProto(),
+	})
+}
+
+func (r *RomanceRemoval) UnmarshalProto(data []byte) error {
+	pb := &messagepb.RomanceRemoved{}
+	if err := proto.Unmarshal(data, pb); err != nil {
+		return err
+	}
+
+	minUserId, err := uuid.Parse(pb.MinUserId)
+	if err != nil {
+		return fmt.Errorf("invalid min user id %q: %w", pb.MinUserId, err)
+	}
+	maxUserId, err := uuid.Parse(pb.MaxUserId)
+	if err != nil {
+		return fmt.Errorf("invalid max user id %q: %w", pb.MaxUserId, err)
+	}
+	if pb.MinUserVoteType > math.MaxUint8 || pb.MaxUserVoteType > math.MaxUint8 {
+		return fmt.Errorf("vote types %d/%d out of range", pb.MinUserVoteType, pb.MaxUserVoteType)
+	}
+
+	*r = RomanceRemoval{
+		StreamEventId:      pb.StreamEventId,
+		MinUserId:          minUserId,
+		MaxUserId:          maxUserId,
+		MinUserVoteType:    uint8(pb.MinUserVoteType),
+		MaxUserVoteType:    uint8(pb.MaxUserVoteType),
+		Version:            pb.Version,
+		RemovedAt:          pb.RemovedAt.AsTime(),
+		MinUserVoteContext: newVoteContextFromProto(pb.MinUserVoteContext),
+		MaxUserVoteContext: newVoteContextFromProto(pb.MaxUserVoteContext),
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package message
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/message/messagepb"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
+	romanceValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	voteRateLimitedMessageName          = "vote_rate_limited"
+	voteRateLimitedMessageSchemaVersion = 1
+)
+
+// VoteRateLimitedMessage is a trust & safety signal about a vote rejected by the vote limits
+type VoteRateLimitedMessage struct {
+	Headers
+	ActiveUserId uuid.UUID    `json:"active_user_id"`
+	PeerId       uuid.UUID    `json:"peer_id"`
+	CountryId    uint16       `json:"country_id"`
+	VoteType     uint8        `json:"vote_type"`
+	Counter      string       `json:"counter"`
+	Window       string       `json:"window"`
+	Limit        uint32       `json:"limit"`
+	ResetAt      time.Time    `json:"reset_at"`
+	LimitedAt    time.Time    `json:"limited_at"`
+	VoteContext  *VoteContext `json:"vote_context,omitempty"`
+}
+
+func NewVoteRateLimitedMessage(
+	voteId valueobject.VoteId,
+	voteType romanceValueObject.VoteType,
+	limitErr *counter.VoteRateLimitError,
+	limitedAt time.Time,
+	voteContext *romanceValueObject.VoteContext,
+) *VoteRateLimitedMessage {
+	return &VoteRateLimitedMessage{
+		ActiveUserId: voteId.ActiveUserId(),
+		PeerId:       voteId.PeerUserId(),
+		CountryId:    voteId.CountryId(),
+		VoteType:     uint8(voteType),
+		Counter:      limitErr.Counter,
+		Window:       string(limitErr.Window),
+		Limit:        limitErr.Limit,
+		ResetAt:      limitErr.ResetAt,
+		LimitedAt:    limitedAt.UTC(),
+		VoteContext:  newVoteContext(voteContext),
+	}
+}
+
+func newVoteContext(voteContext *romanceValueObject.VoteContext) *VoteContext {
+	if voteContext == nil {
+		return nil
+	}
+	return &VoteContext{
+		Surface:          voteContext.Surface.String(),
+		RecommendationId: voteContext.RecommendationId,
+		Position:         voteContext.Position,
+		ExperimentBucket: voteContext.ExperimentBucket,
+	}
+}
+
+// GetDeduplicationId collapses the signals of one user within a single limit window
+func (m *VoteRateLimitedMessage) GetDeduplicationId() string {
+	return fmt.Sprintf("%s_%d_%s_%s_%d", m.ActiveUserId.String(), m.CountryId, m.Counter, m.Window, m.ResetAt.Unix())
+}
+
+func (m *VoteRateLimitedMessage) GetPayload() messaging.Payload {
+	payload, err := m.Encode(codec.Json)
+	if err != nil {
+		return nil
+	}
+	return payload
+}
+
+func (m *VoteRateLimitedMessage) Encode(c codec.Codec) (messaging.Payload, error) {
+	return MarshalMessage(c, voteRateLimitedMessageName, voteRateLimitedMessageSchemaVersion, m)
+}
+
+func (m *VoteRateLimitedMessage) Load(payload messaging.Payload) error {
+	tmp, err := UnmarshalMessage[*VoteRateLimitedMessage](payload, voteRateLimitedMessageName)
+	if err != nil {
+		return err
+	}
+
+	*m = *tmp
+	return nil
+}
+
+func (m *VoteRateLimitedMessage) MarshalProto() ([]byte, error) {
+	return codec.MarshalProto(&messagepb.VoteRateLimited{
+		ActiveUserId: m.ActiveUserId.String(),
+		PeerId:       m.PeerId.String(),
+		CountryId:    uint32(m.CountryId),
+		VoteType:     uint32(m.VoteType),
+		Counter:      m.Counter,
+		Window:       m.Window,
+		Limit:        m.Limit,
+		ResetAt:      timestamppb.New(m.ResetAt),
+		LimitedAt:    timestamppb.New(m.LimitedAt),
+		VoteContext:  m.VoteContext.toProto(),
+	})
+}
+
+func (m *VoteRateLimitedMessage) UnmarshalProto(data []byte) error {
+	pb := &messagepb.VoteRateLimited{}
+	if err := proto.Unmarshal(data, pb); err != nil {
+		return err
+	}
+
+	activeUserId, countryId, err := parseProtoUserKey(pb.ActiveUserId, pb.CountryId)
+	if err != nil {
+		return err
+	}
+	peerId, err := uuid.Parse(pb.PeerId)
+	if err != nil {
+		return fmt.Errorf("invalid peer id %q: %w", pb.PeerId, err)
+	}
+	if pb.VoteType > math.MaxUint8 {
+		return fmt.Errorf("vote type %d out of range", pb.VoteType)
+	}
+
+	m.ActiveUserId = activeUserId
+	m.PeerId = peerId
+	m.CountryId = countryId
+	m.VoteType = uint8(pb.VoteType)
+	m.Counter = pb.Counter
+	m.Window = pb.Window
+	m.Limit = pb.Limit
+	m.ResetAt = pb.ResetAt.AsTime()
+	m.LimitedAt = pb.LimitedAt.AsTime()
+	m.VoteContext = newVoteContextFromProto(pb.VoteContext)
+	return nil
+}
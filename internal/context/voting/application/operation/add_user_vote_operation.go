@@ -5,32 +5,70 @@ import (
 	"errors"
 	"fmt"
 	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/message"
+	counterDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
 	countersRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/repository"
 	countersValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
+	quotaEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	rewindEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
 	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
 	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
 	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
 	"time"
 )
 
 type AddUserVoteOperation struct {
-	romancesRepository romancesRepo.RomancesRepository
-	countersRepository countersRepo.CountersRepository
-	logger             platform.Logger
+	romancesRepository         romancesRepo.RomancesRepository
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository
+	countersRepository         countersRepo.CountersRepository
+	transitionPolicy           *romanceDomain.VoteTransitionPolicy
+	votedAtPolicy              *romanceDomain.VotedAtPolicy
+	eligibilityPolicy          *romanceDomain.EligibilityPolicy
+	votePolicy                 *counterDomain.VotePolicy
+	checkVoter                 *CheckVoterOperation
+	consumeQuota               *ConsumeVoteQuotaOperation
+	recordLastVote             *RecordLastVoteOperation
+	submitCompliment           *SubmitComplimentOperation
+	publisher                  messaging.Publisher
+	voteSignalsTopic           messaging.Topic
+	logger                     platform.Logger
 }
 
 func NewAddUserVoteOperation(
 	romancesRepository romancesRepo.RomancesRepository,
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository,
 	countersRepository countersRepo.CountersRepository,
+	transitionPolicy *romanceDomain.VoteTransitionPolicy,
+	votedAtPolicy *romanceDomain.VotedAtPolicy,
+	eligibilityPolicy *romanceDomain.EligibilityPolicy,
+	votePolicy *counterDomain.VotePolicy,
+	checkVoter *CheckVoterOperation,
+	consumeQuota *ConsumeVoteQuotaOperation,
+	recordLastVote *RecordLastVoteOperation,
+	submitCompliment *SubmitComplimentOperation,
+	publisher messaging.Publisher,
+	cfg config.Config,
 	logger platform.Logger,
 ) *AddUserVoteOperation {
 	return &AddUserVoteOperation{
-		romancesRepository: romancesRepository,
-		countersRepository: countersRepository,
-		logger:             logger,
+		romancesRepository:         romancesRepository,
+		exclusionFiltersRepository: exclusionFiltersRepository,
+		countersRepository:         countersRepository,
+		transitionPolicy:           transitionPolicy,
+		votedAtPolicy:              votedAtPolicy,
+		eligibilityPolicy:          eligibilityPolicy,
+		votePolicy:                 votePolicy,
+		checkVoter:                 checkVoter,
+		consumeQuota:               consumeQuota,
+		recordLastVote:             recordLastVote,
+		submitCompliment:           submitCompliment,
+		publisher:                  publisher,
+		voteSignalsTopic:           messaging.Topic(cfg.Messaging.VoteSignalsTopic),
+		logger:                     logger,
 	}
 }
 
@@ -39,29 +77,71 @@ func (r *AddUserVoteOperation) Run(
 	voteId sharedValueObject.VoteId,
 	voteType romancesValueObject.VoteType,
 	votedAt time.Time,
-) (entity.Vote, error) {
+	voteContext *romancesValueObject.VoteContext,
+	complimentText string,
+) (_ entity.Vote, _ uint32, err error) {
+	if err = r.votedAtPolicy.Check(votedAt, time.Now()); err != nil {
+		return entity.Vote{}, 0, err
+	}
+	if err = r.checkVoter.Run(ctx, voteId.ActiveUserKey(), voteType); err != nil {
+		return entity.Vote{}, 0, err
+	}
+	if err = r.submitCompliment.Check(voteType, complimentText); err != nil {
+		return entity.Vote{}, 0, err
+	}
+
 	tries := 0
 
+	// the quota and the vote limits are taken once across retries and given back when the vote is rejected
+	var consumption *quotaEntity.Consumption
+	var limitWindows []counterDomain.VoteLimitWindow
+	defer func() {
+		if err != nil {
+			r.consumeQuota.Refund(ctx, consumption)
+			r.releaseVoteLimits(ctx, voteId, limitWindows)
+		}
+	}()
+
+	var compliment *romancesValueObject.Compliment
+	complimentSubmitted := false
+
 	getRomanceOperation := NewGetRomanceOperation(r.romancesRepository)
 	for {
 		romance, err := getRomanceOperation.Run(ctx, voteId)
 		if err != nil {
 			r.logger.Error(fmt.Sprintf("GetRomance error: %+v", err))
-			return entity.Vote{}, err
+			return entity.Vote{}, 0, err
+		}
+
+		if romance.IsBlocked() {
+			return entity.Vote{}, 0, romanceDomain.ErrRomanceBlocked
+		}
+
+		if romance.IsRevoteBanned(time.Now()) {
+			return entity.Vote{}, 0, romanceDomain.ErrUnmatched
 		}
 
-		if err := isVoteTypeChangeAllowed(romance.ActiveUserVote, voteType); err != nil {
-			return entity.Vote{}, err
+		if romance.ActiveUserVote.IsNewerThan(votedAt) {
+			return entity.Vote{}, 0, romanceDomain.ErrStaleVote
 		}
 
-		if voteType == romance.ActiveUserVote.VoteType {
-			return entity.Vote{}, romanceDomain.ErrVoteDuplicate
+		// a no past its cooling period made the peer eligible again, voting no again renews it
+		renewsNo := voteType == romancesValueObject.VoteTypeNo && r.eligibilityPolicy.IsNoExpired(romance.ActiveUserVote, time.Now())
+		if !renewsNo {
+			err = r.transitionPolicy.CheckTransition(voteId.CountryId(), romance.ActiveUserVote.VoteType, voteType)
+			if err != nil {
+				return entity.Vote{}, 0, err
+			}
+
+			if voteType == romance.ActiveUserVote.VoteType {
+				return entity.Vote{}, 0, romanceDomain.ErrVoteDuplicate
+			}
 		}
 
 		currentTime := time.Now()
 		counterUpdateGroup, err := countersValueObject.NewCounterUpdateGroup(currentTime)
 		if err != nil {
-			return entity.Vote{}, err
+			return entity.Vote{}, 0, err
 		}
 
 		newVoteIsPositive := voteType.IsPositive()
@@ -69,11 +149,40 @@ func (r *AddUserVoteOperation) Run(
 		oldVoteIsNotPositive := !romance.ActiveUserVote.VoteType.IsPositive()
 		oldVoteIsNotNegative := !romance.ActiveUserVote.VoteType.IsNegative()
 
+		// only votes which increment an outgoing counter are limited
+		if limitWindows == nil && ((newVoteIsPositive && oldVoteIsNotPositive) || (newVoteIsNegative && oldVoteIsNotNegative)) {
+			limitWindows, err = r.reserveVoteLimits(ctx, voteId, voteType, voteContext, currentTime)
+			if err != nil {
+				return entity.Vote{}, 0, err
+			}
+		}
+
+		if consumption == nil {
+			consumption, err = r.consumeQuota.Run(ctx, voteId.ActiveUserKey(), voteType, currentTime)
+			if err != nil {
+				return entity.Vote{}, 0, err
+			}
+		}
+
+		// the text is moderated once across retries and only for a vote which passed the checks
+		if !complimentSubmitted {
+			compliment, err = r.submitCompliment.Run(ctx, voteId, voteType, complimentText, currentTime)
+			if err != nil {
+				return entity.Vote{}, 0, err
+			}
+			complimentSubmitted = true
+		}
+
+		previousVote := romance.ActiveUserVote
+		previousUnmatch := romance.Unmatch
+		wasMatched := romance.IsMatched()
 		romance, err = r.romancesRepository.AddActiveUserVoteToRomance(
 			ctx,
 			romance,
 			voteType,
 			votedAt,
+			voteContext,
+			compliment,
 		)
 
 		if err != nil {
@@ -82,17 +191,86 @@ func (r *AddUserVoteOperation) Run(
 				continue
 			}
 			r.logger.Error(fmt.Sprintf("AddActiveUserVoteToRomance error: %+v", err))
-			return entity.Vote{}, err
+			return entity.Vote{}, 0, err
 		}
 
-		if newVoteIsPositive && oldVoteIsNotPositive {
+		countedYes := newVoteIsPositive && oldVoteIsNotPositive
+		if countedYes {
 			r.countersRepository.IncrYesCounters(ctx, voteId, counterUpdateGroup)
 		}
 
-		if newVoteIsNegative && oldVoteIsNotNegative {
+		countedNo := newVoteIsNegative && oldVoteIsNotNegative
+		if countedNo {
 			r.countersRepository.IncrNoCounters(ctx, voteId, counterUpdateGroup)
 		}
 
-		return romance.ActiveUserVote, nil
+		if !wasMatched && romance.IsMatched() {
+			r.countersRepository.IncrMatchCounters(ctx, voteId, counterUpdateGroup)
+		}
+
+		r.recordLastVote.Run(ctx, voteId.ActiveUserKey(), rewindEntity.LastVote{
+			PeerId:          voteId.PeerUserId(),
+			PeerCountryId:   voteId.PeerCountryId(),
+			VoteType:        voteType,
+			VotedAt:         votedAt,
+			PreviousVote:    previousVote,
+			RecordedAt:      currentTime,
+			CountedYes:      countedYes,
+			CountedNo:       countedNo,
+			PreviousUnmatch: previousUnmatch,
+			Consumption:     consumption,
+		})
+		r.exclusionFiltersRepository.AddToExclusionFilter(ctx, voteId.ActiveUserKey(), voteId.PeerUserId())
+
+		return romance.ActiveUserVote, romance.Version, nil
+	}
+}
+
+// reserveVoteLimits counts the vote in its limit windows before it is written, the conditional write
+// makes concurrent votes of a user unable to pass a limit together
+func (r *AddUserVoteOperation) reserveVoteLimits(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	voteType romancesValueObject.VoteType,
+	voteContext *romancesValueObject.VoteContext,
+	now time.Time,
+) ([]counterDomain.VoteLimitWindow, error) {
+	if !r.votePolicy.IsEnabled() {
+		return nil, nil
+	}
+
+	windows := r.votePolicy.Windows(voteId.CountryId(), voteType, now)
+	if len(windows) == 0 {
+		return nil, nil
+	}
+	err := r.countersRepository.ReserveVoteLimits(ctx, voteId.ActiveUserKey(), windows)
+	var limitErr *counterDomain.VoteRateLimitError
+	if errors.As(err, &limitErr) {
+		m := message.NewVoteRateLimitedMessage(voteId, voteType, limitErr, now, voteContext)
+		m.SetTraceContext(messaging.TraceContextFromContext(ctx))
+		if publishErr := r.publisher.Publish(r.voteSignalsTopic, m); publishErr != nil {
+			r.logger.Error(fmt.Sprintf("Publish VoteRateLimited error: %+v", publishErr))
+		}
+		return nil, err
+	}
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("ReserveVoteLimits error: %+v", err))
+		return nil, err
+	}
+	return windows, nil
+}
+
+// releaseVoteLimits gives back the windows of a rejected vote, a failed release is only logged
+// as the vote error is what the caller has to report
+func (r *AddUserVoteOperation) releaseVoteLimits(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	windows []counterDomain.VoteLimitWindow,
+) {
+	if len(windows) == 0 {
+		return
+	}
+	if err := r.countersRepository.ReleaseVoteLimits(ctx, voteId.ActiveUserKey(), windows); err != nil {
+		r.logger.Error(fmt.Sprintf("ReleaseVoteLimits error: %+v", err))
 	}
 }
@@ -3,16 +3,26 @@ package operation
 import (
 	"context"
 	"errors"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
 	"io"
 	"log/slog"
 	"testing"
 	"time"
 
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/message"
+	counterDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	quotaEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	rewindDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
+	rewindEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
 	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
 	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
 	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	userDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user"
+	userValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -20,12 +30,26 @@ import (
 
 type AddUserVoteOperationUnitTestSuite struct {
 	suite.Suite
-	voteId       sharedValueObject.VoteId
-	ctrl         *gomock.Controller
-	romancesRepo *mocks.MockRomancesRepository
-	countersRepo *mocks.MockCountersRepository
-	logger       *slog.Logger
-	ctx          context.Context
+	voteId           sharedValueObject.VoteId
+	ctrl             *gomock.Controller
+	romancesRepo     *mocks.MockRomancesRepository
+	exclusionFilters *mocks.MockExclusionFiltersRepository
+	countersRepo     *mocks.MockCountersRepository
+	logger           *slog.Logger
+	transitionPolicy *romanceDomain.VoteTransitionPolicy
+	votedAtPolicy    *romanceDomain.VotedAtPolicy
+	eligibility      *romanceDomain.EligibilityPolicy
+	votePolicy       *counterDomain.VotePolicy
+	publisher        *mocks.MockPublisher
+	quotasRepo       *mocks.MockQuotasRepository
+	timeZones        *mocks.MockTimeZoneProvider
+	checkVoter       *CheckVoterOperation
+	quotaPolicy      *quotaDomain.QuotaPolicy
+	lastVotesRepo    *mocks.MockLastVotesRepository
+	recordLastVote   *RecordLastVoteOperation
+	moderation       *mocks.MockModerationProvider
+	cfg              config.Config
+	ctx              context.Context
 }
 
 func TestAddUserVoteOperationUnitSuite(t *testing.T) {
@@ -41,17 +65,63 @@ func (s *AddUserVoteOperationUnitTestSuite) SetupSuite() {
 	s.Require().NoError(err)
 	s.voteId = voteId
 	s.ctx = context.Background()
+	s.cfg = config.Config{Messaging: config.MessagingConfig{VoteSignalsTopic: "vote-signals.fifo"}}
 	s.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	transitionPolicy, err := romanceDomain.NewVoteTransitionPolicy(config.Config{})
+	s.Require().NoError(err)
+	s.transitionPolicy = transitionPolicy
+	s.votedAtPolicy = romanceDomain.NewVotedAtPolicy(config.Config{
+		Voting: config.VotingConfig{MaxClockSkew: time.Minute, StaleVoteWindow: 72 * time.Hour},
+	})
+	s.eligibility = romanceDomain.NewEligibilityPolicy(config.Config{
+		Voting: config.VotingConfig{NoVoteCoolingPeriod: 24 * time.Hour},
+	})
+	votePolicy, err := counterDomain.NewVotePolicy(config.Config{})
+	s.Require().NoError(err)
+	s.votePolicy = votePolicy
+	quotaPolicy, err := quotaDomain.NewQuotaPolicy(config.Config{})
+	s.Require().NoError(err)
+	s.quotaPolicy = quotaPolicy
 }
 
 func (s *AddUserVoteOperationUnitTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.romancesRepo = mocks.NewMockRomancesRepository(s.ctrl)
+	s.exclusionFilters = mocks.NewMockExclusionFiltersRepository(s.ctrl)
+	s.exclusionFilters.EXPECT().AddToExclusionFilter(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	s.countersRepo = mocks.NewMockCountersRepository(s.ctrl)
+	s.quotasRepo = mocks.NewMockQuotasRepository(s.ctrl)
+	s.timeZones = mocks.NewMockTimeZoneProvider(s.ctrl)
+	s.quotasRepo.EXPECT().GetOverrides(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	userStatusProvider := mocks.NewMockUserStatusProvider(s.ctrl)
+	userStatusProvider.EXPECT().GetStatus(gomock.Any(), gomock.Any()).Return(userValueObject.UserStatusActive, nil).AnyTimes()
+	entitlementProvider := mocks.NewMockEntitlementProvider(s.ctrl)
+	entitlementProvider.EXPECT().IsEntitled(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+	s.checkVoter = NewCheckVoterOperation(userStatusProvider, entitlementProvider)
+	s.lastVotesRepo = mocks.NewMockLastVotesRepository(s.ctrl)
+	s.recordLastVote = NewRecordLastVoteOperation(s.lastVotesRepo, rewindDomain.NewRewindPolicy(config.Config{}), s.logger)
+	s.publisher = mocks.NewMockPublisher(s.ctrl)
+	s.moderation = mocks.NewMockModerationProvider(s.ctrl)
 }
 
 func (s *AddUserVoteOperationUnitTestSuite) newOperation() *AddUserVoteOperation {
-	return NewAddUserVoteOperation(s.romancesRepo, s.countersRepo, s.logger)
+	return NewAddUserVoteOperation(
+		s.romancesRepo,
+		s.exclusionFilters,
+		s.countersRepo,
+		s.transitionPolicy,
+		s.votedAtPolicy,
+		s.eligibility,
+		s.votePolicy,
+		s.checkVoter,
+		NewConsumeVoteQuotaOperation(s.quotasRepo, s.quotaPolicy, s.timeZones, s.logger),
+		s.recordLastVote,
+		NewSubmitComplimentOperation(s.moderation, s.logger),
+		s.publisher,
+		s.cfg,
+		s.logger,
+	)
 }
 
 func (s *AddUserVoteOperationUnitTestSuite) TestGetRomanceReturnsError() {
@@ -62,7 +132,7 @@ func (s *AddUserVoteOperationUnitTestSuite) TestGetRomanceReturnsError() {
 		Return(romanceEntity.Romance{}, expectedErr)
 
 	operation := s.newOperation()
-	vote, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, time.Now())
+	vote, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, time.Now(), nil, "")
 
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, expectedErr)
@@ -79,11 +149,11 @@ func (s *AddUserVoteOperationUnitTestSuite) TestAddActiveUserVoteToRomanceReturn
 		Return(romance, nil)
 
 	s.romancesRepo.EXPECT().
-		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt).
+		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt, nil, nil).
 		Return(romanceEntity.Romance{}, expectedErr)
 
 	operation := s.newOperation()
-	vote, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt)
+	vote, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt, nil, "")
 
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, expectedErr)
@@ -101,7 +171,7 @@ func (s *AddUserVoteOperationUnitTestSuite) TestVersionConflictRetriesAndSucceed
 
 	// First AddActiveUserVoteToRomance fails with version conflict
 	s.romancesRepo.EXPECT().
-		AddActiveUserVoteToRomance(s.ctx, gomock.Any(), romancesValueObject.VoteTypeYes, gomock.Any()).
+		AddActiveUserVoteToRomance(s.ctx, gomock.Any(), romancesValueObject.VoteTypeYes, gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(romanceEntity.Romance{}, romanceDomain.ErrVersionConflict)
 
 	// Second call to GetRomance (retry)
@@ -114,7 +184,7 @@ func (s *AddUserVoteOperationUnitTestSuite) TestVersionConflictRetriesAndSucceed
 	updatedRomance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
 	updatedRomance.ActiveUserVote.VotedAt = &votedAt
 	s.romancesRepo.EXPECT().
-		AddActiveUserVoteToRomance(s.ctx, gomock.Any(), romancesValueObject.VoteTypeYes, gomock.Any()).
+		AddActiveUserVoteToRomance(s.ctx, gomock.Any(), romancesValueObject.VoteTypeYes, gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(updatedRomance, nil)
 
 	// Counter should be incremented (no return value)
@@ -122,13 +192,101 @@ func (s *AddUserVoteOperationUnitTestSuite) TestVersionConflictRetriesAndSucceed
 		IncrYesCounters(s.ctx, s.voteId, gomock.Any())
 
 	operation := s.newOperation()
-	vote, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt)
+	vote, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt, nil, "")
 
 	s.Require().NoError(err)
 	s.Require().Equal(romancesValueObject.VoteTypeYes, vote.VoteType)
 	s.Require().Equal(&votedAt, vote.VotedAt)
 }
 
+func (s *AddUserVoteOperationUnitTestSuite) TestVoteContextIsStoredWithTheVote() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	votedAt := time.Now()
+	voteContext := &romancesValueObject.VoteContext{Surface: romancesValueObject.VoteSurfaceDeck, RecommendationId: "rec-1"}
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	updatedRomance := romance
+	updatedRomance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	updatedRomance.ActiveUserVote.VotedAt = &votedAt
+	updatedRomance.ActiveUserVote.Context = voteContext
+	s.romancesRepo.EXPECT().
+		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt, voteContext, nil).
+		Return(updatedRomance, nil)
+	s.countersRepo.EXPECT().
+		IncrYesCounters(s.ctx, s.voteId, gomock.Any())
+
+	vote, _, err := s.newOperation().Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt, voteContext, "")
+
+	s.Require().NoError(err)
+	s.Require().Equal(voteContext, vote.Context)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestComplimentIsModeratedBeforeItIsStored() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	votedAt := time.Now()
+
+	s.moderation.EXPECT().
+		Moderate(s.ctx, s.voteId, "Lovely smile").
+		Return(romancesValueObject.ModerationStatusApproved, nil)
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.romancesRepo.EXPECT().
+		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeCompliment, votedAt, nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, romance romanceEntity.Romance, voteType romancesValueObject.VoteType, _ time.Time, _ *romancesValueObject.VoteContext, compliment *romancesValueObject.Compliment) (romanceEntity.Romance, error) {
+			s.Require().Equal("Lovely smile", compliment.Text)
+			s.Require().Equal(romancesValueObject.ModerationStatusApproved, compliment.Status)
+			s.Require().NotNil(compliment.ModeratedAt)
+			romance.ActiveUserVote.VoteType = voteType
+			romance.ActiveUserVote.Compliment = compliment
+			return romance, nil
+		})
+	s.countersRepo.EXPECT().
+		IncrYesCounters(s.ctx, s.voteId, gomock.Any())
+
+	vote, _, err := s.newOperation().Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCompliment, votedAt, nil, "Lovely smile")
+
+	s.Require().NoError(err)
+	s.Require().True(vote.Compliment.IsApproved())
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestComplimentStaysPendingWhenModerationFails() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	votedAt := time.Now()
+
+	s.moderation.EXPECT().
+		Moderate(s.ctx, s.voteId, "Lovely smile").
+		Return(romancesValueObject.ModerationStatusApproved, errors.New("moderation unavailable"))
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.romancesRepo.EXPECT().
+		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeCompliment, votedAt, nil, gomock.Any()).
+		DoAndReturn(func(_ context.Context, romance romanceEntity.Romance, voteType romancesValueObject.VoteType, _ time.Time, _ *romancesValueObject.VoteContext, compliment *romancesValueObject.Compliment) (romanceEntity.Romance, error) {
+			s.Require().Equal(romancesValueObject.ModerationStatusPending, compliment.Status)
+			s.Require().Nil(compliment.ModeratedAt)
+			romance.ActiveUserVote.VoteType = voteType
+			romance.ActiveUserVote.Compliment = compliment
+			return romance, nil
+		})
+	s.countersRepo.EXPECT().
+		IncrYesCounters(s.ctx, s.voteId, gomock.Any())
+
+	vote, _, err := s.newOperation().Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCompliment, votedAt, nil, "Lovely smile")
+
+	s.Require().NoError(err)
+	s.Require().False(vote.Compliment.IsApproved())
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestComplimentTextIsRejectedForOtherVoteTypes() {
+	_, _, err := s.newOperation().Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, time.Now(), nil, "Lovely smile")
+
+	s.Require().ErrorIs(err, romanceDomain.ErrComplimentNotAllowed)
+}
+
 func (s *AddUserVoteOperationUnitTestSuite) TestAddVoteSuccessfully() {
 	testCases := []struct {
 		name             string
@@ -176,7 +334,7 @@ func (s *AddUserVoteOperationUnitTestSuite) TestAddVoteSuccessfully() {
 			updatedRomance.ActiveUserVote.VoteType = tc.voteType
 			updatedRomance.ActiveUserVote.VotedAt = &votedAt
 			s.romancesRepo.EXPECT().
-				AddActiveUserVoteToRomance(s.ctx, romance, tc.voteType, votedAt).
+				AddActiveUserVoteToRomance(s.ctx, romance, tc.voteType, votedAt, nil, nil).
 				Return(updatedRomance, nil)
 
 			if tc.expectYesCounter {
@@ -189,7 +347,7 @@ func (s *AddUserVoteOperationUnitTestSuite) TestAddVoteSuccessfully() {
 			}
 
 			operation := s.newOperation()
-			vote, err := operation.Run(s.ctx, s.voteId, tc.voteType, votedAt)
+			vote, _, err := operation.Run(s.ctx, s.voteId, tc.voteType, votedAt, nil, "")
 
 			s.Require().NoError(err)
 			s.Require().Equal(tc.voteType, vote.VoteType)
@@ -197,3 +355,422 @@ func (s *AddUserVoteOperationUnitTestSuite) TestAddVoteSuccessfully() {
 		})
 	}
 }
+
+func (s *AddUserVoteOperationUnitTestSuite) TestOlderVoteIsRejectedAsStale() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	storedVotedAt := time.Now()
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeNo
+	romance.ActiveUserVote.VotedAt = &storedVotedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, storedVotedAt.Add(-time.Minute), nil, "")
+
+	s.Require().ErrorIs(err, romanceDomain.ErrStaleVote)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestVoteOnBlockedRomanceIsRejected() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	blockedAt := time.Now()
+	romance.PeerUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, time.Now(), nil, "")
+
+	s.Require().ErrorIs(err, romanceDomain.ErrRomanceBlocked)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestComplimentOnBlockedRomanceIsNotModerated() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	blockedAt := time.Now()
+	romance.PeerUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCompliment, time.Now(), nil, "Lovely smile")
+
+	s.Require().ErrorIs(err, romanceDomain.ErrRomanceBlocked)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestVoteOnUnmatchedRomanceIsRejectedDuringBan() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.Unmatch = &romanceEntity.Unmatch{
+		UnmatchedBy:       s.voteId.PeerUserId(),
+		UnmatchedAt:       time.Now().Add(-time.Hour),
+		RevoteBannedUntil: time.Now().Add(time.Hour),
+	}
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, time.Now(), nil, "")
+
+	s.Require().ErrorIs(err, romanceDomain.ErrUnmatched)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestVoteAfterBanDoesNotMatchAgain() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.Unmatch = &romanceEntity.Unmatch{
+		UnmatchedBy:       s.voteId.PeerUserId(),
+		UnmatchedAt:       time.Now().Add(-2 * time.Hour),
+		RevoteBannedUntil: time.Now().Add(-time.Hour),
+	}
+	romance.Version = 3
+	votedAt := time.Now()
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	updatedRomance := romance
+	updatedRomance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	updatedRomance.Unmatch = nil
+	updatedRomance.Version = 4
+	s.romancesRepo.EXPECT().
+		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt, nil, nil).
+		Return(updatedRomance, nil)
+	s.countersRepo.EXPECT().IncrYesCounters(s.ctx, s.voteId, gomock.Any())
+
+	_, _, err := s.newOperation().Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt, nil, "")
+
+	s.Require().NoError(err)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestExpiredNoIsRenewedWithoutCounting() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	storedVotedAt := time.Now().Add(-48 * time.Hour)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeNo
+	romance.ActiveUserVote.VotedAt = &storedVotedAt
+	romance.Version = 1
+	votedAt := time.Now()
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	updatedRomance := romance
+	updatedRomance.ActiveUserVote.VotedAt = &votedAt
+	updatedRomance.Version = 2
+	s.romancesRepo.EXPECT().
+		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeNo, votedAt, nil, nil).
+		Return(updatedRomance, nil)
+
+	vote, _, err := s.newOperation().Run(s.ctx, s.voteId, romancesValueObject.VoteTypeNo, votedAt, nil, "")
+
+	s.Require().NoError(err)
+	s.Require().Equal(&votedAt, vote.VotedAt)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestNoInCoolingPeriodIsNotRenewed() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	storedVotedAt := time.Now().Add(-time.Hour)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeNo
+	romance.ActiveUserVote.VotedAt = &storedVotedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	_, _, err := s.newOperation().Run(s.ctx, s.voteId, romancesValueObject.VoteTypeNo, time.Now(), nil, "")
+
+	s.Require().ErrorIs(err, romanceDomain.ErrWrongVote)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestVoteCreatingMatchIncrementsMatchCounters() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.PeerUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.Version = 1
+	votedAt := time.Now()
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	updatedRomance := romance
+	updatedRomance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	updatedRomance.Version = 2
+	s.romancesRepo.EXPECT().
+		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt, nil, nil).
+		Return(updatedRomance, nil)
+	s.countersRepo.EXPECT().IncrYesCounters(s.ctx, s.voteId, gomock.Any())
+	s.countersRepo.EXPECT().IncrMatchCounters(s.ctx, s.voteId, gomock.Any())
+
+	operation := s.newOperation()
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt, nil, "")
+
+	s.Require().NoError(err)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestAcceptedVoteIsRecordedForRewind() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	votedAt := time.Now()
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	updatedRomance := romance
+	updatedRomance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeNo
+	updatedRomance.ActiveUserVote.VotedAt = &votedAt
+	updatedRomance.Version = 1
+	s.romancesRepo.EXPECT().
+		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeNo, votedAt, nil, nil).
+		Return(updatedRomance, nil)
+	s.countersRepo.EXPECT().IncrNoCounters(s.ctx, s.voteId, gomock.Any())
+	s.lastVotesRepo.EXPECT().
+		GetLastVotes(s.ctx, s.voteId.ActiveUserKey()).
+		Return(rewindEntity.LastVotes{ActiveUserKey: s.voteId.ActiveUserKey()}, nil)
+	s.lastVotesRepo.EXPECT().
+		SaveLastVotes(s.ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, lastVotes rewindEntity.LastVotes, expiresAt time.Time) error {
+			s.Require().Len(lastVotes.Votes, 1)
+			vote := lastVotes.Votes[0]
+			s.Require().Equal(s.voteId.PeerUserId(), vote.PeerId)
+			s.Require().Equal(romancesValueObject.VoteTypeNo, vote.VoteType)
+			s.Require().Equal(romance.ActiveUserVote, vote.PreviousVote)
+			s.Require().True(vote.CountedNo)
+			s.Require().False(vote.CountedYes)
+			s.Require().Equal(vote.RecordedAt.Add(5*time.Minute), expiresAt)
+			return nil
+		})
+
+	rewindPolicy := rewindDomain.NewRewindPolicy(config.Config{
+		Voting: config.VotingConfig{RewindWindow: 5 * time.Minute, RewindHistorySize: 5},
+	})
+	s.recordLastVote = NewRecordLastVoteOperation(s.lastVotesRepo, rewindPolicy, s.logger)
+
+	operation := s.newOperation()
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeNo, votedAt, nil, "")
+
+	s.Require().NoError(err)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestVotedAtOutsideClockSkewIsRejected() {
+	operation := s.newOperation()
+
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, time.Now().Add(time.Hour), nil, "")
+	s.Require().ErrorIs(err, romanceDomain.ErrVotedAtInFuture)
+
+	_, _, err = operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, time.Now().Add(-100*time.Hour), nil, "")
+	s.Require().ErrorIs(err, romanceDomain.ErrVotedAtTooOld)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestVoteOverHourlyLimitIsRejectedWithSignal() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	now := time.Now().UTC()
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.countersRepo.EXPECT().
+		ReserveVoteLimits(s.ctx, s.voteId.ActiveUserKey(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ sharedValueObject.ActiveUserKey, windows []counterDomain.VoteLimitWindow) error {
+			s.Require().Len(windows, 1)
+			return windows[0].Exceeded()
+		})
+	voteContext := &romancesValueObject.VoteContext{
+		Surface:          romancesValueObject.VoteSurfaceDeck,
+		RecommendationId: "rec-42",
+	}
+	s.publisher.EXPECT().
+		Publish(messaging.Topic("vote-signals.fifo"), gomock.Any()).
+		DoAndReturn(func(_ messaging.Topic, m messaging.Message) error {
+			limited := m.(*message.VoteRateLimitedMessage)
+			s.Require().Equal(&message.VoteContext{Surface: "deck", RecommendationId: "rec-42"}, limited.VoteContext)
+			return nil
+		})
+
+	operation := s.newLimitedOperation(map[string]uint32{"yes": 2})
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, now, voteContext, "")
+
+	var limitErr *counterDomain.VoteRateLimitError
+	s.Require().ErrorAs(err, &limitErr)
+	s.Require().Equal(counterDomain.LimitWindowHour, limitErr.Window)
+	s.Require().Equal(now.Truncate(time.Hour).Add(time.Hour), limitErr.ResetAt)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestVoteUnderLimitIsAdded() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	votedAt := time.Now()
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.countersRepo.EXPECT().
+		ReserveVoteLimits(s.ctx, s.voteId.ActiveUserKey(), gomock.Len(1)).
+		Return(nil)
+
+	updatedRomance := romance
+	updatedRomance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeNo
+	s.romancesRepo.EXPECT().
+		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeNo, votedAt, nil, nil).
+		Return(updatedRomance, nil)
+	s.countersRepo.EXPECT().
+		IncrNoCounters(s.ctx, s.voteId, gomock.Any())
+
+	operation := s.newLimitedOperation(map[string]uint32{"no": 2})
+	vote, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeNo, votedAt, nil, "")
+
+	s.Require().NoError(err)
+	s.Require().Equal(romancesValueObject.VoteTypeNo, vote.VoteType)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestVoteLimitIsReleasedWhenVoteIsRejected() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	expectedErr := errors.New("database error")
+	votedAt := time.Now()
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.countersRepo.EXPECT().
+		ReserveVoteLimits(s.ctx, s.voteId.ActiveUserKey(), gomock.Len(1)).
+		Return(nil)
+	s.romancesRepo.EXPECT().
+		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt, nil, nil).
+		Return(romanceEntity.Romance{}, expectedErr)
+	s.countersRepo.EXPECT().
+		ReleaseVoteLimits(s.ctx, s.voteId.ActiveUserKey(), gomock.Len(1)).
+		Return(nil)
+
+	operation := s.newLimitedOperation(map[string]uint32{"yes": 2})
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt, nil, "")
+
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) newLimitedOperation(hourlyLimits map[string]uint32) *AddUserVoteOperation {
+	votePolicy, err := counterDomain.NewVotePolicy(config.Config{
+		Voting: config.VotingConfig{HourlyLimits: hourlyLimits},
+	})
+	s.Require().NoError(err)
+
+	return NewAddUserVoteOperation(
+		s.romancesRepo,
+		s.exclusionFilters,
+		s.countersRepo,
+		s.transitionPolicy,
+		s.votedAtPolicy,
+		s.eligibility,
+		votePolicy,
+		s.checkVoter,
+		NewConsumeVoteQuotaOperation(s.quotasRepo, s.quotaPolicy, s.timeZones, s.logger),
+		s.recordLastVote,
+		NewSubmitComplimentOperation(s.moderation, s.logger),
+		s.publisher,
+		s.cfg,
+		s.logger,
+	)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestExhaustedQuotaRejectsVote() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	location, err := time.LoadLocation("Asia/Kolkata")
+	s.Require().NoError(err)
+	now := time.Now()
+	day, resetAt := quotaDomain.Day(now, location)
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.timeZones.EXPECT().
+		GetTimeZone(s.ctx, s.voteId.ActiveUserKey()).
+		Return(location, nil)
+	s.quotasRepo.EXPECT().
+		Consume(s.ctx, gomock.Any(), uint32(1), resetAt).
+		DoAndReturn(func(_ context.Context, consumption quotaEntity.Consumption, _ uint32, _ time.Time) error {
+			s.Require().Equal(day, consumption.Day)
+			return quotaDomain.ErrQuotaExhausted
+		})
+
+	operation := s.newQuotaOperation(map[string]uint32{"crush": 1})
+	_, _, err = operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, now, nil, "")
+
+	var quotaErr *quotaDomain.QuotaExhaustedError
+	s.Require().ErrorAs(err, &quotaErr)
+	s.Require().Equal(uint32(1), quotaErr.Limit)
+	s.Require().Equal(resetAt, quotaErr.ResetAt)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestQuotaIsRefundedWhenVoteIsRejected() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	expectedErr := errors.New("database error")
+	votedAt := time.Now()
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.timeZones.EXPECT().
+		GetTimeZone(s.ctx, s.voteId.ActiveUserKey()).
+		Return(time.UTC, nil)
+	s.quotasRepo.EXPECT().
+		Consume(s.ctx, gomock.Any(), uint32(3), gomock.Any()).
+		Return(nil)
+	s.romancesRepo.EXPECT().
+		AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeCompliment, votedAt, nil, nil).
+		Return(romanceEntity.Romance{}, expectedErr)
+	s.quotasRepo.EXPECT().
+		Refund(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, consumption quotaEntity.Consumption) error {
+			s.Require().Equal(romancesValueObject.VoteTypeCompliment, consumption.VoteType)
+			s.Require().Equal(s.voteId.ActiveUserKey(), consumption.ActiveUserKey)
+			return nil
+		})
+
+	operation := s.newQuotaOperation(map[string]uint32{"compliment": 3})
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCompliment, votedAt, nil, "")
+
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) TestQuotaIsNotConsumedWithoutTheUserTimeZone() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.timeZones.EXPECT().
+		GetTimeZone(s.ctx, s.voteId.ActiveUserKey()).
+		Return(nil, userDomain.ErrUserCheckUnavailable)
+
+	operation := s.newQuotaOperation(map[string]uint32{"crush": 1})
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, time.Now(), nil, "")
+
+	s.Require().ErrorIs(err, userDomain.ErrUserCheckUnavailable)
+}
+
+func (s *AddUserVoteOperationUnitTestSuite) newQuotaOperation(dailyQuotas map[string]uint32) *AddUserVoteOperation {
+	quotaPolicy, err := quotaDomain.NewQuotaPolicy(config.Config{
+		Voting: config.VotingConfig{DailyQuotas: dailyQuotas},
+	})
+	s.Require().NoError(err)
+
+	return NewAddUserVoteOperation(
+		s.romancesRepo,
+		s.exclusionFilters,
+		s.countersRepo,
+		s.transitionPolicy,
+		s.votedAtPolicy,
+		s.eligibility,
+		s.votePolicy,
+		s.checkVoter,
+		NewConsumeVoteQuotaOperation(s.quotasRepo, quotaPolicy, s.timeZones, s.logger),
+		s.recordLastVote,
+		NewSubmitComplimentOperation(s.moderation, s.logger),
+		s.publisher,
+		s.cfg,
+		s.logger,
+	)
+}
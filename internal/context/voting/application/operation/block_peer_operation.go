@@ -0,0 +1,64 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"time"
+)
+
+type BlockPeerOperation struct {
+	romancesRepository         romancesRepo.RomancesRepository
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository
+	logger                     platform.Logger
+}
+
+func NewBlockPeerOperation(
+	romancesRepository romancesRepo.RomancesRepository,
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository,
+	logger platform.Logger,
+) *BlockPeerOperation {
+	return &BlockPeerOperation{
+		romancesRepository:         romancesRepository,
+		exclusionFiltersRepository: exclusionFiltersRepository,
+		logger:                     logger,
+	}
+}
+
+// Run blocks the peer for the active user, blocking an already blocked peer keeps the first block time
+func (r *BlockPeerOperation) Run(ctx context.Context, voteId sharedValueObject.VoteId, blockedAt time.Time) error {
+	tries := 0
+
+	getRomanceOperation := NewGetRomanceOperation(r.romancesRepository)
+	for {
+		romance, err := getRomanceOperation.Run(ctx, voteId)
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("GetRomance error: %+v", err))
+			return err
+		}
+
+		if romance.ActiveUserBlockedAt != nil {
+			return nil
+		}
+
+		_, err = r.romancesRepository.BlockPeerInRomance(ctx, romance, blockedAt)
+		if err != nil {
+			if errors.Is(err, romanceDomain.ErrVersionConflict) && tries < config.DynamoDbVersionConflictRetriesCount {
+				tries += 1
+				continue
+			}
+			r.logger.Error(fmt.Sprintf("BlockPeerInRomance error: %+v", err))
+			return err
+		}
+
+		// a block excludes both users from the recommendations of each other
+		r.exclusionFiltersRepository.AddToExclusionFilter(ctx, voteId.ActiveUserKey(), voteId.PeerUserId())
+		r.exclusionFiltersRepository.AddToExclusionFilter(ctx, voteId.PeerUserKey(), voteId.ActiveUserId())
+		return nil
+	}
+}
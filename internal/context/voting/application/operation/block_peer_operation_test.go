@@ -0,0 +1,114 @@
+package operation
+
+import (
+	"context"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type BlockPeerOperationUnitTestSuite struct {
+	suite.Suite
+	voteId           sharedValueObject.VoteId
+	ctrl             *gomock.Controller
+	romancesRepo     *mocks.MockRomancesRepository
+	exclusionFilters *mocks.MockExclusionFiltersRepository
+	logger           *slog.Logger
+	ctx              context.Context
+}
+
+func TestBlockPeerOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(BlockPeerOperationUnitTestSuite))
+}
+
+func (s *BlockPeerOperationUnitTestSuite) SetupSuite() {
+	voteId, err := sharedValueObject.NewVoteId(11, uuidhelper.NewUUID(s.T()), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.voteId = voteId
+	s.ctx = context.Background()
+	s.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func (s *BlockPeerOperationUnitTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.romancesRepo = mocks.NewMockRomancesRepository(s.ctrl)
+	s.exclusionFilters = mocks.NewMockExclusionFiltersRepository(s.ctrl)
+}
+
+func (s *BlockPeerOperationUnitTestSuite) TestBlockRetriesVersionConflict() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	blockedAt := time.Now()
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil).
+		Times(2)
+	s.romancesRepo.EXPECT().
+		BlockPeerInRomance(s.ctx, romance, blockedAt).
+		Return(romanceEntity.Romance{}, romanceDomain.ErrVersionConflict)
+	s.romancesRepo.EXPECT().
+		BlockPeerInRomance(s.ctx, romance, blockedAt).
+		Return(romance, nil)
+	s.exclusionFilters.EXPECT().AddToExclusionFilter(s.ctx, s.voteId.ActiveUserKey(), s.voteId.PeerUserId())
+	s.exclusionFilters.EXPECT().AddToExclusionFilter(s.ctx, s.voteId.PeerUserKey(), s.voteId.ActiveUserId())
+
+	err := NewBlockPeerOperation(s.romancesRepo, s.exclusionFilters, s.logger).Run(s.ctx, s.voteId, blockedAt)
+
+	s.Require().NoError(err)
+}
+
+func (s *BlockPeerOperationUnitTestSuite) TestBlockOfBlockedPeerKeepsFirstBlock() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	blockedAt := time.Now().Add(-time.Hour)
+	romance.ActiveUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	err := NewBlockPeerOperation(s.romancesRepo, s.exclusionFilters, s.logger).Run(s.ctx, s.voteId, time.Now())
+
+	s.Require().NoError(err)
+}
+
+func (s *BlockPeerOperationUnitTestSuite) TestUnblockKeepsBlockOfPeer() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	blockedAt := time.Now()
+	romance.PeerUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	err := NewUnblockPeerOperation(s.romancesRepo, s.exclusionFilters, s.logger).Run(s.ctx, s.voteId)
+
+	s.Require().NoError(err)
+}
+
+func (s *BlockPeerOperationUnitTestSuite) TestUnblockRemovesBlock() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	blockedAt := time.Now()
+	romance.ActiveUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.romancesRepo.EXPECT().
+		UnblockPeerInRomance(s.ctx, romance).
+		Return(romanceEntity.CreateEmptyRomance(s.voteId), nil)
+	s.exclusionFilters.EXPECT().DeleteExclusionFilter(s.ctx, s.voteId.ActiveUserKey())
+	s.exclusionFilters.EXPECT().DeleteExclusionFilter(s.ctx, s.voteId.PeerUserKey())
+
+	err := NewUnblockPeerOperation(s.romancesRepo, s.exclusionFilters, s.logger).Run(s.ctx, s.voteId)
+
+	s.Require().NoError(err)
+}
@@ -6,49 +6,55 @@ import (
 	"fmt"
 	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	countersRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/repository"
+	countersValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
+	quotaEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	rewindEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
 	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
 	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
 	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"slices"
+	"time"
 )
 
-var allowedVoteTransitions = map[romancesValueObject.VoteType]map[romancesValueObject.VoteType]struct{}{
-	romancesValueObject.VoteTypeEmpty: {
-		romancesValueObject.VoteTypeNo:         {},
-		romancesValueObject.VoteTypeYes:        {},
-		romancesValueObject.VoteTypeCrush:      {},
-		romancesValueObject.VoteTypeCompliment: {},
-	},
-	romancesValueObject.VoteTypeNo: {
-		romancesValueObject.VoteTypeYes:        {},
-		romancesValueObject.VoteTypeCrush:      {},
-		romancesValueObject.VoteTypeCompliment: {},
-	},
-	romancesValueObject.VoteTypeYes: {
-		romancesValueObject.VoteTypeCrush:      {},
-		romancesValueObject.VoteTypeCompliment: {},
-	},
-	romancesValueObject.VoteTypeCrush:      {},
-	romancesValueObject.VoteTypeCompliment: {},
-}
-
 type ChangeUserVoteOperation struct {
-	romancesRepository romancesRepo.RomancesRepository
-	countersRepository countersRepo.CountersRepository
-	logger             platform.Logger
+	romancesRepository         romancesRepo.RomancesRepository
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository
+	countersRepository         countersRepo.CountersRepository
+	transitionPolicy           *romanceDomain.VoteTransitionPolicy
+	votedAtPolicy              *romanceDomain.VotedAtPolicy
+	checkVoter                 *CheckVoterOperation
+	consumeQuota               *ConsumeVoteQuotaOperation
+	recordLastVote             *RecordLastVoteOperation
+	submitCompliment           *SubmitComplimentOperation
+	logger                     platform.Logger
 }
 
 func NewChangeUserVoteOperation(
 	romancesRepository romancesRepo.RomancesRepository,
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository,
 	countersRepository countersRepo.CountersRepository,
+	transitionPolicy *romanceDomain.VoteTransitionPolicy,
+	votedAtPolicy *romanceDomain.VotedAtPolicy,
+	checkVoter *CheckVoterOperation,
+	consumeQuota *ConsumeVoteQuotaOperation,
+	recordLastVote *RecordLastVoteOperation,
+	submitCompliment *SubmitComplimentOperation,
 	logger platform.Logger,
 ) *ChangeUserVoteOperation {
 	return &ChangeUserVoteOperation{
-		romancesRepository: romancesRepository,
-		countersRepository: countersRepository,
-		logger:             logger,
+		romancesRepository:         romancesRepository,
+		exclusionFiltersRepository: exclusionFiltersRepository,
+		countersRepository:         countersRepository,
+		transitionPolicy:           transitionPolicy,
+		votedAtPolicy:              votedAtPolicy,
+		checkVoter:                 checkVoter,
+		consumeQuota:               consumeQuota,
+		recordLastVote:             recordLastVote,
+		submitCompliment:           submitCompliment,
+		logger:                     logger,
 	}
 }
 
@@ -56,54 +62,159 @@ func (r *ChangeUserVoteOperation) Run(
 	ctx context.Context,
 	voteId sharedValueObject.VoteId,
 	newVoteType romancesValueObject.VoteType,
-) (entity.Vote, error) {
+	votedAt time.Time,
+	voteContext *romancesValueObject.VoteContext,
+	complimentText string,
+) (entity.Vote, uint32, error) {
+	return r.run(ctx, voteId, newVoteType, votedAt, voteContext, complimentText, nil)
+}
+
+// RunIfVersion changes the vote only while the romance is still at one of acceptedVersions,
+// a concurrent update is reported as ErrVersionMismatch instead of being retried.
+func (r *ChangeUserVoteOperation) RunIfVersion(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	newVoteType romancesValueObject.VoteType,
+	votedAt time.Time,
+	voteContext *romancesValueObject.VoteContext,
+	complimentText string,
+	acceptedVersions []uint32,
+) (entity.Vote, uint32, error) {
+	if acceptedVersions == nil {
+		acceptedVersions = []uint32{}
+	}
+	return r.run(ctx, voteId, newVoteType, votedAt, voteContext, complimentText, acceptedVersions)
+}
+
+func (r *ChangeUserVoteOperation) run(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	newVoteType romancesValueObject.VoteType,
+	votedAt time.Time,
+	voteContext *romancesValueObject.VoteContext,
+	complimentText string,
+	acceptedVersions []uint32,
+) (_ entity.Vote, _ uint32, err error) {
+	if err = r.votedAtPolicy.Check(votedAt, time.Now()); err != nil {
+		return entity.Vote{}, 0, err
+	}
+	if err = r.checkVoter.Run(ctx, voteId.ActiveUserKey(), newVoteType); err != nil {
+		return entity.Vote{}, 0, err
+	}
+	if err = r.submitCompliment.Check(newVoteType, complimentText); err != nil {
+		return entity.Vote{}, 0, err
+	}
+
 	tries := 0
 
+	// the quota is consumed once across retries and given back when the change is rejected
+	var consumption *quotaEntity.Consumption
+	defer func() {
+		if err != nil {
+			r.consumeQuota.Refund(ctx, consumption)
+		}
+	}()
+
+	var compliment *romancesValueObject.Compliment
+	complimentSubmitted := false
+
 	getRomanceOperation := NewGetRomanceOperation(r.romancesRepository)
 	for {
 		romance, err := getRomanceOperation.Run(ctx, voteId)
 		if err != nil {
 			r.logger.Error(fmt.Sprintf("GetRomance error: %+v", err))
-			return entity.Vote{}, err
+			return entity.Vote{}, 0, err
+		}
+
+		if romance.IsBlocked() {
+			return entity.Vote{}, 0, romanceDomain.ErrRomanceBlocked
 		}
 
-		if err := isVoteTypeChangeAllowed(romance.ActiveUserVote, newVoteType); err != nil {
-			return entity.Vote{}, err
+		if romance.IsRevoteBanned(time.Now()) {
+			return entity.Vote{}, 0, romanceDomain.ErrUnmatched
+		}
+
+		if acceptedVersions != nil && !slices.Contains(acceptedVersions, romance.Version) {
+			return entity.Vote{}, 0, romanceDomain.ErrVersionMismatch
+		}
+
+		if romance.ActiveUserVote.IsNewerThan(votedAt) {
+			return entity.Vote{}, 0, romanceDomain.ErrStaleVote
+		}
+
+		err = r.transitionPolicy.CheckTransition(voteId.CountryId(), romance.ActiveUserVote.VoteType, newVoteType)
+		if err != nil {
+			return entity.Vote{}, 0, err
 		}
 
 		if newVoteType == romance.ActiveUserVote.VoteType {
-			return entity.Vote{}, romanceDomain.ErrVoteDuplicate
+			return entity.Vote{}, 0, romanceDomain.ErrVoteDuplicate
+		}
+
+		if consumption == nil {
+			consumption, err = r.consumeQuota.Run(ctx, voteId.ActiveUserKey(), newVoteType, time.Now())
+			if err != nil {
+				return entity.Vote{}, 0, err
+			}
+		}
+
+		currentTime := time.Now()
+		counterUpdateGroup, err := countersValueObject.NewCounterUpdateGroup(currentTime)
+		if err != nil {
+			return entity.Vote{}, 0, err
+		}
+
+		// the text is moderated once across retries and only for a vote which passed the checks
+		if !complimentSubmitted {
+			compliment, err = r.submitCompliment.Run(ctx, voteId, newVoteType, complimentText, currentTime)
+			if err != nil {
+				return entity.Vote{}, 0, err
+			}
+			complimentSubmitted = true
 		}
 
+		previousVote := romance.ActiveUserVote
+		previousUnmatch := romance.Unmatch
+		wasMatched := romance.IsMatched()
 		romance, err = r.romancesRepository.ChangeActiveUserVoteTypeInRomance(
 			ctx,
 			romance,
 			newVoteType,
+			votedAt,
+			voteContext,
+			compliment,
 		)
 
 		if err != nil {
+			if errors.Is(err, romanceDomain.ErrVersionConflict) && acceptedVersions != nil {
+				return entity.Vote{}, 0, romanceDomain.ErrVersionMismatch
+			}
 			if errors.Is(err, romanceDomain.ErrVersionConflict) && tries < config.DynamoDbVersionConflictRetriesCount {
 				tries += 1
 				continue
 			}
 			r.logger.Error(fmt.Sprintf("ChangeActiveUserVoteTypeInRomance error: %+v", err))
-			return entity.Vote{}, err
+			return entity.Vote{}, 0, err
 		}
 
-		return romance.ActiveUserVote, nil
-	}
-}
+		if !wasMatched && romance.IsMatched() {
+			r.countersRepository.IncrMatchCounters(ctx, voteId, counterUpdateGroup)
+		}
 
-func isVoteTypeChangeAllowed(oldVote entity.Vote, newVoteType romancesValueObject.VoteType) error {
-	allowedTargets, ok := allowedVoteTransitions[oldVote.VoteType]
-	if !ok {
-		return romanceDomain.NewChangingVoteTypeError(oldVote.VoteType, newVoteType)
-	}
+		// a change does not count the vote again, so there are no counters to reverse on rewind
+		r.recordLastVote.Run(ctx, voteId.ActiveUserKey(), rewindEntity.LastVote{
+			PeerId:          voteId.PeerUserId(),
+			PeerCountryId:   voteId.PeerCountryId(),
+			VoteType:        newVoteType,
+			VotedAt:         votedAt,
+			PreviousVote:    previousVote,
+			RecordedAt:      currentTime,
+			PreviousUnmatch: previousUnmatch,
+			Consumption:     consumption,
+		})
 
-	_, allowed := allowedTargets[newVoteType]
-	if !allowed {
-		return romanceDomain.NewChangingVoteTypeError(oldVote.VoteType, newVoteType)
+		// a vote changed from an expired no excludes the peer again
+		r.exclusionFiltersRepository.AddToExclusionFilter(ctx, voteId.ActiveUserKey(), voteId.PeerUserId())
+		return romance.ActiveUserVote, romance.Version, nil
 	}
-
-	return nil
 }
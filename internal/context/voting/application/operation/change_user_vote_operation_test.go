@@ -3,16 +3,20 @@ package operation
 import (
 	"context"
 	"errors"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
 	"io"
 	"log/slog"
 	"testing"
 	"time"
 
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	rewindDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
 	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
 	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
 	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	userValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/valueobject"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -20,12 +24,21 @@ import (
 
 type ChangeUserVoteOperationUnitTestSuite struct {
 	suite.Suite
-	voteId       sharedValueObject.VoteId
-	ctrl         *gomock.Controller
-	romancesRepo *mocks.MockRomancesRepository
-	countersRepo *mocks.MockCountersRepository
-	logger       *slog.Logger
-	ctx          context.Context
+	voteId           sharedValueObject.VoteId
+	ctrl             *gomock.Controller
+	romancesRepo     *mocks.MockRomancesRepository
+	exclusionFilters *mocks.MockExclusionFiltersRepository
+	countersRepo     *mocks.MockCountersRepository
+	logger           *slog.Logger
+	transitionPolicy *romanceDomain.VoteTransitionPolicy
+	votedAtPolicy    *romanceDomain.VotedAtPolicy
+	quotasRepo       *mocks.MockQuotasRepository
+	checkVoter       *CheckVoterOperation
+	quotaPolicy      *quotaDomain.QuotaPolicy
+	lastVotesRepo    *mocks.MockLastVotesRepository
+	recordLastVote   *RecordLastVoteOperation
+	moderation       *mocks.MockModerationProvider
+	ctx              context.Context
 }
 
 func TestChangeUserVoteOperationUnitSuite(t *testing.T) {
@@ -42,16 +55,49 @@ func (s *ChangeUserVoteOperationUnitTestSuite) SetupSuite() {
 	s.voteId = voteId
 	s.ctx = context.Background()
 	s.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	transitionPolicy, err := romanceDomain.NewVoteTransitionPolicy(config.Config{})
+	s.Require().NoError(err)
+	s.transitionPolicy = transitionPolicy
+	s.votedAtPolicy = romanceDomain.NewVotedAtPolicy(config.Config{
+		Voting: config.VotingConfig{MaxClockSkew: time.Minute, StaleVoteWindow: 72 * time.Hour},
+	})
+	quotaPolicy, err := quotaDomain.NewQuotaPolicy(config.Config{})
+	s.Require().NoError(err)
+	s.quotaPolicy = quotaPolicy
 }
 
 func (s *ChangeUserVoteOperationUnitTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.romancesRepo = mocks.NewMockRomancesRepository(s.ctrl)
+	s.exclusionFilters = mocks.NewMockExclusionFiltersRepository(s.ctrl)
+	s.exclusionFilters.EXPECT().AddToExclusionFilter(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	s.countersRepo = mocks.NewMockCountersRepository(s.ctrl)
+	s.quotasRepo = mocks.NewMockQuotasRepository(s.ctrl)
+	s.quotasRepo.EXPECT().GetOverrides(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	userStatusProvider := mocks.NewMockUserStatusProvider(s.ctrl)
+	userStatusProvider.EXPECT().GetStatus(gomock.Any(), gomock.Any()).Return(userValueObject.UserStatusActive, nil).AnyTimes()
+	entitlementProvider := mocks.NewMockEntitlementProvider(s.ctrl)
+	entitlementProvider.EXPECT().IsEntitled(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+	s.checkVoter = NewCheckVoterOperation(userStatusProvider, entitlementProvider)
+	s.lastVotesRepo = mocks.NewMockLastVotesRepository(s.ctrl)
+	s.recordLastVote = NewRecordLastVoteOperation(s.lastVotesRepo, rewindDomain.NewRewindPolicy(config.Config{}), s.logger)
+	s.moderation = mocks.NewMockModerationProvider(s.ctrl)
 }
 
 func (s *ChangeUserVoteOperationUnitTestSuite) newOperation() *ChangeUserVoteOperation {
-	return NewChangeUserVoteOperation(s.romancesRepo, s.countersRepo, s.logger)
+	return NewChangeUserVoteOperation(
+		s.romancesRepo,
+		s.exclusionFilters,
+		s.countersRepo,
+		s.transitionPolicy,
+		s.votedAtPolicy,
+		s.checkVoter,
+		NewConsumeVoteQuotaOperation(s.quotasRepo, s.quotaPolicy, mocks.NewMockTimeZoneProvider(s.ctrl), s.logger),
+		s.recordLastVote,
+		NewSubmitComplimentOperation(s.moderation, s.logger),
+		s.logger,
+	)
 }
 
 func (s *ChangeUserVoteOperationUnitTestSuite) TestGetRomanceReturnsError() {
@@ -62,7 +108,7 @@ func (s *ChangeUserVoteOperationUnitTestSuite) TestGetRomanceReturnsError() {
 		Return(romanceEntity.Romance{}, expectedErr)
 
 	operation := s.newOperation()
-	vote, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush)
+	vote, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, time.Now(), nil, "")
 
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, expectedErr)
@@ -84,11 +130,11 @@ func (s *ChangeUserVoteOperationUnitTestSuite) TestChangeActiveUserVoteTypeInRom
 		Return(romance, nil)
 
 	s.romancesRepo.EXPECT().
-		ChangeActiveUserVoteTypeInRomance(s.ctx, romance, romancesValueObject.VoteTypeCrush).
+		ChangeActiveUserVoteTypeInRomance(s.ctx, romance, romancesValueObject.VoteTypeCrush, gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(romanceEntity.Romance{}, expectedErr)
 
 	operation := s.newOperation()
-	vote, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush)
+	vote, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, time.Now(), nil, "")
 
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, expectedErr)
@@ -110,7 +156,7 @@ func (s *ChangeUserVoteOperationUnitTestSuite) TestVersionConflictRetriesAndSucc
 
 	// First ChangeActiveUserVoteTypeInRomance fails with version conflict
 	s.romancesRepo.EXPECT().
-		ChangeActiveUserVoteTypeInRomance(s.ctx, gomock.Any(), romancesValueObject.VoteTypeCrush).
+		ChangeActiveUserVoteTypeInRomance(s.ctx, gomock.Any(), romancesValueObject.VoteTypeCrush, gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(romanceEntity.Romance{}, romanceDomain.ErrVersionConflict)
 
 	// Second call to GetRomance (retry)
@@ -122,11 +168,11 @@ func (s *ChangeUserVoteOperationUnitTestSuite) TestVersionConflictRetriesAndSucc
 	updatedRomance := romance
 	updatedRomance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeCrush
 	s.romancesRepo.EXPECT().
-		ChangeActiveUserVoteTypeInRomance(s.ctx, gomock.Any(), romancesValueObject.VoteTypeCrush).
+		ChangeActiveUserVoteTypeInRomance(s.ctx, gomock.Any(), romancesValueObject.VoteTypeCrush, gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(updatedRomance, nil)
 
 	operation := s.newOperation()
-	vote, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush)
+	vote, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, time.Now(), nil, "")
 
 	s.Require().NoError(err)
 	s.Require().Equal(romancesValueObject.VoteTypeCrush, vote.VoteType)
@@ -146,7 +192,7 @@ func (s *ChangeUserVoteOperationUnitTestSuite) TestInvalidVoteTypeTransition() {
 		Return(romance, nil)
 
 	operation := s.newOperation()
-	vote, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes)
+	vote, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, time.Now(), nil, "")
 
 	s.Require().Error(err)
 	s.Require().Contains(err.Error(), "wrong vote")
@@ -190,11 +236,11 @@ func (s *ChangeUserVoteOperationUnitTestSuite) TestChangeVoteSuccessfully() {
 			updatedRomance := romance
 			updatedRomance.ActiveUserVote.VoteType = tc.toType
 			s.romancesRepo.EXPECT().
-				ChangeActiveUserVoteTypeInRomance(s.ctx, romance, tc.toType).
+				ChangeActiveUserVoteTypeInRomance(s.ctx, romance, tc.toType, gomock.Any(), gomock.Any(), gomock.Any()).
 				Return(updatedRomance, nil)
 
 			operation := s.newOperation()
-			vote, err := operation.Run(s.ctx, s.voteId, tc.toType)
+			vote, _, err := operation.Run(s.ctx, s.voteId, tc.toType, time.Now(), nil, "")
 
 			s.Require().NoError(err)
 			s.Require().Equal(tc.toType, vote.VoteType)
@@ -239,8 +285,7 @@ func (s *ChangeUserVoteOperationUnitTestSuite) TestIsVoteTypeChangeAllowedExhaus
 			testName := fromType.String() + "_to_" + toType.String()
 
 			s.Run(testName, func() {
-				vote := romanceEntity.Vote{VoteType: fromType}
-				err := isVoteTypeChangeAllowed(vote, toType)
+				err := s.transitionPolicy.CheckTransition(s.voteId.CountryId(), fromType, toType)
 
 				// Check if this transition should be valid
 				expectedValid := false
@@ -269,10 +314,138 @@ func (s *ChangeUserVoteOperationUnitTestSuite) TestIsVoteTypeChangeAllowedExhaus
 	// Test unknown/invalid vote type (defensive programming test)
 	s.Run("unknown_vote_type", func() {
 		// Create a vote with an invalid/unknown vote type (e.g., 99)
-		invalidVote := romanceEntity.Vote{VoteType: romancesValueObject.VoteType(99)}
-		err := isVoteTypeChangeAllowed(invalidVote, romancesValueObject.VoteTypeYes)
+		err := s.transitionPolicy.CheckTransition(s.voteId.CountryId(), romancesValueObject.VoteType(99), romancesValueObject.VoteTypeYes)
 
 		// Should return error for unknown vote types
 		s.Require().Error(err, "Unknown vote type should not be changeable")
 	})
 }
+
+func (s *ChangeUserVoteOperationUnitTestSuite) TestRunIfVersionRejectsStaleVersion() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.Version = 5
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	vote, _, err := operation.RunIfVersion(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, time.Now(), nil, "", []uint32{4})
+
+	s.Require().ErrorIs(err, romanceDomain.ErrVersionMismatch)
+	s.Require().Equal(romanceEntity.Vote{}, vote)
+}
+
+func (s *ChangeUserVoteOperationUnitTestSuite) TestRunIfVersionDoesNotRetryVersionConflict() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.Version = 5
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	s.romancesRepo.EXPECT().
+		ChangeActiveUserVoteTypeInRomance(s.ctx, romance, romancesValueObject.VoteTypeCrush, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(romanceEntity.Romance{}, romanceDomain.ErrVersionConflict)
+
+	operation := s.newOperation()
+	_, _, err := operation.RunIfVersion(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, time.Now(), nil, "", []uint32{5})
+
+	s.Require().ErrorIs(err, romanceDomain.ErrVersionMismatch)
+}
+
+func (s *ChangeUserVoteOperationUnitTestSuite) TestRunIfVersionChangesVote() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.Version = 5
+
+	updatedRomance := romance
+	updatedRomance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeCrush
+	updatedRomance.Version = 6
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	s.romancesRepo.EXPECT().
+		ChangeActiveUserVoteTypeInRomance(s.ctx, romance, romancesValueObject.VoteTypeCrush, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(updatedRomance, nil)
+
+	operation := s.newOperation()
+	vote, _, err := operation.RunIfVersion(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, time.Now(), nil, "", []uint32{5})
+
+	s.Require().NoError(err)
+	s.Require().Equal(romancesValueObject.VoteTypeCrush, vote.VoteType)
+}
+
+func (s *ChangeUserVoteOperationUnitTestSuite) TestOlderVoteIsRejectedAsStale() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	storedVotedAt := time.Now()
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.ActiveUserVote.VotedAt = &storedVotedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, storedVotedAt.Add(-time.Minute), nil, "")
+
+	s.Require().ErrorIs(err, romanceDomain.ErrStaleVote)
+}
+
+func (s *ChangeUserVoteOperationUnitTestSuite) TestVoteOnBlockedRomanceIsRejected() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	blockedAt := time.Now()
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.ActiveUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, time.Now(), nil, "")
+
+	s.Require().ErrorIs(err, romanceDomain.ErrRomanceBlocked)
+}
+
+func (s *ChangeUserVoteOperationUnitTestSuite) TestComplimentOnStaleVoteIsNotModerated() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	votedAt := time.Now()
+	romance.ActiveUserVote.VotedAt = &votedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	_, _, err := operation.Run(
+		s.ctx, s.voteId, romancesValueObject.VoteTypeCompliment, votedAt.Add(-time.Minute), nil, "Lovely smile",
+	)
+
+	s.Require().ErrorIs(err, romanceDomain.ErrStaleVote)
+}
+
+func (s *ChangeUserVoteOperationUnitTestSuite) TestStaleVoteFromRepositoryIsNotRetried() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	storedVotedAt := time.Now().Add(-time.Hour)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.ActiveUserVote.VotedAt = &storedVotedAt
+	romance.Version = 2
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.romancesRepo.EXPECT().
+		ChangeActiveUserVoteTypeInRomance(s.ctx, romance, romancesValueObject.VoteTypeCrush, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(romanceEntity.Romance{}, romanceDomain.ErrStaleVote)
+
+	operation := s.newOperation()
+	_, _, err := operation.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, time.Now(), nil, "")
+
+	s.Require().ErrorIs(err, romanceDomain.ErrStaleVote)
+}
@@ -0,0 +1,47 @@
+package operation
+
+import (
+	"context"
+	"time"
+
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/google/uuid"
+)
+
+type CheckEligibilityOperation struct {
+	romancesRepository romancesRepo.RomancesRepository
+	eligibilityPolicy  *romanceDomain.EligibilityPolicy
+}
+
+func NewCheckEligibilityOperation(
+	romancesRepository romancesRepo.RomancesRepository,
+	eligibilityPolicy *romanceDomain.EligibilityPolicy,
+) *CheckEligibilityOperation {
+	return &CheckEligibilityOperation{
+		romancesRepository: romancesRepository,
+		eligibilityPolicy:  eligibilityPolicy,
+	}
+}
+
+// Run returns the candidates which can be shown to the active user again in the order of the candidates
+func (r *CheckEligibilityOperation) Run(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	peerIds []uuid.UUID,
+	now time.Time,
+) ([]uuid.UUID, error) {
+	romances, err := r.romancesRepository.GetRomancesGroup(ctx, userKey, peerIds)
+	if err != nil {
+		return nil, err
+	}
+
+	eligible := make([]uuid.UUID, 0, len(romances))
+	for _, romance := range romances {
+		if r.eligibilityPolicy.IsEligible(romance, now) {
+			eligible = append(eligible, romance.ActiveUserVote.Id.PeerUserId())
+		}
+	}
+	return eligible, nil
+}
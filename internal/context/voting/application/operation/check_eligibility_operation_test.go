@@ -0,0 +1,93 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type CheckEligibilityOperationUnitTestSuite struct {
+	suite.Suite
+	userKey      sharedValueObject.ActiveUserKey
+	romancesRepo *mocks.MockRomancesRepository
+	operation    *CheckEligibilityOperation
+	ctx          context.Context
+}
+
+func TestCheckEligibilityOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(CheckEligibilityOperationUnitTestSuite))
+}
+
+func (s *CheckEligibilityOperationUnitTestSuite) SetupTest() {
+	userKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.userKey = userKey
+	s.ctx = context.Background()
+	s.romancesRepo = mocks.NewMockRomancesRepository(gomock.NewController(s.T()))
+	s.operation = NewCheckEligibilityOperation(s.romancesRepo, romanceDomain.NewEligibilityPolicy(config.Config{
+		Voting: config.VotingConfig{NoVoteCoolingPeriod: 24 * time.Hour},
+	}))
+}
+
+func (s *CheckEligibilityOperationUnitTestSuite) TestEligiblePeersKeepTheCandidatesOrder() {
+	now := time.Now()
+	recentNo := s.romance(romancesValueObject.VoteTypeNo, now.Add(-time.Hour))
+	expiredNo := s.romance(romancesValueObject.VoteTypeNo, now.Add(-48*time.Hour))
+	yes := s.romance(romancesValueObject.VoteTypeYes, now.Add(-48*time.Hour))
+	notVoted := s.romance(romancesValueObject.VoteTypeEmpty, now)
+	peerIds := []uuid.UUID{
+		expiredNo.ActiveUserVote.Id.PeerUserId(),
+		recentNo.ActiveUserVote.Id.PeerUserId(),
+		yes.ActiveUserVote.Id.PeerUserId(),
+		notVoted.ActiveUserVote.Id.PeerUserId(),
+	}
+
+	s.romancesRepo.EXPECT().
+		GetRomancesGroup(s.ctx, s.userKey, peerIds).
+		Return([]romanceEntity.Romance{expiredNo, recentNo, yes, notVoted}, nil)
+
+	eligible, err := s.operation.Run(s.ctx, s.userKey, peerIds, now)
+
+	s.Require().NoError(err)
+	s.Require().Equal([]uuid.UUID{peerIds[0], peerIds[3]}, eligible)
+}
+
+func (s *CheckEligibilityOperationUnitTestSuite) TestGetRomancesReturnsError() {
+	expectedErr := errors.New("database error")
+	peerIds := []uuid.UUID{uuidhelper.NewUUID(s.T())}
+
+	s.romancesRepo.EXPECT().
+		GetRomancesGroup(s.ctx, s.userKey, peerIds).
+		Return(nil, expectedErr)
+
+	_, err := s.operation.Run(s.ctx, s.userKey, peerIds, time.Now())
+
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *CheckEligibilityOperationUnitTestSuite) romance(
+	voteType romancesValueObject.VoteType,
+	votedAt time.Time,
+) romanceEntity.Romance {
+	voteId, err := sharedValueObject.NewVoteId(s.userKey.CountryId(), s.userKey.ActiveUserId(), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	romance := romanceEntity.CreateEmptyRomance(voteId)
+	if voteType != romancesValueObject.VoteTypeEmpty {
+		romance.ActiveUserVote.VoteType = voteType
+		romance.ActiveUserVote.VotedAt = &votedAt
+		romance.Version = 1
+	}
+	return romance
+}
@@ -0,0 +1,57 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	userDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider"
+	userValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/valueobject"
+)
+
+type CheckVoterOperation struct {
+	userStatusProvider  provider.UserStatusProvider
+	entitlementProvider provider.EntitlementProvider
+}
+
+func NewCheckVoterOperation(
+	userStatusProvider provider.UserStatusProvider,
+	entitlementProvider provider.EntitlementProvider,
+) *CheckVoterOperation {
+	return &CheckVoterOperation{
+		userStatusProvider:  userStatusProvider,
+		entitlementProvider: entitlementProvider,
+	}
+}
+
+// Run rejects votes of banned or deleted users and premium votes of users not entitled to them
+func (r *CheckVoterOperation) Run(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	voteType romancesValueObject.VoteType,
+) error {
+	status, err := r.userStatusProvider.GetStatus(ctx, activeUserKey)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case userValueObject.UserStatusBanned:
+		return userDomain.ErrUserBanned
+	case userValueObject.UserStatusDeleted:
+		return userDomain.ErrUserDeleted
+	}
+
+	if !quotaDomain.IsPremium(voteType) {
+		return nil
+	}
+	entitled, err := r.entitlementProvider.IsEntitled(ctx, activeUserKey, voteType)
+	if err != nil {
+		return err
+	}
+	if !entitled {
+		return fmt.Errorf("%w: `%s`", userDomain.ErrVoteTypeNotEntitled, voteType)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package operation
+
+import (
+	"context"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"testing"
+
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	userDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user"
+	userValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type CheckVoterOperationUnitTestSuite struct {
+	suite.Suite
+	activeUserKey       sharedValueObject.ActiveUserKey
+	ctrl                *gomock.Controller
+	userStatusProvider  *mocks.MockUserStatusProvider
+	entitlementProvider *mocks.MockEntitlementProvider
+	ctx                 context.Context
+}
+
+func TestCheckVoterOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(CheckVoterOperationUnitTestSuite))
+}
+
+func (s *CheckVoterOperationUnitTestSuite) SetupSuite() {
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.activeUserKey = activeUserKey
+	s.ctx = context.Background()
+}
+
+func (s *CheckVoterOperationUnitTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userStatusProvider = mocks.NewMockUserStatusProvider(s.ctrl)
+	s.entitlementProvider = mocks.NewMockEntitlementProvider(s.ctrl)
+}
+
+func (s *CheckVoterOperationUnitTestSuite) newOperation() *CheckVoterOperation {
+	return NewCheckVoterOperation(s.userStatusProvider, s.entitlementProvider)
+}
+
+func (s *CheckVoterOperationUnitTestSuite) TestInactiveUsersAreRejected() {
+	testCases := []struct {
+		status      userValueObject.UserStatus
+		expectedErr error
+	}{
+		{status: userValueObject.UserStatusBanned, expectedErr: userDomain.ErrUserBanned},
+		{status: userValueObject.UserStatusDeleted, expectedErr: userDomain.ErrUserDeleted},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.status.String(), func() {
+			s.userStatusProvider.EXPECT().
+				GetStatus(s.ctx, s.activeUserKey).
+				Return(tc.status, nil)
+
+			err := s.newOperation().Run(s.ctx, s.activeUserKey, romancesValueObject.VoteTypeYes)
+			s.Require().ErrorIs(err, tc.expectedErr)
+		})
+	}
+}
+
+func (s *CheckVoterOperationUnitTestSuite) TestUnavailableStatusIsReturned() {
+	s.userStatusProvider.EXPECT().
+		GetStatus(s.ctx, s.activeUserKey).
+		Return(userValueObject.UserStatusActive, userDomain.ErrUserCheckUnavailable)
+
+	err := s.newOperation().Run(s.ctx, s.activeUserKey, romancesValueObject.VoteTypeYes)
+	s.Require().ErrorIs(err, userDomain.ErrUserCheckUnavailable)
+}
+
+func (s *CheckVoterOperationUnitTestSuite) TestRegularVotesSkipEntitlements() {
+	s.userStatusProvider.EXPECT().
+		GetStatus(s.ctx, s.activeUserKey).
+		Return(userValueObject.UserStatusActive, nil)
+
+	err := s.newOperation().Run(s.ctx, s.activeUserKey, romancesValueObject.VoteTypeNo)
+	s.Require().NoError(err)
+}
+
+func (s *CheckVoterOperationUnitTestSuite) TestPremiumVoteRequiresEntitlement() {
+	s.userStatusProvider.EXPECT().
+		GetStatus(s.ctx, s.activeUserKey).
+		Return(userValueObject.UserStatusActive, nil).
+		Times(2)
+	s.entitlementProvider.EXPECT().
+		IsEntitled(s.ctx, s.activeUserKey, romancesValueObject.VoteTypeCrush).
+		Return(false, nil)
+	s.entitlementProvider.EXPECT().
+		IsEntitled(s.ctx, s.activeUserKey, romancesValueObject.VoteTypeCompliment).
+		Return(true, nil)
+
+	operation := s.newOperation()
+	s.Require().ErrorIs(operation.Run(s.ctx, s.activeUserKey, romancesValueObject.VoteTypeCrush), userDomain.ErrVoteTypeNotEntitled)
+	s.Require().NoError(operation.Run(s.ctx, s.activeUserKey, romancesValueObject.VoteTypeCompliment))
+}
@@ -0,0 +1,90 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	quotasRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/repository"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	userProvider "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"time"
+)
+
+type ConsumeVoteQuotaOperation struct {
+	quotasRepository quotasRepo.QuotasRepository
+	quotaPolicy      *quotaDomain.QuotaPolicy
+	timeZoneProvider userProvider.TimeZoneProvider
+	logger           platform.Logger
+}
+
+func NewConsumeVoteQuotaOperation(
+	quotasRepository quotasRepo.QuotasRepository,
+	quotaPolicy *quotaDomain.QuotaPolicy,
+	timeZoneProvider userProvider.TimeZoneProvider,
+	logger platform.Logger,
+) *ConsumeVoteQuotaOperation {
+	return &ConsumeVoteQuotaOperation{
+		quotasRepository: quotasRepository,
+		quotaPolicy:      quotaPolicy,
+		timeZoneProvider: timeZoneProvider,
+		logger:           logger,
+	}
+}
+
+// Run uses one vote of the daily quota of voteType in the day of the user time zone, the returned
+// consumption is nil when the vote type is not limited for the user and there is nothing to refund.
+func (r *ConsumeVoteQuotaOperation) Run(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	voteType romancesValueObject.VoteType,
+	now time.Time,
+) (*entity.Consumption, error) {
+	if !quotaDomain.IsPremium(voteType) {
+		return nil, nil
+	}
+
+	overrides, err := r.quotasRepository.GetOverrides(ctx, activeUserKey)
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("GetOverrides error: %+v", err))
+		return nil, err
+	}
+	limit, limited := r.quotaPolicy.Limit(voteType, overrides)
+	if !limited {
+		return nil, nil
+	}
+
+	location, err := r.timeZoneProvider.GetTimeZone(ctx, activeUserKey)
+	if err != nil {
+		return nil, err
+	}
+	day, resetAt := quotaDomain.Day(now, location)
+	consumption := entity.Consumption{
+		ActiveUserKey: activeUserKey,
+		VoteType:      voteType,
+		Day:           day,
+	}
+	err = r.quotasRepository.Consume(ctx, consumption, limit, resetAt)
+	if errors.Is(err, quotaDomain.ErrQuotaExhausted) {
+		return nil, &quotaDomain.QuotaExhaustedError{VoteType: voteType, Limit: limit, ResetAt: resetAt}
+	}
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("Consume quota error: %+v", err))
+		return nil, err
+	}
+	return &consumption, nil
+}
+
+// Refund gives back a consumption of a rejected vote, a failed refund is only logged
+// as the vote error is what the caller has to report.
+func (r *ConsumeVoteQuotaOperation) Refund(ctx context.Context, consumption *entity.Consumption) {
+	if consumption == nil {
+		return
+	}
+	if err := r.quotasRepository.Refund(ctx, *consumption); err != nil {
+		r.logger.Error(fmt.Sprintf("Refund quota error: %+v", err))
+	}
+}
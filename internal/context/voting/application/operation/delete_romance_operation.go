@@ -7,17 +7,25 @@ import (
 )
 
 type DeleteRomanceOperation struct {
-	romancesRepository romancesRepo.RomancesRepository
+	romancesRepository         romancesRepo.RomancesRepository
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository
 }
 
 func NewDeleteRomanceOperation(
 	romancesRepository romancesRepo.RomancesRepository,
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository,
 ) *DeleteRomanceOperation {
 	return &DeleteRomanceOperation{
-		romancesRepository: romancesRepository,
+		romancesRepository:         romancesRepository,
+		exclusionFiltersRepository: exclusionFiltersRepository,
 	}
 }
 
 func (r *DeleteRomanceOperation) Run(ctx context.Context, voteId sharedValueObject.VoteId) error {
-	return r.romancesRepository.DeleteRomance(ctx, voteId)
+	if err := r.romancesRepository.DeleteRomance(ctx, voteId); err != nil {
+		return err
+	}
+	r.exclusionFiltersRepository.DeleteExclusionFilter(ctx, voteId.ActiveUserKey())
+	r.exclusionFiltersRepository.DeleteExclusionFilter(ctx, voteId.PeerUserKey())
+	return nil
 }
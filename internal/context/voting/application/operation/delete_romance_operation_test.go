@@ -14,10 +14,11 @@ import (
 
 type DeleteRomanceOperationUnitTestSuite struct {
 	suite.Suite
-	voteId       sharedValueObject.VoteId
-	ctrl         *gomock.Controller
-	romancesRepo *mocks.MockRomancesRepository
-	ctx          context.Context
+	voteId           sharedValueObject.VoteId
+	ctrl             *gomock.Controller
+	romancesRepo     *mocks.MockRomancesRepository
+	exclusionFilters *mocks.MockExclusionFiltersRepository
+	ctx              context.Context
 }
 
 func TestDeleteRomanceOperationUnitSuite(t *testing.T) {
@@ -38,10 +39,11 @@ func (s *DeleteRomanceOperationUnitTestSuite) SetupSuite() {
 func (s *DeleteRomanceOperationUnitTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.romancesRepo = mocks.NewMockRomancesRepository(s.ctrl)
+	s.exclusionFilters = mocks.NewMockExclusionFiltersRepository(s.ctrl)
 }
 
 func (s *DeleteRomanceOperationUnitTestSuite) newOperation() *DeleteRomanceOperation {
-	return NewDeleteRomanceOperation(s.romancesRepo)
+	return NewDeleteRomanceOperation(s.romancesRepo, s.exclusionFilters)
 }
 
 func (s *DeleteRomanceOperationUnitTestSuite) TestDeleteRomanceReturnsError() {
@@ -62,6 +64,8 @@ func (s *DeleteRomanceOperationUnitTestSuite) TestDeleteRomanceSuccessfully() {
 	s.romancesRepo.EXPECT().
 		DeleteRomance(s.ctx, s.voteId).
 		Return(nil)
+	s.exclusionFilters.EXPECT().DeleteExclusionFilter(s.ctx, s.voteId.ActiveUserKey())
+	s.exclusionFilters.EXPECT().DeleteExclusionFilter(s.ctx, s.voteId.PeerUserKey())
 
 	operation := s.newOperation()
 	err := operation.Run(s.ctx, s.voteId)
@@ -44,7 +44,7 @@ func (r *DeleteRomancesOperation) Run(ctx context.Context, userKey sharedValueOb
 	for peerId := range peerIdsChan {
 		peerIds = append(peerIds, peerId)
 		if len(peerIds) == getRomancesGroupLimit {
-			err = r.publisher.Publish(DeleteRomancesGroupTopic, message.NewDeleteRomancesGroupMessage(userKey, peerIds))
+			err = r.publishDeleteRomancesGroup(ctx, userKey, peerIds)
 			if err != nil {
 				r.logger.Error(err.Error())
 				return err
@@ -54,7 +54,7 @@ func (r *DeleteRomancesOperation) Run(ctx context.Context, userKey sharedValueOb
 	}
 
 	if len(peerIds) > 0 {
-		err = r.publisher.Publish(DeleteRomancesGroupTopic, message.NewDeleteRomancesGroupMessage(userKey, peerIds))
+		err = r.publishDeleteRomancesGroup(ctx, userKey, peerIds)
 		if err != nil {
 			r.logger.Error(err.Error())
 			return err
@@ -63,3 +63,14 @@ func (r *DeleteRomancesOperation) Run(ctx context.Context, userKey sharedValueOb
 
 	return nil
 }
+
+func (r *DeleteRomancesOperation) publishDeleteRomancesGroup(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	peerIds []uuid.UUID,
+) error {
+	m := message.NewDeleteRomancesGroupMessage(userKey, peerIds)
+	m.SetTraceContext(messaging.TraceContextFromContext(ctx))
+
+	return r.publisher.Publish(DeleteRomancesGroupTopic, m)
+}
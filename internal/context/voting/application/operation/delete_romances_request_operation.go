@@ -26,5 +26,8 @@ func NewDeleteRomancesRequestOperation(
 }
 
 func (r *DeleteRomancesRequestOperation) Run(ctx context.Context, userKey sharedValueObject.ActiveUserKey) error {
-	return r.publisher.Publish(DeleteRomancesTopic, message.NewDeleteRomancesMessage(userKey))
+	m := message.NewDeleteRomancesMessage(userKey)
+	m.SetTraceContext(messaging.TraceContextFromContext(ctx))
+
+	return r.publisher.Publish(DeleteRomancesTopic, m)
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/message"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -75,3 +76,20 @@ func (s *DeleteRomancesRequestOperationUnitTestSuite) TestDeleteRomancesRequestS
 
 	s.Require().NoError(err)
 }
+
+func (s *DeleteRomancesRequestOperationUnitTestSuite) TestDeleteRomancesRequestPropagatesTraceContext() {
+	traceContext := messaging.NewTraceContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "")
+	ctx := messaging.ContextWithTraceContext(s.ctx, traceContext)
+
+	expectedMessage := message.NewDeleteRomancesMessage(s.activeUserKey)
+	expectedMessage.SetTraceContext(traceContext)
+
+	s.publisher.EXPECT().
+		Publish(DeleteRomancesTopic, expectedMessage).
+		Return(nil)
+
+	operation := s.newOperation()
+	err := operation.Run(ctx, s.activeUserKey)
+
+	s.Require().NoError(err)
+}
@@ -10,27 +10,53 @@ import (
 	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"slices"
 )
 
 type DeleteUserVoteOperation struct {
-	romancesRepository romancesRepo.RomancesRepository
-	countersRepository countersRepo.CountersRepository
-	logger             platform.Logger
+	romancesRepository         romancesRepo.RomancesRepository
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository
+	countersRepository         countersRepo.CountersRepository
+	logger                     platform.Logger
 }
 
 func NewDeleteUserVoteOperation(
 	romancesRepository romancesRepo.RomancesRepository,
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository,
 	countersRepository countersRepo.CountersRepository,
 	logger platform.Logger,
 ) *DeleteUserVoteOperation {
 	return &DeleteUserVoteOperation{
-		romancesRepository: romancesRepository,
-		countersRepository: countersRepository,
-		logger:             logger,
+		romancesRepository:         romancesRepository,
+		exclusionFiltersRepository: exclusionFiltersRepository,
+		countersRepository:         countersRepository,
+		logger:                     logger,
 	}
 }
 
-func (r *DeleteUserVoteOperation) Run(ctx context.Context, voteId sharedValueObject.VoteId) error {
+// Run returns the version of the romance after the deletion
+func (r *DeleteUserVoteOperation) Run(ctx context.Context, voteId sharedValueObject.VoteId) (uint32, error) {
+	return r.run(ctx, voteId, nil)
+}
+
+// RunIfVersion deletes the vote only while the romance is still at one of acceptedVersions,
+// a concurrent update is reported as ErrVersionMismatch instead of being retried.
+func (r *DeleteUserVoteOperation) RunIfVersion(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	acceptedVersions []uint32,
+) (uint32, error) {
+	if acceptedVersions == nil {
+		acceptedVersions = []uint32{}
+	}
+	return r.run(ctx, voteId, acceptedVersions)
+}
+
+func (r *DeleteUserVoteOperation) run(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	acceptedVersions []uint32,
+) (uint32, error) {
 	tries := 0
 
 	getRomanceOperation := NewGetRomanceOperation(r.romancesRepository)
@@ -38,20 +64,38 @@ func (r *DeleteUserVoteOperation) Run(ctx context.Context, voteId sharedValueObj
 		romance, err := getRomanceOperation.Run(ctx, voteId)
 		if err != nil {
 			r.logger.Error(fmt.Sprintf("GetRomance error: %+v", err))
-			return err
+			return 0, err
+		}
+
+		if romance.IsBlocked() {
+			return 0, romanceDomain.ErrRomanceBlocked
+		}
+
+		// the revote ban only rejects casting a vote again, deleting one is allowed
+		if acceptedVersions != nil && !slices.Contains(acceptedVersions, romance.Version) {
+			return 0, romanceDomain.ErrVersionMismatch
 		}
 
-		err = r.romancesRepository.DeleteActiveUserVoteFromRomance(ctx, romance)
+		wasMatched := romance.IsMatched()
+		romance, err = r.romancesRepository.DeleteActiveUserVoteFromRomance(ctx, romance)
 
 		if err != nil {
+			if errors.Is(err, romanceDomain.ErrVersionConflict) && acceptedVersions != nil {
+				return 0, romanceDomain.ErrVersionMismatch
+			}
 			if errors.Is(err, romanceDomain.ErrVersionConflict) && tries < config.DynamoDbVersionConflictRetriesCount {
 				tries += 1
 				continue
 			}
 			r.logger.Error(fmt.Sprintf("DeleteUserVoteFromRomance error: %+v", err))
-			return err
+			return 0, err
+		}
+
+		if wasMatched && !romance.IsMatched() {
+			r.countersRepository.DecrMatchCounters(ctx, voteId)
 		}
 
-		return nil
+		r.exclusionFiltersRepository.DeleteExclusionFilter(ctx, voteId.ActiveUserKey())
+		return romance.Version, nil
 	}
 }
@@ -7,9 +7,11 @@ import (
 	"io"
 	"log/slog"
 	"testing"
+	"time"
 
 	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
 	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
 	"github.com/stretchr/testify/suite"
@@ -18,12 +20,13 @@ import (
 
 type DeleteUserVoteOperationUnitTestSuite struct {
 	suite.Suite
-	voteId       sharedValueObject.VoteId
-	ctrl         *gomock.Controller
-	romancesRepo *mocks.MockRomancesRepository
-	countersRepo *mocks.MockCountersRepository
-	logger       *slog.Logger
-	ctx          context.Context
+	voteId           sharedValueObject.VoteId
+	ctrl             *gomock.Controller
+	romancesRepo     *mocks.MockRomancesRepository
+	exclusionFilters *mocks.MockExclusionFiltersRepository
+	countersRepo     *mocks.MockCountersRepository
+	logger           *slog.Logger
+	ctx              context.Context
 }
 
 func TestDeleteUserVoteOperationUnitSuite(t *testing.T) {
@@ -45,11 +48,13 @@ func (s *DeleteUserVoteOperationUnitTestSuite) SetupSuite() {
 func (s *DeleteUserVoteOperationUnitTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.romancesRepo = mocks.NewMockRomancesRepository(s.ctrl)
+	s.exclusionFilters = mocks.NewMockExclusionFiltersRepository(s.ctrl)
+	s.exclusionFilters.EXPECT().DeleteExclusionFilter(gomock.Any(), gomock.Any()).AnyTimes()
 	s.countersRepo = mocks.NewMockCountersRepository(s.ctrl)
 }
 
 func (s *DeleteUserVoteOperationUnitTestSuite) newOperation() *DeleteUserVoteOperation {
-	return NewDeleteUserVoteOperation(s.romancesRepo, s.countersRepo, s.logger)
+	return NewDeleteUserVoteOperation(s.romancesRepo, s.exclusionFilters, s.countersRepo, s.logger)
 }
 
 func (s *DeleteUserVoteOperationUnitTestSuite) TestGetRomanceReturnsError() {
@@ -60,7 +65,7 @@ func (s *DeleteUserVoteOperationUnitTestSuite) TestGetRomanceReturnsError() {
 		Return(romanceEntity.Romance{}, expectedErr)
 
 	operation := s.newOperation()
-	err := operation.Run(s.ctx, s.voteId)
+	_, err := operation.Run(s.ctx, s.voteId)
 
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, expectedErr)
@@ -76,10 +81,10 @@ func (s *DeleteUserVoteOperationUnitTestSuite) TestDeleteActiveUserVoteFromRoman
 
 	s.romancesRepo.EXPECT().
 		DeleteActiveUserVoteFromRomance(s.ctx, romance).
-		Return(expectedErr)
+		Return(romanceEntity.Romance{}, expectedErr)
 
 	operation := s.newOperation()
-	err := operation.Run(s.ctx, s.voteId)
+	_, err := operation.Run(s.ctx, s.voteId)
 
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, expectedErr)
@@ -96,7 +101,7 @@ func (s *DeleteUserVoteOperationUnitTestSuite) TestVersionConflictRetriesAndSucc
 	// First DeleteActiveUserVoteFromRomance fails with version conflict
 	s.romancesRepo.EXPECT().
 		DeleteActiveUserVoteFromRomance(s.ctx, romance).
-		Return(romanceDomain.ErrVersionConflict)
+		Return(romanceEntity.Romance{}, romanceDomain.ErrVersionConflict)
 
 	// Second call to GetRomance (retry)
 	s.romancesRepo.EXPECT().
@@ -106,10 +111,10 @@ func (s *DeleteUserVoteOperationUnitTestSuite) TestVersionConflictRetriesAndSucc
 	// Second DeleteActiveUserVoteFromRomance succeeds
 	s.romancesRepo.EXPECT().
 		DeleteActiveUserVoteFromRomance(s.ctx, romance).
-		Return(nil)
+		Return(romance, nil)
 
 	operation := s.newOperation()
-	err := operation.Run(s.ctx, s.voteId)
+	_, err := operation.Run(s.ctx, s.voteId)
 
 	s.Require().NoError(err)
 }
@@ -123,10 +128,126 @@ func (s *DeleteUserVoteOperationUnitTestSuite) TestDeleteVoteSuccessfully() {
 
 	s.romancesRepo.EXPECT().
 		DeleteActiveUserVoteFromRomance(s.ctx, romance).
-		Return(nil)
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	_, err := operation.Run(s.ctx, s.voteId)
+
+	s.Require().NoError(err)
+}
+
+func (s *DeleteUserVoteOperationUnitTestSuite) TestRunIfVersionRejectsStaleVersion() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.Version = 3
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	_, err := operation.RunIfVersion(s.ctx, s.voteId, []uint32{2})
+
+	s.Require().ErrorIs(err, romanceDomain.ErrVersionMismatch)
+}
+
+func (s *DeleteUserVoteOperationUnitTestSuite) TestRunIfVersionDoesNotRetryVersionConflict() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.Version = 3
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	s.romancesRepo.EXPECT().
+		DeleteActiveUserVoteFromRomance(s.ctx, romance).
+		Return(romanceEntity.Romance{}, romanceDomain.ErrVersionConflict)
+
+	operation := s.newOperation()
+	_, err := operation.RunIfVersion(s.ctx, s.voteId, []uint32{3})
+
+	s.Require().ErrorIs(err, romanceDomain.ErrVersionMismatch)
+}
+
+func (s *DeleteUserVoteOperationUnitTestSuite) TestRunIfVersionDeletesVote() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.Version = 3
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	deleted := romance
+	deleted.Version = 4
+	s.romancesRepo.EXPECT().
+		DeleteActiveUserVoteFromRomance(s.ctx, romance).
+		Return(deleted, nil)
 
 	operation := s.newOperation()
-	err := operation.Run(s.ctx, s.voteId)
+	version, err := operation.RunIfVersion(s.ctx, s.voteId, []uint32{2, 3})
+
+	s.Require().NoError(err)
+	s.Require().Equal(uint32(4), version)
+}
+
+func (s *DeleteUserVoteOperationUnitTestSuite) TestDeleteOnBlockedRomanceIsRejected() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	blockedAt := time.Now()
+	romance.PeerUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	_, err := operation.Run(s.ctx, s.voteId)
+
+	s.Require().ErrorIs(err, romanceDomain.ErrRomanceBlocked)
+}
+
+func (s *DeleteUserVoteOperationUnitTestSuite) TestDeleteDissolvingMatchDecrementsMatchCounters() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.PeerUserVote.VoteType = romancesValueObject.VoteTypeYes
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	deleted := romance
+	deleted.ActiveUserVote = romanceEntity.Vote{Id: s.voteId}
+	s.romancesRepo.EXPECT().
+		DeleteActiveUserVoteFromRomance(s.ctx, romance).
+		Return(deleted, nil)
+	s.countersRepo.EXPECT().
+		DecrMatchCounters(s.ctx, s.voteId)
+
+	_, err := s.newOperation().Run(s.ctx, s.voteId)
+
+	s.Require().NoError(err)
+}
+
+func (s *DeleteUserVoteOperationUnitTestSuite) TestDeleteDuringRevoteBanIsAllowed() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.PeerUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.Unmatch = &romanceEntity.Unmatch{
+		UnmatchedBy:       s.voteId.PeerUserId(),
+		UnmatchedAt:       time.Now(),
+		RevoteBannedUntil: time.Now().Add(time.Hour),
+	}
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	// the unmatch already took the match out of the counters
+	deleted := romance
+	deleted.ActiveUserVote = romanceEntity.Vote{Id: s.voteId}
+	s.romancesRepo.EXPECT().
+		DeleteActiveUserVoteFromRomance(s.ctx, romance).
+		Return(deleted, nil)
+
+	_, err := s.newOperation().Run(s.ctx, s.voteId)
 
 	s.Require().NoError(err)
 }
@@ -0,0 +1,75 @@
+package operation
+
+import (
+	"context"
+	"time"
+
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/callerscope"
+)
+
+const exportRomancesPageSize = 100
+
+type ExportRomancesOperation struct {
+	romancesRepository       romancesRepo.RomancesRepository
+	peerVoteProjectionPolicy *romanceDomain.PeerVoteProjectionPolicy
+}
+
+func NewExportRomancesOperation(
+	romancesRepository romancesRepo.RomancesRepository,
+	peerVoteProjectionPolicy *romanceDomain.PeerVoteProjectionPolicy,
+) *ExportRomancesOperation {
+	return &ExportRomancesOperation{
+		romancesRepository:       romancesRepository,
+		peerVoteProjectionPolicy: peerVoteProjectionPolicy,
+	}
+}
+
+// Run pages through all visible romances of the active user and passes every read page to emit,
+// romances are filtered like in ListRomancesOperation. A zero since exports all romances, otherwise
+// only those the active user voted on at or after since. Every page carries the cursor to resume after
+// it, the export stops at the first error of emit or once the context is done.
+func (r *ExportRomancesOperation) Run(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	since time.Time,
+	scope callerscope.Scope,
+	cursor string,
+	emit func(page entity.RomancesPage) error,
+) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		storedPage, err := r.romancesRepository.ListRomancesForActiveUser(ctx, userKey, exportRomancesPageSize, cursor)
+		if err != nil {
+			return err
+		}
+
+		page := entity.RomancesPage{
+			Romances:   make([]entity.Romance, 0, len(storedPage.Romances)),
+			NextCursor: storedPage.NextCursor,
+		}
+		for _, romance := range storedPage.Romances {
+			if romance.IsBlocked() && romance.ActiveUserBlockedAt == nil {
+				continue
+			}
+			if !since.IsZero() && (romance.ActiveUserVote.VotedAt == nil || romance.ActiveUserVote.VotedAt.Before(since)) {
+				continue
+			}
+			page.Romances = append(page.Romances, r.peerVoteProjectionPolicy.Project(visibleRomance(romance), scope.IsUnrestricted()))
+		}
+		if err = emit(page); err != nil {
+			return err
+		}
+
+		cursor = storedPage.NextCursor
+		if cursor == "" {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"testing"
+	"time"
+
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/callerscope"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type ExportRomancesOperationUnitTestSuite struct {
+	suite.Suite
+	userKey      sharedValueObject.ActiveUserKey
+	ctrl         *gomock.Controller
+	romancesRepo *mocks.MockRomancesRepository
+	ctx          context.Context
+}
+
+func TestExportRomancesOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(ExportRomancesOperationUnitTestSuite))
+}
+
+func (s *ExportRomancesOperationUnitTestSuite) SetupSuite() {
+	userKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.userKey = userKey
+	s.ctx = context.Background()
+}
+
+func (s *ExportRomancesOperationUnitTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.romancesRepo = mocks.NewMockRomancesRepository(s.ctrl)
+}
+
+func (s *ExportRomancesOperationUnitTestSuite) TestExportPagesFromTheCursorUntilTheEnd() {
+	first := s.romance(romancesValueObject.VoteTypeYes, time.Now())
+	second := s.romance(romancesValueObject.VoteTypeNo, time.Now())
+
+	gomock.InOrder(
+		s.romancesRepo.EXPECT().
+			ListRomancesForActiveUser(s.ctx, s.userKey, int32(exportRomancesPageSize), "resume").
+			Return(romanceEntity.RomancesPage{Romances: []romanceEntity.Romance{first}, NextCursor: "first"}, nil),
+		s.romancesRepo.EXPECT().
+			ListRomancesForActiveUser(s.ctx, s.userKey, int32(exportRomancesPageSize), "first").
+			Return(romanceEntity.RomancesPage{Romances: []romanceEntity.Romance{second}}, nil),
+	)
+
+	var pages []romanceEntity.RomancesPage
+	err := s.newOperation().Run(s.ctx, s.userKey, time.Time{}, callerscope.ScopeAdmin, "resume",
+		func(page romanceEntity.RomancesPage) error {
+			pages = append(pages, page)
+			return nil
+		})
+
+	s.Require().NoError(err)
+	s.Require().Equal([]romanceEntity.RomancesPage{
+		{Romances: []romanceEntity.Romance{first}, NextCursor: "first"},
+		{Romances: []romanceEntity.Romance{second}},
+	}, pages)
+}
+
+func (s *ExportRomancesOperationUnitTestSuite) TestExportLeavesOutRomancesBlockedByPeerAndVotedBeforeSince() {
+	since := time.Now().Add(-time.Hour)
+	recent := s.romance(romancesValueObject.VoteTypeYes, time.Now())
+	old := s.romance(romancesValueObject.VoteTypeYes, since.Add(-time.Minute))
+	blockedByPeer := s.romance(romancesValueObject.VoteTypeYes, time.Now())
+	blockedAt := time.Now()
+	blockedByPeer.PeerUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		ListRomancesForActiveUser(s.ctx, s.userKey, int32(exportRomancesPageSize), "").
+		Return(romanceEntity.RomancesPage{Romances: []romanceEntity.Romance{recent, old, blockedByPeer}}, nil)
+
+	var exported []romanceEntity.Romance
+	err := s.newOperation().Run(s.ctx, s.userKey, since, callerscope.ScopeAdmin, "",
+		func(page romanceEntity.RomancesPage) error {
+			exported = append(exported, page.Romances...)
+			return nil
+		})
+
+	s.Require().NoError(err)
+	s.Require().Equal([]romanceEntity.Romance{recent}, exported)
+}
+
+func (s *ExportRomancesOperationUnitTestSuite) TestExportStopsAtEmitError() {
+	expectedErr := errors.New("client gone")
+
+	s.romancesRepo.EXPECT().
+		ListRomancesForActiveUser(s.ctx, s.userKey, int32(exportRomancesPageSize), "").
+		Return(romanceEntity.RomancesPage{NextCursor: "first"}, nil)
+
+	err := s.newOperation().Run(s.ctx, s.userKey, time.Time{}, callerscope.ScopeUser, "",
+		func(romanceEntity.RomancesPage) error {
+			return expectedErr
+		})
+
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *ExportRomancesOperationUnitTestSuite) TestExportStopsOnceContextIsCanceled() {
+	ctx, cancel := context.WithCancel(s.ctx)
+
+	s.romancesRepo.EXPECT().
+		ListRomancesForActiveUser(ctx, s.userKey, int32(exportRomancesPageSize), "").
+		Return(romanceEntity.RomancesPage{NextCursor: "first"}, nil)
+
+	err := s.newOperation().Run(ctx, s.userKey, time.Time{}, callerscope.ScopeUser, "",
+		func(romanceEntity.RomancesPage) error {
+			cancel()
+			return nil
+		})
+
+	s.Require().ErrorIs(err, context.Canceled)
+}
+
+func (s *ExportRomancesOperationUnitTestSuite) newOperation() *ExportRomancesOperation {
+	return NewExportRomancesOperation(s.romancesRepo, romanceDomain.NewPeerVoteProjectionPolicy())
+}
+
+func (s *ExportRomancesOperationUnitTestSuite) romance(
+	activeUserVoteType romancesValueObject.VoteType,
+	votedAt time.Time,
+) romanceEntity.Romance {
+	voteId, err := sharedValueObject.NewVoteId(s.userKey.CountryId(), s.userKey.ActiveUserId(), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	romance := romanceEntity.CreateEmptyRomance(voteId)
+	romance.ActiveUserVote.VoteType = activeUserVoteType
+	romance.ActiveUserVote.VotedAt = &votedAt
+	romance.Version = 1
+	return romance
+}
@@ -0,0 +1,58 @@
+package operation
+
+import (
+	"context"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/entity"
+	countersRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/repository"
+	countersValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+)
+
+type GetCountersSeriesOperation struct {
+	countersRepository countersRepo.CountersRepository
+}
+
+func NewGetCountersSeriesOperation(
+	countersRepository countersRepo.CountersRepository,
+) *GetCountersSeriesOperation {
+	return &GetCountersSeriesOperation{
+		countersRepository: countersRepository,
+	}
+}
+
+// Run returns one counters group per bucket of the range, HourUnixTimestamp is the start of the bucket.
+// Buckets without votes are zero, so are buckets older than the retention of their granularity.
+func (r *GetCountersSeriesOperation) Run(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	countersRange countersValueObject.CountersRange,
+) ([]entity.CountersGroup, error) {
+	storedCounters, err := r.countersRepository.GetCountersRange(ctx, activeUserKey, countersRange)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]entity.CountersGroup, countersRange.BucketsCount())
+	for i := range series {
+		series[i] = entity.CountersGroup{
+			ActiveUserKey:     activeUserKey,
+			HourUnixTimestamp: int32(countersRange.BucketStart(i).Unix()),
+		}
+	}
+
+	for _, stored := range storedCounters {
+		i, ok := countersRange.BucketIndex(time.Unix(int64(stored.HourUnixTimestamp), 0))
+		if !ok {
+			continue
+		}
+		series[i].IncomingYes += stored.IncomingYes
+		series[i].IncomingNo += stored.IncomingNo
+		series[i].OutgoingYes += stored.OutgoingYes
+		series[i].OutgoingNo += stored.OutgoingNo
+		series[i].Matches += stored.Matches
+	}
+
+	return series, nil
+}
@@ -0,0 +1,106 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"testing"
+	"time"
+
+	counterEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/entity"
+	countersValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type GetCountersSeriesOperationUnitTestSuite struct {
+	suite.Suite
+	activeUserKey sharedValueObject.ActiveUserKey
+	ctrl          *gomock.Controller
+	countersRepo  *mocks.MockCountersRepository
+	ctx           context.Context
+	from          time.Time
+}
+
+func TestGetCountersSeriesOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(GetCountersSeriesOperationUnitTestSuite))
+}
+
+func (s *GetCountersSeriesOperationUnitTestSuite) SetupSuite() {
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.activeUserKey = activeUserKey
+	s.ctx = context.Background()
+	s.from = time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC)
+}
+
+func (s *GetCountersSeriesOperationUnitTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.countersRepo = mocks.NewMockCountersRepository(s.ctrl)
+}
+
+func (s *GetCountersSeriesOperationUnitTestSuite) newOperation() *GetCountersSeriesOperation {
+	return NewGetCountersSeriesOperation(s.countersRepo)
+}
+
+func (s *GetCountersSeriesOperationUnitTestSuite) TestHourlySeriesIsZeroFilled() {
+	countersRange, err := countersValueObject.NewCountersRange(s.from, s.from.Add(3*time.Hour), countersValueObject.CountersGranularityHour)
+	s.Require().NoError(err)
+
+	s.countersRepo.EXPECT().
+		GetCountersRange(s.ctx, s.activeUserKey, countersRange).
+		Return([]counterEntity.CountersGroup{s.counters(s.from.Add(time.Hour), 3)}, nil)
+
+	series, err := s.newOperation().Run(s.ctx, s.activeUserKey, countersRange)
+
+	s.Require().NoError(err)
+	s.Require().Equal([]counterEntity.CountersGroup{
+		{ActiveUserKey: s.activeUserKey, HourUnixTimestamp: int32(s.from.Unix())},
+		s.counters(s.from.Add(time.Hour), 3),
+		{ActiveUserKey: s.activeUserKey, HourUnixTimestamp: int32(s.from.Add(2 * time.Hour).Unix())},
+	}, series)
+}
+
+func (s *GetCountersSeriesOperationUnitTestSuite) TestWeeklySeriesPlacesRollupsInTheirWeeks() {
+	firstMonday := time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC)
+	lastMonday := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	countersRange, err := countersValueObject.NewCountersRange(s.from, lastMonday.Add(time.Hour), countersValueObject.CountersGranularityWeek)
+	s.Require().NoError(err)
+
+	s.countersRepo.EXPECT().
+		GetCountersRange(s.ctx, s.activeUserKey, countersRange).
+		Return([]counterEntity.CountersGroup{s.counters(lastMonday, 7), s.counters(firstMonday, 4)}, nil)
+
+	series, err := s.newOperation().Run(s.ctx, s.activeUserKey, countersRange)
+
+	s.Require().NoError(err)
+	s.Require().Equal([]counterEntity.CountersGroup{
+		s.counters(firstMonday, 4),
+		{ActiveUserKey: s.activeUserKey, HourUnixTimestamp: int32(firstMonday.AddDate(0, 0, 7).Unix())},
+		s.counters(lastMonday, 7),
+	}, series)
+}
+
+func (s *GetCountersSeriesOperationUnitTestSuite) TestSeriesReturnsError() {
+	expectedErr := errors.New("database error")
+	countersRange, err := countersValueObject.NewCountersRange(s.from, s.from.Add(time.Hour), countersValueObject.CountersGranularityHour)
+	s.Require().NoError(err)
+
+	s.countersRepo.EXPECT().
+		GetCountersRange(s.ctx, s.activeUserKey, countersRange).
+		Return(nil, expectedErr)
+
+	_, err = s.newOperation().Run(s.ctx, s.activeUserKey, countersRange)
+
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *GetCountersSeriesOperationUnitTestSuite) counters(start time.Time, outgoingYes uint32) counterEntity.CountersGroup {
+	return counterEntity.CountersGroup{
+		ActiveUserKey:     s.activeUserKey,
+		HourUnixTimestamp: int32(start.Unix()),
+		OutgoingYes:       outgoingYes,
+	}
+}
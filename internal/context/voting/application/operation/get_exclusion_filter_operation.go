@@ -0,0 +1,87 @@
+package operation
+
+import (
+	"context"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/google/uuid"
+)
+
+const exclusionFilterPageSize = 100
+
+type GetExclusionFilterOperation struct {
+	romancesRepository         romancesRepo.RomancesRepository
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository
+	eligibilityPolicy          *romanceDomain.EligibilityPolicy
+	falsePositiveRate          float64
+}
+
+func NewGetExclusionFilterOperation(
+	romancesRepository romancesRepo.RomancesRepository,
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository,
+	eligibilityPolicy *romanceDomain.EligibilityPolicy,
+	cfg config.Config,
+) (*GetExclusionFilterOperation, error) {
+	// the rate is checked on start rather than on the first request
+	if _, err := romancesValueObject.NewExclusionFilter(1, cfg.Exclusion.FalsePositiveRate); err != nil {
+		return nil, err
+	}
+	return &GetExclusionFilterOperation{
+		romancesRepository:         romancesRepository,
+		exclusionFiltersRepository: exclusionFiltersRepository,
+		eligibilityPolicy:          eligibilityPolicy,
+		falsePositiveRate:          cfg.Exclusion.FalsePositiveRate,
+	}, nil
+}
+
+// Run returns the filter of the peers which are not eligible for the active user, see EligibilityPolicy.
+// A built filter is kept and has room for the peers excluded by later votes, it is built again once
+// they no longer fit in it. Errors of the kept filters are returned rather than building it on every request.
+func (r *GetExclusionFilterOperation) Run(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	now time.Time,
+) (romancesValueObject.ExclusionFilter, error) {
+	filter, ok, err := r.exclusionFiltersRepository.GetExclusionFilter(ctx, userKey)
+	if err != nil {
+		return romancesValueObject.ExclusionFilter{}, err
+	}
+	if ok && filter.FalsePositiveRate() <= r.falsePositiveRate {
+		return filter, nil
+	}
+
+	var excluded []uuid.UUID
+	cursor := ""
+	for {
+		page, err := r.romancesRepository.ListRomancesForActiveUser(ctx, userKey, exclusionFilterPageSize, cursor)
+		if err != nil {
+			return romancesValueObject.ExclusionFilter{}, err
+		}
+		for _, romance := range page.Romances {
+			if !r.eligibilityPolicy.IsEligible(romance, now) {
+				excluded = append(excluded, romance.ActiveUserVote.Id.PeerUserId())
+			}
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	filter, err = romancesValueObject.NewExclusionFilter(len(excluded)+len(excluded)/4+16, r.falsePositiveRate)
+	if err != nil {
+		return romancesValueObject.ExclusionFilter{}, err
+	}
+	for _, peerId := range excluded {
+		filter.Add(peerId)
+	}
+	if err = r.exclusionFiltersRepository.SaveExclusionFilter(ctx, userKey, filter); err != nil {
+		return romancesValueObject.ExclusionFilter{}, err
+	}
+	return filter, nil
+}
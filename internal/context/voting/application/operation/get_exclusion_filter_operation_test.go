@@ -0,0 +1,183 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type GetExclusionFilterOperationUnitTestSuite struct {
+	suite.Suite
+	userKey          sharedValueObject.ActiveUserKey
+	romancesRepo     *mocks.MockRomancesRepository
+	exclusionFilters *mocks.MockExclusionFiltersRepository
+	operation        *GetExclusionFilterOperation
+	ctx              context.Context
+}
+
+func TestGetExclusionFilterOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(GetExclusionFilterOperationUnitTestSuite))
+}
+
+func (s *GetExclusionFilterOperationUnitTestSuite) SetupTest() {
+	userKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.userKey = userKey
+	s.ctx = context.Background()
+
+	ctrl := gomock.NewController(s.T())
+	s.romancesRepo = mocks.NewMockRomancesRepository(ctrl)
+	s.exclusionFilters = mocks.NewMockExclusionFiltersRepository(ctrl)
+	cfg := config.Config{
+		Voting:    config.VotingConfig{NoVoteCoolingPeriod: 24 * time.Hour},
+		Exclusion: config.ExclusionFilterConfig{FalsePositiveRate: 0.0001},
+	}
+	s.operation, err = NewGetExclusionFilterOperation(s.romancesRepo, s.exclusionFilters, romanceDomain.NewEligibilityPolicy(cfg), cfg)
+	s.Require().NoError(err)
+}
+
+func (s *GetExclusionFilterOperationUnitTestSuite) TestFilterIsBuiltFromAllPagesAndKept() {
+	now := time.Now()
+	voted := s.romance()
+	voted.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	blockedByPeer := s.romance()
+	blockedByPeer.PeerUserBlockedAt = &now
+	incoming := s.romance()
+	incoming.PeerUserVote.VoteType = romancesValueObject.VoteTypeYes
+
+	gomock.InOrder(
+		s.exclusionFilters.EXPECT().
+			GetExclusionFilter(s.ctx, s.userKey).
+			Return(romancesValueObject.ExclusionFilter{}, false, nil),
+		s.romancesRepo.EXPECT().
+			ListRomancesForActiveUser(s.ctx, s.userKey, int32(exclusionFilterPageSize), "").
+			Return(romanceEntity.RomancesPage{Romances: []romanceEntity.Romance{voted, incoming}, NextCursor: "next"}, nil),
+		s.romancesRepo.EXPECT().
+			ListRomancesForActiveUser(s.ctx, s.userKey, int32(exclusionFilterPageSize), "next").
+			Return(romanceEntity.RomancesPage{Romances: []romanceEntity.Romance{blockedByPeer}}, nil),
+		s.exclusionFilters.EXPECT().
+			SaveExclusionFilter(s.ctx, s.userKey, gomock.Any()).
+			Return(nil),
+	)
+
+	filter, err := s.operation.Run(s.ctx, s.userKey, now)
+
+	s.Require().NoError(err)
+	s.Require().True(filter.MayContain(voted.ActiveUserVote.Id.PeerUserId()))
+	s.Require().True(filter.MayContain(blockedByPeer.ActiveUserVote.Id.PeerUserId()))
+	s.Require().False(filter.MayContain(incoming.ActiveUserVote.Id.PeerUserId()), "the vote of the peer does not exclude them")
+}
+
+func (s *GetExclusionFilterOperationUnitTestSuite) TestKeptFilterIsServed() {
+	kept, err := romancesValueObject.NewExclusionFilter(1, 0.01)
+	s.Require().NoError(err)
+
+	s.exclusionFilters.EXPECT().
+		GetExclusionFilter(s.ctx, s.userKey).
+		Return(kept, true, nil)
+
+	filter, err := s.operation.Run(s.ctx, s.userKey, time.Now())
+
+	s.Require().NoError(err)
+	s.Require().Equal(kept, filter)
+}
+
+func (s *GetExclusionFilterOperationUnitTestSuite) TestKeptFilterOverCapacityIsBuiltAgain() {
+	kept, err := romancesValueObject.NewExclusionFilter(1, 0.0001)
+	s.Require().NoError(err)
+	for range 50 {
+		kept.Add(uuidhelper.NewUUID(s.T()))
+	}
+	voted := s.romance()
+	voted.ActiveUserVote.VoteType = romancesValueObject.VoteTypeNo
+
+	gomock.InOrder(
+		s.exclusionFilters.EXPECT().
+			GetExclusionFilter(s.ctx, s.userKey).
+			Return(kept, true, nil),
+		s.romancesRepo.EXPECT().
+			ListRomancesForActiveUser(s.ctx, s.userKey, int32(exclusionFilterPageSize), "").
+			Return(romanceEntity.RomancesPage{Romances: []romanceEntity.Romance{voted}}, nil),
+		s.exclusionFilters.EXPECT().
+			SaveExclusionFilter(s.ctx, s.userKey, gomock.Any()).
+			Return(nil),
+	)
+
+	filter, err := s.operation.Run(s.ctx, s.userKey, time.Now())
+
+	s.Require().NoError(err)
+	s.Require().True(filter.MayContain(voted.ActiveUserVote.Id.PeerUserId()))
+	s.Require().LessOrEqual(filter.FalsePositiveRate(), 0.0001)
+}
+
+func (s *GetExclusionFilterOperationUnitTestSuite) TestListErrorIsNotKept() {
+	expectedErr := errors.New("database error")
+
+	s.exclusionFilters.EXPECT().
+		GetExclusionFilter(s.ctx, s.userKey).
+		Return(romancesValueObject.ExclusionFilter{}, false, nil)
+	s.romancesRepo.EXPECT().
+		ListRomancesForActiveUser(s.ctx, s.userKey, int32(exclusionFilterPageSize), "").
+		Return(romanceEntity.RomancesPage{}, expectedErr)
+
+	_, err := s.operation.Run(s.ctx, s.userKey, time.Now())
+
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *GetExclusionFilterOperationUnitTestSuite) TestKeptFilterErrorIsReturnedWithoutBuilding() {
+	expectedErr := errors.New("database error")
+
+	s.exclusionFilters.EXPECT().
+		GetExclusionFilter(s.ctx, s.userKey).
+		Return(romancesValueObject.ExclusionFilter{}, false, expectedErr)
+
+	_, err := s.operation.Run(s.ctx, s.userKey, time.Now())
+
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *GetExclusionFilterOperationUnitTestSuite) TestSaveErrorIsReturned() {
+	expectedErr := errors.New("database error")
+
+	gomock.InOrder(
+		s.exclusionFilters.EXPECT().
+			GetExclusionFilter(s.ctx, s.userKey).
+			Return(romancesValueObject.ExclusionFilter{}, false, nil),
+		s.romancesRepo.EXPECT().
+			ListRomancesForActiveUser(s.ctx, s.userKey, int32(exclusionFilterPageSize), "").
+			Return(romanceEntity.RomancesPage{}, nil),
+		s.exclusionFilters.EXPECT().
+			SaveExclusionFilter(s.ctx, s.userKey, gomock.Any()).
+			Return(expectedErr),
+	)
+
+	_, err := s.operation.Run(s.ctx, s.userKey, time.Now())
+
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *GetExclusionFilterOperationUnitTestSuite) TestInvalidFalsePositiveRateIsRejected() {
+	_, err := NewGetExclusionFilterOperation(s.romancesRepo, s.exclusionFilters, romanceDomain.NewEligibilityPolicy(config.Config{}), config.Config{})
+
+	s.Require().ErrorIs(err, romancesValueObject.ErrInvalidFalsePositiveRate)
+}
+
+func (s *GetExclusionFilterOperationUnitTestSuite) romance() romanceEntity.Romance {
+	voteId, err := sharedValueObject.NewVoteId(s.userKey.CountryId(), s.userKey.ActiveUserId(), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	romance := romanceEntity.CreateEmptyRomance(voteId)
+	romance.Version = 1
+	return romance
+}
@@ -22,3 +22,26 @@ func NewGetRomanceOperation(
 func (r *GetRomanceOperation) Run(ctx context.Context, voteId sharedValueObject.VoteId) (entity.Romance, error) {
 	return r.romancesRepository.GetRomance(ctx, voteId)
 }
+
+// RunVisible projects the romance the way the active user is allowed to see it, see visibleRomance
+func (r *GetRomanceOperation) RunVisible(ctx context.Context, voteId sharedValueObject.VoteId) (entity.Romance, error) {
+	romance, err := r.Run(ctx, voteId)
+	if err != nil {
+		return romance, err
+	}
+	return visibleRomance(romance), nil
+}
+
+// visibleRomance hides a romance blocked by either user as if nobody voted yet, only the own block of
+// the active user stays visible. A compliment of the peer is hidden until it is approved.
+func visibleRomance(romance entity.Romance) entity.Romance {
+	if romance.IsBlocked() {
+		visible := entity.CreateEmptyRomance(romance.ActiveUserVote.Id)
+		visible.ActiveUserBlockedAt = romance.ActiveUserBlockedAt
+		return visible
+	}
+	if !romance.PeerUserVote.Compliment.IsApproved() {
+		romance.PeerUserVote.Compliment = nil
+	}
+	return romance
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
 	"testing"
+	"time"
 
 	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
 	"github.com/stretchr/testify/suite"
@@ -73,3 +75,59 @@ func (s *GetRomanceOperationUnitTestSuite) TestGetRomanceSuccessfully() {
 	s.Require().NoError(err)
 	s.Require().Equal(expectedRomance, romance)
 }
+
+func (s *GetRomanceOperationUnitTestSuite) TestRunVisibleHidesUnapprovedPeerCompliment() {
+	testCases := []struct {
+		name    string
+		status  romancesValueObject.ModerationStatus
+		visible bool
+	}{
+		{name: "pending", status: romancesValueObject.ModerationStatusPending, visible: false},
+		{name: "rejected", status: romancesValueObject.ModerationStatusRejected, visible: false},
+		{name: "approved", status: romancesValueObject.ModerationStatusApproved, visible: true},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			storedRomance := romanceEntity.CreateEmptyRomance(s.voteId)
+			storedRomance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeCompliment
+			storedRomance.ActiveUserVote.Compliment = &romancesValueObject.Compliment{Text: "Hi", Status: romancesValueObject.ModerationStatusPending}
+			storedRomance.PeerUserVote.VoteType = romancesValueObject.VoteTypeCompliment
+			storedRomance.PeerUserVote.Compliment = &romancesValueObject.Compliment{Text: "Hello", Status: tc.status}
+
+			s.romancesRepo.EXPECT().
+				GetRomance(s.ctx, s.voteId).
+				Return(storedRomance, nil)
+
+			romance, err := s.newOperation().RunVisible(s.ctx, s.voteId)
+
+			s.Require().NoError(err)
+			s.Require().Equal(romancesValueObject.VoteTypeCompliment, romance.PeerUserVote.VoteType)
+			s.Require().Equal(tc.visible, romance.PeerUserVote.Compliment != nil)
+			s.Require().NotNil(romance.ActiveUserVote.Compliment, "the own compliment is visible in any status")
+		})
+	}
+}
+
+func (s *GetRomanceOperationUnitTestSuite) TestRunVisibleKeepsOnlyOwnBlock() {
+	blockedAt := time.Now()
+	storedRomance := romanceEntity.CreateEmptyRomance(s.voteId)
+	storedRomance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	storedRomance.PeerUserVote.VoteType = romancesValueObject.VoteTypeYes
+	storedRomance.Version = 3
+	storedRomance.ActiveUserBlockedAt = &blockedAt
+	storedRomance.PeerUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(storedRomance, nil)
+
+	romance, err := s.newOperation().RunVisible(s.ctx, s.voteId)
+
+	s.Require().NoError(err)
+	s.Require().Equal(romancesValueObject.RomanceStateBlocked, romance.State())
+	s.Require().Equal(romancesValueObject.VoteTypeEmpty, romance.PeerUserVote.VoteType)
+	s.Require().Equal(uint32(0), romance.Version)
+	s.Require().Nil(romance.PeerUserBlockedAt, "the block of the peer is never revealed")
+}
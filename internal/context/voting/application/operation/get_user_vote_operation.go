@@ -20,11 +20,17 @@ func NewGetUserVoteOperation(
 }
 
 func (r *GetUserVoteOperation) Run(ctx context.Context, voteId sharedValueObject.VoteId) (entity.Vote, error) {
+	vote, _, err := r.RunWithVersion(ctx, voteId)
+	return vote, err
+}
+
+// RunWithVersion also returns the version of the romance the vote belongs to
+func (r *GetUserVoteOperation) RunWithVersion(ctx context.Context, voteId sharedValueObject.VoteId) (entity.Vote, uint32, error) {
 	getRomanceOperation := NewGetRomanceOperation(r.romancesRepository)
-	romance, err := getRomanceOperation.Run(ctx, voteId)
+	romance, err := getRomanceOperation.RunVisible(ctx, voteId)
 	if err != nil {
-		return entity.Vote{}, err
+		return entity.Vote{}, 0, err
 	}
 
-	return romance.ActiveUserVote, nil
+	return romance.ActiveUserVote, romance.Version, nil
 }
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
 	"testing"
+	"time"
 
 	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
 	"github.com/stretchr/testify/suite"
@@ -73,3 +75,22 @@ func (s *GetUserVoteOperationUnitTestSuite) TestGetUserVoteSuccessfully() {
 	s.Require().NoError(err)
 	s.Require().Equal(romance.ActiveUserVote, vote)
 }
+
+func (s *GetUserVoteOperationUnitTestSuite) TestBlockedRomanceIsHidden() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	blockedAt := time.Now()
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.PeerUserBlockedAt = &blockedAt
+	romance.Version = 4
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	operation := s.newOperation()
+	vote, version, err := operation.RunWithVersion(s.ctx, s.voteId)
+
+	s.Require().NoError(err)
+	s.Require().Equal(romanceEntity.CreateEmptyRomance(s.voteId).ActiveUserVote, vote)
+	s.Require().Equal(uint32(0), version)
+}
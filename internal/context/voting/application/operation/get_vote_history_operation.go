@@ -0,0 +1,50 @@
+package operation
+
+import (
+	"context"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/callerscope"
+)
+
+type GetVoteHistoryOperation struct {
+	romancesRepository       romancesRepo.RomancesRepository
+	peerVoteProjectionPolicy *romanceDomain.PeerVoteProjectionPolicy
+}
+
+func NewGetVoteHistoryOperation(
+	romancesRepository romancesRepo.RomancesRepository,
+	peerVoteProjectionPolicy *romanceDomain.PeerVoteProjectionPolicy,
+) *GetVoteHistoryOperation {
+	return &GetVoteHistoryOperation{
+		romancesRepository:       romancesRepository,
+		peerVoteProjectionPolicy: peerVoteProjectionPolicy,
+	}
+}
+
+// Run returns the history of both users in the romance. Unrestricted callers read it as stored for support
+// and analysis, restricted callers get an empty history while the peer blocks them and the entries of the
+// peer are projected against the romance they are allowed to see.
+func (r *GetVoteHistoryOperation) Run(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	scope callerscope.Scope,
+	limit int32,
+	cursor string,
+) (entity.VoteHistoryPage, error) {
+	page, err := r.romancesRepository.GetVoteHistory(ctx, voteId, limit, cursor)
+	if err != nil || scope.IsUnrestricted() {
+		return page, err
+	}
+
+	romance, err := r.romancesRepository.GetRomance(ctx, voteId)
+	if err != nil {
+		return entity.VoteHistoryPage{}, err
+	}
+	if romance.IsBlocked() && romance.ActiveUserBlockedAt == nil {
+		return entity.VoteHistoryPage{Entries: []entity.VoteHistoryEntry{}}, nil
+	}
+	return r.peerVoteProjectionPolicy.ProjectHistory(visibleRomance(romance), page, false), nil
+}
@@ -0,0 +1,152 @@
+package operation
+
+import (
+	"context"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"testing"
+	"time"
+
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/callerscope"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type GetVoteHistoryOperationUnitTestSuite struct {
+	suite.Suite
+	voteId       sharedValueObject.VoteId
+	ctrl         *gomock.Controller
+	romancesRepo *mocks.MockRomancesRepository
+	ctx          context.Context
+}
+
+func TestGetVoteHistoryOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(GetVoteHistoryOperationUnitTestSuite))
+}
+
+func (s *GetVoteHistoryOperationUnitTestSuite) SetupSuite() {
+	voteId, err := sharedValueObject.NewVoteId(11, uuidhelper.NewUUID(s.T()), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.voteId = voteId
+	s.ctx = context.Background()
+}
+
+func (s *GetVoteHistoryOperationUnitTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.romancesRepo = mocks.NewMockRomancesRepository(s.ctrl)
+}
+
+func (s *GetVoteHistoryOperationUnitTestSuite) newOperation() *GetVoteHistoryOperation {
+	return NewGetVoteHistoryOperation(s.romancesRepo, romanceDomain.NewPeerVoteProjectionPolicy())
+}
+
+func (s *GetVoteHistoryOperationUnitTestSuite) TestGetVoteHistoryReturnsPage() {
+	page := romanceEntity.VoteHistoryPage{
+		Entries: []romanceEntity.VoteHistoryEntry{
+			{ActorId: s.voteId.PeerUserId(), Action: romancesValueObject.VoteActionAdd, ToVoteType: romancesValueObject.VoteTypeYes},
+		},
+		NextCursor: "next",
+	}
+
+	s.romancesRepo.EXPECT().
+		GetVoteHistory(s.ctx, s.voteId, int32(10), "cursor").
+		Return(page, nil)
+
+	result, err := s.newOperation().Run(s.ctx, s.voteId, callerscope.ScopeInternal, 10, "cursor")
+
+	s.Require().NoError(err)
+	s.Require().Equal(page, result)
+}
+
+func (s *GetVoteHistoryOperationUnitTestSuite) TestInvalidCursorIsRejected() {
+	s.romancesRepo.EXPECT().
+		GetVoteHistory(s.ctx, s.voteId, int32(10), "broken").
+		Return(romanceEntity.VoteHistoryPage{}, romanceDomain.ErrInvalidHistoryCursor)
+
+	_, err := s.newOperation().Run(s.ctx, s.voteId, callerscope.ScopeUser, 10, "broken")
+
+	s.Require().ErrorIs(err, romanceDomain.ErrInvalidHistoryCursor)
+}
+
+func (s *GetVoteHistoryOperationUnitTestSuite) TestPeerEntriesAreProjectedForUsers() {
+	page := romanceEntity.VoteHistoryPage{
+		Entries: []romanceEntity.VoteHistoryEntry{
+			{ActorId: s.voteId.PeerUserId(), Action: romancesValueObject.VoteActionAdd, ToVoteType: romancesValueObject.VoteTypeNo},
+			{ActorId: s.voteId.ActiveUserId(), Action: romancesValueObject.VoteActionAdd, ToVoteType: romancesValueObject.VoteTypeYes},
+		},
+	}
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.PeerUserVote.VoteType = romancesValueObject.VoteTypeNo
+
+	s.romancesRepo.EXPECT().
+		GetVoteHistory(s.ctx, s.voteId, int32(10), "").
+		Return(page, nil)
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	result, err := s.newOperation().Run(s.ctx, s.voteId, callerscope.ScopeUser, 10, "")
+
+	s.Require().NoError(err)
+	s.Require().Equal(page.Entries[1:], result.Entries)
+}
+
+func (s *GetVoteHistoryOperationUnitTestSuite) TestHistoryIsEmptyForUsersBlockedByThePeer() {
+	page := romanceEntity.VoteHistoryPage{
+		Entries: []romanceEntity.VoteHistoryEntry{
+			{ActorId: s.voteId.ActiveUserId(), Action: romancesValueObject.VoteActionAdd, ToVoteType: romancesValueObject.VoteTypeYes},
+		},
+		NextCursor: "next",
+	}
+	blockedAt := time.Now()
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.PeerUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		GetVoteHistory(s.ctx, s.voteId, int32(10), "").
+		Return(page, nil).
+		Times(2)
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	result, err := s.newOperation().Run(s.ctx, s.voteId, callerscope.ScopeUser, 10, "")
+	s.Require().NoError(err)
+	s.Require().Empty(result.Entries)
+	s.Require().Empty(result.NextCursor)
+
+	result, err = s.newOperation().Run(s.ctx, s.voteId, callerscope.ScopeAdmin, 10, "")
+	s.Require().NoError(err)
+	s.Require().Equal(page, result)
+}
+
+func (s *GetVoteHistoryOperationUnitTestSuite) TestPeerEntriesAreHiddenWhileTheActiveUserBlocks() {
+	page := romanceEntity.VoteHistoryPage{
+		Entries: []romanceEntity.VoteHistoryEntry{
+			{ActorId: s.voteId.PeerUserId(), Action: romancesValueObject.VoteActionAdd, ToVoteType: romancesValueObject.VoteTypeYes},
+			{ActorId: s.voteId.ActiveUserId(), Action: romancesValueObject.VoteActionAdd, ToVoteType: romancesValueObject.VoteTypeYes},
+		},
+	}
+	blockedAt := time.Now()
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.PeerUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.ActiveUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		GetVoteHistory(s.ctx, s.voteId, int32(10), "").
+		Return(page, nil)
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	result, err := s.newOperation().Run(s.ctx, s.voteId, callerscope.ScopeUser, 10, "")
+
+	s.Require().NoError(err)
+	s.Require().Equal(page.Entries[1:], result.Entries, "a blocked match is not mutual for the active user")
+}
@@ -0,0 +1,66 @@
+package operation
+
+import (
+	"context"
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	quotasRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/repository"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	userProvider "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider"
+	"time"
+)
+
+type GetVoteQuotasOperation struct {
+	quotasRepository quotasRepo.QuotasRepository
+	quotaPolicy      *quotaDomain.QuotaPolicy
+	timeZoneProvider userProvider.TimeZoneProvider
+}
+
+func NewGetVoteQuotasOperation(
+	quotasRepository quotasRepo.QuotasRepository,
+	quotaPolicy *quotaDomain.QuotaPolicy,
+	timeZoneProvider userProvider.TimeZoneProvider,
+) *GetVoteQuotasOperation {
+	return &GetVoteQuotasOperation{
+		quotasRepository: quotasRepository,
+		quotaPolicy:      quotaPolicy,
+		timeZoneProvider: timeZoneProvider,
+	}
+}
+
+// Run returns the quota of every premium vote type for the quota day containing now
+// and the user time zone the day is taken in
+func (r *GetVoteQuotasOperation) Run(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	now time.Time,
+) ([]entity.Quota, *time.Location, error) {
+	overrides, err := r.quotasRepository.GetOverrides(ctx, activeUserKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	location, err := r.timeZoneProvider.GetTimeZone(ctx, activeUserKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	day, resetAt := quotaDomain.Day(now, location)
+	usage, err := r.quotasRepository.GetUsage(ctx, activeUserKey, day)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	quotas := make([]entity.Quota, 0, len(quotaDomain.PremiumVoteTypes))
+	for _, voteType := range quotaDomain.PremiumVoteTypes {
+		quota := entity.Quota{
+			VoteType: voteType,
+			Used:     usage[voteType],
+			ResetAt:  resetAt,
+		}
+		if limit, limited := r.quotaPolicy.Limit(voteType, overrides); limited {
+			quota.Limit = &limit
+		}
+		quotas = append(quotas, quota)
+	}
+	return quotas, location, nil
+}
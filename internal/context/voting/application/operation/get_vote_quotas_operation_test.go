@@ -0,0 +1,120 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"testing"
+	"time"
+
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	userDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type GetVoteQuotasOperationUnitTestSuite struct {
+	suite.Suite
+	activeUserKey sharedValueObject.ActiveUserKey
+	ctrl          *gomock.Controller
+	quotasRepo    *mocks.MockQuotasRepository
+	quotaPolicy   *quotaDomain.QuotaPolicy
+	timeZones     *mocks.MockTimeZoneProvider
+	ctx           context.Context
+}
+
+func TestGetVoteQuotasOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(GetVoteQuotasOperationUnitTestSuite))
+}
+
+func (s *GetVoteQuotasOperationUnitTestSuite) SetupSuite() {
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.activeUserKey = activeUserKey
+	s.ctx = context.Background()
+
+	quotaPolicy, err := quotaDomain.NewQuotaPolicy(config.Config{
+		Voting: config.VotingConfig{DailyQuotas: map[string]uint32{"crush": 1, "compliment": 3}},
+	})
+	s.Require().NoError(err)
+	s.quotaPolicy = quotaPolicy
+}
+
+func (s *GetVoteQuotasOperationUnitTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.quotasRepo = mocks.NewMockQuotasRepository(s.ctrl)
+	s.timeZones = mocks.NewMockTimeZoneProvider(s.ctrl)
+}
+
+func (s *GetVoteQuotasOperationUnitTestSuite) newOperation() *GetVoteQuotasOperation {
+	return NewGetVoteQuotasOperation(s.quotasRepo, s.quotaPolicy, s.timeZones)
+}
+
+func (s *GetVoteQuotasOperationUnitTestSuite) TestGetOverridesReturnsError() {
+	expectedErr := errors.New("database error")
+
+	s.quotasRepo.EXPECT().
+		GetOverrides(s.ctx, s.activeUserKey).
+		Return(nil, expectedErr)
+
+	quotas, _, err := s.newOperation().Run(s.ctx, s.activeUserKey, time.Now())
+
+	s.Require().ErrorIs(err, expectedErr)
+	s.Require().Nil(quotas)
+}
+
+func (s *GetVoteQuotasOperationUnitTestSuite) TestQuotasUseOverridesAndUsage() {
+	location, err := time.LoadLocation("America/New_York")
+	s.Require().NoError(err)
+	// it is still March 9th in New York
+	now := time.Date(2025, 3, 10, 2, 30, 0, 0, time.UTC)
+	day, resetAt := quotaDomain.Day(now, location)
+
+	s.quotasRepo.EXPECT().
+		GetOverrides(s.ctx, s.activeUserKey).
+		Return(map[romancesValueObject.VoteType]uint32{romancesValueObject.VoteTypeCrush: 5}, nil)
+	s.timeZones.EXPECT().
+		GetTimeZone(s.ctx, s.activeUserKey).
+		Return(location, nil)
+	s.quotasRepo.EXPECT().
+		GetUsage(s.ctx, s.activeUserKey, day).
+		Return(map[romancesValueObject.VoteType]uint32{
+			romancesValueObject.VoteTypeCrush:      2,
+			romancesValueObject.VoteTypeCompliment: 4,
+		}, nil)
+
+	quotas, quotasLocation, err := s.newOperation().Run(s.ctx, s.activeUserKey, now)
+
+	s.Require().NoError(err)
+	s.Require().Equal(location, quotasLocation)
+	s.Require().Len(quotas, 2)
+
+	s.Require().Equal(romancesValueObject.VoteTypeCrush, quotas[0].VoteType)
+	s.Require().Equal(uint32(5), *quotas[0].Limit)
+	s.Require().Equal(uint32(3), *quotas[0].Remaining())
+
+	s.Require().Equal(romancesValueObject.VoteTypeCompliment, quotas[1].VoteType)
+	s.Require().Equal(uint32(3), *quotas[1].Limit)
+	s.Require().Equal(uint32(0), *quotas[1].Remaining())
+
+	s.Require().Equal(resetAt, quotas[0].ResetAt)
+	s.Require().True(time.Date(2025, 3, 10, 4, 0, 0, 0, time.UTC).Equal(resetAt))
+}
+
+func (s *GetVoteQuotasOperationUnitTestSuite) TestTimeZoneProviderReturnsError() {
+	s.quotasRepo.EXPECT().
+		GetOverrides(s.ctx, s.activeUserKey).
+		Return(nil, nil)
+	s.timeZones.EXPECT().
+		GetTimeZone(s.ctx, s.activeUserKey).
+		Return(nil, userDomain.ErrUserCheckUnavailable)
+
+	quotas, _, err := s.newOperation().Run(s.ctx, s.activeUserKey, time.Now())
+
+	s.Require().ErrorIs(err, userDomain.ErrUserCheckUnavailable)
+	s.Require().Nil(quotas)
+}
@@ -0,0 +1,70 @@
+package operation
+
+import (
+	"context"
+	"slices"
+
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/callerscope"
+)
+
+type ListRomancesOperation struct {
+	romancesRepository       romancesRepo.RomancesRepository
+	peerVoteProjectionPolicy *romanceDomain.PeerVoteProjectionPolicy
+}
+
+func NewListRomancesOperation(
+	romancesRepository romancesRepo.RomancesRepository,
+	peerVoteProjectionPolicy *romanceDomain.PeerVoteProjectionPolicy,
+) *ListRomancesOperation {
+	return &ListRomancesOperation{
+		romancesRepository:       romancesRepository,
+		peerVoteProjectionPolicy: peerVoteProjectionPolicy,
+	}
+}
+
+// Run lists the visible romances of the active user in the given states, all states match when none
+// are given. Romances blocked by the peer are left out and the states are matched after the peer vote
+// was projected for the caller scope. A page may need several reads when the states match rarely.
+func (r *ListRomancesOperation) Run(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	states []romancesValueObject.RomanceState,
+	scope callerscope.Scope,
+	limit int32,
+	cursor string,
+) (entity.RomancesPage, error) {
+	page := entity.RomancesPage{Romances: make([]entity.Romance, 0, limit)}
+	for {
+		storedPage, err := r.romancesRepository.ListRomancesForActiveUser(
+			ctx,
+			userKey,
+			limit-int32(len(page.Romances)),
+			cursor,
+		)
+		if err != nil {
+			return entity.RomancesPage{}, err
+		}
+
+		for _, romance := range storedPage.Romances {
+			if romance.IsBlocked() && romance.ActiveUserBlockedAt == nil {
+				continue
+			}
+			romance = r.peerVoteProjectionPolicy.Project(visibleRomance(romance), scope.IsUnrestricted())
+			if len(states) > 0 && !slices.Contains(states, romance.State()) {
+				continue
+			}
+			page.Romances = append(page.Romances, romance)
+		}
+
+		cursor = storedPage.NextCursor
+		if cursor == "" || len(page.Romances) >= int(limit) {
+			page.NextCursor = cursor
+			return page, nil
+		}
+	}
+}
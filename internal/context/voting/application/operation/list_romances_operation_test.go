@@ -0,0 +1,166 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"testing"
+	"time"
+
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/callerscope"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type ListRomancesOperationUnitTestSuite struct {
+	suite.Suite
+	userKey      sharedValueObject.ActiveUserKey
+	ctrl         *gomock.Controller
+	romancesRepo *mocks.MockRomancesRepository
+	ctx          context.Context
+}
+
+func TestListRomancesOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(ListRomancesOperationUnitTestSuite))
+}
+
+func (s *ListRomancesOperationUnitTestSuite) SetupSuite() {
+	userKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.userKey = userKey
+	s.ctx = context.Background()
+}
+
+func (s *ListRomancesOperationUnitTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.romancesRepo = mocks.NewMockRomancesRepository(s.ctrl)
+}
+
+func (s *ListRomancesOperationUnitTestSuite) TestListAllStates() {
+	page := romanceEntity.RomancesPage{
+		Romances: []romanceEntity.Romance{
+			s.romance(romancesValueObject.VoteTypeYes, romancesValueObject.VoteTypeEmpty),
+			s.romance(romancesValueObject.VoteTypeNo, romancesValueObject.VoteTypeYes),
+		},
+		NextCursor: "next",
+	}
+
+	s.romancesRepo.EXPECT().
+		ListRomancesForActiveUser(s.ctx, s.userKey, int32(2), "cursor").
+		Return(page, nil)
+
+	result, err := s.newOperation().Run(s.ctx, s.userKey, nil, callerscope.ScopeInternal, 2, "cursor")
+
+	s.Require().NoError(err)
+	s.Require().Equal(page, result)
+}
+
+func (s *ListRomancesOperationUnitTestSuite) TestStatesFilterReadsUntilThePageIsFull() {
+	matched := s.romance(romancesValueObject.VoteTypeYes, romancesValueObject.VoteTypeCrush)
+	secondMatched := s.romance(romancesValueObject.VoteTypeCompliment, romancesValueObject.VoteTypeYes)
+
+	gomock.InOrder(
+		s.romancesRepo.EXPECT().
+			ListRomancesForActiveUser(s.ctx, s.userKey, int32(2), "").
+			Return(romanceEntity.RomancesPage{
+				Romances: []romanceEntity.Romance{
+					s.romance(romancesValueObject.VoteTypeNo, romancesValueObject.VoteTypeYes),
+					matched,
+				},
+				NextCursor: "first",
+			}, nil),
+		s.romancesRepo.EXPECT().
+			ListRomancesForActiveUser(s.ctx, s.userKey, int32(1), "first").
+			Return(romanceEntity.RomancesPage{
+				Romances:   []romanceEntity.Romance{secondMatched},
+				NextCursor: "second",
+			}, nil),
+	)
+
+	result, err := s.newOperation().Run(
+		s.ctx,
+		s.userKey,
+		[]romancesValueObject.RomanceState{romancesValueObject.RomanceStateMatched},
+		callerscope.ScopeUser,
+		2,
+		"",
+	)
+
+	s.Require().NoError(err)
+	s.Require().Equal([]romanceEntity.Romance{matched, secondMatched}, result.Romances)
+	s.Require().Equal("second", result.NextCursor)
+}
+
+func (s *ListRomancesOperationUnitTestSuite) TestBlockedRomancesAreProjected() {
+	blockedAt := time.Now()
+	blockedByPeer := s.romance(romancesValueObject.VoteTypeYes, romancesValueObject.VoteTypeYes)
+	blockedByPeer.PeerUserBlockedAt = &blockedAt
+	blockedByActiveUser := s.romance(romancesValueObject.VoteTypeEmpty, romancesValueObject.VoteTypeYes)
+	blockedByActiveUser.ActiveUserBlockedAt = &blockedAt
+
+	s.romancesRepo.EXPECT().
+		ListRomancesForActiveUser(s.ctx, s.userKey, int32(10), "").
+		Return(romanceEntity.RomancesPage{
+			Romances: []romanceEntity.Romance{blockedByPeer, blockedByActiveUser},
+		}, nil)
+
+	result, err := s.newOperation().Run(s.ctx, s.userKey, nil, callerscope.ScopeUser, 10, "")
+
+	s.Require().NoError(err)
+	s.Require().Empty(result.NextCursor)
+	s.Require().Len(result.Romances, 1, "a romance blocked by the peer is hidden")
+	s.Require().Equal(romancesValueObject.RomanceStateBlocked, result.Romances[0].State())
+	s.Require().Equal(romancesValueObject.VoteTypeEmpty, result.Romances[0].PeerUserVote.VoteType)
+}
+
+func (s *ListRomancesOperationUnitTestSuite) TestStatesAreMatchedAfterThePeerVoteIsProjected() {
+	rejectedByPeer := s.romance(romancesValueObject.VoteTypeYes, romancesValueObject.VoteTypeNo)
+
+	s.romancesRepo.EXPECT().
+		ListRomancesForActiveUser(s.ctx, s.userKey, int32(10), "").
+		Return(romanceEntity.RomancesPage{Romances: []romanceEntity.Romance{rejectedByPeer}}, nil).
+		Times(2)
+
+	rejected := []romancesValueObject.RomanceState{romancesValueObject.RomanceStateRejected}
+	result, err := s.newOperation().Run(s.ctx, s.userKey, rejected, callerscope.ScopeUser, 10, "")
+	s.Require().NoError(err)
+	s.Require().Empty(result.Romances, "a restricted caller can not find out about the no of the peer")
+
+	result, err = s.newOperation().Run(s.ctx, s.userKey, rejected, callerscope.ScopeAdmin, 10, "")
+	s.Require().NoError(err)
+	s.Require().Equal([]romanceEntity.Romance{rejectedByPeer}, result.Romances)
+}
+
+func (s *ListRomancesOperationUnitTestSuite) TestListReturnsError() {
+	expectedErr := errors.New("database error")
+
+	s.romancesRepo.EXPECT().
+		ListRomancesForActiveUser(s.ctx, s.userKey, int32(10), "").
+		Return(romanceEntity.RomancesPage{}, expectedErr)
+
+	_, err := s.newOperation().Run(s.ctx, s.userKey, nil, callerscope.ScopeUser, 10, "")
+
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *ListRomancesOperationUnitTestSuite) newOperation() *ListRomancesOperation {
+	return NewListRomancesOperation(s.romancesRepo, romanceDomain.NewPeerVoteProjectionPolicy())
+}
+
+func (s *ListRomancesOperationUnitTestSuite) romance(
+	activeUserVoteType romancesValueObject.VoteType,
+	peerUserVoteType romancesValueObject.VoteType,
+) romanceEntity.Romance {
+	voteId, err := sharedValueObject.NewVoteId(s.userKey.CountryId(), s.userKey.ActiveUserId(), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	romance := romanceEntity.CreateEmptyRomance(voteId)
+	romance.ActiveUserVote.VoteType = activeUserVoteType
+	romance.PeerUserVote.VoteType = peerUserVoteType
+	romance.Version = 1
+	return romance
+}
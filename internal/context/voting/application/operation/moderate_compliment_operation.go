@@ -0,0 +1,79 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ModerateComplimentOperation applies the decision of a moderation callback to the compliment
+// of the active user, an approved compliment becomes visible to the peer
+type ModerateComplimentOperation struct {
+	romancesRepository romancesRepo.RomancesRepository
+	logger             platform.Logger
+}
+
+func NewModerateComplimentOperation(
+	romancesRepository romancesRepo.RomancesRepository,
+	logger platform.Logger,
+) *ModerateComplimentOperation {
+	return &ModerateComplimentOperation{
+		romancesRepository: romancesRepository,
+		logger:             logger,
+	}
+}
+
+// Run returns ErrComplimentNotFound when the compliment was removed or replaced since it was
+// submitted, repeating the current status returns the vote unchanged
+func (r *ModerateComplimentOperation) Run(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	complimentId uuid.UUID,
+	status romancesValueObject.ModerationStatus,
+	now time.Time,
+) (entity.Vote, error) {
+	if status == romancesValueObject.ModerationStatusPending {
+		return entity.Vote{}, romanceDomain.ErrModerationStatus
+	}
+
+	tries := 0
+
+	getRomanceOperation := NewGetRomanceOperation(r.romancesRepository)
+	for {
+		romance, err := getRomanceOperation.Run(ctx, voteId)
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("GetRomance error: %+v", err))
+			return entity.Vote{}, err
+		}
+
+		compliment := romance.ActiveUserVote.Compliment
+		if compliment == nil || compliment.Id != complimentId {
+			return entity.Vote{}, romanceDomain.ErrComplimentNotFound
+		}
+
+		if compliment.Status == status {
+			return romance.ActiveUserVote, nil
+		}
+
+		romance, err = r.romancesRepository.ModerateComplimentInRomance(ctx, romance, status, now)
+		if err != nil {
+			if errors.Is(err, romanceDomain.ErrVersionConflict) && tries < config.DynamoDbVersionConflictRetriesCount {
+				tries += 1
+				continue
+			}
+			r.logger.Error(fmt.Sprintf("ModerateComplimentInRomance error: %+v", err))
+			return entity.Vote{}, err
+		}
+
+		return romance.ActiveUserVote, nil
+	}
+}
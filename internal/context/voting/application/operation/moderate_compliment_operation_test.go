@@ -0,0 +1,113 @@
+package operation
+
+import (
+	"context"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type ModerateComplimentOperationUnitTestSuite struct {
+	suite.Suite
+	voteId       sharedValueObject.VoteId
+	ctrl         *gomock.Controller
+	romancesRepo *mocks.MockRomancesRepository
+	romance      romanceEntity.Romance
+	ctx          context.Context
+}
+
+func TestModerateComplimentOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(ModerateComplimentOperationUnitTestSuite))
+}
+
+func (s *ModerateComplimentOperationUnitTestSuite) SetupSuite() {
+	voteId, err := sharedValueObject.NewVoteId(11, uuidhelper.NewUUID(s.T()), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.voteId = voteId
+	s.ctx = context.Background()
+}
+
+func (s *ModerateComplimentOperationUnitTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.romancesRepo = mocks.NewMockRomancesRepository(s.ctrl)
+
+	s.romance = romanceEntity.CreateEmptyRomance(s.voteId)
+	s.romance.Version = 3
+	s.romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeCompliment
+	s.romance.ActiveUserVote.Compliment = &romancesValueObject.Compliment{
+		Id:     uuidhelper.NewUUID(s.T()),
+		Text:   "Lovely smile",
+		Status: romancesValueObject.ModerationStatusPending,
+	}
+}
+
+func (s *ModerateComplimentOperationUnitTestSuite) newOperation() *ModerateComplimentOperation {
+	return NewModerateComplimentOperation(s.romancesRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func (s *ModerateComplimentOperationUnitTestSuite) TestApproveRetriesOnVersionConflict() {
+	now := time.Now()
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(s.romance, nil).
+		Times(2)
+
+	approved := s.romance
+	approved.ActiveUserVote.Compliment = &romancesValueObject.Compliment{
+		Id:          s.romance.ActiveUserVote.Compliment.Id,
+		Text:        "Lovely smile",
+		Status:      romancesValueObject.ModerationStatusApproved,
+		ModeratedAt: &now,
+	}
+	gomock.InOrder(
+		s.romancesRepo.EXPECT().
+			ModerateComplimentInRomance(s.ctx, s.romance, romancesValueObject.ModerationStatusApproved, now).
+			Return(romanceEntity.Romance{}, romanceDomain.ErrVersionConflict),
+		s.romancesRepo.EXPECT().
+			ModerateComplimentInRomance(s.ctx, s.romance, romancesValueObject.ModerationStatusApproved, now).
+			Return(approved, nil),
+	)
+
+	vote, err := s.newOperation().Run(s.ctx, s.voteId, s.romance.ActiveUserVote.Compliment.Id, romancesValueObject.ModerationStatusApproved, now)
+
+	s.Require().NoError(err)
+	s.Require().True(vote.Compliment.IsApproved())
+}
+
+func (s *ModerateComplimentOperationUnitTestSuite) TestReplacedComplimentIsNotFound() {
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(s.romance, nil)
+
+	_, err := s.newOperation().Run(s.ctx, s.voteId, uuidhelper.NewUUID(s.T()), romancesValueObject.ModerationStatusRejected, time.Now())
+
+	s.Require().ErrorIs(err, romanceDomain.ErrComplimentNotFound)
+}
+
+func (s *ModerateComplimentOperationUnitTestSuite) TestRepeatedDecisionIsNotWrittenAgain() {
+	s.romance.ActiveUserVote.Compliment.Status = romancesValueObject.ModerationStatusRejected
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(s.romance, nil)
+
+	vote, err := s.newOperation().Run(s.ctx, s.voteId, s.romance.ActiveUserVote.Compliment.Id, romancesValueObject.ModerationStatusRejected, time.Now())
+
+	s.Require().NoError(err)
+	s.Require().Equal(s.romance.ActiveUserVote, vote)
+}
+
+func (s *ModerateComplimentOperationUnitTestSuite) TestPendingIsNotADecision() {
+	_, err := s.newOperation().Run(s.ctx, s.voteId, s.romance.ActiveUserVote.Compliment.Id, romancesValueObject.ModerationStatusPending, time.Now())
+
+	s.Require().ErrorIs(err, romanceDomain.ErrModerationStatus)
+}
@@ -0,0 +1,63 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	rewindDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
+	lastVotesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/repository"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+)
+
+// RecordLastVoteOperation remembers accepted votes so they can be rewound, failures are only
+// logged because the vote itself is already stored.
+type RecordLastVoteOperation struct {
+	lastVotesRepository lastVotesRepo.LastVotesRepository
+	rewindPolicy        *rewindDomain.RewindPolicy
+	logger              platform.Logger
+}
+
+func NewRecordLastVoteOperation(
+	lastVotesRepository lastVotesRepo.LastVotesRepository,
+	rewindPolicy *rewindDomain.RewindPolicy,
+	logger platform.Logger,
+) *RecordLastVoteOperation {
+	return &RecordLastVoteOperation{
+		lastVotesRepository: lastVotesRepository,
+		rewindPolicy:        rewindPolicy,
+		logger:              logger,
+	}
+}
+
+func (r *RecordLastVoteOperation) Run(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	vote entity.LastVote,
+) {
+	if !r.rewindPolicy.IsEnabled() {
+		return
+	}
+
+	tries := 0
+	for {
+		lastVotes, err := r.lastVotesRepository.GetLastVotes(ctx, activeUserKey)
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("GetLastVotes error: %+v", err))
+			return
+		}
+
+		lastVotes.Push(vote, r.rewindPolicy.HistorySize())
+		err = r.lastVotesRepository.SaveLastVotes(ctx, lastVotes, r.rewindPolicy.ExpiresAt(vote.RecordedAt))
+		if err != nil {
+			if errors.Is(err, rewindDomain.ErrVersionConflict) && tries < config.DynamoDbVersionConflictRetriesCount {
+				tries += 1
+				continue
+			}
+			r.logger.Error(fmt.Sprintf("SaveLastVotes error: %+v", err))
+		}
+		return
+	}
+}
@@ -0,0 +1,182 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	countersRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/repository"
+	countersValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
+	rewindDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
+	rewindEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
+	lastVotesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/repository"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"time"
+)
+
+// RewindVoteOperation undoes the most recent vote of the active user while it is in the
+// rewind window and still the current vote in its romance.
+type RewindVoteOperation struct {
+	romancesRepository         romancesRepo.RomancesRepository
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository
+	countersRepository         countersRepo.CountersRepository
+	lastVotesRepository        lastVotesRepo.LastVotesRepository
+	rewindPolicy               *rewindDomain.RewindPolicy
+	consumeQuota               *ConsumeVoteQuotaOperation
+	logger                     platform.Logger
+}
+
+func NewRewindVoteOperation(
+	romancesRepository romancesRepo.RomancesRepository,
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository,
+	countersRepository countersRepo.CountersRepository,
+	lastVotesRepository lastVotesRepo.LastVotesRepository,
+	rewindPolicy *rewindDomain.RewindPolicy,
+	consumeQuota *ConsumeVoteQuotaOperation,
+	logger platform.Logger,
+) *RewindVoteOperation {
+	return &RewindVoteOperation{
+		romancesRepository:         romancesRepository,
+		exclusionFiltersRepository: exclusionFiltersRepository,
+		countersRepository:         countersRepository,
+		lastVotesRepository:        lastVotesRepository,
+		rewindPolicy:               rewindPolicy,
+		consumeQuota:               consumeQuota,
+		logger:                     logger,
+	}
+}
+
+// Run returns the restored vote, its type is empty when the rewound vote was the first one
+func (r *RewindVoteOperation) Run(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	now time.Time,
+) (entity.Vote, uint32, error) {
+	lastVotes, err := r.lastVotesRepository.GetLastVotes(ctx, activeUserKey)
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("GetLastVotes error: %+v", err))
+		return entity.Vote{}, 0, err
+	}
+
+	lastVote, ok := lastVotes.Newest()
+	if !ok || !r.rewindPolicy.CanRewind(lastVote, now) {
+		return entity.Vote{}, 0, rewindDomain.ErrNothingToRewind
+	}
+
+	voteId, err := lastVote.VoteId(activeUserKey)
+	if err != nil {
+		return entity.Vote{}, 0, err
+	}
+
+	tries := 0
+
+	getRomanceOperation := NewGetRomanceOperation(r.romancesRepository)
+	for {
+		romance, err := getRomanceOperation.Run(ctx, voteId)
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("GetRomance error: %+v", err))
+			return entity.Vote{}, 0, err
+		}
+
+		if romance.IsBlocked() {
+			return entity.Vote{}, 0, romanceDomain.ErrRomanceBlocked
+		}
+
+		if romance.IsRevoteBanned(now) {
+			return entity.Vote{}, 0, romanceDomain.ErrUnmatched
+		}
+
+		// the vote was already replaced, e.g. by a vote from another device
+		if !lastVote.IsCurrent(romance.ActiveUserVote) {
+			return entity.Vote{}, 0, rewindDomain.ErrNothingToRewind
+		}
+
+		// an unmatch stored after the vote wins over the one the vote ended
+		previousUnmatch := lastVote.PreviousUnmatch
+		if romance.IsUnmatched() {
+			previousUnmatch = nil
+		}
+
+		wasMatched := romance.IsMatched()
+		romance, err = r.romancesRepository.RestoreActiveUserVoteInRomance(ctx, romance, lastVote.PreviousVote, previousUnmatch)
+		if err != nil {
+			if errors.Is(err, romanceDomain.ErrVersionConflict) && tries < config.DynamoDbVersionConflictRetriesCount {
+				tries += 1
+				continue
+			}
+			r.logger.Error(fmt.Sprintf("RestoreActiveUserVoteInRomance error: %+v", err))
+			return entity.Vote{}, 0, err
+		}
+
+		r.forget(ctx, activeUserKey, lastVote)
+		r.exclusionFiltersRepository.DeleteExclusionFilter(ctx, activeUserKey)
+		r.reverseCounters(ctx, voteId, lastVote, wasMatched, romance.IsMatched())
+		r.consumeQuota.Refund(ctx, lastVote.Consumption)
+
+		return romance.ActiveUserVote, romance.Version, nil
+	}
+}
+
+// forget drops the rewound vote so the next rewind undoes the vote before it, a vote which
+// stays in the buffer can not be rewound twice because it is no longer current.
+func (r *RewindVoteOperation) forget(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	lastVote rewindEntity.LastVote,
+) {
+	tries := 0
+	for {
+		lastVotes, err := r.lastVotesRepository.GetLastVotes(ctx, activeUserKey)
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("GetLastVotes error: %+v", err))
+			return
+		}
+
+		lastVotes.Remove(lastVote)
+		newest, ok := lastVotes.Newest()
+		if !ok {
+			newest = lastVote
+		}
+		err = r.lastVotesRepository.SaveLastVotes(ctx, lastVotes, r.rewindPolicy.ExpiresAt(newest.RecordedAt))
+		if err != nil {
+			if errors.Is(err, rewindDomain.ErrVersionConflict) && tries < config.DynamoDbVersionConflictRetriesCount {
+				tries += 1
+				continue
+			}
+			r.logger.Error(fmt.Sprintf("SaveLastVotes error: %+v", err))
+		}
+		return
+	}
+}
+
+func (r *RewindVoteOperation) reverseCounters(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	lastVote rewindEntity.LastVote,
+	wasMatched bool,
+	isMatched bool,
+) {
+	counterUpdateGroup, err := countersValueObject.NewCounterUpdateGroup(lastVote.RecordedAt)
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("NewCounterUpdateGroup error: %+v", err))
+		return
+	}
+
+	if lastVote.CountedYes {
+		r.countersRepository.DecrYesCounters(ctx, voteId, counterUpdateGroup)
+	}
+	if lastVote.CountedNo {
+		r.countersRepository.DecrNoCounters(ctx, voteId, counterUpdateGroup)
+	}
+
+	if wasMatched && !isMatched {
+		r.countersRepository.DecrMatchCounters(ctx, voteId)
+	}
+	if !wasMatched && isMatched {
+		r.countersRepository.IncrMatchCounters(ctx, voteId, counterUpdateGroup)
+	}
+}
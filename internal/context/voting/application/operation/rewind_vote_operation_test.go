@@ -0,0 +1,313 @@
+package operation
+
+import (
+	"context"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	quotaEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	rewindDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
+	rewindEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type RewindVoteOperationUnitTestSuite struct {
+	suite.Suite
+	voteId           sharedValueObject.VoteId
+	ctrl             *gomock.Controller
+	romancesRepo     *mocks.MockRomancesRepository
+	exclusionFilters *mocks.MockExclusionFiltersRepository
+	countersRepo     *mocks.MockCountersRepository
+	lastVotesRepo    *mocks.MockLastVotesRepository
+	quotasRepo       *mocks.MockQuotasRepository
+	logger           *slog.Logger
+	ctx              context.Context
+}
+
+func TestRewindVoteOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(RewindVoteOperationUnitTestSuite))
+}
+
+func (s *RewindVoteOperationUnitTestSuite) SetupSuite() {
+	voteId, err := sharedValueObject.NewVoteId(11, uuidhelper.NewUUID(s.T()), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.voteId = voteId
+	s.ctx = context.Background()
+	s.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func (s *RewindVoteOperationUnitTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.romancesRepo = mocks.NewMockRomancesRepository(s.ctrl)
+	s.exclusionFilters = mocks.NewMockExclusionFiltersRepository(s.ctrl)
+	s.exclusionFilters.EXPECT().DeleteExclusionFilter(gomock.Any(), gomock.Any()).AnyTimes()
+	s.countersRepo = mocks.NewMockCountersRepository(s.ctrl)
+	s.lastVotesRepo = mocks.NewMockLastVotesRepository(s.ctrl)
+	s.quotasRepo = mocks.NewMockQuotasRepository(s.ctrl)
+}
+
+func (s *RewindVoteOperationUnitTestSuite) newOperation() *RewindVoteOperation {
+	rewindPolicy := rewindDomain.NewRewindPolicy(config.Config{
+		Voting: config.VotingConfig{RewindWindow: 5 * time.Minute, RewindHistorySize: 5},
+	})
+	quotaPolicy, err := quotaDomain.NewQuotaPolicy(config.Config{})
+	s.Require().NoError(err)
+	consumeQuota := NewConsumeVoteQuotaOperation(s.quotasRepo, quotaPolicy, mocks.NewMockTimeZoneProvider(s.ctrl), s.logger)
+	return NewRewindVoteOperation(s.romancesRepo, s.exclusionFilters, s.countersRepo, s.lastVotesRepo, rewindPolicy, consumeQuota, s.logger)
+}
+
+func (s *RewindVoteOperationUnitTestSuite) newLastVotes(votes ...rewindEntity.LastVote) rewindEntity.LastVotes {
+	return rewindEntity.LastVotes{
+		ActiveUserKey: s.voteId.ActiveUserKey(),
+		Votes:         votes,
+		Version:       1,
+	}
+}
+
+func (s *RewindVoteOperationUnitTestSuite) newVotedRomance(voteType romancesValueObject.VoteType, votedAt time.Time) romanceEntity.Romance {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.ActiveUserVote.VoteType = voteType
+	romance.ActiveUserVote.VotedAt = &votedAt
+	romance.Version = 1
+	return romance
+}
+
+func (s *RewindVoteOperationUnitTestSuite) TestRewindRestoresEmptyVoteAndDecrementsCounters() {
+	now := time.Now()
+	votedAt := now.Add(-time.Minute)
+	lastVote := rewindEntity.LastVote{
+		PeerId:       s.voteId.PeerUserId(),
+		VoteType:     romancesValueObject.VoteTypeYes,
+		VotedAt:      votedAt,
+		PreviousVote: romanceEntity.Vote{Id: s.voteId},
+		RecordedAt:   votedAt,
+		CountedYes:   true,
+	}
+	romance := s.newVotedRomance(romancesValueObject.VoteTypeYes, votedAt)
+	restoredRomance := romanceEntity.CreateEmptyRomance(s.voteId)
+	restoredRomance.Version = 2
+
+	s.lastVotesRepo.EXPECT().
+		GetLastVotes(s.ctx, s.voteId.ActiveUserKey()).
+		Return(s.newLastVotes(lastVote), nil).
+		Times(2)
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.romancesRepo.EXPECT().
+		RestoreActiveUserVoteInRomance(s.ctx, romance, lastVote.PreviousVote, nil).
+		Return(restoredRomance, nil)
+	s.lastVotesRepo.EXPECT().
+		SaveLastVotes(s.ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, lastVotes rewindEntity.LastVotes, _ time.Time) error {
+			s.Require().Empty(lastVotes.Votes)
+			return nil
+		})
+	s.countersRepo.EXPECT().DecrYesCounters(s.ctx, s.voteId, gomock.Any())
+
+	vote, _, err := s.newOperation().Run(s.ctx, s.voteId.ActiveUserKey(), now)
+
+	s.Require().NoError(err)
+	s.Require().True(vote.VoteType.IsEmpty())
+}
+
+func (s *RewindVoteOperationUnitTestSuite) TestRewindOfCrossCountryVoteUsesThePeerCountry() {
+	now := time.Now()
+	votedAt := now.Add(-time.Minute)
+	voteId, err := sharedValueObject.NewCrossCountryVoteId(s.voteId.CountryId(), s.voteId.ActiveUserId(), 22, s.voteId.PeerUserId())
+	s.Require().NoError(err)
+	lastVote := rewindEntity.LastVote{
+		PeerId:        voteId.PeerUserId(),
+		PeerCountryId: 22,
+		VoteType:      romancesValueObject.VoteTypeYes,
+		VotedAt:       votedAt,
+		PreviousVote:  romanceEntity.Vote{Id: voteId},
+		RecordedAt:    votedAt,
+		CountedYes:    true,
+	}
+	romance := romanceEntity.CreateEmptyRomance(voteId)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.ActiveUserVote.VotedAt = &votedAt
+	romance.Version = 1
+	restoredRomance := romanceEntity.CreateEmptyRomance(voteId)
+	restoredRomance.Version = 2
+
+	s.lastVotesRepo.EXPECT().
+		GetLastVotes(s.ctx, s.voteId.ActiveUserKey()).
+		Return(s.newLastVotes(lastVote), nil).
+		Times(2)
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, voteId).
+		Return(romance, nil)
+	s.romancesRepo.EXPECT().
+		RestoreActiveUserVoteInRomance(s.ctx, romance, lastVote.PreviousVote, nil).
+		Return(restoredRomance, nil)
+	s.lastVotesRepo.EXPECT().SaveLastVotes(s.ctx, gomock.Any(), gomock.Any())
+	s.countersRepo.EXPECT().DecrYesCounters(s.ctx, voteId, gomock.Any())
+
+	_, _, err = s.newOperation().Run(s.ctx, s.voteId.ActiveUserKey(), now)
+
+	s.Require().NoError(err)
+}
+
+func (s *RewindVoteOperationUnitTestSuite) TestRewindOfMatchingVoteDecrementsMatchCounters() {
+	now := time.Now()
+	votedAt := now.Add(-time.Minute)
+	previousVotedAt := now.Add(-time.Hour)
+	lastVote := rewindEntity.LastVote{
+		PeerId:   s.voteId.PeerUserId(),
+		VoteType: romancesValueObject.VoteTypeCrush,
+		VotedAt:  votedAt,
+		PreviousVote: romanceEntity.Vote{
+			Id:       s.voteId,
+			VoteType: romancesValueObject.VoteTypeNo,
+			VotedAt:  &previousVotedAt,
+		},
+		RecordedAt: votedAt,
+	}
+	romance := s.newVotedRomance(romancesValueObject.VoteTypeCrush, votedAt)
+	romance.PeerUserVote.VoteType = romancesValueObject.VoteTypeYes
+	restoredRomance := romance
+	restoredRomance.ActiveUserVote = lastVote.PreviousVote
+	restoredRomance.Version = 2
+
+	s.lastVotesRepo.EXPECT().
+		GetLastVotes(s.ctx, s.voteId.ActiveUserKey()).
+		Return(s.newLastVotes(lastVote), nil).
+		Times(2)
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.romancesRepo.EXPECT().
+		RestoreActiveUserVoteInRomance(s.ctx, romance, lastVote.PreviousVote, nil).
+		Return(restoredRomance, nil)
+	s.lastVotesRepo.EXPECT().SaveLastVotes(s.ctx, gomock.Any(), gomock.Any()).Return(nil)
+	s.countersRepo.EXPECT().DecrMatchCounters(s.ctx, s.voteId)
+
+	vote, _, err := s.newOperation().Run(s.ctx, s.voteId.ActiveUserKey(), now)
+
+	s.Require().NoError(err)
+	s.Require().Equal(romancesValueObject.VoteTypeNo, vote.VoteType)
+}
+
+func (s *RewindVoteOperationUnitTestSuite) TestRewindRestoresUnmatchAndRefundsQuota() {
+	now := time.Now()
+	votedAt := now.Add(-time.Minute)
+	unmatch := &romanceEntity.Unmatch{
+		UnmatchedBy:       s.voteId.PeerUserId(),
+		UnmatchedAt:       now.Add(-48 * time.Hour),
+		RevoteBannedUntil: now.Add(-24 * time.Hour),
+	}
+	consumption := &quotaEntity.Consumption{
+		ActiveUserKey: s.voteId.ActiveUserKey(),
+		VoteType:      romancesValueObject.VoteTypeCrush,
+		Day:           now.Truncate(24 * time.Hour),
+	}
+	lastVote := rewindEntity.LastVote{
+		PeerId:          s.voteId.PeerUserId(),
+		VoteType:        romancesValueObject.VoteTypeCrush,
+		VotedAt:         votedAt,
+		PreviousVote:    romanceEntity.Vote{Id: s.voteId},
+		RecordedAt:      votedAt,
+		PreviousUnmatch: unmatch,
+		Consumption:     consumption,
+	}
+	romance := s.newVotedRomance(romancesValueObject.VoteTypeCrush, votedAt)
+	restoredRomance := romanceEntity.CreateEmptyRomance(s.voteId)
+	restoredRomance.Unmatch = unmatch
+	restoredRomance.Version = 2
+
+	s.lastVotesRepo.EXPECT().
+		GetLastVotes(s.ctx, s.voteId.ActiveUserKey()).
+		Return(s.newLastVotes(lastVote), nil).
+		Times(2)
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.romancesRepo.EXPECT().
+		RestoreActiveUserVoteInRomance(s.ctx, romance, lastVote.PreviousVote, unmatch).
+		Return(restoredRomance, nil)
+	s.lastVotesRepo.EXPECT().SaveLastVotes(s.ctx, gomock.Any(), gomock.Any()).Return(nil)
+	s.quotasRepo.EXPECT().Refund(s.ctx, *consumption).Return(nil)
+
+	_, version, err := s.newOperation().Run(s.ctx, s.voteId.ActiveUserKey(), now)
+
+	s.Require().NoError(err)
+	s.Require().Equal(uint32(2), version)
+}
+
+func (s *RewindVoteOperationUnitTestSuite) TestVoteOutsideWindowIsNotRewound() {
+	now := time.Now()
+	votedAt := now.Add(-10 * time.Minute)
+	lastVote := rewindEntity.LastVote{
+		PeerId:     s.voteId.PeerUserId(),
+		VoteType:   romancesValueObject.VoteTypeYes,
+		VotedAt:    votedAt,
+		RecordedAt: votedAt,
+	}
+
+	s.lastVotesRepo.EXPECT().
+		GetLastVotes(s.ctx, s.voteId.ActiveUserKey()).
+		Return(s.newLastVotes(lastVote), nil)
+
+	_, _, err := s.newOperation().Run(s.ctx, s.voteId.ActiveUserKey(), now)
+
+	s.Require().ErrorIs(err, rewindDomain.ErrNothingToRewind)
+}
+
+func (s *RewindVoteOperationUnitTestSuite) TestReplacedVoteIsNotRewound() {
+	now := time.Now()
+	votedAt := now.Add(-time.Minute)
+	lastVote := rewindEntity.LastVote{
+		PeerId:     s.voteId.PeerUserId(),
+		VoteType:   romancesValueObject.VoteTypeYes,
+		VotedAt:    votedAt,
+		RecordedAt: votedAt,
+	}
+
+	s.lastVotesRepo.EXPECT().
+		GetLastVotes(s.ctx, s.voteId.ActiveUserKey()).
+		Return(s.newLastVotes(lastVote), nil)
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(s.newVotedRomance(romancesValueObject.VoteTypeNo, now), nil)
+
+	_, _, err := s.newOperation().Run(s.ctx, s.voteId.ActiveUserKey(), now)
+
+	s.Require().ErrorIs(err, rewindDomain.ErrNothingToRewind)
+}
+
+func (s *RewindVoteOperationUnitTestSuite) TestVoteInBlockedRomanceIsNotRewound() {
+	now := time.Now()
+	votedAt := now.Add(-time.Minute)
+	lastVote := rewindEntity.LastVote{
+		PeerId:     s.voteId.PeerUserId(),
+		VoteType:   romancesValueObject.VoteTypeYes,
+		VotedAt:    votedAt,
+		RecordedAt: votedAt,
+	}
+	romance := s.newVotedRomance(romancesValueObject.VoteTypeYes, votedAt)
+	romance.PeerUserBlockedAt = &now
+
+	s.lastVotesRepo.EXPECT().
+		GetLastVotes(s.ctx, s.voteId.ActiveUserKey()).
+		Return(s.newLastVotes(lastVote), nil)
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	_, _, err := s.newOperation().Run(s.ctx, s.voteId.ActiveUserKey(), now)
+
+	s.Require().ErrorIs(err, romanceDomain.ErrRomanceBlocked)
+}
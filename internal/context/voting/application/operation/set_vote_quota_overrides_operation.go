@@ -0,0 +1,32 @@
+package operation
+
+import (
+	"context"
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	quotasRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/repository"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+)
+
+type SetVoteQuotaOverridesOperation struct {
+	quotasRepository quotasRepo.QuotasRepository
+}
+
+func NewSetVoteQuotaOverridesOperation(
+	quotasRepository quotasRepo.QuotasRepository,
+) *SetVoteQuotaOverridesOperation {
+	return &SetVoteQuotaOverridesOperation{
+		quotasRepository: quotasRepository,
+	}
+}
+
+func (r *SetVoteQuotaOverridesOperation) Run(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	overrides map[romancesValueObject.VoteType]uint32,
+) error {
+	if err := quotaDomain.ValidateOverrides(overrides); err != nil {
+		return err
+	}
+	return r.quotasRepository.SetOverrides(ctx, activeUserKey, overrides)
+}
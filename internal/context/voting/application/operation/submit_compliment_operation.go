@@ -0,0 +1,73 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/provider"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"github.com/google/uuid"
+	"time"
+)
+
+// SubmitComplimentOperation moderates the text sent with a compliment vote before it is stored
+type SubmitComplimentOperation struct {
+	moderationProvider provider.ModerationProvider
+	logger             platform.Logger
+}
+
+func NewSubmitComplimentOperation(
+	moderationProvider provider.ModerationProvider,
+	logger platform.Logger,
+) *SubmitComplimentOperation {
+	return &SubmitComplimentOperation{
+		moderationProvider: moderationProvider,
+		logger:             logger,
+	}
+}
+
+// Check rejects a text sent with a vote which is not a compliment, it does not call the provider
+// so votes are validated before anything is reserved for them
+func (r *SubmitComplimentOperation) Check(voteType romancesValueObject.VoteType, text string) error {
+	if text != "" && voteType != romancesValueObject.VoteTypeCompliment {
+		return romanceDomain.ErrComplimentNotAllowed
+	}
+	return nil
+}
+
+// Run returns nil without a text, a compliment the provider could not moderate stays pending
+// so the vote is not rejected while the text is kept hidden from the peer
+func (r *SubmitComplimentOperation) Run(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	voteType romancesValueObject.VoteType,
+	text string,
+	now time.Time,
+) (*romancesValueObject.Compliment, error) {
+	if err := r.Check(voteType, text); err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return nil, nil
+	}
+
+	compliment := &romancesValueObject.Compliment{
+		Id:     uuid.New(),
+		Text:   text,
+		Status: romancesValueObject.ModerationStatusPending,
+	}
+
+	status, err := r.moderationProvider.Moderate(ctx, voteId, text)
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("Moderate compliment error: %+v", err))
+		return compliment, nil
+	}
+
+	compliment.Status = status
+	if status != romancesValueObject.ModerationStatusPending {
+		compliment.ModeratedAt = &now
+	}
+	return compliment, nil
+}
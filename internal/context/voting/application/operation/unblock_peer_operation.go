@@ -0,0 +1,62 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+)
+
+type UnblockPeerOperation struct {
+	romancesRepository         romancesRepo.RomancesRepository
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository
+	logger                     platform.Logger
+}
+
+func NewUnblockPeerOperation(
+	romancesRepository romancesRepo.RomancesRepository,
+	exclusionFiltersRepository romancesRepo.ExclusionFiltersRepository,
+	logger platform.Logger,
+) *UnblockPeerOperation {
+	return &UnblockPeerOperation{
+		romancesRepository:         romancesRepository,
+		exclusionFiltersRepository: exclusionFiltersRepository,
+		logger:                     logger,
+	}
+}
+
+// Run lifts the block of the active user only, a block of the peer stays in place
+func (r *UnblockPeerOperation) Run(ctx context.Context, voteId sharedValueObject.VoteId) error {
+	tries := 0
+
+	getRomanceOperation := NewGetRomanceOperation(r.romancesRepository)
+	for {
+		romance, err := getRomanceOperation.Run(ctx, voteId)
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("GetRomance error: %+v", err))
+			return err
+		}
+
+		if romance.ActiveUserBlockedAt == nil {
+			return nil
+		}
+
+		_, err = r.romancesRepository.UnblockPeerInRomance(ctx, romance)
+		if err != nil {
+			if errors.Is(err, romanceDomain.ErrVersionConflict) && tries < config.DynamoDbVersionConflictRetriesCount {
+				tries += 1
+				continue
+			}
+			r.logger.Error(fmt.Sprintf("UnblockPeerInRomance error: %+v", err))
+			return err
+		}
+
+		r.exclusionFiltersRepository.DeleteExclusionFilter(ctx, voteId.ActiveUserKey())
+		r.exclusionFiltersRepository.DeleteExclusionFilter(ctx, voteId.PeerUserKey())
+		return nil
+	}
+}
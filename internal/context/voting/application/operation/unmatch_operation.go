@@ -0,0 +1,90 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	countersRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/repository"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesRepo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"time"
+)
+
+// UnmatchOperation ends a match for both users, unlike deleting a vote it does not turn
+// the match back into a pending like of the peer.
+type UnmatchOperation struct {
+	romancesRepository romancesRepo.RomancesRepository
+	countersRepository countersRepo.CountersRepository
+	revoteBan          time.Duration
+	logger             platform.Logger
+}
+
+func NewUnmatchOperation(
+	romancesRepository romancesRepo.RomancesRepository,
+	countersRepository countersRepo.CountersRepository,
+	cfg config.Config,
+	logger platform.Logger,
+) *UnmatchOperation {
+	return &UnmatchOperation{
+		romancesRepository: romancesRepository,
+		countersRepository: countersRepository,
+		revoteBan:          cfg.Voting.UnmatchRevoteBan,
+		logger:             logger,
+	}
+}
+
+// Run unmatches the romance of voteId, unmatching an unmatched romance returns it unchanged
+func (r *UnmatchOperation) Run(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	reason romancesValueObject.UnmatchReason,
+	now time.Time,
+) (entity.Romance, error) {
+	tries := 0
+
+	getRomanceOperation := NewGetRomanceOperation(r.romancesRepository)
+	for {
+		romance, err := getRomanceOperation.Run(ctx, voteId)
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("GetRomance error: %+v", err))
+			return entity.Romance{}, err
+		}
+
+		if romance.IsBlocked() {
+			return entity.Romance{}, romanceDomain.ErrRomanceBlocked
+		}
+
+		if romance.IsUnmatched() {
+			return romance, nil
+		}
+
+		if !romance.IsMutual() {
+			return entity.Romance{}, romanceDomain.ErrNotMatched
+		}
+
+		unmatch := entity.Unmatch{
+			UnmatchedBy:       voteId.ActiveUserId(),
+			UnmatchedAt:       now,
+			Reason:            reason,
+			RevoteBannedUntil: now.Add(r.revoteBan),
+		}
+		romance, err = r.romancesRepository.UnmatchRomance(ctx, romance, unmatch)
+		if err != nil {
+			if errors.Is(err, romanceDomain.ErrVersionConflict) && tries < config.DynamoDbVersionConflictRetriesCount {
+				tries += 1
+				continue
+			}
+			r.logger.Error(fmt.Sprintf("UnmatchRomance error: %+v", err))
+			return entity.Romance{}, err
+		}
+
+		r.countersRepository.DecrMatchCounters(ctx, voteId)
+
+		return romance, nil
+	}
+}
@@ -0,0 +1,137 @@
+package operation
+
+import (
+	"context"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type UnmatchOperationUnitTestSuite struct {
+	suite.Suite
+	voteId       sharedValueObject.VoteId
+	ctrl         *gomock.Controller
+	romancesRepo *mocks.MockRomancesRepository
+	countersRepo *mocks.MockCountersRepository
+	logger       *slog.Logger
+	ctx          context.Context
+}
+
+func TestUnmatchOperationUnitSuite(t *testing.T) {
+	suite.Run(t, new(UnmatchOperationUnitTestSuite))
+}
+
+func (s *UnmatchOperationUnitTestSuite) SetupSuite() {
+	voteId, err := sharedValueObject.NewVoteId(11, uuidhelper.NewUUID(s.T()), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.voteId = voteId
+	s.ctx = context.Background()
+	s.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func (s *UnmatchOperationUnitTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.romancesRepo = mocks.NewMockRomancesRepository(s.ctrl)
+	s.countersRepo = mocks.NewMockCountersRepository(s.ctrl)
+}
+
+func (s *UnmatchOperationUnitTestSuite) newOperation() *UnmatchOperation {
+	return NewUnmatchOperation(s.romancesRepo, s.countersRepo, config.Config{
+		Voting: config.VotingConfig{UnmatchRevoteBan: 24 * time.Hour},
+	}, s.logger)
+}
+
+func (s *UnmatchOperationUnitTestSuite) newMatchedRomance() romanceEntity.Romance {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+	romance.PeerUserVote.VoteType = romancesValueObject.VoteTypeCrush
+	romance.Version = 2
+	return romance
+}
+
+func (s *UnmatchOperationUnitTestSuite) TestUnmatchStoresStateAndDecrementsMatchCounters() {
+	romance := s.newMatchedRomance()
+	now := time.Now()
+	expectedUnmatch := romanceEntity.Unmatch{
+		UnmatchedBy:       s.voteId.ActiveUserId(),
+		UnmatchedAt:       now,
+		Reason:            romancesValueObject.UnmatchReasonNotInterested,
+		RevoteBannedUntil: now.Add(24 * time.Hour),
+	}
+	unmatchedRomance := romance
+	unmatchedRomance.Unmatch = &expectedUnmatch
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+	s.romancesRepo.EXPECT().
+		UnmatchRomance(s.ctx, romance, expectedUnmatch).
+		Return(unmatchedRomance, nil)
+	s.countersRepo.EXPECT().DecrMatchCounters(s.ctx, s.voteId)
+
+	result, err := s.newOperation().Run(s.ctx, s.voteId, romancesValueObject.UnmatchReasonNotInterested, now)
+
+	s.Require().NoError(err)
+	s.Require().Equal(unmatchedRomance, result)
+}
+
+func (s *UnmatchOperationUnitTestSuite) TestVersionConflictRetriesAndSucceeds() {
+	romance := s.newMatchedRomance()
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil).
+		Times(2)
+	s.romancesRepo.EXPECT().
+		UnmatchRomance(s.ctx, romance, gomock.Any()).
+		Return(romanceEntity.Romance{}, romanceDomain.ErrVersionConflict)
+	s.romancesRepo.EXPECT().
+		UnmatchRomance(s.ctx, romance, gomock.Any()).
+		Return(romance, nil)
+	s.countersRepo.EXPECT().DecrMatchCounters(s.ctx, s.voteId)
+
+	_, err := s.newOperation().Run(s.ctx, s.voteId, romancesValueObject.UnmatchReasonUnspecified, time.Now())
+
+	s.Require().NoError(err)
+}
+
+func (s *UnmatchOperationUnitTestSuite) TestNotMutualRomanceIsRejected() {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	romance.ActiveUserVote.VoteType = romancesValueObject.VoteTypeYes
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	_, err := s.newOperation().Run(s.ctx, s.voteId, romancesValueObject.UnmatchReasonUnspecified, time.Now())
+
+	s.Require().ErrorIs(err, romanceDomain.ErrNotMatched)
+}
+
+func (s *UnmatchOperationUnitTestSuite) TestUnmatchedRomanceIsReturnedUnchanged() {
+	romance := s.newMatchedRomance()
+	romance.Unmatch = &romanceEntity.Unmatch{
+		UnmatchedBy: s.voteId.PeerUserId(),
+		UnmatchedAt: time.Now().Add(-time.Hour),
+	}
+
+	s.romancesRepo.EXPECT().
+		GetRomance(s.ctx, s.voteId).
+		Return(romance, nil)
+
+	result, err := s.newOperation().Run(s.ctx, s.voteId, romancesValueObject.UnmatchReasonFakeProfile, time.Now())
+
+	s.Require().NoError(err)
+	s.Require().Equal(romance, result)
+}
@@ -2,15 +2,24 @@ package application
 
 import (
 	"context"
+	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/operation"
 	counterEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/entity"
 	countersValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	quotaEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
 	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
 	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1/command"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1/contract"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1/query"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/callerscope"
 	"github.com/google/uuid"
+	"strings"
+	"time"
 )
 
 type VotingService struct {
@@ -25,6 +34,21 @@ type VotingService struct {
 	deleteRomancesGroupOperation   *operation.DeleteRomancesGroupOperation
 	getLifetimeCountersOperation   *operation.GetLifetimeCountersOperation
 	getHourlyCountersOperation     *operation.GetHourlyCountersOperation
+	getCountersSeriesOperation     *operation.GetCountersSeriesOperation
+	getVoteQuotasOperation         *operation.GetVoteQuotasOperation
+	setVoteQuotaOverridesOperation *operation.SetVoteQuotaOverridesOperation
+	blockPeerOperation             *operation.BlockPeerOperation
+	unblockPeerOperation           *operation.UnblockPeerOperation
+	unmatchOperation               *operation.UnmatchOperation
+	rewindVoteOperation            *operation.RewindVoteOperation
+	getVoteHistoryOperation        *operation.GetVoteHistoryOperation
+	moderateComplimentOperation    *operation.ModerateComplimentOperation
+	listRomancesOperation          *operation.ListRomancesOperation
+	exportRomancesOperation        *operation.ExportRomancesOperation
+	checkEligibilityOperation      *operation.CheckEligibilityOperation
+	getExclusionFilterOperation    *operation.GetExclusionFilterOperation
+	peerVoteProjectionPolicy       *romanceDomain.PeerVoteProjectionPolicy
+	peerCountryRequired            bool
 }
 
 func NewVotingService(
@@ -39,6 +63,21 @@ func NewVotingService(
 	deleteRomancesGroupOperation *operation.DeleteRomancesGroupOperation,
 	getLifetimeCountersOperation *operation.GetLifetimeCountersOperation,
 	getHourlyCountersOperation *operation.GetHourlyCountersOperation,
+	getCountersSeriesOperation *operation.GetCountersSeriesOperation,
+	getVoteQuotasOperation *operation.GetVoteQuotasOperation,
+	setVoteQuotaOverridesOperation *operation.SetVoteQuotaOverridesOperation,
+	blockPeerOperation *operation.BlockPeerOperation,
+	unblockPeerOperation *operation.UnblockPeerOperation,
+	unmatchOperation *operation.UnmatchOperation,
+	rewindVoteOperation *operation.RewindVoteOperation,
+	getVoteHistoryOperation *operation.GetVoteHistoryOperation,
+	moderateComplimentOperation *operation.ModerateComplimentOperation,
+	listRomancesOperation *operation.ListRomancesOperation,
+	exportRomancesOperation *operation.ExportRomancesOperation,
+	checkEligibilityOperation *operation.CheckEligibilityOperation,
+	getExclusionFilterOperation *operation.GetExclusionFilterOperation,
+	peerVoteProjectionPolicy *romanceDomain.PeerVoteProjectionPolicy,
+	cfg config.Config,
 ) *VotingService {
 	return &VotingService{
 		addUserVoteOperation:           addUserVoteOperation,
@@ -52,73 +91,209 @@ func NewVotingService(
 		deleteRomancesGroupOperation:   deleteRomancesGroupOperation,
 		getLifetimeCountersOperation:   getLifetimeCountersOperation,
 		getHourlyCountersOperation:     getHourlyCountersOperation,
+		getCountersSeriesOperation:     getCountersSeriesOperation,
+		getVoteQuotasOperation:         getVoteQuotasOperation,
+		setVoteQuotaOverridesOperation: setVoteQuotaOverridesOperation,
+		blockPeerOperation:             blockPeerOperation,
+		unblockPeerOperation:           unblockPeerOperation,
+		unmatchOperation:               unmatchOperation,
+		rewindVoteOperation:            rewindVoteOperation,
+		getVoteHistoryOperation:        getVoteHistoryOperation,
+		moderateComplimentOperation:    moderateComplimentOperation,
+		listRomancesOperation:          listRomancesOperation,
+		exportRomancesOperation:        exportRomancesOperation,
+		checkEligibilityOperation:      checkEligibilityOperation,
+		getExclusionFilterOperation:    getExclusionFilterOperation,
+		peerVoteProjectionPolicy:       peerVoteProjectionPolicy,
+		peerCountryRequired:            cfg.Voting.PeerCountryRequired,
 	}
 }
 
-func (v *VotingService) AddUserVote(ctx context.Context, command command.VoteAdd) (romanceEntity.Vote, error) {
-	voteId, err := sharedValueObject.NewVoteId(
+// newVoteId assumes the peer is in the country of the active user when the request does not tell,
+// unless the deployment serves countries from several regions and the home region could be missed
+func (v *VotingService) newVoteId(
+	countryId uint16,
+	activeUserId uuid.UUID,
+	peerCountryId uint16,
+	peerId uuid.UUID,
+) (sharedValueObject.VoteId, error) {
+	if peerCountryId == 0 && !v.peerCountryRequired {
+		return sharedValueObject.NewVoteId(countryId, activeUserId, peerId)
+	}
+	return sharedValueObject.NewCrossCountryVoteId(countryId, activeUserId, peerCountryId, peerId)
+}
+
+// AddUserVote also returns the version of the romance after the vote
+func (v *VotingService) AddUserVote(ctx context.Context, command command.VoteAdd) (romanceEntity.Vote, uint32, error) {
+	voteId, err := v.newVoteId(
 		command.CountryId,
 		command.Body.ActiveUserId,
+		command.Body.PeerCountryId,
 		command.Body.PeerId,
 	)
 	if err != nil {
-		return romanceEntity.Vote{}, err
+		return romanceEntity.Vote{}, 0, err
 	}
-	return v.addUserVoteOperation.Run(ctx, voteId, romancesValueObject.VoteType(command.Body.VoteType), command.Body.VotedAt)
+	return v.addUserVoteOperation.Run(ctx, voteId, romancesValueObject.VoteType(command.Body.VoteType), command.Body.VotedAt, command.Body.Context.ToValueObject(), strings.TrimSpace(command.Body.Compliment))
 }
 
-func (v *VotingService) GetUserVote(ctx context.Context, get query.VoteGet) (romanceEntity.Vote, error) {
-	voteId, err := sharedValueObject.NewVoteId(
+func (v *VotingService) GetUserVote(ctx context.Context, get query.VoteGet) (romanceEntity.Vote, uint32, error) {
+	voteId, err := v.newVoteId(
 		get.CountryId,
 		get.ActiveUserId,
+		get.PeerCountryId,
 		get.PeerId,
 	)
 	if err != nil {
-		return romanceEntity.Vote{}, err
+		return romanceEntity.Vote{}, 0, err
 	}
-	return v.getUserVoteOperation.Run(ctx, voteId)
+	return v.getUserVoteOperation.RunWithVersion(ctx, voteId)
 }
 
-func (v *VotingService) DeleteUserVote(ctx context.Context, command command.DeleteVote) error {
-	voteId, err := sharedValueObject.NewVoteId(
+// DeleteUserVote returns the version of the romance after the deletion
+func (v *VotingService) DeleteUserVote(ctx context.Context, command command.DeleteVote) (uint32, error) {
+	voteId, err := v.newVoteId(
 		command.CountryId,
 		command.ActiveUserId,
+		command.PeerCountryId,
 		command.PeerId,
 	)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	acceptedVersions, unconditional := contract.IfMatchVersions(command.IfMatch)
+	if unconditional {
+		return v.deleteUserVoteOperation.Run(ctx, voteId)
 	}
-	return v.deleteUserVoteOperation.Run(ctx, voteId)
+	if len(acceptedVersions) == 0 {
+		return 0, romanceDomain.ErrVersionMismatch
+	}
+	return v.deleteUserVoteOperation.RunIfVersion(ctx, voteId, acceptedVersions)
+}
+
+// RewindVote also returns the version of the romance after the rewind
+func (v *VotingService) RewindVote(ctx context.Context, command command.RewindVote) (romanceEntity.Vote, uint32, error) {
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(command.CountryId, command.ActiveUserId)
+	if err != nil {
+		return romanceEntity.Vote{}, 0, err
+	}
+	return v.rewindVoteOperation.Run(ctx, activeUserKey, time.Now())
 }
 
-func (v *VotingService) ChangeUserVote(ctx context.Context, command command.ChangeVoteType) (romanceEntity.Vote, error) {
-	voteId, err := sharedValueObject.NewVoteId(
+// ChangeUserVote also returns the version of the romance after the change
+func (v *VotingService) ChangeUserVote(ctx context.Context, command command.ChangeVoteType) (romanceEntity.Vote, uint32, error) {
+	voteId, err := v.newVoteId(
 		command.CountryId,
 		command.ActiveUserId,
+		command.PeerCountryId,
 		command.PeerId,
 	)
 	if err != nil {
-		return romanceEntity.Vote{}, err
+		return romanceEntity.Vote{}, 0, err
+	}
+
+	newVoteType := romancesValueObject.VoteType(command.Body.NewType)
+	votedAt := command.Body.VotedAt
+	if votedAt.IsZero() {
+		votedAt = time.Now()
 	}
-	return v.changeUserVoteOperation.Run(ctx, voteId, romancesValueObject.VoteType(command.Body.NewType))
+	// a missing If-Match and the "*" wildcard are unconditional, the vote existence is already
+	// checked by the operations
+	acceptedVersions, unconditional := contract.IfMatchVersions(command.IfMatch)
+	if unconditional {
+		return v.changeUserVoteOperation.Run(ctx, voteId, newVoteType, votedAt, command.Body.Context.ToValueObject(), strings.TrimSpace(command.Body.Compliment))
+	}
+	if len(acceptedVersions) == 0 {
+		return romanceEntity.Vote{}, 0, romanceDomain.ErrVersionMismatch
+	}
+	return v.changeUserVoteOperation.RunIfVersion(ctx, voteId, newVoteType, votedAt, command.Body.Context.ToValueObject(), strings.TrimSpace(command.Body.Compliment), acceptedVersions)
 }
 
 func (v *VotingService) GetRomance(ctx context.Context, get query.RomanceGet) (romanceEntity.Romance, error) {
-	voteId, err := sharedValueObject.NewVoteId(
+	voteId, err := v.newVoteId(
 		get.CountryId,
 		get.ActiveUserId,
+		get.PeerCountryId,
 		get.PeerId,
 	)
 	if err != nil {
 		return romanceEntity.Romance{}, err
 	}
-	return v.getRomanceOperation.Run(ctx, voteId)
+	romance, err := v.getRomanceOperation.RunVisible(ctx, voteId)
+	if err != nil {
+		return romanceEntity.Romance{}, err
+	}
+	return v.peerVoteProjectionPolicy.Project(romance, callerscope.FromContext(ctx).IsUnrestricted()), nil
+}
+
+func (v *VotingService) GetRomanceHistory(ctx context.Context, get query.RomanceHistoryGet) (romanceEntity.VoteHistoryPage, error) {
+	voteId, err := v.newVoteId(
+		get.CountryId,
+		get.ActiveUserId,
+		get.PeerCountryId,
+		get.PeerId,
+	)
+	if err != nil {
+		return romanceEntity.VoteHistoryPage{}, err
+	}
+	return v.getVoteHistoryOperation.Run(ctx, voteId, callerscope.FromContext(ctx), get.Limit, get.Cursor)
+}
+
+func (v *VotingService) ListRomances(ctx context.Context, list query.RomancesList) (romanceEntity.RomancesPage, error) {
+	userKey, err := sharedValueObject.NewActiveUserKey(list.CountryId, list.ActiveUserId)
+	if err != nil {
+		return romanceEntity.RomancesPage{}, err
+	}
+
+	states := make([]romancesValueObject.RomanceState, 0, len(list.State))
+	for _, name := range list.State {
+		state, ok := romancesValueObject.RomanceStateFromString(name)
+		if !ok {
+			return romanceEntity.RomancesPage{}, fmt.Errorf("%w: %q", romanceDomain.ErrUnknownRomanceState, name)
+		}
+		states = append(states, state)
+	}
+	return v.listRomancesOperation.Run(ctx, userKey, states, callerscope.FromContext(ctx), list.Limit, list.Cursor)
+}
+
+func (v *VotingService) ExportRomances(
+	ctx context.Context,
+	export query.RomancesExport,
+	emit func(page romanceEntity.RomancesPage) error,
+) error {
+	userKey, err := sharedValueObject.NewActiveUserKey(export.CountryId, export.ActiveUserId)
+	if err != nil {
+		return err
+	}
+	return v.exportRomancesOperation.Run(ctx, userKey, export.Since, callerscope.FromContext(ctx), export.Cursor, emit)
+}
+
+func (v *VotingService) CheckEligibility(ctx context.Context, command command.EligibilityCheck) ([]uuid.UUID, error) {
+	userKey, err := sharedValueObject.NewActiveUserKey(command.CountryId, command.ActiveUserId)
+	if err != nil {
+		return nil, err
+	}
+	return v.checkEligibilityOperation.Run(ctx, userKey, command.Body.PeerIds, time.Now())
+}
+
+func (v *VotingService) GetExclusionFilter(ctx context.Context, get query.ExclusionFilterGet) ([]byte, error) {
+	userKey, err := sharedValueObject.NewActiveUserKey(get.CountryId, get.ActiveUserId)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := v.getExclusionFilterOperation.Run(ctx, userKey, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return filter.MarshalBinary()
 }
 
 func (v *VotingService) DeleteRomance(ctx context.Context, command command.DeleteRomance) error {
-	voteId, err := sharedValueObject.NewVoteId(
+	voteId, err := v.newVoteId(
 		command.CountryId,
 		command.ActiveUserId,
+		command.PeerCountryId,
 		command.PeerId,
 	)
 	if err != nil {
@@ -127,6 +302,27 @@ func (v *VotingService) DeleteRomance(ctx context.Context, command command.Delet
 	return v.deleteRomanceOperation.Run(ctx, voteId)
 }
 
+func (v *VotingService) UnmatchRomance(ctx context.Context, command command.UnmatchRomance) (romanceEntity.Romance, error) {
+	voteId, err := v.newVoteId(
+		command.CountryId,
+		command.ActiveUserId,
+		command.PeerCountryId,
+		command.PeerId,
+	)
+	if err != nil {
+		return romanceEntity.Romance{}, err
+	}
+
+	reason := romancesValueObject.UnmatchReasonUnspecified
+	if command.Body.Reason != "" {
+		var ok bool
+		if reason, ok = romancesValueObject.UnmatchReasonFromString(command.Body.Reason); !ok {
+			return romanceEntity.Romance{}, fmt.Errorf("%w: %q", romanceDomain.ErrUnknownUnmatchReason, command.Body.Reason)
+		}
+	}
+	return v.unmatchOperation.Run(ctx, voteId, reason, time.Now())
+}
+
 func (v *VotingService) DeleteRomancesRequest(ctx context.Context, command command.DeleteRomances) error {
 	userKey, err := sharedValueObject.NewActiveUserKey(
 		command.CountryId,
@@ -179,3 +375,101 @@ func (v *VotingService) GetHourlyCounters(ctx context.Context, query query.Hourl
 	}
 	return v.getHourlyCountersOperation.Run(ctx, activeUserKey, hoursOffsetGroups)
 }
+
+func (v *VotingService) GetCountersSeries(ctx context.Context, query query.CountersSeriesGet) ([]counterEntity.CountersGroup, error) {
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(
+		query.CountryId,
+		query.ActiveUserId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity, ok := countersValueObject.CountersGranularityFromString(query.Granularity)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown granularity %q", countersValueObject.ErrInvalidCountersRange, query.Granularity)
+	}
+	countersRange, err := countersValueObject.NewCountersRange(query.From, query.To, granularity)
+	if err != nil {
+		return nil, err
+	}
+	if err = countersRange.CheckRetention(time.Now()); err != nil {
+		return nil, err
+	}
+	return v.getCountersSeriesOperation.Run(ctx, activeUserKey, countersRange)
+}
+
+func (v *VotingService) GetVoteQuotas(ctx context.Context, query query.QuotasGet) ([]quotaEntity.Quota, *time.Location, error) {
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(
+		query.CountryId,
+		query.ActiveUserId,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v.getVoteQuotasOperation.Run(ctx, activeUserKey, time.Now())
+}
+
+func (v *VotingService) SetVoteQuotaOverrides(ctx context.Context, command command.QuotaOverridesSet) error {
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(
+		command.CountryId,
+		command.ActiveUserId,
+	)
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[romancesValueObject.VoteType]uint32, len(command.Body.Overrides))
+	for name, limit := range command.Body.Overrides {
+		voteType, ok := romancesValueObject.VoteTypeFromString(name)
+		if !ok {
+			return fmt.Errorf("%w: unknown vote type %q", quotaDomain.ErrInvalidQuotas, name)
+		}
+		overrides[voteType] = limit
+	}
+	return v.setVoteQuotaOverridesOperation.Run(ctx, activeUserKey, overrides)
+}
+
+func (v *VotingService) BlockPeer(ctx context.Context, command command.BlockPeer) error {
+	voteId, err := v.newVoteId(
+		command.CountryId,
+		command.ActiveUserId,
+		command.PeerCountryId,
+		command.PeerId,
+	)
+	if err != nil {
+		return err
+	}
+	return v.blockPeerOperation.Run(ctx, voteId, time.Now())
+}
+
+func (v *VotingService) UnblockPeer(ctx context.Context, command command.UnblockPeer) error {
+	voteId, err := v.newVoteId(
+		command.CountryId,
+		command.ActiveUserId,
+		command.PeerCountryId,
+		command.PeerId,
+	)
+	if err != nil {
+		return err
+	}
+	return v.unblockPeerOperation.Run(ctx, voteId)
+}
+
+func (v *VotingService) ModerateCompliment(ctx context.Context, command command.ModerateCompliment) (romanceEntity.Vote, error) {
+	voteId, err := v.newVoteId(
+		command.CountryId,
+		command.ActiveUserId,
+		command.PeerCountryId,
+		command.PeerId,
+	)
+	if err != nil {
+		return romanceEntity.Vote{}, err
+	}
+
+	status, ok := romancesValueObject.ModerationStatusFromString(command.Body.Status)
+	if !ok {
+		return romanceEntity.Vote{}, fmt.Errorf("%w: %q", romanceDomain.ErrModerationStatus, command.Body.Status)
+	}
+	return v.moderateComplimentOperation.Run(ctx, voteId, command.Body.ComplimentId, status, time.Now())
+}
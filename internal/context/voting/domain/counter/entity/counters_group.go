@@ -11,4 +11,6 @@ type CountersGroup struct {
 	IncomingNo        uint32
 	OutgoingYes       uint32
 	OutgoingNo        uint32
+	// Matches counts the matches of the hour, the lifetime counter excludes unmatched romances
+	Matches uint32
 }
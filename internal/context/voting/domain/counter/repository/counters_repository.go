@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	counterDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/entity"
 	countersValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"time"
 )
 
 //go:generate mockgen -destination=../../../../../testlib/mocks/counters_repository_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/repository CountersRepository
@@ -20,6 +22,37 @@ type CountersRepository interface {
 		hoursOffsetGroups countersValueObject.HoursOffsetGroups,
 	) (map[uint8]*entity.CountersGroup, error)
 
+	// GetHourlyCountersSince returns the hourly counters of the active user starting from the hour of since
+	GetHourlyCountersSince(
+		ctx context.Context,
+		activeUserKey sharedValueObject.ActiveUserKey,
+		since time.Time,
+	) ([]entity.CountersGroup, error)
+
+	// GetCountersRange returns the counters of the active user in the buckets of the range, hours are
+	// read from the hourly counters, days and weeks from their rollups. Buckets without votes are left
+	// out and HourUnixTimestamp is the start of the bucket.
+	GetCountersRange(
+		ctx context.Context,
+		activeUserKey sharedValueObject.ActiveUserKey,
+		countersRange countersValueObject.CountersRange,
+	) ([]entity.CountersGroup, error)
+
+	// ReserveVoteLimits counts one outgoing vote of the active user in every window with a single conditional
+	// write, nothing is counted and the VoteRateLimitError of a full window is returned when one reached its limit
+	ReserveVoteLimits(
+		ctx context.Context,
+		activeUserKey sharedValueObject.ActiveUserKey,
+		windows []counterDomain.VoteLimitWindow,
+	) error
+
+	// ReleaseVoteLimits gives back a vote counted by ReserveVoteLimits, e.g. when the vote was rejected
+	ReleaseVoteLimits(
+		ctx context.Context,
+		activeUserKey sharedValueObject.ActiveUserKey,
+		windows []counterDomain.VoteLimitWindow,
+	) error
+
 	IncrYesCounters(
 		ctx context.Context,
 		voteId sharedValueObject.VoteId,
@@ -31,4 +64,30 @@ type CountersRepository interface {
 		voteId sharedValueObject.VoteId,
 		counterGroup countersValueObject.CounterUpdateGroup,
 	)
+
+	// DecrYesCounters reverses IncrYesCounters of the same hour, e.g. when a vote is rewound
+	DecrYesCounters(
+		ctx context.Context,
+		voteId sharedValueObject.VoteId,
+		counterGroup countersValueObject.CounterUpdateGroup,
+	)
+
+	DecrNoCounters(
+		ctx context.Context,
+		voteId sharedValueObject.VoteId,
+		counterGroup countersValueObject.CounterUpdateGroup,
+	)
+
+	// IncrMatchCounters counts a new match for both users
+	IncrMatchCounters(
+		ctx context.Context,
+		voteId sharedValueObject.VoteId,
+		counterGroup countersValueObject.CounterUpdateGroup,
+	)
+
+	// DecrMatchCounters takes an unmatched romance out of the lifetime matches of both users
+	DecrMatchCounters(
+		ctx context.Context,
+		voteId sharedValueObject.VoteId,
+	)
 }
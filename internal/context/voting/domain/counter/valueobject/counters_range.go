@@ -0,0 +1,130 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/timeutil"
+)
+
+var ErrInvalidCountersRange = errors.New("invalid counters range")
+
+// MaxCountersBuckets is the most buckets of one counters series request, a month of hours
+const MaxCountersBuckets = 31 * 24
+
+// CountersGranularity is the length of the UTC buckets of a counters series, weeks are ISO weeks
+// starting on Monday
+type CountersGranularity uint8
+
+const (
+	CountersGranularityHour CountersGranularity = iota
+	CountersGranularityDay
+	CountersGranularityWeek
+)
+
+var CountersGranularityToString = map[CountersGranularity]string{
+	CountersGranularityHour: "hour",
+	CountersGranularityDay:  "day",
+	CountersGranularityWeek: "week",
+}
+
+func CountersGranularityFromString(name string) (CountersGranularity, bool) {
+	for granularity, granularityName := range CountersGranularityToString {
+		if granularityName == name {
+			return granularity, true
+		}
+	}
+	return CountersGranularityHour, false
+}
+
+func (g CountersGranularity) String() string {
+	return CountersGranularityToString[g]
+}
+
+func (g CountersGranularity) Duration() time.Duration {
+	switch g {
+	case CountersGranularityDay:
+		return 24 * time.Hour
+	case CountersGranularityWeek:
+		return 7 * 24 * time.Hour
+	default:
+		return time.Hour
+	}
+}
+
+// BucketStart returns the start of the UTC bucket t falls into
+func (g CountersGranularity) BucketStart(t time.Time) time.Time {
+	switch g {
+	case CountersGranularityDay:
+		return timeutil.DayStart(t.UTC())
+	case CountersGranularityWeek:
+		dayStart := timeutil.DayStart(t.UTC())
+		return dayStart.AddDate(0, 0, -(int(dayStart.Weekday())+6)%7)
+	default:
+		return timeutil.HourStart(t.UTC())
+	}
+}
+
+// CountersRange covers whole UTC buckets of its granularity, from is moved to the start of its bucket
+// and the exclusive to up to the end of its bucket
+type CountersRange struct {
+	from        time.Time
+	to          time.Time
+	granularity CountersGranularity
+}
+
+func NewCountersRange(from time.Time, to time.Time, granularity CountersGranularity) (CountersRange, error) {
+	if !from.Before(to) {
+		return CountersRange{}, fmt.Errorf("%w: from must be before to", ErrInvalidCountersRange)
+	}
+
+	alignedFrom := granularity.BucketStart(from)
+	alignedTo := granularity.BucketStart(to)
+	if alignedTo.Before(to) {
+		alignedTo = alignedTo.Add(granularity.Duration())
+	}
+	if alignedTo.Sub(alignedFrom) > MaxCountersBuckets*granularity.Duration() {
+		return CountersRange{}, fmt.Errorf("%w: range can not have more than %d buckets", ErrInvalidCountersRange, MaxCountersBuckets)
+	}
+
+	return CountersRange{from: alignedFrom, to: alignedTo, granularity: granularity}, nil
+}
+
+// CheckRetention rejects an hourly range starting with an hour whose counters may already have expired,
+// daily and weekly buckets past the retention of their rollups read as zero
+func (r CountersRange) CheckRetention(now time.Time) error {
+	if r.granularity == CountersGranularityHour && !r.from.Add(config.CountersTtlHours*time.Hour).After(now) {
+		return fmt.Errorf("%w: hourly counters are kept for %d hours", ErrInvalidCountersRange, config.CountersTtlHours)
+	}
+	return nil
+}
+
+func (r CountersRange) From() time.Time {
+	return r.from
+}
+
+func (r CountersRange) To() time.Time {
+	return r.to
+}
+
+func (r CountersRange) Granularity() CountersGranularity {
+	return r.granularity
+}
+
+func (r CountersRange) BucketsCount() int {
+	return int(r.to.Sub(r.from) / r.granularity.Duration())
+}
+
+// BucketIndex tells which bucket of the range t falls into
+func (r CountersRange) BucketIndex(t time.Time) (int, bool) {
+	if t.Before(r.from) || !t.Before(r.to) {
+		return 0, false
+	}
+	return int(t.Sub(r.from) / r.granularity.Duration()), true
+}
+
+func (r CountersRange) BucketStart(index int) time.Time {
+	return r.from.Add(time.Duration(index) * r.granularity.Duration())
+}
@@ -0,0 +1,70 @@
+package valueobject
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountersRangeIsAlignedToWholeBuckets(t *testing.T) {
+	from := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 2, 1, 15, 0, 0, time.UTC)
+
+	hourly, err := NewCountersRange(from, to, CountersGranularityHour)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), hourly.From())
+	assert.Equal(t, time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC), hourly.To())
+	assert.Equal(t, 16, hourly.BucketsCount())
+
+	daily, err := NewCountersRange(from, to, CountersGranularityDay)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), daily.From())
+	assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), daily.To())
+	assert.Equal(t, 2, daily.BucketsCount())
+
+	index, ok := daily.BucketIndex(time.Date(2026, 3, 2, 23, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, 1, index)
+	_, ok = daily.BucketIndex(daily.To())
+	assert.False(t, ok)
+}
+
+func TestCountersRangeRejectsEmptyAndTooLongRanges(t *testing.T) {
+	now := time.Now()
+
+	_, err := NewCountersRange(now, now, CountersGranularityHour)
+	assert.ErrorIs(t, err, ErrInvalidCountersRange)
+
+	_, err = NewCountersRange(now.Add(-MaxCountersBuckets*time.Hour-time.Hour), now, CountersGranularityHour)
+	assert.ErrorIs(t, err, ErrInvalidCountersRange)
+
+	_, err = NewCountersRange(now.Add(-MaxCountersBuckets*time.Hour-time.Hour), now, CountersGranularityDay)
+	assert.NoError(t, err, "the same range has fewer daily buckets")
+}
+
+func TestWeeklyBucketsStartOnMonday(t *testing.T) {
+	sunday := time.Date(2026, 3, 8, 18, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), CountersGranularityWeek.BucketStart(sunday))
+	assert.Equal(t, monday, CountersGranularityWeek.BucketStart(monday))
+	assert.Equal(t, monday, CountersGranularityWeek.BucketStart(monday.Add(50*time.Hour)))
+}
+
+func TestHourlyCountersRangeIsRejectedPastRetention(t *testing.T) {
+	now := time.Date(2026, 3, 3, 10, 20, 0, 0, time.UTC)
+
+	kept, err := NewCountersRange(now.Add(-47*time.Hour), now, CountersGranularityHour)
+	require.NoError(t, err)
+	assert.NoError(t, kept.CheckRetention(now))
+
+	expired, err := NewCountersRange(now.Add(-48*time.Hour), now, CountersGranularityHour)
+	require.NoError(t, err)
+	assert.ErrorIs(t, expired.CheckRetention(now), ErrInvalidCountersRange)
+
+	daily, err := NewCountersRange(now.Add(-30*24*time.Hour), now, CountersGranularityDay)
+	require.NoError(t, err)
+	assert.NoError(t, daily.CheckRetention(now), "rollups past their retention read as zero")
+}
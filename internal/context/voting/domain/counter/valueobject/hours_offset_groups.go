@@ -1,11 +1,14 @@
 package valueobject
 
 import (
+	"errors"
 	"fmt"
 	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	"sort"
 )
 
+var ErrInvalidHoursOffsets = errors.New("invalid hours offsets")
+
 type HoursOffsetGroups struct {
 	values []uint8
 }
@@ -30,17 +33,17 @@ func (h HoursOffsetGroups) Values() []uint8 {
 
 func ValidateHoursOffsets(offsets []uint8) error {
 	if len(offsets) == 0 {
-		return fmt.Errorf("hours offset groups cannot be empty")
+		return fmt.Errorf("%w: hours offset groups cannot be empty", ErrInvalidHoursOffsets)
 	}
 
 	seen := map[uint8]struct{}{}
 
 	for _, h := range offsets {
 		if h > config.CountersTtlHours || h < 1 {
-			return fmt.Errorf("invalid hour offset: %d (must be 1-%d)", h, config.CountersTtlHours)
+			return fmt.Errorf("%w: invalid hour offset: %d (must be 1-%d)", ErrInvalidHoursOffsets, h, config.CountersTtlHours)
 		}
 		if _, ok := seen[h]; ok {
-			return fmt.Errorf("duplicate hour offset: %d", h)
+			return fmt.Errorf("%w: duplicate hour offset: %d", ErrInvalidHoursOffsets, h)
 		}
 		seen[h] = struct{}{}
 	}
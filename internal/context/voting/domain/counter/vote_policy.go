@@ -0,0 +1,202 @@
+package counter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romanceValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/timeutil"
+)
+
+const limitOverrideSeparator = "/"
+
+const (
+	yesCounterName = "yes"
+	noCounterName  = "no"
+)
+
+type LimitWindow string
+
+const (
+	LimitWindowHour LimitWindow = "hour"
+	LimitWindowDay  LimitWindow = "day"
+)
+
+var (
+	ErrVoteRateLimited   = errors.New("vote rate limit exceeded")
+	ErrInvalidVoteLimits = errors.New("invalid vote limits")
+)
+
+type VoteRateLimitError struct {
+	Counter string
+	Window  LimitWindow
+	Limit   uint32
+	ResetAt time.Time
+}
+
+func (e *VoteRateLimitError) Error() string {
+	return fmt.Sprintf(
+		"%s: %d %s votes per %s, resets at %s",
+		ErrVoteRateLimited, e.Limit, e.Counter, e.Window, e.ResetAt.Format(time.RFC3339),
+	)
+}
+
+func (e *VoteRateLimitError) Unwrap() error {
+	return ErrVoteRateLimited
+}
+
+// VoteLimitWindow is a fixed UTC window in which the outgoing votes of one counter are limited
+type VoteLimitWindow struct {
+	Counter string
+	Window  LimitWindow
+	Start   time.Time
+	Limit   uint32
+}
+
+func (w VoteLimitWindow) End() time.Time {
+	if w.Window == LimitWindowDay {
+		return w.Start.AddDate(0, 0, 1)
+	}
+	return w.Start.Add(time.Hour)
+}
+
+// Exceeded is the error of a vote which did not fit in the window any more
+func (w VoteLimitWindow) Exceeded() *VoteRateLimitError {
+	return &VoteRateLimitError{Counter: w.Counter, Window: w.Window, Limit: w.Limit, ResetAt: w.End()}
+}
+
+type limitKey struct {
+	counter string
+	window  LimitWindow
+}
+
+// voteLimits keeps only enabled limits, a zero limit is never stored
+type voteLimits map[limitKey]uint32
+
+// VotePolicy caps the outgoing votes of a user by the outgoing counter a vote increments,
+// the windows are fixed UTC hours and days.
+type VotePolicy struct {
+	defaults  voteLimits
+	countries map[uint16]voteLimits
+}
+
+func NewVotePolicy(cfg config.Config) (*VotePolicy, error) {
+	defaults := voteLimits{}
+	if err := defaults.setAll(LimitWindowHour, cfg.Voting.HourlyLimits); err != nil {
+		return nil, err
+	}
+	if err := defaults.setAll(LimitWindowDay, cfg.Voting.DailyLimits); err != nil {
+		return nil, err
+	}
+
+	countries := map[uint16]voteLimits{}
+	for key, limit := range cfg.Voting.LimitOverrides {
+		parts := strings.Split(key, limitOverrideSeparator)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%w: override %q must be in country/counter/window format", ErrInvalidVoteLimits, key)
+		}
+		countryId, err := strconv.ParseUint(parts[0], 10, 16)
+		if err != nil || countryId == 0 {
+			return nil, fmt.Errorf("%w: invalid country in override %q", ErrInvalidVoteLimits, key)
+		}
+		window := LimitWindow(strings.TrimSpace(parts[2]))
+		if window != LimitWindowHour && window != LimitWindowDay {
+			return nil, fmt.Errorf("%w: unknown window %q", ErrInvalidVoteLimits, parts[2])
+		}
+
+		limits, exists := countries[uint16(countryId)]
+		if !exists {
+			limits = defaults.clone()
+			countries[uint16(countryId)] = limits
+		}
+		if err := limits.set(window, parts[1], limit); err != nil {
+			return nil, err
+		}
+	}
+
+	return &VotePolicy{
+		defaults:  defaults,
+		countries: countries,
+	}, nil
+}
+
+// IsEnabled tells if any limit is configured, no window has to be counted otherwise
+func (p *VotePolicy) IsEnabled() bool {
+	if len(p.defaults) > 0 {
+		return true
+	}
+	for _, limits := range p.countries {
+		if len(limits) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Windows returns the limited windows one more vote of voteType is counted in at now, there are
+// none when the vote type does not increment an outgoing counter or its counter is not limited
+func (p *VotePolicy) Windows(countryId uint16, voteType romanceValueObject.VoteType, now time.Time) []VoteLimitWindow {
+	var counter string
+	switch {
+	case voteType.IsPositive():
+		counter = yesCounterName
+	case voteType.IsNegative():
+		counter = noCounterName
+	default:
+		return nil
+	}
+
+	now = now.UTC()
+	limits := p.forCountry(countryId)
+	var windows []VoteLimitWindow
+	if limit, ok := limits[limitKey{counter, LimitWindowHour}]; ok {
+		windows = append(windows, VoteLimitWindow{Counter: counter, Window: LimitWindowHour, Start: timeutil.HourStart(now), Limit: limit})
+	}
+	if limit, ok := limits[limitKey{counter, LimitWindowDay}]; ok {
+		windows = append(windows, VoteLimitWindow{Counter: counter, Window: LimitWindowDay, Start: timeutil.DayStart(now), Limit: limit})
+	}
+	return windows
+}
+
+func (p *VotePolicy) forCountry(countryId uint16) voteLimits {
+	if limits, ok := p.countries[countryId]; ok {
+		return limits
+	}
+	return p.defaults
+}
+
+func (l voteLimits) setAll(window LimitWindow, limits map[string]uint32) error {
+	for counter, limit := range limits {
+		if err := l.set(window, counter, limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l voteLimits) set(window LimitWindow, counter string, limit uint32) error {
+	counter = strings.TrimSpace(counter)
+	if counter != yesCounterName && counter != noCounterName {
+		return fmt.Errorf("%w: unknown counter %q, must be %q or %q", ErrInvalidVoteLimits, counter, yesCounterName, noCounterName)
+	}
+
+	key := limitKey{counter: counter, window: window}
+	if limit == 0 {
+		delete(l, key)
+		return nil
+	}
+	l[key] = limit
+	return nil
+}
+
+func (l voteLimits) clone() voteLimits {
+	cloned := make(voteLimits, len(l))
+	for key, limit := range l {
+		cloned[key] = limit
+	}
+	return cloned
+}
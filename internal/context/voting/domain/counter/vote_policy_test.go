@@ -0,0 +1,87 @@
+package counter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romanceValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVotePolicyWithoutLimits(t *testing.T) {
+	policy, err := NewVotePolicy(config.Config{})
+	require.NoError(t, err)
+
+	assert.False(t, policy.IsEnabled())
+	assert.Empty(t, policy.Windows(11, romanceValueObject.VoteTypeYes, time.Now()))
+}
+
+func TestVotePolicyHourlyAndDailyLimits(t *testing.T) {
+	policy, err := NewVotePolicy(config.Config{
+		Voting: config.VotingConfig{
+			HourlyLimits: map[string]uint32{"yes": 3},
+			DailyLimits:  map[string]uint32{"yes": 5, "no": 10},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, policy.IsEnabled())
+
+	now := time.Date(2025, 1, 2, 15, 30, 0, 0, time.UTC)
+	currentHour := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	windows := policy.Windows(11, romanceValueObject.VoteTypeCompliment, now)
+	assert.Equal(t, []VoteLimitWindow{
+		{Counter: "yes", Window: LimitWindowHour, Start: currentHour, Limit: 3},
+		{Counter: "yes", Window: LimitWindowDay, Start: today, Limit: 5},
+	}, windows)
+
+	limitErr := windows[0].Exceeded()
+	assert.ErrorIs(t, limitErr, ErrVoteRateLimited)
+	assert.Equal(t, VoteRateLimitError{
+		Counter: "yes",
+		Window:  LimitWindowHour,
+		Limit:   3,
+		ResetAt: time.Date(2025, 1, 2, 16, 0, 0, 0, time.UTC),
+	}, *limitErr)
+	assert.Equal(t, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), windows[1].Exceeded().ResetAt)
+
+	assert.Equal(t, []VoteLimitWindow{
+		{Counter: "no", Window: LimitWindowDay, Start: today, Limit: 10},
+	}, policy.Windows(11, romanceValueObject.VoteTypeNo, now))
+	assert.Empty(t, policy.Windows(11, romanceValueObject.VoteTypeEmpty, now))
+}
+
+func TestVotePolicyCountryOverrides(t *testing.T) {
+	policy, err := NewVotePolicy(config.Config{
+		Voting: config.VotingConfig{
+			HourlyLimits:   map[string]uint32{"yes": 3},
+			LimitOverrides: map[string]uint32{"11/yes/hour": 0, "12/no/day": 1},
+		},
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	assert.Empty(t, policy.Windows(11, romanceValueObject.VoteTypeYes, now))
+	assert.Len(t, policy.Windows(12, romanceValueObject.VoteTypeYes, now), 1)
+	assert.Equal(t, uint32(1), policy.Windows(12, romanceValueObject.VoteTypeNo, now)[0].Limit)
+	assert.Equal(t, uint32(3), policy.Windows(13, romanceValueObject.VoteTypeYes, now)[0].Limit)
+}
+
+func TestVotePolicyInvalidConfig(t *testing.T) {
+	testCases := map[string]config.VotingConfig{
+		"unknown_counter": {HourlyLimits: map[string]uint32{"crush": 1}},
+		"malformed_key":   {LimitOverrides: map[string]uint32{"11/yes": 1}},
+		"invalid_country": {LimitOverrides: map[string]uint32{"x/yes/hour": 1}},
+		"unknown_window":  {LimitOverrides: map[string]uint32{"11/yes/week": 1}},
+	}
+
+	for name, votingConfig := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewVotePolicy(config.Config{Voting: votingConfig})
+			assert.ErrorIs(t, err, ErrInvalidVoteLimits)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+)
+
+// Quota is the daily allowance of a premium vote type, a nil Limit means the vote type is not limited
+type Quota struct {
+	VoteType valueobject.VoteType
+	Limit    *uint32
+	Used     uint32
+	ResetAt  time.Time
+}
+
+func (q Quota) Remaining() *uint32 {
+	if q.Limit == nil {
+		return nil
+	}
+	remaining := uint32(0)
+	if q.Used < *q.Limit {
+		remaining = *q.Limit - q.Used
+	}
+	return &remaining
+}
+
+// Consumption is a single use of a quota, it is refunded on the same quota day it was made
+type Consumption struct {
+	ActiveUserKey sharedValueObject.ActiveUserKey
+	VoteType      valueobject.VoteType
+	Day           time.Time
+}
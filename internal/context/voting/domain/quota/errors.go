@@ -0,0 +1,32 @@
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+)
+
+var (
+	ErrQuotaExhausted  = errors.New("daily vote quota exhausted")
+	ErrInvalidQuotas   = errors.New("invalid vote quotas")
+	ErrInvalidTimeZone = errors.New("invalid time zone")
+)
+
+type QuotaExhaustedError struct {
+	VoteType valueobject.VoteType
+	Limit    uint32
+	ResetAt  time.Time
+}
+
+func (e *QuotaExhaustedError) Error() string {
+	return fmt.Sprintf(
+		"%s: %d `%s` votes per day, resets at %s",
+		ErrQuotaExhausted, e.Limit, e.VoteType, e.ResetAt.Format(time.RFC3339),
+	)
+}
+
+func (e *QuotaExhaustedError) Unwrap() error {
+	return ErrQuotaExhausted
+}
@@ -0,0 +1,78 @@
+package quota
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	// the production image has no system time zone database
+	_ "time/tzdata"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+)
+
+// PremiumVoteTypes are the vote types a daily quota can be set for
+var PremiumVoteTypes = []valueobject.VoteType{
+	valueobject.VoteTypeCrush,
+	valueobject.VoteTypeCompliment,
+}
+
+type QuotaPolicy struct {
+	defaults map[valueobject.VoteType]uint32
+}
+
+func NewQuotaPolicy(cfg config.Config) (*QuotaPolicy, error) {
+	defaults := make(map[valueobject.VoteType]uint32, len(cfg.Voting.DailyQuotas))
+	for name, limit := range cfg.Voting.DailyQuotas {
+		voteType, ok := valueobject.VoteTypeFromString(strings.TrimSpace(name))
+		if !ok || !IsPremium(voteType) {
+			return nil, fmt.Errorf("%w: %q is not a premium vote type", ErrInvalidQuotas, name)
+		}
+		defaults[voteType] = limit
+	}
+
+	return &QuotaPolicy{defaults: defaults}, nil
+}
+
+func IsPremium(voteType valueobject.VoteType) bool {
+	return voteType == valueobject.VoteTypeCrush || voteType == valueobject.VoteTypeCompliment
+}
+
+func ValidateOverrides(overrides map[valueobject.VoteType]uint32) error {
+	for voteType := range overrides {
+		if !IsPremium(voteType) {
+			return fmt.Errorf("%w: `%s` is not a premium vote type", ErrInvalidQuotas, voteType)
+		}
+	}
+	return nil
+}
+
+// Limit returns the daily limit of voteType for a user with overrides, false means it is not limited
+func (p *QuotaPolicy) Limit(voteType valueobject.VoteType, overrides map[valueobject.VoteType]uint32) (uint32, bool) {
+	if limit, ok := overrides[voteType]; ok {
+		return limit, true
+	}
+	limit, ok := p.defaults[voteType]
+	return limit, ok
+}
+
+// Day returns the start of the quota day containing now in the user time zone and when the quota resets,
+// the time zone comes from the user profile and never from the request so a client can not open a new
+// quota day by sending another time zone
+func Day(now time.Time, location *time.Location) (time.Time, time.Time) {
+	local := now.In(location)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// LoadTimeZone resolves the IANA time zone name of a user profile, UTC when it is empty
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeZone, name)
+	}
+	return location, nil
+}
@@ -0,0 +1,70 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaPolicyLimit(t *testing.T) {
+	policy, err := NewQuotaPolicy(config.Config{
+		Voting: config.VotingConfig{DailyQuotas: map[string]uint32{"crush": 1}},
+	})
+	require.NoError(t, err)
+
+	limit, limited := policy.Limit(valueobject.VoteTypeCrush, nil)
+	assert.True(t, limited)
+	assert.Equal(t, uint32(1), limit)
+
+	_, limited = policy.Limit(valueobject.VoteTypeCompliment, nil)
+	assert.False(t, limited)
+
+	overrides := map[valueobject.VoteType]uint32{valueobject.VoteTypeCrush: 0, valueobject.VoteTypeCompliment: 4}
+	limit, limited = policy.Limit(valueobject.VoteTypeCrush, overrides)
+	assert.True(t, limited)
+	assert.Equal(t, uint32(0), limit)
+	limit, _ = policy.Limit(valueobject.VoteTypeCompliment, overrides)
+	assert.Equal(t, uint32(4), limit)
+}
+
+func TestNewQuotaPolicyRejectsNonPremiumVoteTypes(t *testing.T) {
+	_, err := NewQuotaPolicy(config.Config{
+		Voting: config.VotingConfig{DailyQuotas: map[string]uint32{"yes": 10}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidQuotas)
+
+	assert.ErrorIs(t, ValidateOverrides(map[valueobject.VoteType]uint32{valueobject.VoteTypeNo: 1}), ErrInvalidQuotas)
+	assert.NoError(t, ValidateOverrides(map[valueobject.VoteType]uint32{valueobject.VoteTypeCrush: 1}))
+}
+
+func TestDayIsTheDayOfTheUserTimeZone(t *testing.T) {
+	location, err := LoadTimeZone("Asia/Kolkata")
+	require.NoError(t, err)
+	now := time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC)
+
+	// it is already June 2nd 01:30 in Kolkata
+	start, resetAt := Day(now, location)
+	assert.True(t, time.Date(2025, 6, 2, 0, 0, 0, 0, location).Equal(start))
+	assert.True(t, time.Date(2025, 6, 3, 0, 0, 0, 0, location).Equal(resetAt))
+
+	// the instant the day is keyed by does not depend on the location now is expressed in
+	sameStart, _ := Day(now.In(time.UTC), location)
+	assert.True(t, start.Equal(sameStart))
+
+	utcStart, utcResetAt := Day(now, time.UTC)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), utcStart)
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), utcResetAt)
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	location, err := LoadTimeZone("")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, location)
+
+	_, err = LoadTimeZone("Mars/Olympus_Mons")
+	assert.ErrorIs(t, err, ErrInvalidTimeZone)
+}
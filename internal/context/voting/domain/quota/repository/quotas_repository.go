@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+)
+
+//go:generate mockgen -destination=../../../../../testlib/mocks/quotas_repository_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/repository QuotasRepository
+type QuotasRepository interface {
+	GetOverrides(
+		ctx context.Context,
+		activeUserKey sharedValueObject.ActiveUserKey,
+	) (map[romancesValueObject.VoteType]uint32, error)
+
+	// SetOverrides replaces all overrides of the user, an empty map removes them
+	SetOverrides(
+		ctx context.Context,
+		activeUserKey sharedValueObject.ActiveUserKey,
+		overrides map[romancesValueObject.VoteType]uint32,
+	) error
+
+	GetUsage(
+		ctx context.Context,
+		activeUserKey sharedValueObject.ActiveUserKey,
+		day time.Time,
+	) (map[romancesValueObject.VoteType]uint32, error)
+
+	// Consume atomically uses one vote of the quota, ErrQuotaExhausted is returned once limit is reached
+	Consume(
+		ctx context.Context,
+		consumption entity.Consumption,
+		limit uint32,
+		expiresAt time.Time,
+	) error
+
+	Refund(
+		ctx context.Context,
+		consumption entity.Consumption,
+	) error
+}
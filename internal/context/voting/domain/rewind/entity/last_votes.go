@@ -0,0 +1,77 @@
+package entity
+
+import (
+	quotaEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/google/uuid"
+	"time"
+)
+
+// LastVote is an accepted vote of the active user with everything needed to undo it
+type LastVote struct {
+	PeerId uuid.UUID
+	// PeerCountryId is the country the peer was voted in, it is 0 for votes recorded before it was kept
+	PeerCountryId uint16
+	VoteType      valueobject.VoteType
+	VotedAt       time.Time
+	PreviousVote  romanceEntity.Vote
+	RecordedAt    time.Time
+	// CountedYes and CountedNo tell which counters of the RecordedAt hour the vote incremented
+	CountedYes bool
+	CountedNo  bool
+	// PreviousUnmatch is the unmatch the vote ended, rewinding the vote brings it back
+	PreviousUnmatch *romanceEntity.Unmatch
+	// Consumption is the daily quota the vote used, rewinding the vote refunds it
+	Consumption *quotaEntity.Consumption
+}
+
+// VoteId identifies the romance of the vote, a vote without the peer country assumes the country of the active user
+func (v LastVote) VoteId(activeUserKey sharedValueObject.ActiveUserKey) (sharedValueObject.VoteId, error) {
+	if v.PeerCountryId == 0 {
+		return sharedValueObject.NewVoteId(activeUserKey.CountryId(), activeUserKey.ActiveUserId(), v.PeerId)
+	}
+	return sharedValueObject.NewCrossCountryVoteId(activeUserKey.CountryId(), activeUserKey.ActiveUserId(), v.PeerCountryId, v.PeerId)
+}
+
+func (v LastVote) IsSame(other LastVote) bool {
+	return v.PeerId == other.PeerId && v.RecordedAt.Equal(other.RecordedAt)
+}
+
+// IsCurrent tells if vote is still the vote of the active user in the romance
+func (v LastVote) IsCurrent(vote romanceEntity.Vote) bool {
+	return vote.VoteType == v.VoteType && vote.VotedAt != nil && vote.VotedAt.Unix() == v.VotedAt.Unix()
+}
+
+// LastVotes is the ring buffer of the most recent votes of the active user, oldest first
+type LastVotes struct {
+	ActiveUserKey sharedValueObject.ActiveUserKey
+	Votes         []LastVote
+	Version       uint32
+}
+
+// Push adds vote as the newest one and drops the oldest votes beyond size
+func (l *LastVotes) Push(vote LastVote, size int) {
+	l.Votes = append(l.Votes, vote)
+	if len(l.Votes) > size {
+		l.Votes = l.Votes[len(l.Votes)-size:]
+	}
+}
+
+func (l *LastVotes) Newest() (LastVote, bool) {
+	if len(l.Votes) == 0 {
+		return LastVote{}, false
+	}
+	return l.Votes[len(l.Votes)-1], true
+}
+
+func (l *LastVotes) Remove(vote LastVote) {
+	votes := make([]LastVote, 0, len(l.Votes))
+	for _, v := range l.Votes {
+		if !v.IsSame(vote) {
+			votes = append(votes, v)
+		}
+	}
+	l.Votes = votes
+}
@@ -0,0 +1,8 @@
+package rewind
+
+import "errors"
+
+var (
+	ErrNothingToRewind = errors.New("no vote to rewind")
+	ErrVersionConflict = errors.New("last votes version conflict")
+)
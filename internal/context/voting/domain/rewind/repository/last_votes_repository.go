@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"time"
+)
+
+//go:generate mockgen -destination=../../../../../testlib/mocks/last_votes_repository_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/repository LastVotesRepository
+type LastVotesRepository interface {
+	GetLastVotes(ctx context.Context, activeUserKey sharedValueObject.ActiveUserKey) (entity.LastVotes, error)
+	// SaveLastVotes writes the votes while they are still at their version, ErrVersionConflict is returned otherwise
+	SaveLastVotes(ctx context.Context, lastVotes entity.LastVotes, expiresAt time.Time) error
+}
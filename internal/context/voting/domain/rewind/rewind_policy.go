@@ -0,0 +1,38 @@
+package rewind
+
+import (
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
+)
+
+// RewindPolicy bounds which votes can be undone, rewind is disabled with a zero window or history size
+type RewindPolicy struct {
+	window      time.Duration
+	historySize int
+}
+
+func NewRewindPolicy(cfg config.Config) *RewindPolicy {
+	return &RewindPolicy{
+		window:      cfg.Voting.RewindWindow,
+		historySize: cfg.Voting.RewindHistorySize,
+	}
+}
+
+func (p *RewindPolicy) IsEnabled() bool {
+	return p.window > 0 && p.historySize > 0
+}
+
+func (p *RewindPolicy) HistorySize() int {
+	return p.historySize
+}
+
+func (p *RewindPolicy) CanRewind(vote entity.LastVote, now time.Time) bool {
+	return p.IsEnabled() && !now.After(vote.RecordedAt.Add(p.window))
+}
+
+// ExpiresAt is when none of the votes recorded until recordedAt can be rewound anymore
+func (p *RewindPolicy) ExpiresAt(recordedAt time.Time) time.Time {
+	return recordedAt.Add(p.window)
+}
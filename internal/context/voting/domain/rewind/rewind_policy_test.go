@@ -0,0 +1,51 @@
+package rewind
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewindPolicyCanRewindWithinWindow(t *testing.T) {
+	policy := NewRewindPolicy(config.Config{
+		Voting: config.VotingConfig{RewindWindow: 5 * time.Minute, RewindHistorySize: 3},
+	})
+	now := time.Now()
+
+	assert.True(t, policy.CanRewind(entity.LastVote{RecordedAt: now.Add(-4 * time.Minute)}, now))
+	assert.False(t, policy.CanRewind(entity.LastVote{RecordedAt: now.Add(-6 * time.Minute)}, now))
+	assert.Equal(t, now.Add(5*time.Minute), policy.ExpiresAt(now))
+}
+
+func TestRewindPolicyIsDisabledWithoutWindow(t *testing.T) {
+	policy := NewRewindPolicy(config.Config{
+		Voting: config.VotingConfig{RewindHistorySize: 3},
+	})
+	now := time.Now()
+
+	assert.False(t, policy.IsEnabled())
+	assert.False(t, policy.CanRewind(entity.LastVote{RecordedAt: now}, now))
+}
+
+func TestLastVotesKeepNewestVotesUpToSize(t *testing.T) {
+	now := time.Now()
+	lastVotes := entity.LastVotes{}
+	for i := range 4 {
+		lastVotes.Push(entity.LastVote{PeerId: uuid.New(), RecordedAt: now.Add(time.Duration(i) * time.Second)}, 3)
+	}
+
+	require.Len(t, lastVotes.Votes, 3)
+	newest, ok := lastVotes.Newest()
+	require.True(t, ok)
+	assert.Equal(t, now.Add(3*time.Second), newest.RecordedAt)
+
+	lastVotes.Remove(newest)
+	newest, _ = lastVotes.Newest()
+	assert.Equal(t, now.Add(2*time.Second), newest.RecordedAt)
+	assert.Len(t, lastVotes.Votes, 2)
+}
@@ -0,0 +1,44 @@
+package romance
+
+import (
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+)
+
+// EligibilityPolicy decides if a peer can be recommended to the active user again, a no only
+// keeps the peer out for the cooling period while the vote itself is kept
+type EligibilityPolicy struct {
+	noVoteCoolingPeriod time.Duration
+}
+
+func NewEligibilityPolicy(cfg config.Config) *EligibilityPolicy {
+	return &EligibilityPolicy{
+		noVoteCoolingPeriod: cfg.Voting.NoVoteCoolingPeriod,
+	}
+}
+
+// IsNoExpired tells if the vote is a no cast before the cooling period, a zero period never expires
+func (p *EligibilityPolicy) IsNoExpired(vote entity.Vote, now time.Time) bool {
+	if p.noVoteCoolingPeriod <= 0 || vote.VoteType != valueobject.VoteTypeNo || vote.VotedAt == nil {
+		return false
+	}
+	return !vote.VotedAt.After(now.Add(-p.noVoteCoolingPeriod))
+}
+
+// IsEligible only looks at what the active user did, the vote of the peer is not revealed
+func (p *EligibilityPolicy) IsEligible(romance entity.Romance, now time.Time) bool {
+	if romance.IsBlocked() || romance.IsRevoteBanned(now) {
+		return false
+	}
+	switch romance.ActiveUserVote.VoteType {
+	case valueobject.VoteTypeEmpty:
+		return true
+	case valueobject.VoteTypeNo:
+		return p.IsNoExpired(romance.ActiveUserVote, now)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,75 @@
+package romance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEligibilityPolicyIsEligible(t *testing.T) {
+	policy := NewEligibilityPolicy(config.Config{
+		Voting: config.VotingConfig{NoVoteCoolingPeriod: 24 * time.Hour},
+	})
+	now := time.Now()
+	recently := now.Add(-time.Hour)
+	longAgo := now.Add(-48 * time.Hour)
+	bannedUntil := now.Add(time.Hour)
+
+	testCases := []struct {
+		name     string
+		romance  entity.Romance
+		eligible bool
+	}{
+		{name: "no_votes", romance: entity.Romance{}, eligible: true},
+		{
+			name:     "peer_voted",
+			romance:  entity.Romance{PeerUserVote: entity.Vote{VoteType: valueobject.VoteTypeNo, VotedAt: &recently}},
+			eligible: true,
+		},
+		{
+			name:     "recent_no",
+			romance:  entity.Romance{ActiveUserVote: entity.Vote{VoteType: valueobject.VoteTypeNo, VotedAt: &recently}},
+			eligible: false,
+		},
+		{
+			name:     "expired_no",
+			romance:  entity.Romance{ActiveUserVote: entity.Vote{VoteType: valueobject.VoteTypeNo, VotedAt: &longAgo}},
+			eligible: true,
+		},
+		{
+			name:     "yes",
+			romance:  entity.Romance{ActiveUserVote: entity.Vote{VoteType: valueobject.VoteTypeYes, VotedAt: &longAgo}},
+			eligible: false,
+		},
+		{
+			name:     "blocked_by_peer",
+			romance:  entity.Romance{PeerUserBlockedAt: &longAgo},
+			eligible: false,
+		},
+		{
+			name: "revote_banned",
+			romance: entity.Romance{
+				ActiveUserVote: entity.Vote{VoteType: valueobject.VoteTypeNo, VotedAt: &longAgo},
+				Unmatch:        &entity.Unmatch{RevoteBannedUntil: bannedUntil},
+			},
+			eligible: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.eligible, policy.IsEligible(tc.romance, now))
+		})
+	}
+}
+
+func TestEligibilityPolicyWithoutCoolingPeriod(t *testing.T) {
+	policy := NewEligibilityPolicy(config.Config{})
+	longAgo := time.Now().Add(-365 * 24 * time.Hour)
+
+	assert.False(t, policy.IsNoExpired(entity.Vote{VoteType: valueobject.VoteTypeNo, VotedAt: &longAgo}, time.Now()))
+}
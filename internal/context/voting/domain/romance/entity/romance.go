@@ -3,12 +3,18 @@ package entity
 import (
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"time"
 )
 
 type Romance struct {
 	ActiveUserVote Vote
 	PeerUserVote   Vote
 	Version        uint32
+	// ActiveUserBlockedAt and PeerUserBlockedAt are set while the user blocks the other one
+	ActiveUserBlockedAt *time.Time
+	PeerUserBlockedAt   *time.Time
+	// Unmatch is set once the romance was unmatched until the next accepted vote
+	Unmatch *Unmatch
 }
 
 func CreateEmptyRomance(voteId sharedValueObject.VoteId) Romance {
@@ -18,6 +24,47 @@ func CreateEmptyRomance(voteId sharedValueObject.VoteId) Romance {
 	}
 }
 
+// IsBlocked tells if either user blocks the other one, a block wins over any vote
+func (r *Romance) IsBlocked() bool {
+	return r.ActiveUserBlockedAt != nil || r.PeerUserBlockedAt != nil
+}
+
+// IsMutual tells if both users voted positively, the romance is a match
+func (r *Romance) IsMutual() bool {
+	return r.ActiveUserVote.VoteType.IsPositive() && r.PeerUserVote.VoteType.IsPositive()
+}
+
+// IsMatched tells if the romance is mutual and was not unmatched
+func (r *Romance) IsMatched() bool {
+	return r.IsMutual() && r.Unmatch == nil
+}
+
+func (r *Romance) IsUnmatched() bool {
+	return r.Unmatch != nil
+}
+
+// IsRevoteBanned tells if votes in the romance are rejected after an unmatch
+func (r *Romance) IsRevoteBanned(now time.Time) bool {
+	return r.Unmatch != nil && r.Unmatch.IsRevoteBanned(now)
+}
+
+// State blocks win over unmatches and unmatches win over votes
+func (r *Romance) State() valueobject.RomanceState {
+	if r.IsBlocked() {
+		return valueobject.RomanceStateBlocked
+	}
+	if r.IsUnmatched() {
+		return valueobject.RomanceStateUnmatched
+	}
+	return valueobject.RomanceStateFromVotes(r.ActiveUserVote.VoteType, r.PeerUserVote.VoteType)
+}
+
 func (r *Romance) IsEmpty() bool {
 	return r.ActiveUserVote.VoteType == valueobject.VoteTypeEmpty && r.PeerUserVote.VoteType == valueobject.VoteTypeEmpty
 }
+
+// RomancesPage lists romances of the active user, NextCursor is empty on the last page
+type RomancesPage struct {
+	Romances   []Romance
+	NextCursor string
+}
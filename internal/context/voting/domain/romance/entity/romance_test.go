@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRomanceState(t *testing.T) {
+	blockedAt := time.Now()
+
+	testCases := []struct {
+		name    string
+		romance Romance
+		state   valueobject.RomanceState
+	}{
+		{name: "empty", romance: Romance{}, state: valueobject.RomanceStateEmpty},
+		{
+			name:    "outgoing_pending",
+			romance: Romance{ActiveUserVote: Vote{VoteType: valueobject.VoteTypeCrush}},
+			state:   valueobject.RomanceStateOutgoingPending,
+		},
+		{
+			name:    "incoming_pending",
+			romance: Romance{PeerUserVote: Vote{VoteType: valueobject.VoteTypeYes}},
+			state:   valueobject.RomanceStateIncomingPending,
+		},
+		{
+			name: "matched",
+			romance: Romance{
+				ActiveUserVote: Vote{VoteType: valueobject.VoteTypeYes},
+				PeerUserVote:   Vote{VoteType: valueobject.VoteTypeCompliment},
+			},
+			state: valueobject.RomanceStateMatched,
+		},
+		{
+			name: "rejected_by_peer",
+			romance: Romance{
+				ActiveUserVote: Vote{VoteType: valueobject.VoteTypeYes},
+				PeerUserVote:   Vote{VoteType: valueobject.VoteTypeNo},
+			},
+			state: valueobject.RomanceStateRejected,
+		},
+		{
+			name:    "rejected_by_active_user",
+			romance: Romance{ActiveUserVote: Vote{VoteType: valueobject.VoteTypeNo}},
+			state:   valueobject.RomanceStateRejected,
+		},
+		{
+			name: "mutually_rejected",
+			romance: Romance{
+				ActiveUserVote: Vote{VoteType: valueobject.VoteTypeNo},
+				PeerUserVote:   Vote{VoteType: valueobject.VoteTypeNo},
+			},
+			state: valueobject.RomanceStateMutuallyRejected,
+		},
+		{
+			name: "unmatched",
+			romance: Romance{
+				ActiveUserVote: Vote{VoteType: valueobject.VoteTypeYes},
+				PeerUserVote:   Vote{VoteType: valueobject.VoteTypeYes},
+				Unmatch:        &Unmatch{},
+			},
+			state: valueobject.RomanceStateUnmatched,
+		},
+		{
+			name: "blocked",
+			romance: Romance{
+				ActiveUserVote:    Vote{VoteType: valueobject.VoteTypeYes},
+				PeerUserVote:      Vote{VoteType: valueobject.VoteTypeYes},
+				PeerUserBlockedAt: &blockedAt,
+			},
+			state: valueobject.RomanceStateBlocked,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.state, tc.romance.State())
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Unmatch is the terminal state of a mutual romance ended by one of the users
+type Unmatch struct {
+	UnmatchedBy uuid.UUID
+	UnmatchedAt time.Time
+	Reason      valueobject.UnmatchReason
+	// RevoteBannedUntil is when the users may vote on each other again
+	RevoteBannedUntil time.Time
+}
+
+func (u Unmatch) IsRevoteBanned(now time.Time) bool {
+	return now.Before(u.RevoteBannedUntil)
+}
@@ -12,4 +12,14 @@ type Vote struct {
 	VotedAt   *time.Time
 	CreatedAt *time.Time
 	UpdatedAt *time.Time
+	// Context is set when the client attributed the vote to a recommendation
+	Context *valueobject.VoteContext
+	// Compliment is set for compliment votes sent with a message
+	Compliment *valueobject.Compliment
+}
+
+// IsNewerThan compares at second precision, the precision voted_at is stored with,
+// so a vote with the same timestamp still wins.
+func (v Vote) IsNewerThan(votedAt time.Time) bool {
+	return v.VotedAt != nil && v.VotedAt.Unix() > votedAt.Unix()
 }
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	"github.com/google/uuid"
+	"time"
+)
+
+// VoteHistoryEntry is an immutable record of one vote write in a romance, both users share the history
+type VoteHistoryEntry struct {
+	ActorId      uuid.UUID
+	Action       valueobject.VoteAction
+	FromVoteType valueobject.VoteType
+	ToVoteType   valueobject.VoteType
+	// VotedAt is the client time of the written vote, it is empty for deletions
+	VotedAt    *time.Time
+	RecordedAt time.Time
+	RequestId  string
+	// RomanceVersion is the version the write produced
+	RomanceVersion uint32
+}
+
+// VoteHistoryPage lists entries newest first, NextCursor is empty on the last page
+type VoteHistoryPage struct {
+	Entries    []VoteHistoryEntry
+	NextCursor string
+}
@@ -7,10 +7,24 @@ import (
 )
 
 var (
-	ErrVoteNotFound    = errors.New("vote not found")
-	ErrWrongVote       = errors.New("wrong vote")
-	ErrVoteDuplicate   = errors.New("vote duplicate")
-	ErrVersionConflict = errors.New("version conflict")
+	ErrVoteNotFound          = errors.New("vote not found")
+	ErrWrongVote             = errors.New("wrong vote")
+	ErrVoteDuplicate         = errors.New("vote duplicate")
+	ErrVersionConflict       = errors.New("version conflict")
+	ErrVersionMismatch       = errors.New("romance version does not match the expected one")
+	ErrStaleVote             = errors.New("a newer vote is already stored")
+	ErrVotedAtInFuture       = errors.New("voted_at is in the future")
+	ErrVotedAtTooOld         = errors.New("voted_at is too old")
+	ErrRomanceBlocked        = errors.New("romance is blocked")
+	ErrNotMatched            = errors.New("romance is not a match")
+	ErrUnmatched             = errors.New("romance was unmatched, voting is not allowed yet")
+	ErrUnknownUnmatchReason  = errors.New("unknown unmatch reason")
+	ErrInvalidHistoryCursor  = errors.New("invalid history cursor")
+	ErrInvalidRomancesCursor = errors.New("invalid romances cursor")
+	ErrUnknownRomanceState   = errors.New("unknown romance state")
+	ErrComplimentNotAllowed  = errors.New("compliment text is only allowed with a compliment vote")
+	ErrComplimentNotFound    = errors.New("compliment not found")
+	ErrModerationStatus      = errors.New("moderation can only approve or reject a compliment")
 )
 
 func NewChangingVoteTypeError(oldVote valueobject.VoteType, newVote valueobject.VoteType) error {
@@ -0,0 +1,80 @@
+package romance
+
+import (
+	"slices"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+)
+
+// PeerVoteProjectionPolicy hides the vote of the peer from restricted callers, so a "no" can not be
+// told apart from a missing vote. The vote is shown in a mutual romance or when it is meant to be seen.
+type PeerVoteProjectionPolicy struct {
+	visibleVoteTypes []valueobject.VoteType
+}
+
+func NewPeerVoteProjectionPolicy() *PeerVoteProjectionPolicy {
+	return &PeerVoteProjectionPolicy{
+		visibleVoteTypes: []valueobject.VoteType{valueobject.VoteTypeCrush, valueobject.VoteTypeCompliment},
+	}
+}
+
+func (p *PeerVoteProjectionPolicy) Project(romance entity.Romance, unrestricted bool) entity.Romance {
+	if unrestricted || romance.IsMutual() || slices.Contains(p.visibleVoteTypes, romance.PeerUserVote.VoteType) {
+		return romance
+	}
+	romance.PeerUserVote = entity.Vote{Id: romance.PeerUserVote.Id}
+	return romance
+}
+
+// ProjectHistory hides the votes of the peer in the history entries like Project does, a hidden vote
+// type reads as empty so the entry looks like an add or a delete, entries left without a vote are dropped
+// unless they unmatched the romance
+func (p *PeerVoteProjectionPolicy) ProjectHistory(
+	romance entity.Romance,
+	page entity.VoteHistoryPage,
+	unrestricted bool,
+) entity.VoteHistoryPage {
+	if unrestricted || romance.IsMutual() {
+		return page
+	}
+
+	peerId := romance.ActiveUserVote.Id.PeerUserId()
+	entries := make([]entity.VoteHistoryEntry, 0, len(page.Entries))
+	for _, entry := range page.Entries {
+		if entry.ActorId != peerId {
+			entries = append(entries, entry)
+			continue
+		}
+
+		fromHidden := !p.isVisible(entry.FromVoteType)
+		toHidden := !p.isVisible(entry.ToVoteType)
+		if fromHidden {
+			entry.FromVoteType = valueobject.VoteTypeEmpty
+		}
+		// the unmatch ended a match both users saw, only the vote it removed is hidden
+		if entry.Action == valueobject.VoteActionUnmatch {
+			entries = append(entries, entry)
+			continue
+		}
+		if toHidden {
+			entry.ToVoteType = valueobject.VoteTypeEmpty
+		}
+		switch {
+		case entry.FromVoteType.IsEmpty() && entry.ToVoteType.IsEmpty():
+			continue
+		case toHidden:
+			entry.Action = valueobject.VoteActionDelete
+			entry.VotedAt = nil
+		case fromHidden:
+			entry.Action = valueobject.VoteActionAdd
+		}
+		entries = append(entries, entry)
+	}
+	page.Entries = entries
+	return page
+}
+
+func (p *PeerVoteProjectionPolicy) isVisible(voteType valueobject.VoteType) bool {
+	return voteType.IsEmpty() || slices.Contains(p.visibleVoteTypes, voteType)
+}
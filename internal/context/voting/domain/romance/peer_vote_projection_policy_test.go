@@ -0,0 +1,110 @@
+package romance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerVoteProjectionPolicy(t *testing.T) {
+	policy := NewPeerVoteProjectionPolicy()
+
+	testCases := []struct {
+		name         string
+		activeUser   valueobject.VoteType
+		peer         valueobject.VoteType
+		unrestricted bool
+		visible      bool
+	}{
+		{name: "peer_no", activeUser: valueobject.VoteTypeYes, peer: valueobject.VoteTypeNo, visible: false},
+		{name: "peer_yes_pending", activeUser: valueobject.VoteTypeEmpty, peer: valueobject.VoteTypeYes, visible: false},
+		{name: "peer_yes_mutual", activeUser: valueobject.VoteTypeCrush, peer: valueobject.VoteTypeYes, visible: true},
+		{name: "peer_crush", activeUser: valueobject.VoteTypeNo, peer: valueobject.VoteTypeCrush, visible: true},
+		{name: "peer_compliment", activeUser: valueobject.VoteTypeEmpty, peer: valueobject.VoteTypeCompliment, visible: true},
+		{name: "unrestricted", activeUser: valueobject.VoteTypeYes, peer: valueobject.VoteTypeNo, unrestricted: true, visible: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			romance := entity.Romance{
+				ActiveUserVote: entity.Vote{VoteType: tc.activeUser},
+				PeerUserVote:   entity.Vote{VoteType: tc.peer, Context: &valueobject.VoteContext{RecommendationId: "rec-1"}},
+			}
+
+			projected := policy.Project(romance, tc.unrestricted)
+
+			assert.Equal(t, tc.activeUser, projected.ActiveUserVote.VoteType)
+			if tc.visible {
+				assert.Equal(t, romance.PeerUserVote, projected.PeerUserVote)
+			} else {
+				assert.Equal(t, entity.Vote{}, projected.PeerUserVote)
+			}
+		})
+	}
+}
+
+func TestPeerVoteProjectionPolicyProjectHistory(t *testing.T) {
+	policy := NewPeerVoteProjectionPolicy()
+	activeUserId := uuid.New()
+	peerId := uuid.New()
+	voteId, err := sharedValueObject.NewVoteId(11, activeUserId, peerId)
+	assert.NoError(t, err)
+	votedAt := time.Now()
+
+	peerEntry := func(action valueobject.VoteAction, from, to valueobject.VoteType) entity.VoteHistoryEntry {
+		return entity.VoteHistoryEntry{ActorId: peerId, Action: action, FromVoteType: from, ToVoteType: to, VotedAt: &votedAt}
+	}
+	activeUserEntry := entity.VoteHistoryEntry{
+		ActorId:    activeUserId,
+		Action:     valueobject.VoteActionAdd,
+		ToVoteType: valueobject.VoteTypeYes,
+		VotedAt:    &votedAt,
+	}
+	page := entity.VoteHistoryPage{
+		Entries: []entity.VoteHistoryEntry{
+			peerEntry(valueobject.VoteActionUnmatch, valueobject.VoteTypeYes, valueobject.VoteTypeEmpty),
+			peerEntry(valueobject.VoteActionChange, valueobject.VoteTypeCrush, valueobject.VoteTypeNo),
+			peerEntry(valueobject.VoteActionChange, valueobject.VoteTypeYes, valueobject.VoteTypeCrush),
+			peerEntry(valueobject.VoteActionAdd, valueobject.VoteTypeEmpty, valueobject.VoteTypeYes),
+			activeUserEntry,
+		},
+		NextCursor: "next",
+	}
+
+	t.Run("restricted", func(t *testing.T) {
+		romance := entity.CreateEmptyRomance(voteId)
+		romance.ActiveUserVote.VoteType = valueobject.VoteTypeYes
+		romance.PeerUserVote.VoteType = valueobject.VoteTypeNo
+
+		projected := policy.ProjectHistory(romance, page, false)
+
+		assert.Equal(t, entity.VoteHistoryPage{
+			Entries: []entity.VoteHistoryEntry{
+				peerEntry(valueobject.VoteActionUnmatch, valueobject.VoteTypeEmpty, valueobject.VoteTypeEmpty),
+				{ActorId: peerId, Action: valueobject.VoteActionDelete, FromVoteType: valueobject.VoteTypeCrush},
+				peerEntry(valueobject.VoteActionAdd, valueobject.VoteTypeEmpty, valueobject.VoteTypeCrush),
+				activeUserEntry,
+			},
+			NextCursor: "next",
+		}, projected)
+	})
+
+	t.Run("mutual", func(t *testing.T) {
+		romance := entity.CreateEmptyRomance(voteId)
+		romance.ActiveUserVote.VoteType = valueobject.VoteTypeYes
+		romance.PeerUserVote.VoteType = valueobject.VoteTypeYes
+
+		assert.Equal(t, page, policy.ProjectHistory(romance, page, false))
+	})
+
+	t.Run("unrestricted", func(t *testing.T) {
+		romance := entity.CreateEmptyRomance(voteId)
+
+		assert.Equal(t, page, policy.ProjectHistory(romance, page, true))
+	})
+}
@@ -0,0 +1,19 @@
+package provider
+
+import (
+	"context"
+
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+)
+
+//go:generate mockgen -destination=../../../../../testlib/mocks/moderation_provider_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/provider ModerationProvider
+type ModerationProvider interface {
+	// Moderate decides the status of a new compliment text, a pending status leaves the decision
+	// to a later moderation callback
+	Moderate(
+		ctx context.Context,
+		voteId sharedValueObject.VoteId,
+		text string,
+	) (romancesValueObject.ModerationStatus, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/google/uuid"
+)
+
+// ExclusionFiltersRepository keeps built exclusion filters per user key. Filters are only kept for a
+// while, so adding and deleting never fail, reading and saving fail so a broken store is not hidden
+// behind a rebuild on every request.
+//
+//go:generate mockgen -destination=../../../../../testlib/mocks/exclusion_filters_repository_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository ExclusionFiltersRepository
+type ExclusionFiltersRepository interface {
+	// GetExclusionFilter returns false without a kept filter
+	GetExclusionFilter(ctx context.Context, userKey sharedValueObject.ActiveUserKey) (romancesValueObject.ExclusionFilter, bool, error)
+	SaveExclusionFilter(ctx context.Context, userKey sharedValueObject.ActiveUserKey, filter romancesValueObject.ExclusionFilter) error
+	// AddToExclusionFilter adds the peer to a kept filter of the user, nothing is done without one
+	AddToExclusionFilter(ctx context.Context, userKey sharedValueObject.ActiveUserKey, peerId uuid.UUID)
+	// DeleteExclusionFilter drops the filter once a peer is no longer excluded, peers can not be removed
+	// from a filter
+	DeleteExclusionFilter(ctx context.Context, userKey sharedValueObject.ActiveUserKey)
+}
@@ -13,6 +13,20 @@ import (
 type RomancesRepository interface {
 	GetRomance(ctx context.Context, voteId sharedValueObject.VoteId) (entity.Romance, error)
 	GetAllPeersForActiveUser(ctx context.Context, activeUserKey sharedValueObject.ActiveUserKey) (<-chan uuid.UUID, error)
+	// ListRomancesForActiveUser pages through the romances of the active user, an empty cursor starts at the first one
+	ListRomancesForActiveUser(
+		ctx context.Context,
+		activeUserKey sharedValueObject.ActiveUserKey,
+		limit int32,
+		cursor string,
+	) (entity.RomancesPage, error)
+	// GetRomancesGroup reads the romances of the active user with the peers in the order of the peers,
+	// romances which are not stored are returned empty
+	GetRomancesGroup(
+		ctx context.Context,
+		userKey sharedValueObject.ActiveUserKey,
+		peerIds []uuid.UUID,
+	) ([]entity.Romance, error)
 	DeleteRomance(ctx context.Context, voteId sharedValueObject.VoteId) error
 	DeleteRomancesGroup(
 		ctx context.Context,
@@ -24,11 +38,44 @@ type RomancesRepository interface {
 		romance entity.Romance,
 		voteType romancesValueObject.VoteType,
 		votedAt time.Time,
+		voteContext *romancesValueObject.VoteContext,
+		compliment *romancesValueObject.Compliment,
 	) (entity.Romance, error)
 	ChangeActiveUserVoteTypeInRomance(
 		ctx context.Context,
 		romance entity.Romance,
 		newVoteType romancesValueObject.VoteType,
+		votedAt time.Time,
+		voteContext *romancesValueObject.VoteContext,
+		compliment *romancesValueObject.Compliment,
+	) (entity.Romance, error)
+	// DeleteActiveUserVoteFromRomance ends the unmatched state of the romance unless its revote ban still lasts
+	DeleteActiveUserVoteFromRomance(ctx context.Context, romance entity.Romance) (entity.Romance, error)
+	// BlockPeerInRomance stores the block of the active user, the romance item is created when missing
+	BlockPeerInRomance(ctx context.Context, romance entity.Romance, blockedAt time.Time) (entity.Romance, error)
+	UnblockPeerInRomance(ctx context.Context, romance entity.Romance) (entity.Romance, error)
+	// UnmatchRomance stores the unmatch, removes the votes of both users and applies the dead romance TTL
+	UnmatchRomance(ctx context.Context, romance entity.Romance, unmatch entity.Unmatch) (entity.Romance, error)
+	// GetVoteHistory pages through the vote history of the romance newest first, an empty cursor starts at the newest entry
+	GetVoteHistory(
+		ctx context.Context,
+		voteId sharedValueObject.VoteId,
+		limit int32,
+		cursor string,
+	) (entity.VoteHistoryPage, error)
+	// ModerateComplimentInRomance sets the moderation status of the compliment of the active user
+	ModerateComplimentInRomance(
+		ctx context.Context,
+		romance entity.Romance,
+		status romancesValueObject.ModerationStatus,
+		moderatedAt time.Time,
+	) (entity.Romance, error)
+	// RestoreActiveUserVoteInRomance replaces the vote of the active user with an earlier one, e.g. on rewind,
+	// a non-nil unmatch ended by the replaced vote is stored again
+	RestoreActiveUserVoteInRomance(
+		ctx context.Context,
+		romance entity.Romance,
+		vote entity.Vote,
+		unmatch *entity.Unmatch,
 	) (entity.Romance, error)
-	DeleteActiveUserVoteFromRomance(ctx context.Context, romance entity.Romance) error
 }
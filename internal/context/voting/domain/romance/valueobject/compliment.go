@@ -0,0 +1,48 @@
+package valueobject
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ModerationStatus uint8
+
+const (
+	ModerationStatusPending ModerationStatus = iota
+	ModerationStatusApproved
+	ModerationStatusRejected
+)
+
+var ModerationStatusToString = map[ModerationStatus]string{
+	ModerationStatusPending:  "pending",
+	ModerationStatusApproved: "approved",
+	ModerationStatusRejected: "rejected",
+}
+
+func ModerationStatusFromString(name string) (ModerationStatus, bool) {
+	for status, statusName := range ModerationStatusToString {
+		if statusName == name {
+			return status, true
+		}
+	}
+	return ModerationStatusPending, false
+}
+
+func (s ModerationStatus) String() string {
+	return ModerationStatusToString[s]
+}
+
+// Compliment is the message sent with a compliment vote, the peer only sees it once it is approved
+type Compliment struct {
+	// Id tells moderation callbacks apart when the compliment was replaced in the meantime
+	Id     uuid.UUID
+	Text   string
+	Status ModerationStatus
+	// ModeratedAt is set once the status was decided
+	ModeratedAt *time.Time
+}
+
+func (c *Compliment) IsApproved() bool {
+	return c != nil && c.Status == ModerationStatusApproved
+}
@@ -0,0 +1,127 @@
+package valueobject
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+
+	"github.com/google/uuid"
+)
+
+const (
+	exclusionFilterFormatVersion = 1
+	exclusionFilterHeaderSize    = 6
+	exclusionFilterMinBits       = 64
+	exclusionFilterMaxHashes     = 30
+)
+
+var ErrInvalidFalsePositiveRate = errors.New("false positive rate must be between 0 and 1")
+
+// ExclusionFilter is a Bloom filter of peer ids, it never misses an added peer but may report a
+// peer which was not added with the false positive rate it was sized for.
+//
+// The binary form is a format version byte, the hash count byte, the bit count as big endian uint32
+// and the bits, bit i is 1<<(i%8) of byte i/8. The bits of a peer are (h1 + j*h2) mod bit count for
+// j below the hash count, h1 and h2 are the low and high halves of the FNV-1a 64 hash of the 16 peer
+// id bytes, h2 with its lowest bit set.
+type ExclusionFilter struct {
+	hashes uint8
+	bits   []byte
+}
+
+// NewExclusionFilter sizes the filter for the expected number of peers
+func NewExclusionFilter(capacity int, falsePositiveRate float64) (ExclusionFilter, error) {
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return ExclusionFilter{}, fmt.Errorf("%w: %v", ErrInvalidFalsePositiveRate, falsePositiveRate)
+	}
+	capacity = max(capacity, 1)
+
+	bitsCount := math.Ceil(-float64(capacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	bitsCount = max(bitsCount, exclusionFilterMinBits)
+	hashes := math.Round(bitsCount / float64(capacity) * math.Ln2)
+	hashes = min(max(hashes, 1), exclusionFilterMaxHashes)
+
+	return ExclusionFilter{
+		hashes: uint8(hashes),
+		bits:   make([]byte, (int(bitsCount)+7)/8),
+	}, nil
+}
+
+func ExclusionFilterFromBinary(data []byte) (ExclusionFilter, error) {
+	if len(data) < exclusionFilterHeaderSize || data[0] != exclusionFilterFormatVersion {
+		return ExclusionFilter{}, errors.New("unknown exclusion filter format")
+	}
+	bitsCount := binary.BigEndian.Uint32(data[2:exclusionFilterHeaderSize])
+	if data[1] == 0 || bitsCount%8 != 0 || int(bitsCount/8) != len(data)-exclusionFilterHeaderSize {
+		return ExclusionFilter{}, errors.New("corrupted exclusion filter")
+	}
+	return ExclusionFilter{
+		hashes: data[1],
+		bits:   append([]byte(nil), data[exclusionFilterHeaderSize:]...),
+	}, nil
+}
+
+// Add changes the filter in place, see Clone for filters which are shared
+func (f ExclusionFilter) Add(peerId uuid.UUID) {
+	f.eachBit(peerId, func(i uint64) bool {
+		f.bits[i/8] |= 1 << (i % 8)
+		return true
+	})
+}
+
+func (f ExclusionFilter) MayContain(peerId uuid.UUID) bool {
+	contains := len(f.bits) > 0
+	f.eachBit(peerId, func(i uint64) bool {
+		contains = f.bits[i/8]&(1<<(i%8)) != 0
+		return contains
+	})
+	return contains
+}
+
+// FalsePositiveRate estimates the rate from the share of set bits, it passes the rate the filter was
+// sized for once more peers than its capacity were added
+func (f ExclusionFilter) FalsePositiveRate() float64 {
+	if len(f.bits) == 0 {
+		return 1
+	}
+	setBits := 0
+	for _, b := range f.bits {
+		setBits += bits.OnesCount8(b)
+	}
+	return math.Pow(float64(setBits)/float64(len(f.bits)*8), float64(f.hashes))
+}
+
+func (f ExclusionFilter) Clone() ExclusionFilter {
+	return ExclusionFilter{
+		hashes: f.hashes,
+		bits:   append([]byte(nil), f.bits...),
+	}
+}
+
+func (f ExclusionFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, exclusionFilterHeaderSize, exclusionFilterHeaderSize+len(f.bits))
+	data[0] = exclusionFilterFormatVersion
+	data[1] = f.hashes
+	binary.BigEndian.PutUint32(data[2:], uint32(len(f.bits)*8))
+	return append(data, f.bits...), nil
+}
+
+func (f ExclusionFilter) eachBit(peerId uuid.UUID, fn func(i uint64) bool) {
+	if len(f.bits) == 0 {
+		return
+	}
+	hash := fnv.New64a()
+	_, _ = hash.Write(peerId[:])
+	sum := hash.Sum64()
+	h1, h2 := sum&math.MaxUint32, sum>>32|1
+
+	bitsCount := uint64(len(f.bits)) * 8
+	for j := uint64(0); j < uint64(f.hashes); j++ {
+		if !fn((h1 + j*h2) % bitsCount) {
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package valueobject
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExclusionFilterContainsAddedPeers(t *testing.T) {
+	filter, err := NewExclusionFilter(1000, 0.01)
+	require.NoError(t, err)
+
+	added := make([]uuid.UUID, 1000)
+	for i := range added {
+		added[i] = uuid.New()
+		filter.Add(added[i])
+	}
+	for _, peerId := range added {
+		assert.True(t, filter.MayContain(peerId))
+	}
+
+	falsePositives := 0
+	for range 10_000 {
+		if filter.MayContain(uuid.New()) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 300, "about 1% of unknown peers may be reported")
+}
+
+func TestExclusionFilterBinaryRoundTrip(t *testing.T) {
+	filter, err := NewExclusionFilter(10, 0.001)
+	require.NoError(t, err)
+	peerId := uuid.New()
+	filter.Add(peerId)
+
+	data, err := filter.MarshalBinary()
+	require.NoError(t, err)
+	decoded, err := ExclusionFilterFromBinary(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, filter, decoded)
+	assert.True(t, decoded.MayContain(peerId))
+
+	_, err = ExclusionFilterFromBinary(data[:len(data)-1])
+	assert.Error(t, err)
+}
+
+func TestExclusionFilterCloneIsIndependent(t *testing.T) {
+	filter, err := NewExclusionFilter(10, 0.01)
+	require.NoError(t, err)
+	peerId := uuid.New()
+
+	clone := filter.Clone()
+	clone.Add(peerId)
+
+	assert.False(t, filter.MayContain(peerId))
+	assert.True(t, clone.MayContain(peerId))
+}
+
+func TestExclusionFilterRejectsInvalidRate(t *testing.T) {
+	for _, rate := range []float64{0, 1, -0.5, 2} {
+		_, err := NewExclusionFilter(10, rate)
+		assert.ErrorIs(t, err, ErrInvalidFalsePositiveRate)
+	}
+}
+
+func TestExclusionFilterFalsePositiveRatePassesRateOverCapacity(t *testing.T) {
+	filter, err := NewExclusionFilter(100, 0.01)
+	require.NoError(t, err)
+	assert.Zero(t, filter.FalsePositiveRate())
+
+	for range 100 {
+		filter.Add(uuid.New())
+	}
+	assert.LessOrEqual(t, filter.FalsePositiveRate(), 0.015)
+
+	for range 100 {
+		filter.Add(uuid.New())
+	}
+	assert.Greater(t, filter.FalsePositiveRate(), 0.01)
+}
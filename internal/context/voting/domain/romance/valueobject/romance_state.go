@@ -0,0 +1,57 @@
+package valueobject
+
+// RomanceState is the state of a romance from the active user's perspective
+type RomanceState uint8
+
+const (
+	RomanceStateEmpty RomanceState = iota
+	RomanceStateOutgoingPending
+	RomanceStateIncomingPending
+	RomanceStateMatched
+	RomanceStateRejected
+	RomanceStateMutuallyRejected
+	RomanceStateBlocked
+	RomanceStateUnmatched
+)
+
+var RomanceStateToString = map[RomanceState]string{
+	RomanceStateEmpty:            "empty",
+	RomanceStateOutgoingPending:  "outgoing_pending",
+	RomanceStateIncomingPending:  "incoming_pending",
+	RomanceStateMatched:          "matched",
+	RomanceStateRejected:         "rejected",
+	RomanceStateMutuallyRejected: "mutually_rejected",
+	RomanceStateBlocked:          "blocked",
+	RomanceStateUnmatched:        "unmatched",
+}
+
+func RomanceStateFromString(name string) (RomanceState, bool) {
+	for state, stateName := range RomanceStateToString {
+		if stateName == name {
+			return state, true
+		}
+	}
+	return RomanceStateEmpty, false
+}
+
+// RomanceStateFromVotes derives the state from the votes alone, blocks and unmatches are not considered
+func RomanceStateFromVotes(activeUserVoteType VoteType, peerUserVoteType VoteType) RomanceState {
+	switch {
+	case activeUserVoteType.IsNegative() && peerUserVoteType.IsNegative():
+		return RomanceStateMutuallyRejected
+	case activeUserVoteType.IsNegative() || peerUserVoteType.IsNegative():
+		return RomanceStateRejected
+	case activeUserVoteType.IsPositive() && peerUserVoteType.IsPositive():
+		return RomanceStateMatched
+	case activeUserVoteType.IsPositive():
+		return RomanceStateOutgoingPending
+	case peerUserVoteType.IsPositive():
+		return RomanceStateIncomingPending
+	default:
+		return RomanceStateEmpty
+	}
+}
+
+func (s RomanceState) String() string {
+	return RomanceStateToString[s]
+}
@@ -0,0 +1,34 @@
+package valueobject
+
+type UnmatchReason uint8
+
+const (
+	UnmatchReasonUnspecified UnmatchReason = iota
+	UnmatchReasonNotInterested
+	UnmatchReasonNoResponse
+	UnmatchReasonInappropriate
+	UnmatchReasonFakeProfile
+	UnmatchReasonMetSomeone
+)
+
+var UnmatchReasonToString = map[UnmatchReason]string{
+	UnmatchReasonUnspecified:   "unspecified",
+	UnmatchReasonNotInterested: "not_interested",
+	UnmatchReasonNoResponse:    "no_response",
+	UnmatchReasonInappropriate: "inappropriate",
+	UnmatchReasonFakeProfile:   "fake_profile",
+	UnmatchReasonMetSomeone:    "met_someone",
+}
+
+func UnmatchReasonFromString(name string) (UnmatchReason, bool) {
+	for reason, reasonName := range UnmatchReasonToString {
+		if reasonName == name {
+			return reason, true
+		}
+	}
+	return UnmatchReasonUnspecified, false
+}
+
+func (r UnmatchReason) String() string {
+	return UnmatchReasonToString[r]
+}
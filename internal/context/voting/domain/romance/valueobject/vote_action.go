@@ -0,0 +1,26 @@
+package valueobject
+
+// VoteAction is what happened to the vote of a user in a romance history entry
+type VoteAction uint8
+
+const (
+	VoteActionAdd VoteAction = iota + 1
+	VoteActionChange
+	VoteActionDelete
+	VoteActionRewind
+	VoteActionModerate
+	VoteActionUnmatch
+)
+
+var VoteActionToString = map[VoteAction]string{
+	VoteActionAdd:      "add",
+	VoteActionChange:   "change",
+	VoteActionDelete:   "delete",
+	VoteActionRewind:   "rewind",
+	VoteActionModerate: "moderate",
+	VoteActionUnmatch:  "unmatch",
+}
+
+func (a VoteAction) String() string {
+	return VoteActionToString[a]
+}
@@ -0,0 +1,41 @@
+package valueobject
+
+// VoteSurface is the part of the app a vote was cast from
+type VoteSurface uint8
+
+const (
+	VoteSurfaceUnspecified VoteSurface = iota
+	VoteSurfaceDeck
+	VoteSurfaceProfile
+	VoteSurfaceSearch
+)
+
+var VoteSurfaceToString = map[VoteSurface]string{
+	VoteSurfaceUnspecified: "unspecified",
+	VoteSurfaceDeck:        "deck",
+	VoteSurfaceProfile:     "profile",
+	VoteSurfaceSearch:      "search",
+}
+
+func VoteSurfaceFromString(name string) (VoteSurface, bool) {
+	for surface, surfaceName := range VoteSurfaceToString {
+		if surfaceName == name {
+			return surface, true
+		}
+	}
+	return VoteSurfaceUnspecified, false
+}
+
+func (s VoteSurface) String() string {
+	return VoteSurfaceToString[s]
+}
+
+// VoteContext attributes a vote to the recommendation it was cast on, it is replaced by every
+// accepted vote of the user and removed together with the vote
+type VoteContext struct {
+	Surface          VoteSurface
+	RecommendationId string
+	// Position is the zero based position of the peer in the recommendations, nil when unknown
+	Position         *uint32
+	ExperimentBucket string
+}
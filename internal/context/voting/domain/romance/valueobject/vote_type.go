@@ -18,6 +18,15 @@ var UserVoteTypeToString = map[VoteType]string{
 	VoteTypeCompliment: "compliment",
 }
 
+func VoteTypeFromString(name string) (VoteType, bool) {
+	for voteType, voteTypeName := range UserVoteTypeToString {
+		if voteTypeName == name {
+			return voteType, true
+		}
+	}
+	return VoteTypeEmpty, false
+}
+
 func (v VoteType) IsPositive() bool {
 	return v == VoteTypeYes || v == VoteTypeCrush || v == VoteTypeCompliment
 }
@@ -0,0 +1,176 @@
+package romance
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+)
+
+const (
+	voteTypesSeparator       = "|"
+	countryOverrideSeparator = "/"
+)
+
+var ErrInvalidVoteTransitions = errors.New("invalid vote transitions")
+
+// DefaultVoteTransitions are used unless the transitions are configured, a vote can only be upgraded
+var DefaultVoteTransitions = map[valueobject.VoteType][]valueobject.VoteType{
+	valueobject.VoteTypeEmpty: {
+		valueobject.VoteTypeNo,
+		valueobject.VoteTypeYes,
+		valueobject.VoteTypeCrush,
+		valueobject.VoteTypeCompliment,
+	},
+	valueobject.VoteTypeNo: {
+		valueobject.VoteTypeYes,
+		valueobject.VoteTypeCrush,
+		valueobject.VoteTypeCompliment,
+	},
+	valueobject.VoteTypeYes: {
+		valueobject.VoteTypeCrush,
+		valueobject.VoteTypeCompliment,
+	},
+}
+
+type voteTransitions map[valueobject.VoteType]map[valueobject.VoteType]struct{}
+
+type VoteTransitionPolicy struct {
+	defaults  voteTransitions
+	countries map[uint16]voteTransitions
+}
+
+func NewVoteTransitionPolicy(cfg config.Config) (*VoteTransitionPolicy, error) {
+	defaults := newVoteTransitions(DefaultVoteTransitions)
+	if len(cfg.Voting.Transitions) > 0 {
+		defaults = voteTransitions{}
+		for from, targets := range cfg.Voting.Transitions {
+			if err := defaults.set(from, targets); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	countries := map[uint16]voteTransitions{}
+	for key, targets := range cfg.Voting.TransitionOverrides {
+		country, from, ok := strings.Cut(key, countryOverrideSeparator)
+		if !ok {
+			return nil, fmt.Errorf("%w: override %q must be in country%sfrom format", ErrInvalidVoteTransitions, key, countryOverrideSeparator)
+		}
+		countryId, err := strconv.ParseUint(country, 10, 16)
+		if err != nil || countryId == 0 {
+			return nil, fmt.Errorf("%w: invalid country in override %q", ErrInvalidVoteTransitions, key)
+		}
+
+		transitions, exists := countries[uint16(countryId)]
+		if !exists {
+			transitions = defaults.clone()
+			countries[uint16(countryId)] = transitions
+		}
+		if err := transitions.set(from, targets); err != nil {
+			return nil, err
+		}
+	}
+
+	return &VoteTransitionPolicy{
+		defaults:  defaults,
+		countries: countries,
+	}, nil
+}
+
+func (p *VoteTransitionPolicy) CheckTransition(countryId uint16, from valueobject.VoteType, to valueobject.VoteType) error {
+	if _, allowed := p.forCountry(countryId)[from][to]; !allowed {
+		return NewChangingVoteTypeError(from, to)
+	}
+	return nil
+}
+
+// AddableVoteTypes are the vote types a vote can be added with in at least one country
+func (p *VoteTransitionPolicy) AddableVoteTypes() []valueobject.VoteType {
+	return p.targets(func(valueobject.VoteType) bool { return true })
+}
+
+// ChangeableVoteTypes are the vote types an existing vote can be changed to in at least one country
+func (p *VoteTransitionPolicy) ChangeableVoteTypes() []valueobject.VoteType {
+	return p.targets(func(from valueobject.VoteType) bool { return !from.IsEmpty() })
+}
+
+func (p *VoteTransitionPolicy) forCountry(countryId uint16) voteTransitions {
+	if transitions, ok := p.countries[countryId]; ok {
+		return transitions
+	}
+	return p.defaults
+}
+
+func (p *VoteTransitionPolicy) targets(fromFilter func(valueobject.VoteType) bool) []valueobject.VoteType {
+	all := append([]voteTransitions{p.defaults}, slices.Collect(maps.Values(p.countries))...)
+
+	var result []valueobject.VoteType
+	for _, transitions := range all {
+		for from, targets := range transitions {
+			if !fromFilter(from) {
+				continue
+			}
+			for to := range targets {
+				if !slices.Contains(result, to) {
+					result = append(result, to)
+				}
+			}
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+func newVoteTransitions(rules map[valueobject.VoteType][]valueobject.VoteType) voteTransitions {
+	transitions := voteTransitions{}
+	for from, targets := range rules {
+		transitions[from] = map[valueobject.VoteType]struct{}{}
+		for _, to := range targets {
+			transitions[from][to] = struct{}{}
+		}
+	}
+	return transitions
+}
+
+func (t voteTransitions) set(fromName string, targetNames string) error {
+	from, ok := valueobject.VoteTypeFromString(strings.TrimSpace(fromName))
+	if !ok {
+		return fmt.Errorf("%w: unknown vote type %q", ErrInvalidVoteTransitions, fromName)
+	}
+
+	targets := map[valueobject.VoteType]struct{}{}
+	for _, toName := range strings.Split(targetNames, voteTypesSeparator) {
+		toName = strings.TrimSpace(toName)
+		if toName == "" {
+			continue
+		}
+		to, ok := valueobject.VoteTypeFromString(toName)
+		if !ok {
+			return fmt.Errorf("%w: unknown vote type %q", ErrInvalidVoteTransitions, toName)
+		}
+		if to.IsEmpty() || to == from {
+			return fmt.Errorf("%w: transition from %q to %q is not possible", ErrInvalidVoteTransitions, from, to)
+		}
+		targets[to] = struct{}{}
+	}
+
+	t[from] = targets
+	return nil
+}
+
+func (t voteTransitions) clone() voteTransitions {
+	cloned := make(voteTransitions, len(t))
+	for from, targets := range t {
+		cloned[from] = make(map[valueobject.VoteType]struct{}, len(targets))
+		for to := range targets {
+			cloned[from][to] = struct{}{}
+		}
+	}
+	return cloned
+}
@@ -0,0 +1,79 @@
+package romance
+
+import (
+	"testing"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteTransitionPolicyDefaults(t *testing.T) {
+	policy, err := NewVoteTransitionPolicy(config.Config{})
+	require.NoError(t, err)
+
+	assert.NoError(t, policy.CheckTransition(11, valueobject.VoteTypeNo, valueobject.VoteTypeYes))
+	assert.ErrorIs(t, policy.CheckTransition(11, valueobject.VoteTypeYes, valueobject.VoteTypeNo), ErrWrongVote)
+	assert.Equal(t, []valueobject.VoteType{
+		valueobject.VoteTypeYes,
+		valueobject.VoteTypeNo,
+		valueobject.VoteTypeCrush,
+		valueobject.VoteTypeCompliment,
+	}, policy.AddableVoteTypes())
+	assert.Equal(t, []valueobject.VoteType{
+		valueobject.VoteTypeYes,
+		valueobject.VoteTypeCrush,
+		valueobject.VoteTypeCompliment,
+	}, policy.ChangeableVoteTypes())
+}
+
+func TestVoteTransitionPolicyCountryOverrides(t *testing.T) {
+	policy, err := NewVoteTransitionPolicy(config.Config{
+		Voting: config.VotingConfig{
+			TransitionOverrides: map[string]string{"11/yes": "no|crush"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, policy.CheckTransition(11, valueobject.VoteTypeYes, valueobject.VoteTypeNo))
+	assert.ErrorIs(t, policy.CheckTransition(11, valueobject.VoteTypeYes, valueobject.VoteTypeCompliment), ErrWrongVote)
+	assert.NoError(t, policy.CheckTransition(11, valueobject.VoteTypeNo, valueobject.VoteTypeYes))
+	assert.ErrorIs(t, policy.CheckTransition(12, valueobject.VoteTypeYes, valueobject.VoteTypeNo), ErrWrongVote)
+	assert.Contains(t, policy.ChangeableVoteTypes(), valueobject.VoteTypeNo)
+}
+
+func TestVoteTransitionPolicyConfiguredTransitions(t *testing.T) {
+	policy, err := NewVoteTransitionPolicy(config.Config{
+		Voting: config.VotingConfig{
+			Transitions: map[string]string{"empty": "yes|no", "no": "yes", "yes": ""},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, policy.CheckTransition(11, valueobject.VoteTypeEmpty, valueobject.VoteTypeNo))
+	assert.ErrorIs(t, policy.CheckTransition(11, valueobject.VoteTypeEmpty, valueobject.VoteTypeCrush), ErrWrongVote)
+	assert.ErrorIs(t, policy.CheckTransition(11, valueobject.VoteTypeYes, valueobject.VoteTypeCrush), ErrWrongVote)
+	assert.Equal(t, []valueobject.VoteType{valueobject.VoteTypeYes, valueobject.VoteTypeNo}, policy.AddableVoteTypes())
+	assert.Equal(t, []valueobject.VoteType{valueobject.VoteTypeYes}, policy.ChangeableVoteTypes())
+}
+
+func TestVoteTransitionPolicyRejectsInvalidConfig(t *testing.T) {
+	testCases := map[string]config.VotingConfig{
+		"unknown_from":       {Transitions: map[string]string{"maybe": "yes"}},
+		"unknown_to":         {Transitions: map[string]string{"empty": "maybe"}},
+		"to_empty":           {Transitions: map[string]string{"yes": "empty"}},
+		"to_itself":          {Transitions: map[string]string{"yes": "yes"}},
+		"override_format":    {TransitionOverrides: map[string]string{"yes": "no"}},
+		"override_country":   {TransitionOverrides: map[string]string{"uk/yes": "no"}},
+		"override_zero":      {TransitionOverrides: map[string]string{"0/yes": "no"}},
+		"override_vote_type": {TransitionOverrides: map[string]string{"11/yes": "maybe"}},
+	}
+
+	for name, votingConfig := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewVoteTransitionPolicy(config.Config{Voting: votingConfig})
+			assert.ErrorIs(t, err, ErrInvalidVoteTransitions)
+		})
+	}
+}
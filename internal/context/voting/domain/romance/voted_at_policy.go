@@ -0,0 +1,31 @@
+package romance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+)
+
+// VotedAtPolicy bounds client reported vote times, a zero duration disables the matching check
+type VotedAtPolicy struct {
+	maxClockSkew time.Duration
+	staleWindow  time.Duration
+}
+
+func NewVotedAtPolicy(cfg config.Config) *VotedAtPolicy {
+	return &VotedAtPolicy{
+		maxClockSkew: cfg.Voting.MaxClockSkew,
+		staleWindow:  cfg.Voting.StaleVoteWindow,
+	}
+}
+
+func (p *VotedAtPolicy) Check(votedAt time.Time, now time.Time) error {
+	if p.maxClockSkew > 0 && votedAt.After(now.Add(p.maxClockSkew)) {
+		return fmt.Errorf("%w: %s is ahead of server time by more than %s", ErrVotedAtInFuture, votedAt.Format(time.RFC3339), p.maxClockSkew)
+	}
+	if p.staleWindow > 0 && votedAt.Before(now.Add(-p.staleWindow)) {
+		return fmt.Errorf("%w: %s is older than %s", ErrVotedAtTooOld, votedAt.Format(time.RFC3339), p.staleWindow)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package romance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVotedAtPolicyCheck(t *testing.T) {
+	policy := NewVotedAtPolicy(config.Config{
+		Voting: config.VotingConfig{MaxClockSkew: time.Minute, StaleVoteWindow: time.Hour},
+	})
+	now := time.Now()
+
+	assert.NoError(t, policy.Check(now, now))
+	assert.NoError(t, policy.Check(now.Add(30*time.Second), now))
+	assert.NoError(t, policy.Check(now.Add(-30*time.Minute), now))
+	assert.ErrorIs(t, policy.Check(now.Add(2*time.Minute), now), ErrVotedAtInFuture)
+	assert.ErrorIs(t, policy.Check(now.Add(-2*time.Hour), now), ErrVotedAtTooOld)
+}
+
+func TestVotedAtPolicyDisabledChecks(t *testing.T) {
+	policy := NewVotedAtPolicy(config.Config{})
+	now := time.Now()
+
+	assert.NoError(t, policy.Check(now.Add(24*time.Hour), now))
+	assert.NoError(t, policy.Check(now.Add(-365*24*time.Hour), now))
+}
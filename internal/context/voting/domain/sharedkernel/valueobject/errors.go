@@ -0,0 +1,5 @@
+package valueobject
+
+import "errors"
+
+var ErrInvalidIdentity = errors.New("invalid identity")
@@ -1,7 +1,7 @@
 package valueobject
 
 import (
-	"errors"
+	"fmt"
 	"github.com/google/uuid"
 )
 
@@ -12,10 +12,10 @@ type ActiveUserKey struct {
 
 func NewActiveUserKey(countryId uint16, activeUserId uuid.UUID) (ActiveUserKey, error) {
 	if countryId == 0 {
-		return ActiveUserKey{}, errors.New("countryId must be non-zero")
+		return ActiveUserKey{}, fmt.Errorf("%w: countryId must be non-zero", ErrInvalidIdentity)
 	}
 	if activeUserId == uuid.Nil {
-		return ActiveUserKey{}, errors.New("activeUserId must not be empty")
+		return ActiveUserKey{}, fmt.Errorf("%w: activeUserId must not be empty", ErrInvalidIdentity)
 	}
 
 	return ActiveUserKey{
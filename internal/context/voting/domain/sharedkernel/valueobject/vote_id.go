@@ -1,31 +1,49 @@
 package valueobject
 
 import (
-	"errors"
+	"bytes"
+	"fmt"
 	"github.com/google/uuid"
 )
 
 type VoteId struct {
 	activeUserKey ActiveUserKey
 	peerUserId    uuid.UUID
+	peerCountryId uint16
 }
 
+// NewVoteId is used when both users are in the same country or the country of the peer is unknown
 func NewVoteId(countryId uint16, activeUserId uuid.UUID, peerUserId uuid.UUID) (VoteId, error) {
+	return NewCrossCountryVoteId(countryId, activeUserId, countryId, peerUserId)
+}
+
+// NewCrossCountryVoteId keeps the country of each user, the peer country is required as the home
+// region of the pair depends on it
+func NewCrossCountryVoteId(
+	countryId uint16,
+	activeUserId uuid.UUID,
+	peerCountryId uint16,
+	peerUserId uuid.UUID,
+) (VoteId, error) {
 	activeUserKey, err := NewActiveUserKey(countryId, activeUserId)
 	if err != nil {
 		return VoteId{}, err
 	}
+	if peerCountryId == 0 {
+		return VoteId{}, fmt.Errorf("%w: peerCountryId must not be empty", ErrInvalidIdentity)
+	}
 
 	if peerUserId == uuid.Nil {
-		return VoteId{}, errors.New("peerUserId must not be empty")
+		return VoteId{}, fmt.Errorf("%w: peerUserId must not be empty", ErrInvalidIdentity)
 	}
 	if activeUserId == peerUserId {
-		return VoteId{}, errors.New("activeUserId and peerUserId must differ")
+		return VoteId{}, fmt.Errorf("%w: activeUserId and peerUserId must differ", ErrInvalidIdentity)
 	}
 
 	return VoteId{
 		activeUserKey: activeUserKey,
 		peerUserId:    peerUserId,
+		peerCountryId: peerCountryId,
 	}, nil
 }
 
@@ -37,16 +55,43 @@ func (id VoteId) ActiveUserId() uuid.UUID {
 	return id.activeUserKey.activeUserId
 }
 
+func (id VoteId) ActiveUserKey() ActiveUserKey {
+	return id.activeUserKey
+}
+
 func (id VoteId) PeerUserId() uuid.UUID {
 	return id.peerUserId
 }
 
+func (id VoteId) PeerCountryId() uint16 {
+	return id.peerCountryId
+}
+
+func (id VoteId) PeerUserKey() ActiveUserKey {
+	return ActiveUserKey{
+		activeUserId: id.peerUserId,
+		countryId:    id.peerCountryId,
+	}
+}
+
+func (id VoteId) IsCrossCountry() bool {
+	return id.activeUserKey.countryId != id.peerCountryId
+}
+
+// HomeCountryId is the same for both users of the pair, it is the country of the user with the lower
+// id. The romance is written in the region of the home country only.
+func (id VoteId) HomeCountryId() uint16 {
+	activeUserId := id.activeUserKey.activeUserId
+	if bytes.Compare(activeUserId[:], id.peerUserId[:]) == -1 {
+		return id.activeUserKey.countryId
+	}
+	return id.peerCountryId
+}
+
 func (id VoteId) ToPeerVoteId() VoteId {
 	return VoteId{
-		activeUserKey: ActiveUserKey{
-			activeUserId: id.peerUserId,
-			countryId:    id.activeUserKey.countryId,
-		},
-		peerUserId: id.activeUserKey.activeUserId,
+		activeUserKey: id.PeerUserKey(),
+		peerUserId:    id.activeUserKey.activeUserId,
+		peerCountryId: id.activeUserKey.countryId,
 	}
 }
@@ -0,0 +1,10 @@
+package user
+
+import "errors"
+
+var (
+	ErrUserBanned           = errors.New("user is banned")
+	ErrUserDeleted          = errors.New("user is deleted")
+	ErrVoteTypeNotEntitled  = errors.New("user is not entitled to the vote type")
+	ErrUserCheckUnavailable = errors.New("user checks are unavailable")
+)
@@ -0,0 +1,18 @@
+package provider
+
+import (
+	"context"
+
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+)
+
+//go:generate mockgen -destination=../../../../../testlib/mocks/entitlement_provider_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider EntitlementProvider
+type EntitlementProvider interface {
+	// IsEntitled returns ErrUserCheckUnavailable when the entitlements can not be resolved and the check fails closed
+	IsEntitled(
+		ctx context.Context,
+		activeUserKey sharedValueObject.ActiveUserKey,
+		voteType romancesValueObject.VoteType,
+	) (bool, error)
+}
@@ -0,0 +1,18 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+)
+
+//go:generate mockgen -destination=../../../../../testlib/mocks/time_zone_provider_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider TimeZoneProvider
+type TimeZoneProvider interface {
+	// GetTimeZone returns the time zone of the user profile, UTC when the profile has none,
+	// and ErrUserCheckUnavailable when it can not be resolved
+	GetTimeZone(
+		ctx context.Context,
+		activeUserKey sharedValueObject.ActiveUserKey,
+	) (*time.Location, error)
+}
@@ -0,0 +1,17 @@
+package provider
+
+import (
+	"context"
+
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/valueobject"
+)
+
+//go:generate mockgen -destination=../../../../../testlib/mocks/user_status_provider_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider UserStatusProvider
+type UserStatusProvider interface {
+	// GetStatus returns ErrUserCheckUnavailable when the status can not be resolved and the check fails closed
+	GetStatus(
+		ctx context.Context,
+		activeUserKey sharedValueObject.ActiveUserKey,
+	) (valueobject.UserStatus, error)
+}
@@ -0,0 +1,28 @@
+package valueobject
+
+type UserStatus uint8
+
+const (
+	UserStatusActive UserStatus = iota
+	UserStatusBanned
+	UserStatusDeleted
+)
+
+var userStatusToString = map[UserStatus]string{
+	UserStatusActive:  "active",
+	UserStatusBanned:  "banned",
+	UserStatusDeleted: "deleted",
+}
+
+func UserStatusFromString(name string) (UserStatus, bool) {
+	for status, statusName := range userStatusToString {
+		if statusName == name {
+			return status, true
+		}
+	}
+	return UserStatusActive, false
+}
+
+func (s UserStatus) String() string {
+	return userStatusToString[s]
+}
@@ -0,0 +1,64 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/provider"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+)
+
+// NewModerationProvider moderates compliments locally by the configured keywords, an external
+// moderation decides on the pending ones through the moderation callbacks
+func NewModerationProvider(cfg config.Config) provider.ModerationProvider {
+	return NewKeywordModerationProvider(cfg.Moderation.BlockedKeywords, cfg.Moderation.AutoApprove)
+}
+
+// KeywordModerationProvider rejects texts containing a blocked word or phrase, matching whole
+// words case-insensitively so that e.g. "class" is not rejected for "ass".
+type KeywordModerationProvider struct {
+	blockedKeywords []string
+	autoApprove     bool
+}
+
+func NewKeywordModerationProvider(blockedKeywords []string, autoApprove bool) *KeywordModerationProvider {
+	normalized := make([]string, 0, len(blockedKeywords))
+	for _, keyword := range blockedKeywords {
+		if keyword = normalizeWords(keyword); strings.TrimSpace(keyword) != "" {
+			normalized = append(normalized, keyword)
+		}
+	}
+	return &KeywordModerationProvider{
+		blockedKeywords: normalized,
+		autoApprove:     autoApprove,
+	}
+}
+
+func (p *KeywordModerationProvider) Moderate(
+	_ context.Context,
+	_ sharedValueObject.VoteId,
+	text string,
+) (romancesValueObject.ModerationStatus, error) {
+	words := normalizeWords(text)
+	for _, keyword := range p.blockedKeywords {
+		if strings.Contains(words, keyword) {
+			return romancesValueObject.ModerationStatusRejected, nil
+		}
+	}
+	if p.autoApprove {
+		return romancesValueObject.ModerationStatusApproved, nil
+	}
+	return romancesValueObject.ModerationStatusPending, nil
+}
+
+// normalizeWords lowercases the words of the text and joins them with single spaces,
+// the leading and trailing space make a contained keyword match whole words only
+func normalizeWords(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return " " + strings.Join(words, " ") + " "
+}
@@ -0,0 +1,41 @@
+package moderation
+
+import (
+	"context"
+	"testing"
+
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeywordModerationProvider(t *testing.T) {
+	voteId, err := sharedValueObject.NewVoteId(11, uuid.New(), uuid.New())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		autoApprove bool
+		text        string
+		expected    romancesValueObject.ModerationStatus
+	}{
+		{"blocked word", false, "You are UGLY!", romancesValueObject.ModerationStatusRejected},
+		{"blocked phrase", true, "send me   money, please", romancesValueObject.ModerationStatusRejected},
+		{"keyword inside a word", true, "Ugliness is not a thing here", romancesValueObject.ModerationStatusApproved},
+		{"phrase split by other words", true, "send the money", romancesValueObject.ModerationStatusApproved},
+		{"pending without auto approve", false, "Nice smile", romancesValueObject.ModerationStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewKeywordModerationProvider([]string{"ugly", "Send me money", " "}, tt.autoApprove)
+
+			status, err := provider.Moderate(context.Background(), voteId, tt.text)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, status)
+		})
+	}
+}
@@ -2,12 +2,14 @@ package persistence
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	counterDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/entity"
 	countersValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
@@ -28,8 +30,43 @@ const (
 	incomingNoAttrName        = "in"
 	outgoingYesAttrName       = "oy"
 	outgoingNoAttrName        = "on"
+	matchesAttrName           = "mt"
 )
 
+// rollupKeyOffsets keep rollup rows apart from the lifetime row and the hourly rows, a rollup key is
+// minus the offset of its granularity and the days since the epoch of the period start. Queries for
+// hours after a time never read them.
+var rollupKeyOffsets = map[countersValueObject.CountersGranularity]int64{
+	countersValueObject.CountersGranularityDay:  1 << 24,
+	countersValueObject.CountersGranularityWeek: 2 << 24,
+}
+
+// limitKeyOffsets keep the rows counting votes against the vote limits apart from the rollup rows,
+// a limit key is minus the offset of its window and the windows since the epoch of the window start
+var limitKeyOffsets = map[counterDomain.LimitWindow]int64{
+	counterDomain.LimitWindowHour: 3 << 24,
+	counterDomain.LimitWindowDay:  4 << 24,
+}
+
+// crossRegionCountersAttempts bounds the writes of the peer counters of a cross-region romance
+const crossRegionCountersAttempts = 3
+
+var limitCounterAttrNames = map[string]string{
+	"yes": outgoingYesAttrName,
+	"no":  outgoingNoAttrName,
+}
+
+type counterDecrement struct {
+	userKey sharedValueObject.ActiveUserKey
+	key     int64
+	counter string
+}
+
+type countersRollup struct {
+	granularity countersValueObject.CountersGranularity
+	retention   time.Duration
+}
+
 type CountersRepository struct {
 	dynamoDbClient platformDynamoDb.Client
 	config         config.Config
@@ -43,6 +80,7 @@ type CountersDocumentSchema struct {
 	IncomingNo        uint32 `dynamodbav:"in"`
 	OutgoingYes       uint32 `dynamodbav:"oy"`
 	OutgoingNo        uint32 `dynamodbav:"on"`
+	Matches           uint32 `dynamodbav:"mt"`
 }
 
 func NewCountersRepository(
@@ -152,12 +190,158 @@ func (c *CountersRepository) GetHourlyCounters(
 			group.IncomingYes += countersGroup.IncomingYes
 			group.OutgoingNo += countersGroup.OutgoingNo
 			group.OutgoingYes += countersGroup.OutgoingYes
+			group.Matches += countersGroup.Matches
 		}
 	}
 
 	return result, nil
 }
 
+func (c *CountersRepository) GetHourlyCountersSince(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	since time.Time,
+) ([]entity.CountersGroup, error) {
+	return c.queryCounters(ctx, activeUserKey, "u = :pk AND h >= :from", map[string]types.AttributeValue{
+		":from": &types.AttributeValueMemberN{Value: strconv.FormatInt(timeutil.HourStart(since.UTC()).Unix(), 10)},
+	})
+}
+
+func (c *CountersRepository) GetCountersRange(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	countersRange countersValueObject.CountersRange,
+) ([]entity.CountersGroup, error) {
+	granularity := countersRange.Granularity()
+	lastBucket := countersRange.To().Add(-granularity.Duration())
+	if granularity == countersValueObject.CountersGranularityHour {
+		return c.queryCounters(ctx, activeUserKey, "u = :pk AND h BETWEEN :from AND :to", map[string]types.AttributeValue{
+			":from": &types.AttributeValueMemberN{Value: strconv.FormatInt(countersRange.From().Unix(), 10)},
+			":to":   &types.AttributeValueMemberN{Value: strconv.FormatInt(lastBucket.Unix(), 10)},
+		})
+	}
+
+	// later periods have lower keys
+	rollups, err := c.queryCounters(ctx, activeUserKey, "u = :pk AND h BETWEEN :from AND :to", map[string]types.AttributeValue{
+		":from": &types.AttributeValueMemberN{Value: strconv.FormatInt(rollupCountersKey(granularity, lastBucket), 10)},
+		":to":   &types.AttributeValueMemberN{Value: strconv.FormatInt(rollupCountersKey(granularity, countersRange.From()), 10)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range rollups {
+		rollups[i].HourUnixTimestamp = int32(rollupPeriodStart(granularity, int64(rollups[i].HourUnixTimestamp)).Unix())
+	}
+	return rollups, nil
+}
+
+// queryCounters reads all pages of the counters rows matching the key condition
+func (c *CountersRepository) queryCounters(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	keyCondition string,
+	values map[string]types.AttributeValue,
+) ([]entity.CountersGroup, error) {
+	values[":pk"] = &types.AttributeValueMemberS{Value: activeUserKey.ActiveUserId().String()}
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(CountersTableName),
+		KeyConditionExpression:    aws.String(keyCondition),
+		ConsistentRead:            aws.Bool(true),
+		ExpressionAttributeValues: values,
+	}
+
+	result := []entity.CountersGroup{}
+	for {
+		out, err := c.dynamoDbClient.Query(ctx, input, func(o *dynamodb.Options) {
+			o.Region = platformDynamoDb.GetDynamodbRegionByCountry(activeUserKey.CountryId())
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range out.Items {
+			countersGroupItem := &CountersDocumentSchema{}
+			if err = attributevalue.UnmarshalMap(item, countersGroupItem); err != nil {
+				return nil, err
+			}
+
+			countersGroup, err := c.transformCountersGroupItemToEntity(activeUserKey.CountryId(), *countersGroupItem)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, countersGroup)
+		}
+
+		if out.LastEvaluatedKey == nil {
+			return result, nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+func (c *CountersRepository) ReserveVoteLimits(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	windows []counterDomain.VoteLimitWindow,
+) error {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	updates := make([]types.TransactWriteItem, 0, len(windows))
+	for _, window := range windows {
+		updates = append(updates, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName:           aws.String(CountersTableName),
+				Key:                 c.getCountersTableKey(activeUserKey.ActiveUserId(), limitCountersKey(window)),
+				UpdateExpression:    aws.String("SET #counterIndex = if_not_exists(#counterIndex, :zero) + :incr, #ttl = :ttl"),
+				ConditionExpression: aws.String("attribute_not_exists(#counterIndex) OR #counterIndex < :limit"),
+				ExpressionAttributeNames: map[string]string{
+					"#counterIndex": limitCounterAttrNames[window.Counter],
+					"#ttl":          platformDynamoDb.TtlAttrName,
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":zero":  &types.AttributeValueMemberN{Value: "0"},
+					":incr":  &types.AttributeValueMemberN{Value: "1"},
+					":limit": &types.AttributeValueMemberN{Value: strconv.FormatUint(uint64(window.Limit), 10)},
+					":ttl":   &types.AttributeValueMemberN{Value: strconv.FormatInt(window.End().Unix(), 10)},
+				},
+			},
+		})
+	}
+
+	err := c.writeCounters(ctx, platformDynamoDb.GetDynamodbRegionByCountry(activeUserKey.CountryId()), updates)
+	var canceledErr *types.TransactionCanceledException
+	if errors.As(err, &canceledErr) {
+		for i, reason := range canceledErr.CancellationReasons {
+			if i < len(windows) && aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return windows[i].Exceeded()
+			}
+		}
+	}
+	return err
+}
+
+func (c *CountersRepository) ReleaseVoteLimits(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	windows []counterDomain.VoteLimitWindow,
+) error {
+	for _, window := range windows {
+		err := c.decrCounter(
+			ctx,
+			activeUserKey.CountryId(),
+			activeUserKey.ActiveUserId(),
+			limitCountersKey(window),
+			limitCounterAttrNames[window.Counter],
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *CountersRepository) IncrYesCounters(
 	ctx context.Context,
 	voteId sharedValueObject.VoteId,
@@ -180,6 +364,116 @@ func (c *CountersRepository) IncrNoCounters(
 	}
 }
 
+func (c *CountersRepository) IncrMatchCounters(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	counterUpdateGroup countersValueObject.CounterUpdateGroup,
+) {
+	err := c.incrCounters(ctx, voteId, counterUpdateGroup, matchesAttrName, matchesAttrName)
+	if err != nil {
+		c.logger.Error(fmt.Sprintf("incrMatchCounters error: %s", err))
+	}
+}
+
+// DecrYesCounters takes back a yes vote from the counters of the hour it was counted in
+func (c *CountersRepository) DecrYesCounters(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	counterUpdateGroup countersValueObject.CounterUpdateGroup,
+) {
+	c.decrVoteCounters(ctx, voteId, counterUpdateGroup, outgoingYesAttrName, incomingYesAttrName)
+}
+
+// DecrNoCounters takes back a no vote from the counters of the hour it was counted in
+func (c *CountersRepository) DecrNoCounters(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	counterUpdateGroup countersValueObject.CounterUpdateGroup,
+) {
+	c.decrVoteCounters(ctx, voteId, counterUpdateGroup, outgoingNoAttrName, incomingNoAttrName)
+}
+
+// DecrMatchCounters decrements the lifetime matches of both users, the hourly counters keep
+// the match as an event of the hour it happened in.
+func (c *CountersRepository) DecrMatchCounters(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+) {
+	for _, userKey := range []sharedValueObject.ActiveUserKey{voteId.ActiveUserKey(), voteId.PeerUserKey()} {
+		if err := c.decrCounter(ctx, userKey.CountryId(), userKey.ActiveUserId(), LifetimeCounterKey, matchesAttrName); err != nil {
+			c.logger.Error(fmt.Sprintf("decrMatchCounters error: %s", err))
+		}
+	}
+}
+
+func (c *CountersRepository) decrVoteCounters(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	counterUpdateGroup countersValueObject.CounterUpdateGroup,
+	activeUserCounter string,
+	peerUserCounter string,
+) {
+	eventStartHourTime := counterUpdateGroup.HourStartTime().Unix()
+	updates := []counterDecrement{
+		{voteId.ActiveUserKey(), eventStartHourTime, activeUserCounter},
+		{voteId.ActiveUserKey(), LifetimeCounterKey, activeUserCounter},
+		{voteId.PeerUserKey(), eventStartHourTime, peerUserCounter},
+		{voteId.PeerUserKey(), LifetimeCounterKey, peerUserCounter},
+	}
+	for _, rollup := range c.rollups() {
+		key := rollupCountersKey(rollup.granularity, counterUpdateGroup.HourStartTime())
+		updates = append(updates,
+			counterDecrement{voteId.ActiveUserKey(), key, activeUserCounter},
+			counterDecrement{voteId.PeerUserKey(), key, peerUserCounter},
+		)
+	}
+	for _, u := range updates {
+		if err := c.decrCounter(ctx, u.userKey.CountryId(), u.userKey.ActiveUserId(), u.key, u.counter); err != nil {
+			c.logger.Error(fmt.Sprintf("decrVoteCounters error: %s", err))
+		}
+	}
+}
+
+// decrCounter never takes a counter below zero, e.g. when the hourly item already expired
+func (c *CountersRepository) decrCounter(
+	ctx context.Context,
+	countryId uint16,
+	userId uuid.UUID,
+	key int64,
+	counter string,
+) error {
+	return c.decrCounterInRegion(ctx, platformDynamoDb.GetDynamodbRegionByCountry(countryId), c.getCountersTableKey(userId, key), counter)
+}
+
+func (c *CountersRepository) decrCounterInRegion(
+	ctx context.Context,
+	region string,
+	key map[string]types.AttributeValue,
+	counter string,
+) error {
+	_, err := c.dynamoDbClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(CountersTableName),
+		Key:                 key,
+		UpdateExpression:    aws.String("SET #counterIndex = #counterIndex - :decr"),
+		ConditionExpression: aws.String("#counterIndex > :zero"),
+		ExpressionAttributeNames: map[string]string{
+			"#counterIndex": counter,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":zero": &types.AttributeValueMemberN{Value: "0"},
+			":decr": &types.AttributeValueMemberN{Value: "1"},
+		},
+	}, func(o *dynamodb.Options) {
+		o.Region = region
+	})
+
+	var condCheckErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condCheckErr) {
+		return nil
+	}
+	return err
+}
+
 func (c *CountersRepository) incrCounters(
 	ctx context.Context,
 	voteId sharedValueObject.VoteId,
@@ -202,68 +496,73 @@ func (c *CountersRepository) incrCounters(
 		":ttl":  &types.AttributeValueMemberN{Value: strconv.FormatInt(ttl, 10)},
 	}
 
-	hourlyUpdateExpression := aws.String("SET #counterIndex = if_not_exists(#counterIndex, :zero) + :incr, #ttl = :ttl")
+	expiringUpdateExpression := aws.String("SET #counterIndex = if_not_exists(#counterIndex, :zero) + :incr, #ttl = :ttl")
 	lifetimeUpdateExpression := aws.String("SET #counterIndex = if_not_exists(#counterIndex, :zero) + :incr")
 
-	input := &dynamodb.TransactWriteItemsInput{
-		TransactItems: []types.TransactWriteItem{
-			{
-				Update: &types.Update{
-					TableName:        aws.String(CountersTableName),
-					Key:              c.getCountersTableKey(voteId.ActiveUserId(), eventStartHourTime),
-					UpdateExpression: hourlyUpdateExpression,
-					ExpressionAttributeNames: map[string]string{
-						"#counterIndex": activeUserCounter,
-						"#ttl":          platformDynamoDb.TtlAttrName,
-					},
-					ExpressionAttributeValues: hourlyValues,
-				},
-			},
-			{
-				Update: &types.Update{
-					TableName:        aws.String(CountersTableName),
-					Key:              c.getCountersTableKey(voteId.ActiveUserId(), LifetimeCounterKey),
-					UpdateExpression: lifetimeUpdateExpression,
-					ExpressionAttributeNames: map[string]string{
-						"#counterIndex": activeUserCounter,
-					},
-					ExpressionAttributeValues: lifetimeValues,
-				},
+	// rollup rows expire once their retention passed after the end of their period
+	type rollupUpdate struct {
+		key    int64
+		values map[string]types.AttributeValue
+	}
+	var rollupUpdates []rollupUpdate
+	for _, rollup := range c.rollups() {
+		periodStart := rollup.granularity.BucketStart(counterUpdateGroup.HourStartTime())
+		rollupTtl := periodStart.Add(rollup.granularity.Duration() + rollup.retention).Unix()
+		rollupUpdates = append(rollupUpdates, rollupUpdate{
+			key: rollupCountersKey(rollup.granularity, periodStart),
+			values: map[string]types.AttributeValue{
+				":zero": &types.AttributeValueMemberN{Value: "0"},
+				":incr": &types.AttributeValueMemberN{Value: "1"},
+				":ttl":  &types.AttributeValueMemberN{Value: strconv.FormatInt(rollupTtl, 10)},
 			},
-			{
-				Update: &types.Update{
-					TableName:        aws.String(CountersTableName),
-					Key:              c.getCountersTableKey(voteId.PeerUserId(), eventStartHourTime),
-					UpdateExpression: hourlyUpdateExpression,
-					ExpressionAttributeNames: map[string]string{
-						"#counterIndex": peerUserCounter,
-						"#ttl":          platformDynamoDb.TtlAttrName,
-					},
-					ExpressionAttributeValues: hourlyValues,
+		})
+	}
+
+	expiringUpdate := func(userId uuid.UUID, key int64, counter string, values map[string]types.AttributeValue) types.TransactWriteItem {
+		return types.TransactWriteItem{
+			Update: &types.Update{
+				TableName:        aws.String(CountersTableName),
+				Key:              c.getCountersTableKey(userId, key),
+				UpdateExpression: expiringUpdateExpression,
+				ExpressionAttributeNames: map[string]string{
+					"#counterIndex": counter,
+					"#ttl":          platformDynamoDb.TtlAttrName,
 				},
+				ExpressionAttributeValues: values,
 			},
+		}
+	}
+
+	userUpdates := func(userId uuid.UUID, counter string) []types.TransactWriteItem {
+		updates := []types.TransactWriteItem{
+			expiringUpdate(userId, eventStartHourTime, counter, hourlyValues),
 			{
 				Update: &types.Update{
 					TableName:        aws.String(CountersTableName),
-					Key:              c.getCountersTableKey(voteId.PeerUserId(), LifetimeCounterKey),
+					Key:              c.getCountersTableKey(userId, LifetimeCounterKey),
 					UpdateExpression: lifetimeUpdateExpression,
 					ExpressionAttributeNames: map[string]string{
-						"#counterIndex": peerUserCounter,
+						"#counterIndex": counter,
 					},
 					ExpressionAttributeValues: lifetimeValues,
 				},
 			},
-		},
+		}
+		for _, rollup := range rollupUpdates {
+			updates = append(updates, expiringUpdate(userId, rollup.key, counter, rollup.values))
+		}
+		return updates
 	}
+	activeUserUpdates := userUpdates(voteId.ActiveUserId(), activeUserCounter)
+	peerUserUpdates := userUpdates(voteId.PeerUserId(), peerUserCounter)
 
-	_, err := c.dynamoDbClient.TransactWriteItems(
+	err := c.writeUsersCounters(
 		ctx,
-		input,
-		func(o *dynamodb.Options) {
-			o.Region = platformDynamoDb.GetDynamodbRegionByCountry(voteId.CountryId())
-		},
+		platformDynamoDb.GetDynamodbRegionByCountry(voteId.CountryId()),
+		platformDynamoDb.GetDynamodbRegionByCountry(voteId.PeerCountryId()),
+		activeUserUpdates,
+		peerUserUpdates,
 	)
-
 	if err == nil {
 		c.logger.Debug(fmt.Sprintf("Counters updated for users: %s and %s", voteId.ActiveUserId(), voteId.PeerUserId()))
 	}
@@ -271,6 +570,61 @@ func (c *CountersRepository) incrCounters(
 	return err
 }
 
+// writeUsersCounters writes the counters of each user in the region of the user's country. A
+// transaction can not span regions so the users of a cross-region romance are counted one after the
+// other: the peer counters are retried with one client request token, DynamoDB applies a transaction
+// whose token it already applied only once, and when they still fail the increments of the active
+// user are taken back so both users keep counting the vote or neither does. A failed take back is
+// only logged, the active user then keeps the vote in the counters.
+func (c *CountersRepository) writeUsersCounters(
+	ctx context.Context,
+	activeUserRegion string,
+	peerUserRegion string,
+	activeUserUpdates []types.TransactWriteItem,
+	peerUserUpdates []types.TransactWriteItem,
+) error {
+	if activeUserRegion == peerUserRegion {
+		return c.writeCounters(ctx, activeUserRegion, append(activeUserUpdates, peerUserUpdates...))
+	}
+	if err := c.writeCounters(ctx, activeUserRegion, activeUserUpdates); err != nil {
+		return err
+	}
+
+	token := uuid.NewString()
+	var err error
+	for attempt := 0; attempt < crossRegionCountersAttempts; attempt++ {
+		_, err = c.dynamoDbClient.TransactWriteItems(
+			ctx,
+			&dynamodb.TransactWriteItemsInput{TransactItems: peerUserUpdates, ClientRequestToken: aws.String(token)},
+			func(o *dynamodb.Options) {
+				o.Region = peerUserRegion
+			},
+		)
+		if err == nil {
+			return nil
+		}
+	}
+
+	for _, update := range activeUserUpdates {
+		decrErr := c.decrCounterInRegion(ctx, activeUserRegion, update.Update.Key, update.Update.ExpressionAttributeNames["#counterIndex"])
+		if decrErr != nil {
+			c.logger.Error(fmt.Sprintf("writeUsersCounters take back error: %s", decrErr))
+		}
+	}
+	return err
+}
+
+func (c *CountersRepository) writeCounters(ctx context.Context, region string, updates []types.TransactWriteItem) error {
+	_, err := c.dynamoDbClient.TransactWriteItems(
+		ctx,
+		&dynamodb.TransactWriteItemsInput{TransactItems: updates},
+		func(o *dynamodb.Options) {
+			o.Region = region
+		},
+	)
+	return err
+}
+
 func (c *CountersRepository) transformCountersGroupItemToEntity(
 	countryId uint16,
 	countersItem CountersDocumentSchema,
@@ -292,6 +646,7 @@ func (c *CountersRepository) transformCountersGroupItemToEntity(
 		IncomingNo:        countersItem.IncomingNo,
 		OutgoingYes:       countersItem.OutgoingYes,
 		OutgoingNo:        countersItem.OutgoingNo,
+		Matches:           countersItem.Matches,
 	}, nil
 }
 
@@ -304,3 +659,32 @@ func (c *CountersRepository) getCountersTableKey(
 		HourUnixTimestampAttrName: &types.AttributeValueMemberN{Value: strconv.FormatInt(dayStartTimeUnixTimestamp, 10)},
 	}
 }
+
+// rollups lists the enabled rollups of the hourly counters
+func (c *CountersRepository) rollups() []countersRollup {
+	rollups := make([]countersRollup, 0, 2)
+	if c.config.Counters.DailyRetention > 0 {
+		rollups = append(rollups, countersRollup{countersValueObject.CountersGranularityDay, c.config.Counters.DailyRetention})
+	}
+	if c.config.Counters.WeeklyRetention > 0 {
+		rollups = append(rollups, countersRollup{countersValueObject.CountersGranularityWeek, c.config.Counters.WeeklyRetention})
+	}
+	return rollups
+}
+
+// rollupCountersKey returns the key of the rollup row of the period t falls into
+func rollupCountersKey(granularity countersValueObject.CountersGranularity, t time.Time) int64 {
+	days := granularity.BucketStart(t).Unix() / timeutil.DaySeconds
+	return -(rollupKeyOffsets[granularity] + days)
+}
+
+func rollupPeriodStart(granularity countersValueObject.CountersGranularity, key int64) time.Time {
+	days := -key - rollupKeyOffsets[granularity]
+	return time.Unix(days*timeutil.DaySeconds, 0).UTC()
+}
+
+// limitCountersKey returns the key of the row counting the votes of the limit window
+func limitCountersKey(window counterDomain.VoteLimitWindow) int64 {
+	windowSeconds := int64(window.End().Sub(window.Start) / time.Second)
+	return -(limitKeyOffsets[window.Window] + window.Start.Unix()/windowSeconds)
+}
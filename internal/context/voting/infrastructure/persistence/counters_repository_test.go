@@ -0,0 +1,230 @@
+package persistence
+
+import (
+	"context"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"io"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	countersValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type CountersRepositoryUnitTestSuite struct {
+	suite.Suite
+	activeUserKey sharedValueObject.ActiveUserKey
+}
+
+func TestCountersRepositoryUnitSuite(t *testing.T) {
+	suite.Run(t, new(CountersRepositoryUnitTestSuite))
+}
+
+func (s *CountersRepositoryUnitTestSuite) SetupSuite() {
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.activeUserKey = activeUserKey
+}
+
+func (s *CountersRepositoryUnitTestSuite) TestGetHourlyCountersRangeReadsAllPages() {
+	ctrl := gomock.NewController(s.T())
+	client := mocks.NewMockClient(ctrl)
+	ctx := context.Background()
+	from := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Hour)
+	firstItem := s.countersItem(from, 2)
+	lastItem := s.countersItem(from.Add(2*time.Hour), 5)
+
+	gomock.InOrder(
+		client.EXPECT().
+			Query(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				s.Require().Equal("u = :pk AND h BETWEEN :from AND :to", *input.KeyConditionExpression)
+				s.Require().Equal(strconv.FormatInt(from.Unix(), 10), input.ExpressionAttributeValues[":from"].(*types.AttributeValueMemberN).Value)
+				s.Require().Equal(strconv.FormatInt(to.Add(-time.Hour).Unix(), 10), input.ExpressionAttributeValues[":to"].(*types.AttributeValueMemberN).Value)
+				s.Require().Nil(input.ExclusiveStartKey)
+				return &dynamodb.QueryOutput{
+					Items:            []map[string]types.AttributeValue{firstItem},
+					LastEvaluatedKey: firstItem,
+				}, nil
+			}),
+		client.EXPECT().
+			Query(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				s.Require().Equal(firstItem, input.ExclusiveStartKey)
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{lastItem}}, nil
+			}),
+	)
+
+	countersRange, err := countersValueObject.NewCountersRange(from, to, countersValueObject.CountersGranularityHour)
+	s.Require().NoError(err)
+
+	counters, err := newCountersRepository(client).GetCountersRange(ctx, s.activeUserKey, countersRange)
+
+	s.Require().NoError(err)
+	s.Require().Len(counters, 2)
+	s.Require().Equal(int32(from.Unix()), counters[0].HourUnixTimestamp)
+	s.Require().Equal(uint32(2), counters[0].OutgoingYes)
+	s.Require().Equal(uint32(5), counters[1].OutgoingYes)
+}
+
+func (s *CountersRepositoryUnitTestSuite) TestGetDailyCountersRangeReadsRollups() {
+	ctrl := gomock.NewController(s.T())
+	client := mocks.NewMockClient(ctrl)
+	ctx := context.Background()
+	firstDay := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstDay.AddDate(0, 0, 2)
+	countersRange, err := countersValueObject.NewCountersRange(firstDay, lastDay.Add(time.Hour), countersValueObject.CountersGranularityDay)
+	s.Require().NoError(err)
+
+	client.EXPECT().
+		Query(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+			dayKey := rollupCountersKey(countersValueObject.CountersGranularityDay, firstDay)
+			s.Require().Equal(strconv.FormatInt(dayKey-2, 10), input.ExpressionAttributeValues[":from"].(*types.AttributeValueMemberN).Value)
+			s.Require().Equal(strconv.FormatInt(dayKey, 10), input.ExpressionAttributeValues[":to"].(*types.AttributeValueMemberN).Value)
+			item := s.countersItem(firstDay, 4)
+			item[HourUnixTimestampAttrName] = &types.AttributeValueMemberN{Value: strconv.FormatInt(dayKey-2, 10)}
+			return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}}, nil
+		})
+
+	counters, err := newCountersRepository(client).GetCountersRange(ctx, s.activeUserKey, countersRange)
+
+	s.Require().NoError(err)
+	s.Require().Len(counters, 1)
+	s.Require().Equal(int32(lastDay.Unix()), counters[0].HourUnixTimestamp)
+	s.Require().Equal(uint32(4), counters[0].OutgoingYes)
+}
+
+func (s *CountersRepositoryUnitTestSuite) TestIncrCountersUpdatesRollups() {
+	ctrl := gomock.NewController(s.T())
+	client := mocks.NewMockClient(ctrl)
+	ctx := context.Background()
+	voteId, err := sharedValueObject.NewVoteId(11, s.activeUserKey.ActiveUserId(), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	// a Sunday, its ISO week started on Monday the 2nd
+	eventTime := time.Date(2026, 3, 8, 15, 20, 0, 0, time.UTC)
+	counterUpdateGroup, err := countersValueObject.NewCounterUpdateGroup(eventTime)
+	s.Require().NoError(err)
+	appConfig := config.Load()
+	appConfig.Counters.DailyRetention = 24 * time.Hour
+	appConfig.Counters.WeeklyRetention = 0
+
+	client.EXPECT().
+		TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+			s.Require().Len(input.TransactItems, 6, "hourly, lifetime and daily rows of both users")
+			daily := input.TransactItems[2].Update
+			dayKey := rollupCountersKey(countersValueObject.CountersGranularityDay, eventTime)
+			s.Require().Equal(strconv.FormatInt(dayKey, 10), daily.Key[HourUnixTimestampAttrName].(*types.AttributeValueMemberN).Value)
+			expiresAt := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+			s.Require().Equal(strconv.FormatInt(expiresAt.Unix(), 10), daily.ExpressionAttributeValues[":ttl"].(*types.AttributeValueMemberN).Value)
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		})
+
+	NewCountersRepository(client, appConfig, slog.New(slog.NewTextHandler(io.Discard, nil))).
+		IncrYesCounters(ctx, voteId, counterUpdateGroup)
+
+	s.Require().Equal(
+		time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		rollupPeriodStart(countersValueObject.CountersGranularityWeek, rollupCountersKey(countersValueObject.CountersGranularityWeek, eventTime)),
+	)
+}
+
+func (s *CountersRepositoryUnitTestSuite) TestCrossRegionPeerCountersAreRetriedWithOneToken() {
+	ctrl := gomock.NewController(s.T())
+	client := mocks.NewMockClient(ctrl)
+	ctx := context.Background()
+	repo := newCountersRepository(client)
+	activeUserUpdates, peerUserUpdates := s.userUpdates(), s.userUpdates()
+
+	var tokens []string
+	gomock.InOrder(
+		client.EXPECT().TransactWriteItems(ctx, gomock.Any(), gomock.Any()).Return(&dynamodb.TransactWriteItemsOutput{}, nil),
+		client.EXPECT().
+			TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				tokens = append(tokens, *input.ClientRequestToken)
+				return nil, &types.InternalServerError{}
+			}),
+		client.EXPECT().
+			TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+				tokens = append(tokens, *input.ClientRequestToken)
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			}),
+	)
+
+	err := repo.writeUsersCounters(ctx, "us-east-2", "eu-west-1", activeUserUpdates, peerUserUpdates)
+
+	s.Require().NoError(err)
+	s.Require().Len(tokens, 2)
+	s.Require().Equal(tokens[0], tokens[1])
+}
+
+func (s *CountersRepositoryUnitTestSuite) TestCrossRegionActiveUserCountersAreTakenBackWhenPeerFails() {
+	ctrl := gomock.NewController(s.T())
+	client := mocks.NewMockClient(ctrl)
+	ctx := context.Background()
+	repo := newCountersRepository(client)
+	activeUserUpdates, peerUserUpdates := s.userUpdates(), s.userUpdates()
+	expectedErr := &types.InternalServerError{}
+
+	client.EXPECT().TransactWriteItems(ctx, gomock.Any(), gomock.Any()).Return(&dynamodb.TransactWriteItemsOutput{}, nil)
+	client.EXPECT().TransactWriteItems(ctx, gomock.Any(), gomock.Any()).Return(nil, expectedErr).Times(crossRegionCountersAttempts)
+	for _, update := range activeUserUpdates {
+		client.EXPECT().
+			UpdateItem(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				s.Require().Equal(update.Update.Key, input.Key)
+				s.Require().Equal(outgoingYesAttrName, input.ExpressionAttributeNames["#counterIndex"])
+				options := &dynamodb.Options{}
+				for _, optFn := range optFns {
+					optFn(options)
+				}
+				s.Require().Equal("us-east-2", options.Region)
+				return &dynamodb.UpdateItemOutput{}, nil
+			})
+	}
+
+	err := repo.writeUsersCounters(ctx, "us-east-2", "eu-west-1", activeUserUpdates, peerUserUpdates)
+
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *CountersRepositoryUnitTestSuite) userUpdates() []types.TransactWriteItem {
+	var updates []types.TransactWriteItem
+	for _, key := range []int64{LifetimeCounterKey, time.Now().Truncate(time.Hour).Unix()} {
+		updates = append(updates, types.TransactWriteItem{
+			Update: &types.Update{
+				Key:                      newCountersRepository(nil).getCountersTableKey(s.activeUserKey.ActiveUserId(), key),
+				ExpressionAttributeNames: map[string]string{"#counterIndex": outgoingYesAttrName},
+			},
+		})
+	}
+	return updates
+}
+
+func (s *CountersRepositoryUnitTestSuite) countersItem(hour time.Time, outgoingYes uint32) map[string]types.AttributeValue {
+	item, err := attributevalue.MarshalMap(CountersDocumentSchema{
+		UserId:            s.activeUserKey.ActiveUserId().String(),
+		HourUnixTimestamp: int32(hour.Unix()),
+		OutgoingYes:       outgoingYes,
+	})
+	s.Require().NoError(err)
+	return item
+}
+
+func newCountersRepository(client *mocks.MockClient) *CountersRepository {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewCountersRepository(client, config.Load(), logger)
+}
@@ -0,0 +1,324 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	platformDynamoDb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/google/uuid"
+)
+
+const (
+	ExclusionFiltersTableName       = "ExclusionFilters"
+	ExclusionFiltersUserAttrName    = "u"
+	exclusionFilterAddedAttrName    = "p"
+	exclusionFilterUserKeySeparator = "#"
+	// exclusionFilterChunkSize keeps every chunk item well below the 400 KB DynamoDB item limit
+	exclusionFilterChunkSize = 300 * 1024
+	// exclusionFilterMaxAdded keeps the added peers of the filter item below the item limit, a full
+	// filter is dropped and built again with the added peers
+	exclusionFilterMaxAdded = 5000
+)
+
+// ExclusionFilterDocumentSchema keeps a built filter of a user, the peers excluded after it was built
+// are added to a set instead of the filter bits so instances can add them at the same time. The bits
+// are split into Chunks chunk items of the Generation as a filter can be larger than an item.
+type ExclusionFilterDocumentSchema struct {
+	UserKey    string   `dynamodbav:"u"`
+	Generation string   `dynamodbav:"g"`
+	Chunks     int      `dynamodbav:"n"`
+	Added      []string `dynamodbav:"p,stringset,omitempty"`
+	Ttl        int64    `dynamodbav:"ttl"`
+}
+
+// ExclusionFilterChunkDocumentSchema keeps a part of the filter bits, chunks are never updated so a
+// saved filter writes a new generation and the chunks of the replaced one expire
+type ExclusionFilterChunkDocumentSchema struct {
+	ChunkKey string `dynamodbav:"u"`
+	Index    int    `dynamodbav:"i"`
+	Data     []byte `dynamodbav:"f"`
+	Ttl      int64  `dynamodbav:"ttl"`
+}
+
+// ExclusionFiltersRepository shares the built filters between the instances, the filters are kept
+// for the configured TTL and see the votes written through any instance
+type ExclusionFiltersRepository struct {
+	dynamoDbClient platformDynamoDb.Client
+	config         config.Config
+	logger         platform.Logger
+}
+
+func NewExclusionFiltersRepository(
+	dynamoDbClient platformDynamoDb.Client,
+	config config.Config,
+	logger platform.Logger,
+) *ExclusionFiltersRepository {
+	return &ExclusionFiltersRepository{
+		dynamoDbClient: dynamoDbClient,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+func (r *ExclusionFiltersRepository) GetExclusionFilter(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+) (romancesValueObject.ExclusionFilter, bool, error) {
+	region := platformDynamoDb.GetDynamodbRegionByCountry(userKey.CountryId())
+	out, err := r.dynamoDbClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(ExclusionFiltersTableName),
+		Key:            r.getExclusionFiltersTableKey(userKey),
+		ConsistentRead: aws.Bool(true),
+	}, func(o *dynamodb.Options) {
+		o.Region = region
+	})
+	if err != nil {
+		return romancesValueObject.ExclusionFilter{}, false, err
+	}
+	if out.Item == nil {
+		return romancesValueObject.ExclusionFilter{}, false, nil
+	}
+
+	item := &ExclusionFilterDocumentSchema{}
+	if err = attributevalue.UnmarshalMap(out.Item, item); err != nil {
+		return romancesValueObject.ExclusionFilter{}, false, err
+	}
+	// DynamoDB deletes expired items lazily
+	if item.Ttl < time.Now().Unix() {
+		return romancesValueObject.ExclusionFilter{}, false, nil
+	}
+
+	data, err := r.getExclusionFilterData(ctx, region, userKey, item)
+	if err != nil {
+		return romancesValueObject.ExclusionFilter{}, false, err
+	}
+	filter, err := romancesValueObject.ExclusionFilterFromBinary(data)
+	if err != nil {
+		return romancesValueObject.ExclusionFilter{}, false, err
+	}
+	for _, added := range item.Added {
+		peerId, err := uuid.Parse(added)
+		if err != nil {
+			return romancesValueObject.ExclusionFilter{}, false, err
+		}
+		filter.Add(peerId)
+	}
+	return filter, true, nil
+}
+
+// getExclusionFilterData joins the chunks of the filter item in order
+func (r *ExclusionFiltersRepository) getExclusionFilterData(
+	ctx context.Context,
+	region string,
+	userKey sharedValueObject.ActiveUserKey,
+	item *ExclusionFilterDocumentSchema,
+) ([]byte, error) {
+	keys := make([]map[string]types.AttributeValue, 0, item.Chunks)
+	for i := range item.Chunks {
+		keys = append(keys, map[string]types.AttributeValue{
+			ExclusionFiltersUserAttrName: &types.AttributeValueMemberS{Value: r.exclusionFilterChunkKey(userKey, item.Generation, i)},
+		})
+	}
+
+	chunks := make([][]byte, item.Chunks)
+	retries := 0
+	delay := unprocessedKeysBaseDelay
+	for len(keys) > 0 {
+		batchSize := min(len(keys), batchGetItemMaxKeys)
+		out, err := r.dynamoDbClient.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]types.KeysAndAttributes{
+				ExclusionFiltersTableName: {Keys: keys[:batchSize], ConsistentRead: aws.Bool(true)},
+			},
+		}, func(o *dynamodb.Options) {
+			o.Region = region
+		})
+		if err != nil {
+			return nil, err
+		}
+		keys = keys[batchSize:]
+
+		var items []ExclusionFilterChunkDocumentSchema
+		if err = attributevalue.UnmarshalListOfMaps(out.Responses[ExclusionFiltersTableName], &items); err != nil {
+			return nil, err
+		}
+		for _, chunk := range items {
+			if chunk.Index < 0 || chunk.Index >= len(chunks) {
+				return nil, fmt.Errorf("exclusion filter chunk %d out of %d chunks", chunk.Index, len(chunks))
+			}
+			chunks[chunk.Index] = chunk.Data
+		}
+
+		unprocessed, exists := out.UnprocessedKeys[ExclusionFiltersTableName]
+		if !exists || len(unprocessed.Keys) == 0 {
+			retries = 0
+			delay = unprocessedKeysBaseDelay
+			continue
+		}
+		if retries == unprocessedKeysMaxRetries {
+			return nil, fmt.Errorf("%d exclusion filter chunks still unprocessed after %d retries", len(unprocessed.Keys), retries)
+		}
+		retries++
+		keys = append(keys, unprocessed.Keys...)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, unprocessedKeysMaxDelay)
+	}
+
+	var data []byte
+	for i, chunk := range chunks {
+		if chunk == nil {
+			return nil, fmt.Errorf("exclusion filter chunk %d of generation %s is missing", i, item.Generation)
+		}
+		data = append(data, chunk...)
+	}
+	return data, nil
+}
+
+// SaveExclusionFilter replaces the kept filter, the peers added to the replaced one are dropped as the
+// saved filter was built from the romances. The chunks are written before the filter item pointing to
+// them, so a concurrent read sees either filter in full.
+func (r *ExclusionFiltersRepository) SaveExclusionFilter(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	filter romancesValueObject.ExclusionFilter,
+) error {
+	data, err := filter.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	region := platformDynamoDb.GetDynamodbRegionByCountry(userKey.CountryId())
+	generation := uuid.NewString()
+	ttl := time.Now().Add(r.config.Exclusion.CacheTtl).Unix()
+
+	chunks := 0
+	for start := 0; start < len(data); start += exclusionFilterChunkSize {
+		chunk, err := attributevalue.MarshalMap(ExclusionFilterChunkDocumentSchema{
+			ChunkKey: r.exclusionFilterChunkKey(userKey, generation, chunks),
+			Index:    chunks,
+			Data:     data[start:min(start+exclusionFilterChunkSize, len(data))],
+			Ttl:      ttl,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = r.dynamoDbClient.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(ExclusionFiltersTableName),
+			Item:      chunk,
+		}, func(o *dynamodb.Options) {
+			o.Region = region
+		})
+		if err != nil {
+			return err
+		}
+		chunks++
+	}
+
+	item, err := attributevalue.MarshalMap(ExclusionFilterDocumentSchema{
+		UserKey:    r.exclusionFilterUserKey(userKey),
+		Generation: generation,
+		Chunks:     chunks,
+		Ttl:        ttl,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.dynamoDbClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(ExclusionFiltersTableName),
+		Item:      item,
+	}, func(o *dynamodb.Options) {
+		o.Region = region
+	})
+	return err
+}
+
+// AddToExclusionFilter drops a filter which already has exclusionFilterMaxAdded added peers instead,
+// the next read builds it again with the peer
+func (r *ExclusionFiltersRepository) AddToExclusionFilter(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	peerId uuid.UUID,
+) {
+	now := time.Now().Unix()
+	_, err := r.dynamoDbClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(ExclusionFiltersTableName),
+		Key:              r.getExclusionFiltersTableKey(userKey),
+		UpdateExpression: aws.String("ADD #added :peer"),
+		ConditionExpression: aws.String("attribute_exists(#user) AND #ttl >= :now" +
+			" AND (attribute_not_exists(#added) OR size(#added) < :maxAdded)"),
+		ExpressionAttributeNames: map[string]string{
+			"#added": exclusionFilterAddedAttrName,
+			"#user":  ExclusionFiltersUserAttrName,
+			"#ttl":   platformDynamoDb.TtlAttrName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":peer":     &types.AttributeValueMemberSS{Value: []string{peerId.String()}},
+			":now":      &types.AttributeValueMemberN{Value: strconv.FormatInt(now, 10)},
+			":maxAdded": &types.AttributeValueMemberN{Value: strconv.Itoa(exclusionFilterMaxAdded)},
+		},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}, func(o *dynamodb.Options) {
+		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(userKey.CountryId())
+	})
+
+	var condCheckErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condCheckErr) {
+		item := &ExclusionFilterDocumentSchema{}
+		if condCheckErr.Item != nil && attributevalue.UnmarshalMap(condCheckErr.Item, item) == nil &&
+			item.Ttl >= now && len(item.Added) >= exclusionFilterMaxAdded {
+			r.DeleteExclusionFilter(ctx, userKey)
+		}
+		return
+	}
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("AddToExclusionFilter error: %+v", err))
+	}
+}
+
+// DeleteExclusionFilter deletes the filter item only, its chunks expire with it
+func (r *ExclusionFiltersRepository) DeleteExclusionFilter(ctx context.Context, userKey sharedValueObject.ActiveUserKey) {
+	_, err := r.dynamoDbClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(ExclusionFiltersTableName),
+		Key:       r.getExclusionFiltersTableKey(userKey),
+	}, func(o *dynamodb.Options) {
+		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(userKey.CountryId())
+	})
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("DeleteExclusionFilter error: %+v", err))
+	}
+}
+
+func (r *ExclusionFiltersRepository) getExclusionFiltersTableKey(userKey sharedValueObject.ActiveUserKey) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		ExclusionFiltersUserAttrName: &types.AttributeValueMemberS{Value: r.exclusionFilterUserKey(userKey)},
+	}
+}
+
+// exclusionFilterChunkKey has more separators than a user key so chunks never collide with a filter item
+func (r *ExclusionFiltersRepository) exclusionFilterChunkKey(
+	userKey sharedValueObject.ActiveUserKey,
+	generation string,
+	index int,
+) string {
+	return r.exclusionFilterUserKey(userKey) + exclusionFilterUserKeySeparator + generation +
+		exclusionFilterUserKeySeparator + strconv.Itoa(index)
+}
+
+// exclusionFilterUserKey keeps the filters of a user in different countries apart
+func (r *ExclusionFiltersRepository) exclusionFilterUserKey(userKey sharedValueObject.ActiveUserKey) string {
+	return strconv.FormatUint(uint64(userKey.CountryId()), 10) + exclusionFilterUserKeySeparator + userKey.ActiveUserId().String()
+}
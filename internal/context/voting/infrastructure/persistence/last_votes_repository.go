@@ -0,0 +1,300 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	quotaEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	rewindDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	platformDynamoDb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/google/uuid"
+)
+
+const (
+	LastVotesTableName      = "LastVotes"
+	LastVotesUserIdAttrName = "u"
+)
+
+// LastVotesDocumentSchema keeps the last votes of a user in a single item, oldest first
+type LastVotesDocumentSchema struct {
+	UserId  string                   `dynamodbav:"u"`
+	Votes   []LastVoteDocumentSchema `dynamodbav:"l"`
+	Version uint32                   `dynamodbav:"v"`
+	Ttl     int64                    `dynamodbav:"ttl"`
+}
+
+type LastVoteDocumentSchema struct {
+	PeerId                string                     `dynamodbav:"p"`
+	PeerCountryId         uint16                     `dynamodbav:"c,omitempty"`
+	VoteType              uint8                      `dynamodbav:"t"`
+	VotedAt               int64                      `dynamodbav:"a"`
+	PreviousVoteType      uint8                      `dynamodbav:"pt"`
+	PreviousVotedAt       *int64                     `dynamodbav:"pa"`
+	PreviousVoteCreatedAt *int64                     `dynamodbav:"pc"`
+	PreviousVoteUpdatedAt *int64                     `dynamodbav:"pu"`
+	PreviousVoteContext   *VoteContextDocumentSchema `dynamodbav:"px,omitempty"`
+	PreviousCompliment    *ComplimentDocumentSchema  `dynamodbav:"pm,omitempty"`
+	// RecordedAt is stored in milliseconds to tell apart votes recorded within the same second
+	RecordedAt int64 `dynamodbav:"r"`
+	CountedYes bool  `dynamodbav:"y"`
+	CountedNo  bool  `dynamodbav:"n"`
+	// PreviousUnmatch is the unmatch ended by the vote
+	PreviousUnmatch *UnmatchDocumentSchema `dynamodbav:"pn,omitempty"`
+	// QuotaDay is the quota day the vote consumed, the vote type is the one of the vote
+	QuotaDay *int64 `dynamodbav:"qd,omitempty"`
+}
+
+type UnmatchDocumentSchema struct {
+	UnmatchedBy       string `dynamodbav:"b"`
+	UnmatchedAt       int64  `dynamodbav:"a"`
+	Reason            uint8  `dynamodbav:"r"`
+	RevoteBannedUntil int64  `dynamodbav:"w"`
+}
+
+type LastVotesRepository struct {
+	dynamoDbClient platformDynamoDb.Client
+	config         config.Config
+	logger         platform.Logger
+}
+
+func NewLastVotesRepository(
+	dynamoDbClient platformDynamoDb.Client,
+	config config.Config,
+	logger platform.Logger,
+) *LastVotesRepository {
+	return &LastVotesRepository{
+		dynamoDbClient: dynamoDbClient,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+func (r *LastVotesRepository) GetLastVotes(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+) (entity.LastVotes, error) {
+	out, err := r.dynamoDbClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(LastVotesTableName),
+		Key:            r.getLastVotesTableKey(activeUserKey),
+		ConsistentRead: aws.Bool(true),
+	}, func(o *dynamodb.Options) {
+		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(activeUserKey.CountryId())
+	})
+	if err != nil {
+		return entity.LastVotes{}, err
+	}
+
+	lastVotes := entity.LastVotes{ActiveUserKey: activeUserKey}
+	if out.Item == nil {
+		return lastVotes, nil
+	}
+
+	item := &LastVotesDocumentSchema{}
+	if err = attributevalue.UnmarshalMap(out.Item, item); err != nil {
+		return entity.LastVotes{}, err
+	}
+
+	// DynamoDB deletes expired items lazily, none of their votes can be rewound anymore
+	if item.Ttl != 0 && item.Ttl < time.Now().Unix() {
+		lastVotes.Version = item.Version
+		return lastVotes, nil
+	}
+
+	for _, voteItem := range item.Votes {
+		vote, err := r.transformLastVoteItemToEntity(activeUserKey, voteItem)
+		if err != nil {
+			return entity.LastVotes{}, err
+		}
+		lastVotes.Votes = append(lastVotes.Votes, vote)
+	}
+	lastVotes.Version = item.Version
+
+	return lastVotes, nil
+}
+
+func (r *LastVotesRepository) SaveLastVotes(
+	ctx context.Context,
+	lastVotes entity.LastVotes,
+	expiresAt time.Time,
+) error {
+	item := LastVotesDocumentSchema{
+		UserId:  lastVotes.ActiveUserKey.ActiveUserId().String(),
+		Votes:   make([]LastVoteDocumentSchema, 0, len(lastVotes.Votes)),
+		Version: lastVotes.Version + 1,
+		Ttl:     expiresAt.Unix(),
+	}
+	for _, vote := range lastVotes.Votes {
+		var previousVoteContext *VoteContextDocumentSchema
+		if vote.PreviousVote.Context != nil {
+			voteContext := newVoteContextDocument(vote.PreviousVote.Context)
+			previousVoteContext = &voteContext
+		}
+		var previousCompliment *ComplimentDocumentSchema
+		if vote.PreviousVote.Compliment != nil {
+			compliment := newComplimentDocument(vote.PreviousVote.Compliment)
+			previousCompliment = &compliment
+		}
+		var previousUnmatch *UnmatchDocumentSchema
+		if vote.PreviousUnmatch != nil {
+			previousUnmatch = &UnmatchDocumentSchema{
+				UnmatchedBy:       vote.PreviousUnmatch.UnmatchedBy.String(),
+				UnmatchedAt:       vote.PreviousUnmatch.UnmatchedAt.Unix(),
+				Reason:            uint8(vote.PreviousUnmatch.Reason),
+				RevoteBannedUntil: vote.PreviousUnmatch.RevoteBannedUntil.Unix(),
+			}
+		}
+		var quotaDay *int64
+		if vote.Consumption != nil {
+			quotaDay = unixOrNil(&vote.Consumption.Day)
+		}
+		item.Votes = append(item.Votes, LastVoteDocumentSchema{
+			PeerId:                vote.PeerId.String(),
+			PeerCountryId:         vote.PeerCountryId,
+			VoteType:              uint8(vote.VoteType),
+			VotedAt:               vote.VotedAt.Unix(),
+			PreviousVoteType:      uint8(vote.PreviousVote.VoteType),
+			PreviousVotedAt:       unixOrNil(vote.PreviousVote.VotedAt),
+			PreviousVoteCreatedAt: unixOrNil(vote.PreviousVote.CreatedAt),
+			PreviousVoteUpdatedAt: unixOrNil(vote.PreviousVote.UpdatedAt),
+			PreviousVoteContext:   previousVoteContext,
+			PreviousCompliment:    previousCompliment,
+			RecordedAt:            vote.RecordedAt.UnixMilli(),
+			CountedYes:            vote.CountedYes,
+			CountedNo:             vote.CountedNo,
+			PreviousUnmatch:       previousUnmatch,
+			QuotaDay:              quotaDay,
+		})
+	}
+
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName:           aws.String(LastVotesTableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(#userId)"),
+		ExpressionAttributeNames: map[string]string{
+			"#userId": LastVotesUserIdAttrName,
+		},
+	}
+	if lastVotes.Version != 0 {
+		input.ConditionExpression = aws.String("#version = :expectedV")
+		input.ExpressionAttributeNames = map[string]string{
+			"#version": versionAttrName,
+		}
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":expectedV": &types.AttributeValueMemberN{Value: strconv.FormatUint(uint64(lastVotes.Version), 10)},
+		}
+	}
+
+	_, err = r.dynamoDbClient.PutItem(ctx, input, func(o *dynamodb.Options) {
+		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(lastVotes.ActiveUserKey.CountryId())
+	})
+
+	var condCheckErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condCheckErr) {
+		return rewindDomain.ErrVersionConflict
+	}
+	return err
+}
+
+func (r *LastVotesRepository) transformLastVoteItemToEntity(
+	activeUserKey sharedValueObject.ActiveUserKey,
+	voteItem LastVoteDocumentSchema,
+) (entity.LastVote, error) {
+	peerId, err := uuid.Parse(voteItem.PeerId)
+	if err != nil {
+		return entity.LastVote{}, err
+	}
+
+	voteId, err := entity.LastVote{PeerId: peerId, PeerCountryId: voteItem.PeerCountryId}.VoteId(activeUserKey)
+	if err != nil {
+		return entity.LastVote{}, err
+	}
+
+	previousCompliment, err := voteItem.PreviousCompliment.toValueObject()
+	if err != nil {
+		return entity.LastVote{}, err
+	}
+
+	var previousUnmatch *romanceEntity.Unmatch
+	if voteItem.PreviousUnmatch != nil {
+		unmatchedBy, err := uuid.Parse(voteItem.PreviousUnmatch.UnmatchedBy)
+		if err != nil {
+			return entity.LastVote{}, err
+		}
+		previousUnmatch = &romanceEntity.Unmatch{
+			UnmatchedBy:       unmatchedBy,
+			UnmatchedAt:       time.Unix(voteItem.PreviousUnmatch.UnmatchedAt, 0).UTC(),
+			Reason:            valueobject.UnmatchReason(voteItem.PreviousUnmatch.Reason),
+			RevoteBannedUntil: time.Unix(voteItem.PreviousUnmatch.RevoteBannedUntil, 0).UTC(),
+		}
+	}
+	var consumption *quotaEntity.Consumption
+	if voteItem.QuotaDay != nil {
+		consumption = &quotaEntity.Consumption{
+			ActiveUserKey: activeUserKey,
+			VoteType:      valueobject.VoteType(voteItem.VoteType),
+			Day:           time.Unix(*voteItem.QuotaDay, 0).UTC(),
+		}
+	}
+
+	return entity.LastVote{
+		PeerId:        peerId,
+		PeerCountryId: voteItem.PeerCountryId,
+		VoteType:      valueobject.VoteType(voteItem.VoteType),
+		VotedAt:       time.Unix(voteItem.VotedAt, 0),
+		PreviousVote: romanceEntity.Vote{
+			Id:         voteId,
+			VoteType:   valueobject.VoteType(voteItem.PreviousVoteType),
+			VotedAt:    timeOrNil(voteItem.PreviousVotedAt),
+			CreatedAt:  timeOrNil(voteItem.PreviousVoteCreatedAt),
+			UpdatedAt:  timeOrNil(voteItem.PreviousVoteUpdatedAt),
+			Context:    voteItem.PreviousVoteContext.toValueObject(),
+			Compliment: previousCompliment,
+		},
+		RecordedAt:      time.UnixMilli(voteItem.RecordedAt),
+		CountedYes:      voteItem.CountedYes,
+		CountedNo:       voteItem.CountedNo,
+		PreviousUnmatch: previousUnmatch,
+		Consumption:     consumption,
+	}, nil
+}
+
+func (r *LastVotesRepository) getLastVotesTableKey(
+	activeUserKey sharedValueObject.ActiveUserKey,
+) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		LastVotesUserIdAttrName: &types.AttributeValueMemberS{Value: activeUserKey.ActiveUserId().String()},
+	}
+}
+
+func unixOrNil(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	unix := t.Unix()
+	return &unix
+}
+
+func timeOrNil(unix *int64) *time.Time {
+	if unix == nil {
+		return nil
+	}
+	t := time.Unix(*unix, 0)
+	return &t
+}
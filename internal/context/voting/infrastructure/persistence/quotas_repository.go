@@ -0,0 +1,203 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	platformDynamoDb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+)
+
+const (
+	QuotasTableName      = "VoteQuotas"
+	QuotaUserIdAttrName  = "u"
+	QuotaDayAttrName     = "d"
+	quotaOverridesDayKey = 0
+	quotaAttrNamePrefix  = "q"
+)
+
+// QuotasRepository keeps one item per user and quota day with the used votes by vote type,
+// the overrides of the user are stored in the item of day 0.
+type QuotasRepository struct {
+	dynamoDbClient platformDynamoDb.Client
+	config         config.Config
+	logger         platform.Logger
+}
+
+func NewQuotasRepository(
+	dynamoDbClient platformDynamoDb.Client,
+	config config.Config,
+	logger platform.Logger,
+) *QuotasRepository {
+	return &QuotasRepository{
+		dynamoDbClient: dynamoDbClient,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+func (r *QuotasRepository) GetOverrides(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+) (map[valueobject.VoteType]uint32, error) {
+	return r.getVoteTypeValues(ctx, activeUserKey, quotaOverridesDayKey)
+}
+
+func (r *QuotasRepository) SetOverrides(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	overrides map[valueobject.VoteType]uint32,
+) error {
+	item := r.getQuotasTableKey(activeUserKey, quotaOverridesDayKey)
+	for voteType, limit := range overrides {
+		item[quotaAttrName(voteType)] = &types.AttributeValueMemberN{Value: strconv.FormatUint(uint64(limit), 10)}
+	}
+
+	_, err := r.dynamoDbClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(QuotasTableName),
+		Item:      item,
+	}, func(o *dynamodb.Options) {
+		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(activeUserKey.CountryId())
+	})
+	return err
+}
+
+func (r *QuotasRepository) GetUsage(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	day time.Time,
+) (map[valueobject.VoteType]uint32, error) {
+	return r.getVoteTypeValues(ctx, activeUserKey, day.Unix())
+}
+
+func (r *QuotasRepository) Consume(
+	ctx context.Context,
+	consumption entity.Consumption,
+	limit uint32,
+	expiresAt time.Time,
+) error {
+	_, err := r.dynamoDbClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(QuotasTableName),
+		Key:                 r.getQuotasTableKey(consumption.ActiveUserKey, consumption.Day.Unix()),
+		UpdateExpression:    aws.String("ADD #used :one SET #ttl = :ttl"),
+		ConditionExpression: aws.String("attribute_not_exists(#used) OR #used < :limit"),
+		ExpressionAttributeNames: map[string]string{
+			"#used": quotaAttrName(consumption.VoteType),
+			"#ttl":  platformDynamoDb.TtlAttrName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one":   &types.AttributeValueMemberN{Value: "1"},
+			":limit": &types.AttributeValueMemberN{Value: strconv.FormatUint(uint64(limit), 10)},
+			":ttl":   &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+		},
+	}, func(o *dynamodb.Options) {
+		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(consumption.ActiveUserKey.CountryId())
+	})
+
+	var condCheckErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condCheckErr) {
+		return quotaDomain.ErrQuotaExhausted
+	}
+	return err
+}
+
+func (r *QuotasRepository) Refund(
+	ctx context.Context,
+	consumption entity.Consumption,
+) error {
+	_, err := r.dynamoDbClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(QuotasTableName),
+		Key:                 r.getQuotasTableKey(consumption.ActiveUserKey, consumption.Day.Unix()),
+		UpdateExpression:    aws.String("ADD #used :minusOne"),
+		ConditionExpression: aws.String("#used > :zero"),
+		ExpressionAttributeNames: map[string]string{
+			"#used": quotaAttrName(consumption.VoteType),
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":minusOne": &types.AttributeValueMemberN{Value: "-1"},
+			":zero":     &types.AttributeValueMemberN{Value: "0"},
+		},
+	}, func(o *dynamodb.Options) {
+		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(consumption.ActiveUserKey.CountryId())
+	})
+
+	// nothing is left to refund, e.g. the quota day item already expired
+	var condCheckErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condCheckErr) {
+		return nil
+	}
+	return err
+}
+
+func (r *QuotasRepository) getVoteTypeValues(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	day int64,
+) (map[valueobject.VoteType]uint32, error) {
+	out, err := r.dynamoDbClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(QuotasTableName),
+		Key:            r.getQuotasTableKey(activeUserKey, day),
+		ConsistentRead: aws.Bool(true),
+	}, func(o *dynamodb.Options) {
+		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(activeUserKey.CountryId())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[valueobject.VoteType]uint32{}
+	for name, attr := range out.Item {
+		voteType, ok := voteTypeFromQuotaAttrName(name)
+		if !ok {
+			continue
+		}
+		number, ok := attr.(*types.AttributeValueMemberN)
+		if !ok {
+			return nil, fmt.Errorf("quota attribute %q is not a number", name)
+		}
+		value, err := strconv.ParseUint(number.Value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("quota attribute %q: %w", name, err)
+		}
+		values[voteType] = uint32(value)
+	}
+	return values, nil
+}
+
+func (r *QuotasRepository) getQuotasTableKey(
+	activeUserKey sharedValueObject.ActiveUserKey,
+	day int64,
+) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		QuotaUserIdAttrName: &types.AttributeValueMemberS{Value: activeUserKey.ActiveUserId().String()},
+		QuotaDayAttrName:    &types.AttributeValueMemberN{Value: strconv.FormatInt(day, 10)},
+	}
+}
+
+func quotaAttrName(voteType valueobject.VoteType) string {
+	return quotaAttrNamePrefix + strconv.Itoa(int(voteType))
+}
+
+func voteTypeFromQuotaAttrName(name string) (valueobject.VoteType, bool) {
+	number, found := strings.CutPrefix(name, quotaAttrNamePrefix)
+	if !found {
+		return valueobject.VoteTypeEmpty, false
+	}
+	voteType, err := strconv.ParseUint(number, 10, 8)
+	if err != nil {
+		return valueobject.VoteTypeEmpty, false
+	}
+	return valueobject.VoteType(voteType), true
+}
@@ -0,0 +1,135 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/google/uuid"
+)
+
+const (
+	batchGetItemMaxKeys = 100
+	// unprocessed keys were throttled, they are read again after a delay doubling from the base delay
+	// up to the max delay and the read fails when they are still unprocessed after the max retries
+	unprocessedKeysBaseDelay  = 50 * time.Millisecond
+	unprocessedKeysMaxDelay   = 2 * time.Second
+	unprocessedKeysMaxRetries = 8
+)
+
+// GetRomancesGroup reads consistent romances grouped by the regions of their home countries, see
+// romanceRegions
+func (r *RomancesRepository) GetRomancesGroup(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	peerIds []uuid.UUID,
+) ([]entity.Romance, error) {
+	return r.getRomancesGroupInRegions(ctx, userKey, peerIds, romanceRegions)
+}
+
+// getRomancesGroupInRegions returns the romances in the order of the peers, a romance is only in
+// one region so the regions where it is not found leave it as it is
+func (r *RomancesRepository) getRomancesGroupInRegions(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	peerIds []uuid.UUID,
+	voteRegions func(voteId sharedValueObject.VoteId) []string,
+) ([]entity.Romance, error) {
+	romances := make([]entity.Romance, len(peerIds))
+	// a peer may be listed more than once
+	positions := make(map[uuid.UUID][]int, len(peerIds))
+	var regions []string
+	keysByRegion := make(map[string][]map[string]types.AttributeValue)
+	for i, peerId := range peerIds {
+		voteId, err := sharedValueObject.NewVoteId(userKey.CountryId(), userKey.ActiveUserId(), peerId)
+		if err != nil {
+			return nil, err
+		}
+		romances[i] = entity.CreateEmptyRomance(voteId)
+
+		if _, exists := positions[peerId]; !exists {
+			for _, region := range voteRegions(voteId) {
+				if _, exists := keysByRegion[region]; !exists {
+					regions = append(regions, region)
+				}
+				keysByRegion[region] = append(keysByRegion[region], r.getRomancesTableKey(NewRomancePrimaryKey(voteId)))
+			}
+		}
+		positions[peerId] = append(positions[peerId], i)
+	}
+
+	for _, region := range regions {
+		items, err := r.batchGetRomances(ctx, region, keysByRegion[region])
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			romance, err := r.transformRomanceItemToEntity(userKey.CountryId(), userKey.ActiveUserId(), item)
+			if err != nil {
+				return nil, err
+			}
+			for _, i := range positions[romance.ActiveUserVote.Id.PeerUserId()] {
+				romances[i] = romance
+			}
+		}
+	}
+
+	return romances, nil
+}
+
+func (r *RomancesRepository) batchGetRomances(
+	ctx context.Context,
+	region string,
+	keys []map[string]types.AttributeValue,
+) ([]RomanceDocumentSchema, error) {
+	var romances []RomanceDocumentSchema
+	retries := 0
+	delay := unprocessedKeysBaseDelay
+	for len(keys) > 0 {
+		batchSize := min(len(keys), batchGetItemMaxKeys)
+		out, err := r.dynamoDbClient.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]types.KeysAndAttributes{
+				RomancesTableName: {Keys: keys[:batchSize], ConsistentRead: aws.Bool(true)},
+			},
+		}, func(o *dynamodb.Options) {
+			o.Region = region
+		})
+		if err != nil {
+			return nil, err
+		}
+		keys = keys[batchSize:]
+
+		var items []RomanceDocumentSchema
+		if err = attributevalue.UnmarshalListOfMaps(out.Responses[RomancesTableName], &items); err != nil {
+			return nil, err
+		}
+		romances = append(romances, items...)
+
+		unprocessed, exists := out.UnprocessedKeys[RomancesTableName]
+		if !exists || len(unprocessed.Keys) == 0 {
+			retries = 0
+			delay = unprocessedKeysBaseDelay
+			continue
+		}
+		if retries == unprocessedKeysMaxRetries {
+			return nil, fmt.Errorf("%d romances still unprocessed after %d retries", len(unprocessed.Keys), retries)
+		}
+		retries++
+		keys = append(keys, unprocessed.Keys...)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, unprocessedKeysMaxDelay)
+	}
+
+	return romances, nil
+}
@@ -0,0 +1,207 @@
+package persistence
+
+import (
+	"context"
+	"encoding/base64"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	platformDynamoDb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/google/uuid"
+)
+
+const romancesByMaxMinUserIndexName = "gsiByMaxMinUser"
+
+// romancesCursor points at the last listed romance, userAttrName is the key attribute holding the
+// active user and region the region it was listed from. Romances where the active user is the
+// partition key are listed first, they are in the region of the active user, the ones where the
+// active user is the sort key are listed from every region. An empty peer starts at the first
+// romance of the key attribute, an empty region at the first region of the key attribute.
+type romancesCursor struct {
+	userAttrName string
+	peerId       uuid.UUID
+	region       string
+}
+
+func (c romancesCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.userAttrName + "#" + c.peerId.String() + "#" + c.region))
+}
+
+func decodeRomancesCursor(cursor string) (romancesCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return romancesCursor{}, romanceDomain.ErrInvalidRomancesCursor
+	}
+	userAttrName, rest, ok := strings.Cut(string(raw), "#")
+	if !ok || (userAttrName != PkUserIdAttrName && userAttrName != SkUserIdAttrName) {
+		return romancesCursor{}, romanceDomain.ErrInvalidRomancesCursor
+	}
+	// cursors issued before romances were listed by region have no region
+	peerId, region, _ := strings.Cut(rest, "#")
+	parsedPeerId, err := uuid.Parse(peerId)
+	if err != nil {
+		return romancesCursor{}, romanceDomain.ErrInvalidRomancesCursor
+	}
+	return romancesCursor{userAttrName: userAttrName, peerId: parsedPeerId, region: region}, nil
+}
+
+// romancesListPass is a query of the romances of the active user by one key attribute in one region
+type romancesListPass struct {
+	userAttrName string
+	region       string
+}
+
+func romancesListPasses(userKey sharedValueObject.ActiveUserKey) []romancesListPass {
+	passes := []romancesListPass{
+		{userAttrName: PkUserIdAttrName, region: platformDynamoDb.GetDynamodbRegionByCountry(userKey.CountryId())},
+	}
+	for _, region := range platformDynamoDb.GetDynamodbRegions() {
+		passes = append(passes, romancesListPass{userAttrName: SkUserIdAttrName, region: region})
+	}
+	return passes
+}
+
+func (r *RomancesRepository) ListRomancesForActiveUser(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	limit int32,
+	cursor string,
+) (entity.RomancesPage, error) {
+	passes := romancesListPasses(userKey)
+	var startKey map[string]types.AttributeValue
+	if cursor != "" {
+		start, err := decodeRomancesCursor(cursor)
+		if err != nil {
+			return entity.RomancesPage{}, err
+		}
+		startPass := slices.IndexFunc(passes, func(pass romancesListPass) bool {
+			return pass.userAttrName == start.userAttrName && (start.region == "" || pass.region == start.region)
+		})
+		if startPass == -1 {
+			return entity.RomancesPage{}, romanceDomain.ErrInvalidRomancesCursor
+		}
+		passes = passes[startPass:]
+		if start.peerId != uuid.Nil {
+			startKey = r.romancesListKey(start.userAttrName, userKey.ActiveUserId(), start.peerId)
+		}
+	}
+
+	page := entity.RomancesPage{Romances: make([]entity.Romance, 0, limit)}
+	for i, pass := range passes {
+		input := &dynamodb.QueryInput{
+			TableName:              aws.String(RomancesTableName),
+			KeyConditionExpression: aws.String("#user = :user"),
+			ExpressionAttributeNames: map[string]string{
+				"#user": pass.userAttrName,
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":user": &types.AttributeValueMemberS{Value: userKey.ActiveUserId().String()},
+			},
+			ExclusiveStartKey: startKey,
+		}
+		// the index only projects the keys, the romances of each page are read from the table in batches
+		if pass.userAttrName == SkUserIdAttrName {
+			input.IndexName = aws.String(romancesByMaxMinUserIndexName)
+		}
+		startKey = nil
+
+		for {
+			input.Limit = aws.Int32(limit - int32(len(page.Romances)))
+			out, err := r.dynamoDbClient.Query(ctx, input, func(o *dynamodb.Options) {
+				o.Region = pass.region
+			})
+			if err != nil {
+				return entity.RomancesPage{}, err
+			}
+
+			var items []RomanceDocumentSchema
+			if err = attributevalue.UnmarshalListOfMaps(out.Items, &items); err != nil {
+				return entity.RomancesPage{}, err
+			}
+
+			romances, err := r.listedRomances(ctx, userKey, pass, items)
+			if err != nil {
+				return entity.RomancesPage{}, err
+			}
+
+			var lastPeerId uuid.UUID
+			for _, romance := range romances {
+				lastPeerId = romance.ActiveUserVote.Id.PeerUserId()
+				// the romance expired or was deleted since the index was read
+				if pass.userAttrName == SkUserIdAttrName && romance.Version == 0 {
+					continue
+				}
+				page.Romances = append(page.Romances, romance)
+			}
+
+			if out.LastEvaluatedKey == nil {
+				break
+			}
+			if len(items) > 0 && len(page.Romances) >= int(limit) {
+				page.NextCursor = romancesCursor{userAttrName: pass.userAttrName, peerId: lastPeerId, region: pass.region}.encode()
+				return page, nil
+			}
+			input.ExclusiveStartKey = out.LastEvaluatedKey
+		}
+
+		if len(page.Romances) >= int(limit) && i < len(passes)-1 {
+			next := passes[i+1]
+			page.NextCursor = romancesCursor{userAttrName: next.userAttrName, peerId: uuid.Nil, region: next.region}.encode()
+			return page, nil
+		}
+	}
+
+	return page, nil
+}
+
+// listedRomances returns the romances of a query page in the order of the items, romances listed
+// from the index are read by their keys from the region of the pass and are empty when they no
+// longer exist
+func (r *RomancesRepository) listedRomances(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	pass romancesListPass,
+	items []RomanceDocumentSchema,
+) ([]entity.Romance, error) {
+	if pass.userAttrName == SkUserIdAttrName {
+		peerIds := make([]uuid.UUID, 0, len(items))
+		for _, item := range items {
+			peerId, err := uuid.Parse(item.PkUserId)
+			if err != nil {
+				return nil, err
+			}
+			peerIds = append(peerIds, peerId)
+		}
+		return r.getRomancesGroupInRegions(ctx, userKey, peerIds, func(sharedValueObject.VoteId) []string {
+			return []string{pass.region}
+		})
+	}
+
+	romances := make([]entity.Romance, 0, len(items))
+	for _, item := range items {
+		romance, err := r.transformRomanceItemToEntity(userKey.CountryId(), userKey.ActiveUserId(), item)
+		if err != nil {
+			return nil, err
+		}
+		romances = append(romances, romance)
+	}
+	return romances, nil
+}
+
+func (r *RomancesRepository) romancesListKey(
+	userAttrName string,
+	activeUserId uuid.UUID,
+	peerId uuid.UUID,
+) map[string]types.AttributeValue {
+	if userAttrName == PkUserIdAttrName {
+		return r.getRomancesTableKey(RomancePrimaryKey{Pk: activeUserId, Sk: peerId})
+	}
+	return r.getRomancesTableKey(RomancePrimaryKey{Pk: peerId, Sk: activeUserId})
+}
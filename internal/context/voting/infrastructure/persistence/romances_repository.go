@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -24,20 +25,40 @@ import (
 )
 
 const (
-	RomancesTableName           = "Romances"
-	PkUserIdAttrName            = "a"
-	SkUserIdAttrName            = "b"
-	pkUserVoteTypeAttrName      = "e"
-	pkUserVotedAtAttrName       = "g"
-	pkUserVoteCreatedAtAttrName = "h"
-	pkUserVoteUpdatedAtAttrName = "i"
-	skUserVoteTypeAttrName      = "l"
-	skUserVotedAtAttrName       = "n"
-	skUserVoteCreatedAtAttrName = "o"
-	skUserVoteUpdatedAtAttrName = "p"
-	versionAttrName             = "v"
+	RomancesTableName             = "Romances"
+	PkUserIdAttrName              = "a"
+	SkUserIdAttrName              = "b"
+	pkUserVoteTypeAttrName        = "e"
+	pkUserVotedAtAttrName         = "g"
+	pkUserVoteCreatedAtAttrName   = "h"
+	pkUserVoteUpdatedAtAttrName   = "i"
+	pkUserBlockedAtAttrName       = "f"
+	pkUserVoteContextAttrName     = "c"
+	pkUserComplimentAttrName      = "j"
+	skUserVoteTypeAttrName        = "l"
+	skUserVotedAtAttrName         = "n"
+	skUserVoteCreatedAtAttrName   = "o"
+	skUserVoteUpdatedAtAttrName   = "p"
+	skUserBlockedAtAttrName       = "m"
+	skUserVoteContextAttrName     = "d"
+	skUserComplimentAttrName      = "k"
+	pkUserCountryIdAttrName       = "q"
+	skUserCountryIdAttrName       = "r"
+	unmatchedByAttrName           = "x"
+	unmatchedAtAttrName           = "y"
+	unmatchReasonAttrName         = "z"
+	revoteBannedUntilAttrName     = "w"
+	versionAttrName               = "v"
+	complimentStatusAttrName      = "s"
+	complimentModeratedAtAttrName = "m"
 )
 
+// lastWriterWinsCondition keeps a delayed write of an older vote from overriding a newer one
+const lastWriterWinsCondition = "(attribute_not_exists(#votedAt) OR #votedAt <= :votedAt)"
+
+// removeUnmatchExpr ends the unmatched state of a romance on the next accepted vote
+const removeUnmatchExpr = " REMOVE #unmatchedBy, #unmatchedAt, #unmatchReason, #revoteBannedUntil"
+
 type RomancesRepository struct {
 	dynamoDbClient platformDynamoDb.Client
 	config         config.Config
@@ -45,17 +66,43 @@ type RomancesRepository struct {
 }
 
 type RomanceDocumentSchema struct {
-	PkUserId            string `dynamodbav:"a"`
-	SkUserId            string `dynamodbav:"b"`
-	PkUserVoteType      uint8  `dynamodbav:"e"`
-	PkUserVotedAt       *int32 `dynamodbav:"g"`
-	PkUserVoteCreatedAt *int32 `dynamodbav:"h"`
-	PkUserVoteUpdatedAt *int32 `dynamodbav:"i"`
-	SkUserVoteType      uint8  `dynamodbav:"l"`
-	SkUserVotedAt       *int32 `dynamodbav:"n"`
-	SkUserVoteCreatedAt *int32 `dynamodbav:"o"`
-	SkUserVoteUpdatedAt *int32 `dynamodbav:"p"`
-	Version             uint32 `dynamodbav:"v"`
+	PkUserId            string                     `dynamodbav:"a"`
+	SkUserId            string                     `dynamodbav:"b"`
+	PkUserVoteType      uint8                      `dynamodbav:"e"`
+	PkUserVotedAt       *int32                     `dynamodbav:"g"`
+	PkUserVoteCreatedAt *int32                     `dynamodbav:"h"`
+	PkUserVoteUpdatedAt *int32                     `dynamodbav:"i"`
+	PkUserVoteContext   *VoteContextDocumentSchema `dynamodbav:"c,omitempty"`
+	PkUserCompliment    *ComplimentDocumentSchema  `dynamodbav:"j,omitempty"`
+	SkUserVoteType      uint8                      `dynamodbav:"l"`
+	SkUserVotedAt       *int32                     `dynamodbav:"n"`
+	SkUserVoteCreatedAt *int32                     `dynamodbav:"o"`
+	SkUserVoteUpdatedAt *int32                     `dynamodbav:"p"`
+	SkUserVoteContext   *VoteContextDocumentSchema `dynamodbav:"d,omitempty"`
+	SkUserCompliment    *ComplimentDocumentSchema  `dynamodbav:"k,omitempty"`
+	PkUserCountryId     uint16                     `dynamodbav:"q,omitempty"`
+	SkUserCountryId     uint16                     `dynamodbav:"r,omitempty"`
+	PkUserBlockedAt     *int32                     `dynamodbav:"f"`
+	SkUserBlockedAt     *int32                     `dynamodbav:"m"`
+	UnmatchedBy         string                     `dynamodbav:"x"`
+	UnmatchedAt         *int32                     `dynamodbav:"y"`
+	UnmatchReason       uint8                      `dynamodbav:"z"`
+	RevoteBannedUntil   *int32                     `dynamodbav:"w"`
+	Version             uint32                     `dynamodbav:"v"`
+}
+
+type VoteContextDocumentSchema struct {
+	Surface          uint8   `dynamodbav:"s"`
+	RecommendationId string  `dynamodbav:"r,omitempty"`
+	Position         *uint32 `dynamodbav:"p,omitempty"`
+	ExperimentBucket string  `dynamodbav:"b,omitempty"`
+}
+
+type ComplimentDocumentSchema struct {
+	Id          string `dynamodbav:"i"`
+	Text        string `dynamodbav:"t"`
+	Status      uint8  `dynamodbav:"s"`
+	ModeratedAt *int64 `dynamodbav:"m,omitempty"`
 }
 
 func NewRomancesRepository(
@@ -77,6 +124,18 @@ func (r *RomancesRepository) getRomancesTableKey(key RomancePrimaryKey) map[stri
 	}
 }
 
+// romanceRegions lists the regions which may hold the romance. The romance is in the region of its
+// home country, the country of the user with the lower id. When that user is the peer its country is
+// not known to the active user, the vote id assumes the country of the active user, so the romance is
+// looked up in every region.
+func romanceRegions(voteId sharedValueObject.VoteId) []string {
+	activeUserId, peerUserId := voteId.ActiveUserId(), voteId.PeerUserId()
+	if bytes.Compare(activeUserId[:], peerUserId[:]) == -1 {
+		return []string{platformDynamoDb.GetDynamodbRegionByCountry(voteId.HomeCountryId())}
+	}
+	return platformDynamoDb.GetDynamodbRegions()
+}
+
 func (r *RomancesRepository) GetRomance(ctx context.Context, voteId sharedValueObject.VoteId) (entity.Romance, error) {
 
 	romanceKey := NewRomancePrimaryKey(voteId)
@@ -85,7 +144,7 @@ func (r *RomancesRepository) GetRomance(ctx context.Context, voteId sharedValueO
 		TableName:      aws.String(RomancesTableName),
 		ConsistentRead: aws.Bool(true),
 	}, func(o *dynamodb.Options) {
-		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(voteId.CountryId())
+		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(voteId.HomeCountryId())
 	})
 
 	if err != nil {
@@ -112,15 +171,13 @@ func (r *RomancesRepository) GetAllPeersForActiveUser(
 ) (<-chan uuid.UUID, error) {
 	out := make(chan uuid.UUID, 64)
 
-	var (
-		lastEvaluatedKey map[string]types.AttributeValue
-		err              error
-	)
+	var err error
 
 	go func() {
 		defer close(out)
 
-		queryFn := func(indexName *string, pkName string) {
+		queryFn := func(indexName *string, pkName string, region string) {
+			var lastEvaluatedKey map[string]types.AttributeValue
 			for {
 
 				input := &dynamodb.QueryInput{
@@ -139,7 +196,9 @@ func (r *RomancesRepository) GetAllPeersForActiveUser(
 					input.IndexName = indexName
 				}
 
-				queryOutput, err := r.dynamoDbClient.Query(ctx, input)
+				queryOutput, err := r.dynamoDbClient.Query(ctx, input, func(o *dynamodb.Options) {
+					o.Region = region
+				})
 				if err != nil {
 					return
 				}
@@ -193,10 +252,14 @@ func (r *RomancesRepository) GetAllPeersForActiveUser(
 			}
 		}
 
-		queryFn(nil, PkUserIdAttrName)
+		// the peers with a higher id are in the region of the active user, the ones with a lower id in
+		// the region of their country
+		queryFn(nil, PkUserIdAttrName, platformDynamoDb.GetDynamodbRegionByCountry(userKey.CountryId()))
 
-		indexName := aws.String("gsiByMaxMinUser")
-		queryFn(indexName, SkUserIdAttrName)
+		indexName := aws.String(romancesByMaxMinUserIndexName)
+		for _, region := range platformDynamoDb.GetDynamodbRegions() {
+			queryFn(indexName, SkUserIdAttrName, region)
+		}
 	}()
 
 	return out, err
@@ -207,10 +270,11 @@ func (r *RomancesRepository) AddActiveUserVoteToRomance(
 	romance entity.Romance,
 	voteType valueobject.VoteType,
 	votedAt time.Time,
+	voteContext *valueobject.VoteContext,
+	compliment *valueobject.Compliment,
 ) (entity.Romance, error) {
 
 	activeUserId := romance.ActiveUserVote.Id.ActiveUserId()
-	countryId := romance.ActiveUserVote.Id.CountryId()
 
 	romanceKey := NewRomancePrimaryKey(romance.ActiveUserVote.Id)
 	now := time.Now()
@@ -224,10 +288,14 @@ func (r *RomancesRepository) AddActiveUserVoteToRomance(
 		exprNames["#voteType"] = pkUserVoteTypeAttrName
 		exprNames["#votedAt"] = pkUserVotedAtAttrName
 		exprNames["#voteCreatedAt"] = pkUserVoteCreatedAtAttrName
+		exprNames["#voteContext"] = pkUserVoteContextAttrName
+		exprNames["#compliment"] = pkUserComplimentAttrName
 	} else {
 		exprNames["#voteType"] = skUserVoteTypeAttrName
 		exprNames["#votedAt"] = skUserVotedAtAttrName
 		exprNames["#voteCreatedAt"] = skUserVoteCreatedAtAttrName
+		exprNames["#voteContext"] = skUserVoteContextAttrName
+		exprNames["#compliment"] = skUserComplimentAttrName
 	}
 
 	currentVersion := int64(romance.Version)
@@ -245,67 +313,115 @@ func (r *RomancesRepository) AddActiveUserVoteToRomance(
 	if romance.Version == 0 {
 		conditionExpression = "attribute_not_exists(a) AND attribute_not_exists(b)"
 	} else {
-		conditionExpression = "#version = :expectedV"
+		conditionExpression = "#version = :expectedV AND " + lastWriterWinsCondition
 		exprValues[":expectedV"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion, 10)}
 	}
 
-	updateExpr := aws.String("SET #voteType = :voteType, #votedAt = :votedAt, #voteCreatedAt = :createdAt, #version = :v, #ttl = :ttl")
+	setContextExpr, removeContextExpr, err := voteContextExpr(voteContext, exprValues)
+	if err != nil {
+		return entity.Romance{}, err
+	}
+	setComplimentExpr, removeComplimentExpr, err := complimentExpr(compliment, exprValues)
+	if err != nil {
+		return entity.Romance{}, err
+	}
 
-	out, err := r.dynamoDbClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
-		Key:                       r.getRomancesTableKey(romanceKey),
-		TableName:                 aws.String(RomancesTableName),
-		UpdateExpression:          updateExpr,
-		ExpressionAttributeNames:  exprNames,
-		ExpressionAttributeValues: exprValues,
-		ConditionExpression:       aws.String(conditionExpression),
-		ReturnValues:              types.ReturnValueAllNew,
-	}, func(o *dynamodb.Options) {
-		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(countryId)
-	})
+	addUnmatchAttrNames(exprNames)
+	updateExpr := aws.String("SET #voteType = :voteType, #votedAt = :votedAt, #voteCreatedAt = :createdAt, #version = :v, #ttl = :ttl, " +
+		countriesExpr(romanceKey, romance.ActiveUserVote.Id, exprNames, exprValues) + setContextExpr + setComplimentExpr + removeUnmatchExpr + removeContextExpr + removeComplimentExpr)
+
+	update := &types.Update{
+		Key:                                 r.getRomancesTableKey(romanceKey),
+		TableName:                           aws.String(RomancesTableName),
+		UpdateExpression:                    updateExpr,
+		ExpressionAttributeNames:            exprNames,
+		ExpressionAttributeValues:           exprValues,
+		ConditionExpression:                 aws.String(conditionExpression),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+	historyEntry := r.newVoteHistoryEntry(ctx, romance, valueobject.VoteActionAdd, voteType, &votedAt, now)
 
-	if err != nil {
+	if err := r.writeVote(ctx, romance.ActiveUserVote.Id.HomeCountryId(), update, historyEntry, romanceKey); err != nil {
 		var condCheckErr *types.ConditionalCheckFailedException
 		if errors.As(err, &condCheckErr) {
-			return entity.Romance{}, romanceDomain.ErrVersionConflict
+			return entity.Romance{}, conditionCheckFailure(condCheckErr, exprNames["#votedAt"], votedAt)
 		}
 
 		return entity.Romance{}, err
 	}
 
-	romanceItem := &RomanceDocumentSchema{}
-	if err = attributevalue.UnmarshalMap(out.Attributes, romanceItem); err != nil {
-		return entity.Romance{}, err
-	}
+	// the transaction returns no attributes, the version condition makes the stored romance known
+	romance.ActiveUserVote.VoteType = voteType
+	romance.ActiveUserVote.VotedAt = unixTimePtr(votedAt)
+	romance.ActiveUserVote.CreatedAt = unixTimePtr(now)
+	romance.ActiveUserVote.Context = voteContext
+	romance.ActiveUserVote.Compliment = compliment
+	romance.Version = historyEntry.RomanceVersion
+	romance.Unmatch = nil
 
-	r.logger.Debug(fmt.Sprintf("Updated romance in dynamodb: %+v", romanceItem))
+	r.logger.Debug(fmt.Sprintf("Updated romance in dynamodb: %+v", romance))
 
-	return r.transformRomanceItemToEntity(countryId, activeUserId, *romanceItem)
+	return romance, nil
 }
 
+// DeleteRomance deletes the romance in every region which may hold it, see romanceRegions
 func (r *RomancesRepository) DeleteRomance(
 	ctx context.Context,
 	voteId sharedValueObject.VoteId,
 ) error {
 	romanceKey := NewRomancePrimaryKey(voteId)
-	out, err := r.dynamoDbClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
-		Key:       r.getRomancesTableKey(romanceKey),
-		TableName: aws.String(RomancesTableName),
-	}, func(o *dynamodb.Options) {
-		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(voteId.CountryId())
-	})
+	for _, region := range romanceRegions(voteId) {
+		out, err := r.dynamoDbClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			Key:       r.getRomancesTableKey(romanceKey),
+			TableName: aws.String(RomancesTableName),
+		}, func(o *dynamodb.Options) {
+			o.Region = region
+		})
 
-	if err != nil {
-		return err
-	}
+		if err != nil {
+			return err
+		}
 
-	r.logger.Debug(fmt.Sprintf("Romance deleted from dynamodb: %+v", out))
+		r.logger.Debug(fmt.Sprintf("Romance deleted from dynamodb in %s: %+v", region, out))
+	}
 	return nil
 }
 
+// DeleteRomancesGroup deletes each romance in every region which may hold it, see romanceRegions
 func (r *RomancesRepository) DeleteRomancesGroup(
 	ctx context.Context,
 	userKey sharedValueObject.ActiveUserKey,
 	peerIds []uuid.UUID,
+) error {
+	var regions []string
+	peerIdsByRegion := make(map[string][]uuid.UUID)
+	for _, peerId := range peerIds {
+		voteId, err := sharedValueObject.NewVoteId(userKey.CountryId(), userKey.ActiveUserId(), peerId)
+		if err != nil {
+			return err
+		}
+		for _, region := range romanceRegions(voteId) {
+			if _, exists := peerIdsByRegion[region]; !exists {
+				regions = append(regions, region)
+			}
+			peerIdsByRegion[region] = append(peerIdsByRegion[region], peerId)
+		}
+	}
+
+	for _, region := range regions {
+		if err := r.deleteRomancesGroupInRegion(ctx, userKey, region, peerIdsByRegion[region]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *RomancesRepository) deleteRomancesGroupInRegion(
+	ctx context.Context,
+	userKey sharedValueObject.ActiveUserKey,
+	region string,
+	peerIds []uuid.UUID,
 ) error {
 	var batch []types.WriteRequest
 	var keysLog []uuid.UUID
@@ -327,6 +443,8 @@ func (r *RomancesRepository) DeleteRomancesGroup(
 				RequestItems: map[string][]types.WriteRequest{
 					RomancesTableName: batch,
 				},
+			}, func(o *dynamodb.Options) {
+				o.Region = region
 			})
 			if err != nil {
 				return err
@@ -334,10 +452,11 @@ func (r *RomancesRepository) DeleteRomancesGroup(
 			batch = batch[:0]
 			r.logger.Debug(
 				fmt.Sprintf(
-					"Deleted records from Romances talbe. Count: %d, ActiveIserId: %s, CountryId: %d, PeerIds: %+v",
+					"Deleted records from Romances talbe. Count: %d, ActiveIserId: %s, CountryId: %d, Region: %s, PeerIds: %+v",
 					len(keysLog),
 					userKey.ActiveUserId(),
 					userKey.CountryId(),
+					region,
 					keysLog,
 				),
 			)
@@ -350,16 +469,19 @@ func (r *RomancesRepository) DeleteRomancesGroup(
 			RequestItems: map[string][]types.WriteRequest{
 				RomancesTableName: batch,
 			},
+		}, func(o *dynamodb.Options) {
+			o.Region = region
 		})
 		if err != nil {
 			return err
 		}
 		r.logger.Debug(
 			fmt.Sprintf(
-				"Deleted records from Romances talbe. Count: %d, ActiveIserId: %s, CountryId: %d, PeerIds: %+v",
+				"Deleted records from Romances talbe. Count: %d, ActiveIserId: %s, CountryId: %d, Region: %s, PeerIds: %+v",
 				len(keysLog),
 				userKey.ActiveUserId(),
 				userKey.CountryId(),
+				region,
 				keysLog,
 			),
 		)
@@ -368,13 +490,12 @@ func (r *RomancesRepository) DeleteRomancesGroup(
 	return nil
 }
 
-func (r *RomancesRepository) DeleteActiveUserVoteFromRomance(ctx context.Context, romance entity.Romance) error {
+func (r *RomancesRepository) DeleteActiveUserVoteFromRomance(ctx context.Context, romance entity.Romance) (entity.Romance, error) {
 	if romance.IsEmpty() {
-		return nil
+		return romance, nil
 	}
 
 	activeUserId := romance.ActiveUserVote.Id.ActiveUserId()
-	countryId := romance.ActiveUserVote.Id.CountryId()
 
 	romanceKey := NewRomancePrimaryKey(romance.ActiveUserVote.Id)
 	exprNames := map[string]string{
@@ -387,16 +508,30 @@ func (r *RomancesRepository) DeleteActiveUserVoteFromRomance(ctx context.Context
 		exprNames["#votedAt"] = pkUserVotedAtAttrName
 		exprNames["#voteCreatedAt"] = pkUserVoteCreatedAtAttrName
 		exprNames["#voteUpdatedAt"] = pkUserVoteUpdatedAtAttrName
+		exprNames["#voteContext"] = pkUserVoteContextAttrName
+		exprNames["#compliment"] = pkUserComplimentAttrName
 	} else {
 		exprNames["#voteType"] = skUserVoteTypeAttrName
 		exprNames["#votedAt"] = skUserVotedAtAttrName
 		exprNames["#voteCreatedAt"] = skUserVoteCreatedAtAttrName
 		exprNames["#voteUpdatedAt"] = skUserVoteUpdatedAtAttrName
+		exprNames["#voteContext"] = skUserVoteContextAttrName
+		exprNames["#compliment"] = skUserComplimentAttrName
 	}
 
 	currentVersion := int64(romance.Version)
 	ttlSeconds := r.getTtlSecondsForVotesPair(valueobject.VoteTypeEmpty, romance.PeerUserVote.VoteType)
 
+	// the unmatch is kept while its revote ban lasts, deleting the vote must not lift the ban
+	keepsUnmatch := romance.IsRevoteBanned(time.Now())
+	removeExpr := " REMOVE #voteType, #votedAt, #voteCreatedAt, #voteUpdatedAt, #voteContext, #compliment"
+	if keepsUnmatch {
+		ttlSeconds = r.config.Romances.DeadRomanceTtlSeconds
+	} else {
+		addUnmatchAttrNames(exprNames)
+		removeExpr += ", #unmatchedBy, #unmatchedAt, #unmatchReason, #revoteBannedUntil"
+	}
+
 	exprValues := map[string]types.AttributeValue{
 		":v":   &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion+1, 10)},
 		":ttl": &types.AttributeValueMemberN{Value: strconv.FormatInt(ttlSeconds, 10)},
@@ -405,37 +540,44 @@ func (r *RomancesRepository) DeleteActiveUserVoteFromRomance(ctx context.Context
 	conditionExpression := "#version = :expectedV"
 	exprValues[":expectedV"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion, 10)}
 
-	updateExpr := aws.String("SET #version = :v, #ttl = :ttl REMOVE #voteType, #votedAt, #voteCreatedAt, #voteUpdatedAt")
+	updateExpr := aws.String("SET #version = :v, #ttl = :ttl" + removeExpr)
 
-	out, err := r.dynamoDbClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+	update := &types.Update{
 		Key:                       r.getRomancesTableKey(romanceKey),
 		TableName:                 aws.String(RomancesTableName),
 		UpdateExpression:          updateExpr,
 		ExpressionAttributeNames:  exprNames,
 		ExpressionAttributeValues: exprValues,
 		ConditionExpression:       aws.String(conditionExpression),
-		ReturnValues:              types.ReturnValueAllNew,
-	}, func(o *dynamodb.Options) {
-		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(countryId)
-	})
+	}
+	historyEntry := r.newVoteHistoryEntry(ctx, romance, valueobject.VoteActionDelete, valueobject.VoteTypeEmpty, nil, time.Now())
 
-	if err != nil {
+	if err := r.writeVote(ctx, romance.ActiveUserVote.Id.HomeCountryId(), update, historyEntry, romanceKey); err != nil {
 		var condCheckErr *types.ConditionalCheckFailedException
 		if errors.As(err, &condCheckErr) {
-			return romanceDomain.ErrVersionConflict
+			return entity.Romance{}, romanceDomain.ErrVersionConflict
 		}
 
-		return err
+		return entity.Romance{}, err
 	}
 
-	r.logger.Debug(fmt.Sprintf("Deleted romance vote from dynamodb: %+v", out))
-	return nil
+	romance.ActiveUserVote = entity.Vote{Id: romance.ActiveUserVote.Id}
+	romance.Version = historyEntry.RomanceVersion
+	if !keepsUnmatch {
+		romance.Unmatch = nil
+	}
+
+	r.logger.Debug(fmt.Sprintf("Deleted romance vote from dynamodb: %+v", romanceKey))
+	return romance, nil
 }
 
 func (r *RomancesRepository) ChangeActiveUserVoteTypeInRomance(
 	ctx context.Context,
 	romance entity.Romance,
 	newVoteType valueobject.VoteType,
+	votedAt time.Time,
+	voteContext *valueobject.VoteContext,
+	compliment *valueobject.Compliment,
 ) (entity.Romance, error) {
 	if romance.ActiveUserVote.VoteType.IsEmpty() {
 		return entity.Romance{}, romanceDomain.ErrVoteNotFound
@@ -446,7 +588,6 @@ func (r *RomancesRepository) ChangeActiveUserVoteTypeInRomance(
 	}
 
 	activeUserId := romance.ActiveUserVote.Id.ActiveUserId()
-	countryId := romance.ActiveUserVote.Id.CountryId()
 
 	romanceKey := NewRomancePrimaryKey(romance.ActiveUserVote.Id)
 	now := time.Now()
@@ -458,10 +599,16 @@ func (r *RomancesRepository) ChangeActiveUserVoteTypeInRomance(
 
 	if romanceKey.isPartitionKey(activeUserId) {
 		exprNames["#voteType"] = pkUserVoteTypeAttrName
+		exprNames["#votedAt"] = pkUserVotedAtAttrName
 		exprNames["#voteUpdatedAt"] = pkUserVoteUpdatedAtAttrName
+		exprNames["#voteContext"] = pkUserVoteContextAttrName
+		exprNames["#compliment"] = pkUserComplimentAttrName
 	} else {
 		exprNames["#voteType"] = skUserVoteTypeAttrName
+		exprNames["#votedAt"] = skUserVotedAtAttrName
 		exprNames["#voteUpdatedAt"] = skUserVoteUpdatedAtAttrName
+		exprNames["#voteContext"] = skUserVoteContextAttrName
+		exprNames["#compliment"] = skUserComplimentAttrName
 	}
 
 	currentVersion := int64(romance.Version)
@@ -469,26 +616,269 @@ func (r *RomancesRepository) ChangeActiveUserVoteTypeInRomance(
 
 	exprValues := map[string]types.AttributeValue{
 		":voteType":  &types.AttributeValueMemberN{Value: strconv.Itoa(int(newVoteType))},
+		":votedAt":   &types.AttributeValueMemberN{Value: strconv.FormatInt(votedAt.Unix(), 10)},
 		":updatedAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
 		":v":         &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion+1, 10)},
 		":ttl":       &types.AttributeValueMemberN{Value: strconv.FormatInt(ttlSeconds, 10)},
 	}
 
-	conditionExpression := "#version = :expectedV"
+	conditionExpression := "#version = :expectedV AND " + lastWriterWinsCondition
 	exprValues[":expectedV"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion, 10)}
 
-	updateExpr := aws.String("SET #voteType = :voteType, #voteUpdatedAt = :updatedAt, #version = :v, #ttl = :ttl")
+	setContextExpr, removeContextExpr, err := voteContextExpr(voteContext, exprValues)
+	if err != nil {
+		return entity.Romance{}, err
+	}
+	setComplimentExpr, removeComplimentExpr, err := complimentExpr(compliment, exprValues)
+	if err != nil {
+		return entity.Romance{}, err
+	}
+
+	addUnmatchAttrNames(exprNames)
+	updateExpr := aws.String("SET #voteType = :voteType, #votedAt = :votedAt, #voteUpdatedAt = :updatedAt, #version = :v, #ttl = :ttl, " +
+		countriesExpr(romanceKey, romance.ActiveUserVote.Id, exprNames, exprValues) + setContextExpr + setComplimentExpr + removeUnmatchExpr + removeContextExpr + removeComplimentExpr)
+
+	update := &types.Update{
+		Key:                                 r.getRomancesTableKey(romanceKey),
+		TableName:                           aws.String(RomancesTableName),
+		UpdateExpression:                    updateExpr,
+		ExpressionAttributeNames:            exprNames,
+		ExpressionAttributeValues:           exprValues,
+		ConditionExpression:                 aws.String(conditionExpression),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+	historyEntry := r.newVoteHistoryEntry(ctx, romance, valueobject.VoteActionChange, newVoteType, &votedAt, now)
+
+	if err := r.writeVote(ctx, romance.ActiveUserVote.Id.HomeCountryId(), update, historyEntry, romanceKey); err != nil {
+		var condCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condCheckErr) {
+			return entity.Romance{}, conditionCheckFailure(condCheckErr, exprNames["#votedAt"], votedAt)
+		}
+
+		return entity.Romance{}, err
+	}
+
+	romance.ActiveUserVote.VoteType = newVoteType
+	romance.ActiveUserVote.VotedAt = unixTimePtr(votedAt)
+	romance.ActiveUserVote.UpdatedAt = unixTimePtr(now)
+	romance.ActiveUserVote.Context = voteContext
+	romance.ActiveUserVote.Compliment = compliment
+	romance.Version = historyEntry.RomanceVersion
+	romance.Unmatch = nil
+
+	r.logger.Debug(fmt.Sprintf("Updated romance in dynamodb: %+v", romance))
+
+	return romance, nil
+}
+
+// RestoreActiveUserVoteInRomance puts back a previous vote of the active user as it was stored,
+// the vote attributes are removed when the previous vote is empty.
+func (r *RomancesRepository) RestoreActiveUserVoteInRomance(
+	ctx context.Context,
+	romance entity.Romance,
+	vote entity.Vote,
+	unmatch *entity.Unmatch,
+) (entity.Romance, error) {
+	activeUserId := romance.ActiveUserVote.Id.ActiveUserId()
+	romanceKey := NewRomancePrimaryKey(romance.ActiveUserVote.Id)
+
+	exprNames := map[string]string{
+		"#version": versionAttrName,
+		"#ttl":     platformDynamoDb.TtlAttrName,
+	}
+	voteAttrNames := map[string]string{}
+	if romanceKey.isPartitionKey(activeUserId) {
+		voteAttrNames["#voteType"] = pkUserVoteTypeAttrName
+		voteAttrNames["#votedAt"] = pkUserVotedAtAttrName
+		voteAttrNames["#voteCreatedAt"] = pkUserVoteCreatedAtAttrName
+		voteAttrNames["#voteUpdatedAt"] = pkUserVoteUpdatedAtAttrName
+		voteAttrNames["#voteContext"] = pkUserVoteContextAttrName
+		voteAttrNames["#compliment"] = pkUserComplimentAttrName
+	} else {
+		voteAttrNames["#voteType"] = skUserVoteTypeAttrName
+		voteAttrNames["#votedAt"] = skUserVotedAtAttrName
+		voteAttrNames["#voteCreatedAt"] = skUserVoteCreatedAtAttrName
+		voteAttrNames["#voteUpdatedAt"] = skUserVoteUpdatedAtAttrName
+		voteAttrNames["#voteContext"] = skUserVoteContextAttrName
+		voteAttrNames["#compliment"] = skUserComplimentAttrName
+	}
+
+	currentVersion := int64(romance.Version)
+	ttlSeconds := r.getTtlSecondsForVotesPair(vote.VoteType, romance.PeerUserVote.VoteType)
+	if unmatch != nil {
+		ttlSeconds = r.config.Romances.DeadRomanceTtlSeconds
+	}
+
+	exprValues := map[string]types.AttributeValue{
+		":v":         &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion+1, 10)},
+		":expectedV": &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion, 10)},
+		":ttl":       &types.AttributeValueMemberN{Value: strconv.FormatInt(ttlSeconds, 10)},
+	}
+
+	setExprs := []string{"#version = :v", "#ttl = :ttl", countriesExpr(romanceKey, romance.ActiveUserVote.Id, exprNames, exprValues)}
+	if unmatch != nil {
+		addUnmatchAttrNames(exprNames)
+		exprValues[":unmatchedBy"] = &types.AttributeValueMemberS{Value: unmatch.UnmatchedBy.String()}
+		exprValues[":unmatchedAt"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(unmatch.UnmatchedAt.Unix(), 10)}
+		exprValues[":unmatchReason"] = &types.AttributeValueMemberN{Value: strconv.Itoa(int(unmatch.Reason))}
+		exprValues[":revoteBannedUntil"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(unmatch.RevoteBannedUntil.Unix(), 10)}
+		setExprs = append(setExprs, "#unmatchedBy = :unmatchedBy", "#unmatchedAt = :unmatchedAt",
+			"#unmatchReason = :unmatchReason", "#revoteBannedUntil = :revoteBannedUntil")
+	}
+	var removeExprs []string
+	restore := func(name string, value types.AttributeValue) {
+		exprNames[name] = voteAttrNames[name]
+		if value == nil {
+			removeExprs = append(removeExprs, name)
+			return
+		}
+		valueName := ":" + strings.TrimPrefix(name, "#")
+		exprValues[valueName] = value
+		setExprs = append(setExprs, name+" = "+valueName)
+	}
+	timeValue := func(t *time.Time) types.AttributeValue {
+		if vote.VoteType.IsEmpty() || t == nil {
+			return nil
+		}
+		return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.Unix(), 10)}
+	}
+
+	var voteTypeValue, voteContextValue, complimentValue types.AttributeValue
+	if !vote.VoteType.IsEmpty() {
+		voteTypeValue = &types.AttributeValueMemberN{Value: strconv.Itoa(int(vote.VoteType))}
+		if vote.Context != nil {
+			var err error
+			if voteContextValue, err = attributevalue.Marshal(newVoteContextDocument(vote.Context)); err != nil {
+				return entity.Romance{}, err
+			}
+		}
+		if vote.Compliment != nil {
+			var err error
+			if complimentValue, err = attributevalue.Marshal(newComplimentDocument(vote.Compliment)); err != nil {
+				return entity.Romance{}, err
+			}
+		}
+	}
+	restore("#voteType", voteTypeValue)
+	restore("#votedAt", timeValue(vote.VotedAt))
+	restore("#voteCreatedAt", timeValue(vote.CreatedAt))
+	restore("#voteUpdatedAt", timeValue(vote.UpdatedAt))
+	restore("#voteContext", voteContextValue)
+	restore("#compliment", complimentValue)
+
+	updateExpr := "SET " + strings.Join(setExprs, ", ")
+	if len(removeExprs) > 0 {
+		updateExpr += " REMOVE " + strings.Join(removeExprs, ", ")
+	}
+
+	update := &types.Update{
+		Key:                       r.getRomancesTableKey(romanceKey),
+		TableName:                 aws.String(RomancesTableName),
+		UpdateExpression:          aws.String(updateExpr),
+		ExpressionAttributeNames:  exprNames,
+		ExpressionAttributeValues: exprValues,
+		ConditionExpression:       aws.String("#version = :expectedV"),
+	}
+	historyEntry := r.newVoteHistoryEntry(ctx, romance, valueobject.VoteActionRewind, vote.VoteType, vote.VotedAt, time.Now())
+
+	if err := r.writeVote(ctx, romance.ActiveUserVote.Id.HomeCountryId(), update, historyEntry, romanceKey); err != nil {
+		var condCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condCheckErr) {
+			return entity.Romance{}, romanceDomain.ErrVersionConflict
+		}
+
+		return entity.Romance{}, err
+	}
+
+	romance.ActiveUserVote = entity.Vote{Id: romance.ActiveUserVote.Id, VoteType: vote.VoteType}
+	if !vote.VoteType.IsEmpty() {
+		romance.ActiveUserVote.VotedAt = optionalUnixTimePtr(vote.VotedAt)
+		romance.ActiveUserVote.CreatedAt = optionalUnixTimePtr(vote.CreatedAt)
+		romance.ActiveUserVote.UpdatedAt = optionalUnixTimePtr(vote.UpdatedAt)
+		romance.ActiveUserVote.Context = vote.Context
+		romance.ActiveUserVote.Compliment = vote.Compliment
+	}
+	if unmatch != nil {
+		romance.Unmatch = unmatch
+	}
+	romance.Version = historyEntry.RomanceVersion
+
+	r.logger.Debug(fmt.Sprintf("Restored romance vote in dynamodb: %+v", romance))
+
+	return romance, nil
+}
+
+// BlockPeerInRomance removes the TTL of the romance so the block outlives the votes
+func (r *RomancesRepository) BlockPeerInRomance(
+	ctx context.Context,
+	romance entity.Romance,
+	blockedAt time.Time,
+) (entity.Romance, error) {
+	exprValues := map[string]types.AttributeValue{
+		":blockedAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(blockedAt.Unix(), 10)},
+	}
+	return r.updateBlockInRomance(ctx, romance, "SET #blockedAt = :blockedAt, #version = :v REMOVE #ttl", exprValues)
+}
+
+// UnblockPeerInRomance restores the TTL matching the votes once no user blocks the other one
+func (r *RomancesRepository) UnblockPeerInRomance(
+	ctx context.Context,
+	romance entity.Romance,
+) (entity.Romance, error) {
+	// the TTL stays removed while the peer still blocks the active user
+	updateExpr := "SET #version = :v REMOVE #blockedAt, #ttl"
+	exprValues := map[string]types.AttributeValue{}
+	if romance.PeerUserBlockedAt == nil {
+		ttlSeconds := r.getTtlSecondsForVotesPair(romance.ActiveUserVote.VoteType, romance.PeerUserVote.VoteType)
+		exprValues[":ttl"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(ttlSeconds, 10)}
+		updateExpr = "SET #version = :v, #ttl = :ttl REMOVE #blockedAt"
+	}
+	return r.updateBlockInRomance(ctx, romance, updateExpr, exprValues)
+}
+
+func (r *RomancesRepository) updateBlockInRomance(
+	ctx context.Context,
+	romance entity.Romance,
+	updateExpr string,
+	exprValues map[string]types.AttributeValue,
+) (entity.Romance, error) {
+	activeUserId := romance.ActiveUserVote.Id.ActiveUserId()
+	countryId := romance.ActiveUserVote.Id.CountryId()
+	romanceKey := NewRomancePrimaryKey(romance.ActiveUserVote.Id)
+
+	exprNames := map[string]string{
+		"#version": versionAttrName,
+		"#ttl":     platformDynamoDb.TtlAttrName,
+	}
+	if romanceKey.isPartitionKey(activeUserId) {
+		exprNames["#blockedAt"] = pkUserBlockedAtAttrName
+	} else {
+		exprNames["#blockedAt"] = skUserBlockedAtAttrName
+	}
+
+	currentVersion := int64(romance.Version)
+	exprValues[":v"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion+1, 10)}
+	updateExpr = "SET " + countriesExpr(romanceKey, romance.ActiveUserVote.Id, exprNames, exprValues) + ", " +
+		strings.TrimPrefix(updateExpr, "SET ")
+
+	var conditionExpression string
+	if romance.Version == 0 {
+		conditionExpression = "attribute_not_exists(a) AND attribute_not_exists(b)"
+	} else {
+		conditionExpression = "#version = :expectedV"
+		exprValues[":expectedV"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion, 10)}
+	}
 
 	out, err := r.dynamoDbClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		Key:                       r.getRomancesTableKey(romanceKey),
 		TableName:                 aws.String(RomancesTableName),
-		UpdateExpression:          updateExpr,
+		UpdateExpression:          aws.String(updateExpr),
 		ExpressionAttributeNames:  exprNames,
 		ExpressionAttributeValues: exprValues,
 		ConditionExpression:       aws.String(conditionExpression),
 		ReturnValues:              types.ReturnValueAllNew,
 	}, func(o *dynamodb.Options) {
-		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(countryId)
+		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(romance.ActiveUserVote.Id.HomeCountryId())
 	})
 
 	if err != nil {
@@ -505,11 +895,267 @@ func (r *RomancesRepository) ChangeActiveUserVoteTypeInRomance(
 		return entity.Romance{}, err
 	}
 
-	r.logger.Debug(fmt.Sprintf("Updated romance in dynamodb: %+v", romanceItem))
+	r.logger.Debug(fmt.Sprintf("Updated romance block in dynamodb: %+v", romanceItem))
 
 	return r.transformRomanceItemToEntity(countryId, activeUserId, *romanceItem)
 }
 
+// UnmatchRomance removes the votes of both users, a match after the revote ban needs new votes from
+// both of them. The votes are kept in the history of the romance and it expires as a dead one.
+func (r *RomancesRepository) UnmatchRomance(
+	ctx context.Context,
+	romance entity.Romance,
+	unmatch entity.Unmatch,
+) (entity.Romance, error) {
+	romanceKey := NewRomancePrimaryKey(romance.ActiveUserVote.Id)
+
+	exprNames := map[string]string{
+		"#version":         versionAttrName,
+		"#ttl":             platformDynamoDb.TtlAttrName,
+		"#pkVoteType":      pkUserVoteTypeAttrName,
+		"#pkVotedAt":       pkUserVotedAtAttrName,
+		"#pkVoteCreatedAt": pkUserVoteCreatedAtAttrName,
+		"#pkVoteUpdatedAt": pkUserVoteUpdatedAtAttrName,
+		"#pkVoteContext":   pkUserVoteContextAttrName,
+		"#pkCompliment":    pkUserComplimentAttrName,
+		"#skVoteType":      skUserVoteTypeAttrName,
+		"#skVotedAt":       skUserVotedAtAttrName,
+		"#skVoteCreatedAt": skUserVoteCreatedAtAttrName,
+		"#skVoteUpdatedAt": skUserVoteUpdatedAtAttrName,
+		"#skVoteContext":   skUserVoteContextAttrName,
+		"#skCompliment":    skUserComplimentAttrName,
+	}
+	addUnmatchAttrNames(exprNames)
+
+	currentVersion := int64(romance.Version)
+	exprValues := map[string]types.AttributeValue{
+		":unmatchedBy":       &types.AttributeValueMemberS{Value: unmatch.UnmatchedBy.String()},
+		":unmatchedAt":       &types.AttributeValueMemberN{Value: strconv.FormatInt(unmatch.UnmatchedAt.Unix(), 10)},
+		":unmatchReason":     &types.AttributeValueMemberN{Value: strconv.Itoa(int(unmatch.Reason))},
+		":revoteBannedUntil": &types.AttributeValueMemberN{Value: strconv.FormatInt(unmatch.RevoteBannedUntil.Unix(), 10)},
+		":v":                 &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion+1, 10)},
+		":expectedV":         &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion, 10)},
+		":ttl":               &types.AttributeValueMemberN{Value: strconv.FormatInt(r.config.Romances.DeadRomanceTtlSeconds, 10)},
+	}
+
+	updateExpr := aws.String("SET #unmatchedBy = :unmatchedBy, #unmatchedAt = :unmatchedAt, #unmatchReason = :unmatchReason, " +
+		"#revoteBannedUntil = :revoteBannedUntil, #version = :v, #ttl = :ttl" +
+		" REMOVE #pkVoteType, #pkVotedAt, #pkVoteCreatedAt, #pkVoteUpdatedAt, #pkVoteContext, #pkCompliment," +
+		" #skVoteType, #skVotedAt, #skVoteCreatedAt, #skVoteUpdatedAt, #skVoteContext, #skCompliment")
+
+	update := &types.Update{
+		Key:                       r.getRomancesTableKey(romanceKey),
+		TableName:                 aws.String(RomancesTableName),
+		UpdateExpression:          updateExpr,
+		ExpressionAttributeNames:  exprNames,
+		ExpressionAttributeValues: exprValues,
+		ConditionExpression:       aws.String("#version = :expectedV"),
+	}
+	historyEntry := r.newVoteHistoryEntry(ctx, romance, valueobject.VoteActionUnmatch, valueobject.VoteTypeEmpty, nil, unmatch.UnmatchedAt)
+
+	if err := r.writeVote(ctx, romance.ActiveUserVote.Id.HomeCountryId(), update, historyEntry, romanceKey); err != nil {
+		var condCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condCheckErr) {
+			return entity.Romance{}, romanceDomain.ErrVersionConflict
+		}
+
+		return entity.Romance{}, err
+	}
+
+	romance.ActiveUserVote = entity.Vote{Id: romance.ActiveUserVote.Id}
+	romance.PeerUserVote = entity.Vote{Id: romance.PeerUserVote.Id}
+	romance.Unmatch = &unmatch
+	romance.Version = historyEntry.RomanceVersion
+
+	r.logger.Debug(fmt.Sprintf("Unmatched romance in dynamodb: %+v", romance))
+
+	return romance, nil
+}
+
+// ModerateComplimentInRomance leaves the TTL as it is, the moderation does not change the votes
+func (r *RomancesRepository) ModerateComplimentInRomance(
+	ctx context.Context,
+	romance entity.Romance,
+	status valueobject.ModerationStatus,
+	moderatedAt time.Time,
+) (entity.Romance, error) {
+	activeUserId := romance.ActiveUserVote.Id.ActiveUserId()
+	romanceKey := NewRomancePrimaryKey(romance.ActiveUserVote.Id)
+
+	exprNames := map[string]string{
+		"#version":     versionAttrName,
+		"#status":      complimentStatusAttrName,
+		"#moderatedAt": complimentModeratedAtAttrName,
+	}
+	if romanceKey.isPartitionKey(activeUserId) {
+		exprNames["#compliment"] = pkUserComplimentAttrName
+	} else {
+		exprNames["#compliment"] = skUserComplimentAttrName
+	}
+
+	currentVersion := int64(romance.Version)
+	exprValues := map[string]types.AttributeValue{
+		":status":      &types.AttributeValueMemberN{Value: strconv.Itoa(int(status))},
+		":moderatedAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(moderatedAt.Unix(), 10)},
+		":v":           &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion+1, 10)},
+		":expectedV":   &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion, 10)},
+	}
+
+	update := &types.Update{
+		Key:                       r.getRomancesTableKey(romanceKey),
+		TableName:                 aws.String(RomancesTableName),
+		UpdateExpression:          aws.String("SET #compliment.#status = :status, #compliment.#moderatedAt = :moderatedAt, #version = :v"),
+		ExpressionAttributeNames:  exprNames,
+		ExpressionAttributeValues: exprValues,
+		ConditionExpression:       aws.String("#version = :expectedV AND attribute_exists(#compliment)"),
+	}
+	vote := romance.ActiveUserVote
+	historyEntry := r.newVoteHistoryEntry(ctx, romance, valueobject.VoteActionModerate, vote.VoteType, vote.VotedAt, time.Now())
+
+	if err := r.writeVote(ctx, vote.Id.HomeCountryId(), update, historyEntry, romanceKey); err != nil {
+		var condCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condCheckErr) {
+			return entity.Romance{}, romanceDomain.ErrVersionConflict
+		}
+
+		return entity.Romance{}, err
+	}
+
+	if vote.Compliment != nil {
+		compliment := *vote.Compliment
+		compliment.Status = status
+		compliment.ModeratedAt = &moderatedAt
+		romance.ActiveUserVote.Compliment = &compliment
+	}
+	romance.Version = historyEntry.RomanceVersion
+
+	r.logger.Debug(fmt.Sprintf("Moderated compliment in dynamodb: %+v", romance))
+
+	return romance, nil
+}
+
+func addUnmatchAttrNames(exprNames map[string]string) {
+	exprNames["#unmatchedBy"] = unmatchedByAttrName
+	exprNames["#unmatchedAt"] = unmatchedAtAttrName
+	exprNames["#unmatchReason"] = unmatchReasonAttrName
+	exprNames["#revoteBannedUntil"] = revoteBannedUntilAttrName
+}
+
+// countriesExpr stores the country of each user, the country of the active user is always up to date
+// while the country of the peer is only assumed when it was never stored
+func countriesExpr(
+	romanceKey RomancePrimaryKey,
+	voteId sharedValueObject.VoteId,
+	exprNames map[string]string,
+	exprValues map[string]types.AttributeValue,
+) string {
+	if romanceKey.isPartitionKey(voteId.ActiveUserId()) {
+		exprNames["#activeUserCountry"] = pkUserCountryIdAttrName
+		exprNames["#peerCountry"] = skUserCountryIdAttrName
+	} else {
+		exprNames["#activeUserCountry"] = skUserCountryIdAttrName
+		exprNames["#peerCountry"] = pkUserCountryIdAttrName
+	}
+	exprValues[":activeUserCountry"] = &types.AttributeValueMemberN{Value: strconv.Itoa(int(voteId.CountryId()))}
+	exprValues[":peerCountry"] = &types.AttributeValueMemberN{Value: strconv.Itoa(int(voteId.PeerCountryId()))}
+	return "#activeUserCountry = :activeUserCountry, #peerCountry = if_not_exists(#peerCountry, :peerCountry)"
+}
+
+// voteContextExpr stores the context of an accepted vote, the context of the replaced vote is
+// removed when the new one has none
+func voteContextExpr(
+	voteContext *valueobject.VoteContext,
+	exprValues map[string]types.AttributeValue,
+) (setExpr string, removeExpr string, err error) {
+	if voteContext == nil {
+		return "", ", #voteContext", nil
+	}
+	exprValues[":voteContext"], err = attributevalue.Marshal(newVoteContextDocument(voteContext))
+	if err != nil {
+		return "", "", err
+	}
+	return ", #voteContext = :voteContext", "", nil
+}
+
+func newVoteContextDocument(voteContext *valueobject.VoteContext) VoteContextDocumentSchema {
+	return VoteContextDocumentSchema{
+		Surface:          uint8(voteContext.Surface),
+		RecommendationId: voteContext.RecommendationId,
+		Position:         voteContext.Position,
+		ExperimentBucket: voteContext.ExperimentBucket,
+	}
+}
+
+func (d *VoteContextDocumentSchema) toValueObject() *valueobject.VoteContext {
+	if d == nil {
+		return nil
+	}
+	return &valueobject.VoteContext{
+		Surface:          valueobject.VoteSurface(d.Surface),
+		RecommendationId: d.RecommendationId,
+		Position:         d.Position,
+		ExperimentBucket: d.ExperimentBucket,
+	}
+}
+
+// complimentExpr stores the compliment sent with an accepted vote, a vote without one removes the
+// compliment of the replaced vote
+func complimentExpr(
+	compliment *valueobject.Compliment,
+	exprValues map[string]types.AttributeValue,
+) (setExpr string, removeExpr string, err error) {
+	if compliment == nil {
+		return "", ", #compliment", nil
+	}
+	exprValues[":compliment"], err = attributevalue.Marshal(newComplimentDocument(compliment))
+	if err != nil {
+		return "", "", err
+	}
+	return ", #compliment = :compliment", "", nil
+}
+
+func newComplimentDocument(compliment *valueobject.Compliment) ComplimentDocumentSchema {
+	return ComplimentDocumentSchema{
+		Id:          compliment.Id.String(),
+		Text:        compliment.Text,
+		Status:      uint8(compliment.Status),
+		ModeratedAt: unixOrNil(compliment.ModeratedAt),
+	}
+}
+
+func (d *ComplimentDocumentSchema) toValueObject() (*valueobject.Compliment, error) {
+	if d == nil {
+		return nil, nil
+	}
+	id, err := uuid.Parse(d.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &valueobject.Compliment{
+		Id:          id,
+		Text:        d.Text,
+		Status:      valueobject.ModerationStatus(d.Status),
+		ModeratedAt: timeOrNil(d.ModeratedAt),
+	}, nil
+}
+
+// conditionCheckFailure tells a newer stored vote apart from a concurrent update of the romance
+func conditionCheckFailure(
+	condCheckErr *types.ConditionalCheckFailedException,
+	votedAtAttrName string,
+	votedAt time.Time,
+) error {
+	stored, ok := condCheckErr.Item[votedAtAttrName].(*types.AttributeValueMemberN)
+	if !ok {
+		return romanceDomain.ErrVersionConflict
+	}
+	storedVotedAt, err := strconv.ParseInt(stored.Value, 10, 64)
+	if err == nil && storedVotedAt > votedAt.Unix() {
+		return romanceDomain.ErrStaleVote
+	}
+	return romanceDomain.ErrVersionConflict
+}
+
 func (r *RomancesRepository) transformRomanceItemToEntity(
 	countryId uint16,
 	activeUserId uuid.UUID,
@@ -531,6 +1177,11 @@ func (r *RomancesRepository) transformRomanceItemToEntity(
 		VotedAt:   timeutil.UnixToTimePtr(romanceItem.PkUserVotedAt),
 		CreatedAt: timeutil.UnixToTimePtr(romanceItem.PkUserVoteCreatedAt),
 		UpdatedAt: timeutil.UnixToTimePtr(romanceItem.PkUserVoteUpdatedAt),
+		Context:   romanceItem.PkUserVoteContext.toValueObject(),
+	}
+	pkUserVote.Compliment, err = romanceItem.PkUserCompliment.toValueObject()
+	if err != nil {
+		return entity.Romance{}, err
 	}
 
 	skUserVote := entity.Vote{
@@ -538,16 +1189,28 @@ func (r *RomancesRepository) transformRomanceItemToEntity(
 		VotedAt:   timeutil.UnixToTimePtr(romanceItem.SkUserVotedAt),
 		CreatedAt: timeutil.UnixToTimePtr(romanceItem.SkUserVoteCreatedAt),
 		UpdatedAt: timeutil.UnixToTimePtr(romanceItem.SkUserVoteUpdatedAt),
+		Context:   romanceItem.SkUserVoteContext.toValueObject(),
+	}
+	skUserVote.Compliment, err = romanceItem.SkUserCompliment.toValueObject()
+	if err != nil {
+		return entity.Romance{}, err
 	}
 
 	var peerUserId uuid.UUID
+	var peerCountryId uint16
 	if activeUserId == pkUserId {
 		peerUserId = skUserId
+		peerCountryId = romanceItem.SkUserCountryId
 	} else {
 		peerUserId = pkUserId
+		peerCountryId = romanceItem.PkUserCountryId
 	}
 
-	activeUserVoteId, err := sharedValueObject.NewVoteId(countryId, activeUserId, peerUserId)
+	// romances stored before the countries were kept belong to users of the same country
+	if peerCountryId == 0 {
+		peerCountryId = countryId
+	}
+	activeUserVoteId, err := sharedValueObject.NewCrossCountryVoteId(countryId, activeUserId, peerCountryId, peerUserId)
 	if err != nil {
 		return entity.Romance{}, err
 	}
@@ -555,23 +1218,47 @@ func (r *RomancesRepository) transformRomanceItemToEntity(
 
 	var resultActiveUserVote entity.Vote
 	var resultPeerUserVote entity.Vote
+	var activeUserBlockedAt, peerUserBlockedAt *time.Time
 
 	if activeUserId == pkUserId {
 		resultActiveUserVote = pkUserVote
 		resultActiveUserVote.Id = activeUserVoteId
 		resultPeerUserVote = skUserVote
 		resultPeerUserVote.Id = peerUserVoteId
+		activeUserBlockedAt = timeutil.UnixToTimePtr(romanceItem.PkUserBlockedAt)
+		peerUserBlockedAt = timeutil.UnixToTimePtr(romanceItem.SkUserBlockedAt)
 	} else {
 		resultActiveUserVote = skUserVote
 		resultActiveUserVote.Id = activeUserVoteId
 		resultPeerUserVote = pkUserVote
 		resultPeerUserVote.Id = peerUserVoteId
+		activeUserBlockedAt = timeutil.UnixToTimePtr(romanceItem.SkUserBlockedAt)
+		peerUserBlockedAt = timeutil.UnixToTimePtr(romanceItem.PkUserBlockedAt)
+	}
+
+	var unmatch *entity.Unmatch
+	if romanceItem.UnmatchedAt != nil {
+		unmatchedBy, err := uuid.Parse(romanceItem.UnmatchedBy)
+		if err != nil {
+			return entity.Romance{}, err
+		}
+		unmatch = &entity.Unmatch{
+			UnmatchedBy: unmatchedBy,
+			UnmatchedAt: *timeutil.UnixToTimePtr(romanceItem.UnmatchedAt),
+			Reason:      valueobject.UnmatchReason(romanceItem.UnmatchReason),
+		}
+		if romanceItem.RevoteBannedUntil != nil {
+			unmatch.RevoteBannedUntil = *timeutil.UnixToTimePtr(romanceItem.RevoteBannedUntil)
+		}
 	}
 
 	return entity.Romance{
-		ActiveUserVote: resultActiveUserVote,
-		PeerUserVote:   resultPeerUserVote,
-		Version:        romanceItem.Version,
+		ActiveUserVote:      resultActiveUserVote,
+		PeerUserVote:        resultPeerUserVote,
+		Version:             romanceItem.Version,
+		ActiveUserBlockedAt: activeUserBlockedAt,
+		PeerUserBlockedAt:   peerUserBlockedAt,
+		Unmatch:             unmatch,
 	}, nil
 }
 
@@ -580,15 +1267,14 @@ func (r *RomancesRepository) getTtlSecondsForVotesPair(
 	peerUserVoteType valueobject.VoteType,
 ) int64 {
 
-	if activeUserVoteType.IsNegative() || peerUserVoteType.IsNegative() {
+	switch valueobject.RomanceStateFromVotes(activeUserVoteType, peerUserVoteType) {
+	case valueobject.RomanceStateRejected, valueobject.RomanceStateMutuallyRejected:
 		return r.config.Romances.DeadRomanceTtlSeconds
-	}
-
-	if activeUserVoteType.IsPositive() && peerUserVoteType.IsPositive() {
+	case valueobject.RomanceStateMatched:
 		return r.config.Romances.MutualRomanceTtlSeconds
+	default:
+		return r.config.Romances.NonMutualRomanceTtlSeconds
 	}
-
-	return r.config.Romances.NonMutualRomanceTtlSeconds
 }
 
 type RomancePrimaryKey struct {
@@ -5,16 +5,23 @@ import (
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
 	"io"
 	"log/slog"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
 	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
 	rvo "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	platformDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/requestid"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
 )
@@ -65,12 +72,12 @@ func (s *RomancesRepositoryUnitTestSuite) TestAddVoteWithDbException() {
 	expectedErr := &types.InvalidEndpointException{}
 
 	mock.EXPECT().
-		UpdateItem(ctx, gomock.Any(), gomock.Any()).
+		TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
 		Return(nil, expectedErr)
 
 	repo := newRomancesRepository(mock)
 
-	_, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now())
+	_, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now(), nil, nil)
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, expectedErr)
 }
@@ -112,12 +119,12 @@ func (s *RomancesRepositoryUnitTestSuite) TestDeleteActiveUserVoteWithDbExceptio
 	expectedErr := &types.InvalidEndpointException{}
 
 	mock.EXPECT().
-		UpdateItem(ctx, gomock.Any(), gomock.Any()).
+		TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
 		Return(nil, expectedErr)
 
 	repo := newRomancesRepository(mock)
 
-	err := repo.DeleteActiveUserVoteFromRomance(ctx, romance)
+	_, err := repo.DeleteActiveUserVoteFromRomance(ctx, romance)
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, expectedErr)
 }
@@ -140,18 +147,338 @@ func (s *RomancesRepositoryUnitTestSuite) TestChangeActiveUserVoteTypeInRomanceW
 	expectedErr := &types.InvalidEndpointException{}
 
 	mock.EXPECT().
-		UpdateItem(ctx, gomock.Any(), gomock.Any()).
+		TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
 		Return(nil, expectedErr)
 
 	repo := newRomancesRepository(mock)
 
-	newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeYes)
+	newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeYes, now, nil, nil)
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, expectedErr)
 	s.assertEmptyRomance(newRomance)
 }
 
+func (s *RomancesRepositoryUnitTestSuite) TestChangeVoteRejectedByNewerStoredVote() {
+	ctrl := gomock.NewController(s.T())
+	mock := mocks.NewMockClient(ctrl)
+
+	ctx := context.Background()
+	romance := s.romanceWithVote(rvo.VoteTypeYes)
+	votedAt := time.Now().Add(-time.Minute)
+
+	mock.EXPECT().
+		TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
+		Return(nil, s.conditionCheckFailure(votedAt.Add(time.Second)))
+
+	repo := newRomancesRepository(mock)
+
+	_, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeCrush, votedAt, nil, nil)
+	s.Require().ErrorIs(err, romanceDomain.ErrStaleVote)
+}
+
+func (s *RomancesRepositoryUnitTestSuite) TestAddVoteConflictWithOlderStoredVote() {
+	ctrl := gomock.NewController(s.T())
+	mock := mocks.NewMockClient(ctrl)
+
+	ctx := context.Background()
+	romance := s.romanceWithVote(rvo.VoteTypeNo)
+	votedAt := time.Now()
+
+	mock.EXPECT().
+		TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
+		Return(nil, s.conditionCheckFailure(votedAt.Add(-time.Minute)))
+
+	repo := newRomancesRepository(mock)
+
+	_, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, votedAt, nil, nil)
+	s.Require().ErrorIs(err, romanceDomain.ErrVersionConflict)
+}
+
+func (s *RomancesRepositoryUnitTestSuite) TestChangeVoteAppendsHistoryInSameTransaction() {
+	ctrl := gomock.NewController(s.T())
+	mock := mocks.NewMockClient(ctrl)
+
+	ctx := requestid.ContextWithRequestId(context.Background(), "req-1")
+	romance := s.romanceWithVote(rvo.VoteTypeNo)
+	votedAt := time.Now()
+
+	mock.EXPECT().
+		TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+			s.Require().Len(input.TransactItems, 2)
+			s.Require().Equal(RomancesTableName, aws.ToString(input.TransactItems[0].Update.TableName))
+			s.Require().Equal(VoteHistoryTableName, aws.ToString(input.TransactItems[1].Put.TableName))
+
+			historyItem := VoteHistoryDocumentSchema{}
+			s.Require().NoError(attributevalue.UnmarshalMap(input.TransactItems[1].Put.Item, &historyItem))
+			s.Require().Equal(s.voteId.ActiveUserId().String(), historyItem.ActorId)
+			s.Require().Equal(uint8(rvo.VoteActionChange), historyItem.Action)
+			s.Require().Equal(uint8(rvo.VoteTypeNo), historyItem.FromVoteType)
+			s.Require().Equal(uint8(rvo.VoteTypeYes), historyItem.ToVoteType)
+			s.Require().Equal(votedAt.Unix(), *historyItem.VotedAt)
+			s.Require().Equal("req-1", historyItem.RequestId)
+			s.Require().Equal(uint32(2), historyItem.RomanceVersion)
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		})
+
+	repo := newRomancesRepository(mock)
+
+	newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeYes, votedAt, nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(rvo.VoteTypeYes, newRomance.ActiveUserVote.VoteType)
+	s.Require().Equal(votedAt.Unix(), newRomance.ActiveUserVote.VotedAt.Unix())
+	s.Require().Equal(uint32(2), newRomance.Version)
+}
+
+func (s *RomancesRepositoryUnitTestSuite) TestAddVoteStoresContext() {
+	ctrl := gomock.NewController(s.T())
+	mock := mocks.NewMockClient(ctrl)
+
+	ctx := context.Background()
+	voteContext := &rvo.VoteContext{
+		Surface:          rvo.VoteSurfaceProfile,
+		RecommendationId: "rec-1",
+		Position:         aws.Uint32(3),
+	}
+
+	mock.EXPECT().
+		TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+			update := input.TransactItems[0].Update
+			s.Require().Contains(aws.ToString(update.UpdateExpression), "#voteContext = :voteContext")
+
+			stored := VoteContextDocumentSchema{}
+			s.Require().NoError(attributevalue.Unmarshal(update.ExpressionAttributeValues[":voteContext"], &stored))
+			s.Require().Equal(voteContext, stored.toValueObject())
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		})
+
+	repo := newRomancesRepository(mock)
+
+	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(s.voteId), rvo.VoteTypeYes, time.Now(), voteContext, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(voteContext, newRomance.ActiveUserVote.Context)
+}
+
+func (s *RomancesRepositoryUnitTestSuite) TestChangeVoteWithoutContextRemovesPreviousContext() {
+	ctrl := gomock.NewController(s.T())
+	mock := mocks.NewMockClient(ctrl)
+
+	ctx := context.Background()
+	romance := s.romanceWithVote(rvo.VoteTypeNo)
+	romance.ActiveUserVote.Context = &rvo.VoteContext{Surface: rvo.VoteSurfaceDeck}
+
+	mock.EXPECT().
+		TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+			update := input.TransactItems[0].Update
+			s.Require().Contains(aws.ToString(update.UpdateExpression), ", #voteContext")
+			s.Require().NotContains(update.ExpressionAttributeValues, ":voteContext")
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		})
+
+	repo := newRomancesRepository(mock)
+
+	newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeYes, time.Now(), nil, nil)
+	s.Require().NoError(err)
+	s.Require().Nil(newRomance.ActiveUserVote.Context)
+}
+
+func (s *RomancesRepositoryUnitTestSuite) TestAddCrossCountryVoteStoresCountries() {
+	ctrl := gomock.NewController(s.T())
+	mock := mocks.NewMockClient(ctrl)
+
+	ctx := context.Background()
+	voteId, err := sharedValueObject.NewCrossCountryVoteId(11, s.voteId.ActiveUserId(), 22, s.voteId.PeerUserId())
+	s.Require().NoError(err)
+
+	mock.EXPECT().
+		TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+			update := input.TransactItems[0].Update
+			s.Require().Contains(aws.ToString(update.UpdateExpression), "#peerCountry = if_not_exists(#peerCountry, :peerCountry)")
+			s.Require().Equal(&types.AttributeValueMemberN{Value: "11"}, update.ExpressionAttributeValues[":activeUserCountry"])
+			s.Require().Equal(&types.AttributeValueMemberN{Value: "22"}, update.ExpressionAttributeValues[":peerCountry"])
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		})
+
+	repo := newRomancesRepository(mock)
+
+	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(voteId), rvo.VoteTypeYes, time.Now(), nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(voteId, newRomance.ActiveUserVote.Id)
+}
+
+func (s *RomancesRepositoryUnitTestSuite) TestGetCrossCountryRomanceFromBothSides() {
+	ctrl := gomock.NewController(s.T())
+	mock := mocks.NewMockClient(ctrl)
+
+	ctx := context.Background()
+	voteId, err := sharedValueObject.NewCrossCountryVoteId(11, s.voteId.ActiveUserId(), 22, s.voteId.PeerUserId())
+	s.Require().NoError(err)
+	romanceKey := NewRomancePrimaryKey(voteId)
+	item := RomanceDocumentSchema{
+		PkUserId:        romanceKey.Pk.String(),
+		SkUserId:        romanceKey.Sk.String(),
+		Version:         1,
+		PkUserCountryId: 11,
+		SkUserCountryId: 22,
+	}
+	if !romanceKey.isPartitionKey(voteId.ActiveUserId()) {
+		item.PkUserCountryId, item.SkUserCountryId = 22, 11
+	}
+	storedItem, err := attributevalue.MarshalMap(item)
+	s.Require().NoError(err)
+
+	mock.EXPECT().
+		GetItem(ctx, gomock.Any(), gomock.Any()).
+		Return(&dynamodb.GetItemOutput{Item: storedItem}, nil).
+		Times(2)
+
+	repo := newRomancesRepository(mock)
+
+	romance, err := repo.GetRomance(ctx, voteId)
+	s.Require().NoError(err)
+	s.Require().Equal(voteId, romance.ActiveUserVote.Id)
+	s.Require().Equal(voteId.ToPeerVoteId(), romance.PeerUserVote.Id)
+
+	// the peer reads the romance from the home region with the country of the active user unknown
+	peerVoteId, err := sharedValueObject.NewVoteId(22, voteId.PeerUserId(), voteId.ActiveUserId())
+	s.Require().NoError(err)
+	peerRomance, err := repo.GetRomance(ctx, peerVoteId)
+	s.Require().NoError(err)
+	s.Require().Equal(voteId.ToPeerVoteId(), peerRomance.ActiveUserVote.Id)
+	s.Require().Equal(voteId.HomeCountryId(), peerRomance.ActiveUserVote.Id.HomeCountryId())
+}
+
 // Helper methods
+func (s *RomancesRepositoryUnitTestSuite) TestAddComplimentVoteStoresCompliment() {
+	ctrl := gomock.NewController(s.T())
+	mock := mocks.NewMockClient(ctrl)
+
+	ctx := context.Background()
+	compliment := &rvo.Compliment{
+		Id:     uuidhelper.NewUUID(s.T()),
+		Text:   "Lovely smile",
+		Status: rvo.ModerationStatusPending,
+	}
+
+	mock.EXPECT().
+		TransactWriteItems(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+			update := input.TransactItems[0].Update
+			s.Require().Contains(aws.ToString(update.UpdateExpression), "#compliment = :compliment")
+
+			stored := ComplimentDocumentSchema{}
+			s.Require().NoError(attributevalue.Unmarshal(update.ExpressionAttributeValues[":compliment"], &stored))
+			storedCompliment, err := stored.toValueObject()
+			s.Require().NoError(err)
+			s.Require().Equal(compliment, storedCompliment)
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		})
+
+	repo := newRomancesRepository(mock)
+
+	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(s.voteId), rvo.VoteTypeCompliment, time.Now(), nil, compliment)
+	s.Require().NoError(err)
+	s.Require().Equal(compliment, newRomance.ActiveUserVote.Compliment)
+}
+
+func (s *RomancesRepositoryUnitTestSuite) TestGetRomancesGroupRetriesUnprocessedKeysAfterDelay() {
+	ctrl := gomock.NewController(s.T())
+	mock := mocks.NewMockClient(ctrl)
+	ctx := context.Background()
+	key := newRomancesRepository(mock).getRomancesTableKey(NewRomancePrimaryKey(s.voteId))
+	item, err := attributevalue.MarshalMap(RomanceDocumentSchema{
+		PkUserId:       key[PkUserIdAttrName].(*types.AttributeValueMemberS).Value,
+		SkUserId:       key[SkUserIdAttrName].(*types.AttributeValueMemberS).Value,
+		PkUserVoteType: uint8(rvo.VoteTypeYes),
+		SkUserVoteType: uint8(rvo.VoteTypeYes),
+		Version:        1,
+	})
+	s.Require().NoError(err)
+
+	var firstReadAt time.Time
+	gomock.InOrder(
+		mock.EXPECT().
+			BatchGetItem(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+				firstReadAt = time.Now()
+				return &dynamodb.BatchGetItemOutput{
+					UnprocessedKeys: map[string]types.KeysAndAttributes{
+						RomancesTableName: {Keys: []map[string]types.AttributeValue{key}},
+					},
+				}, nil
+			}),
+		mock.EXPECT().
+			BatchGetItem(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+				s.Require().GreaterOrEqual(time.Since(firstReadAt), unprocessedKeysBaseDelay)
+				s.Require().Equal([]map[string]types.AttributeValue{key}, input.RequestItems[RomancesTableName].Keys)
+				return &dynamodb.BatchGetItemOutput{
+					Responses: map[string][]map[string]types.AttributeValue{RomancesTableName: {item}},
+				}, nil
+			}),
+	)
+
+	romances, err := newRomancesRepository(mock).GetRomancesGroup(ctx, s.voteId.ActiveUserKey(), []uuid.UUID{s.voteId.PeerUserId()})
+
+	s.Require().NoError(err)
+	s.Require().Len(romances, 1)
+	s.Require().Equal(rvo.VoteTypeYes, romances[0].ActiveUserVote.VoteType)
+}
+
+func (s *RomancesRepositoryUnitTestSuite) TestGetRomancesGroupStopsRetryingWhenContextIsDone() {
+	ctrl := gomock.NewController(s.T())
+	mock := mocks.NewMockClient(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	key := newRomancesRepository(mock).getRomancesTableKey(NewRomancePrimaryKey(s.voteId))
+
+	mock.EXPECT().
+		BatchGetItem(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+			cancel()
+			return &dynamodb.BatchGetItemOutput{
+				UnprocessedKeys: map[string]types.KeysAndAttributes{
+					RomancesTableName: {Keys: []map[string]types.AttributeValue{key}},
+				},
+			}, nil
+		})
+
+	_, err := newRomancesRepository(mock).GetRomancesGroup(ctx, s.voteId.ActiveUserKey(), []uuid.UUID{s.voteId.PeerUserId()})
+
+	s.Require().ErrorIs(err, context.Canceled)
+}
+
+func (s *RomancesRepositoryUnitTestSuite) romanceWithVote(voteType rvo.VoteType) romanceEntity.Romance {
+	romance := romanceEntity.CreateEmptyRomance(s.voteId)
+	now := time.Now()
+	romance.ActiveUserVote.VoteType = voteType
+	romance.ActiveUserVote.VotedAt = &now
+	romance.ActiveUserVote.CreatedAt = &now
+	romance.ActiveUserVote.UpdatedAt = &now
+	romance.Version = 1
+	return romance
+}
+
+// conditionCheckFailure cancels the vote transaction on the romance update with both users voted
+// at storedVotedAt in the old item
+func (s *RomancesRepositoryUnitTestSuite) conditionCheckFailure(storedVotedAt time.Time) error {
+	stored := &types.AttributeValueMemberN{Value: strconv.FormatInt(storedVotedAt.Unix(), 10)}
+	return &types.TransactionCanceledException{
+		CancellationReasons: []types.CancellationReason{
+			{
+				Code: aws.String("ConditionalCheckFailed"),
+				Item: map[string]types.AttributeValue{
+					pkUserVotedAtAttrName: stored,
+					skUserVotedAtAttrName: stored,
+				},
+			},
+			{Code: aws.String("None")},
+		},
+	}
+}
+
 func (s *RomancesRepositoryUnitTestSuite) assertEmptyRomance(romanceToCheck romanceEntity.Romance) {
 	s.Require().Equal(romanceEntity.Romance{}, romanceToCheck)
 }
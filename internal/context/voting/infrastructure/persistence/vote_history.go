@@ -0,0 +1,212 @@
+package persistence
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	platformDynamoDb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/requestid"
+	"github.com/google/uuid"
+)
+
+const (
+	VoteHistoryTableName       = "RomanceVoteHistory"
+	VoteHistoryRomanceAttrName = "k"
+	VoteHistorySortAttrName    = "s"
+)
+
+// VoteHistoryDocumentSchema is one history entry, entries of a romance share the partition key of
+// both users and sort by the write time and the romance version it produced.
+type VoteHistoryDocumentSchema struct {
+	RomanceKey     string `dynamodbav:"k"`
+	SortKey        string `dynamodbav:"s"`
+	ActorId        string `dynamodbav:"a"`
+	Action         uint8  `dynamodbav:"o"`
+	FromVoteType   uint8  `dynamodbav:"f"`
+	ToVoteType     uint8  `dynamodbav:"t"`
+	VotedAt        *int64 `dynamodbav:"va"`
+	RecordedAt     int64  `dynamodbav:"r"`
+	RequestId      string `dynamodbav:"q,omitempty"`
+	RomanceVersion uint32 `dynamodbav:"v"`
+	Ttl            int64  `dynamodbav:"ttl"`
+}
+
+// writeVote applies the romance update and appends its history entry in one transaction, a failed
+// update condition is reported as ConditionalCheckFailedException like for a single UpdateItem.
+func (r *RomancesRepository) writeVote(
+	ctx context.Context,
+	countryId uint16,
+	update *types.Update,
+	historyEntry entity.VoteHistoryEntry,
+	romanceKey RomancePrimaryKey,
+) error {
+	historyItem, err := r.newVoteHistoryItem(romanceKey, historyEntry)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.dynamoDbClient.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Update: update},
+			{Put: &types.Put{
+				TableName: aws.String(VoteHistoryTableName),
+				Item:      historyItem,
+			}},
+		},
+	}, func(o *dynamodb.Options) {
+		o.Region = platformDynamoDb.GetDynamodbRegionByCountry(countryId)
+	})
+
+	var canceledErr *types.TransactionCanceledException
+	if errors.As(err, &canceledErr) && len(canceledErr.CancellationReasons) > 0 {
+		reason := canceledErr.CancellationReasons[0]
+		if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+			return &types.ConditionalCheckFailedException{Message: reason.Message, Item: reason.Item}
+		}
+	}
+	return err
+}
+
+func (r *RomancesRepository) newVoteHistoryEntry(
+	ctx context.Context,
+	romance entity.Romance,
+	action valueobject.VoteAction,
+	toVoteType valueobject.VoteType,
+	votedAt *time.Time,
+	now time.Time,
+) entity.VoteHistoryEntry {
+	return entity.VoteHistoryEntry{
+		ActorId:        romance.ActiveUserVote.Id.ActiveUserId(),
+		Action:         action,
+		FromVoteType:   romance.ActiveUserVote.VoteType,
+		ToVoteType:     toVoteType,
+		VotedAt:        votedAt,
+		RecordedAt:     now,
+		RequestId:      requestid.FromContext(ctx),
+		RomanceVersion: romance.Version + 1,
+	}
+}
+
+func (r *RomancesRepository) newVoteHistoryItem(
+	romanceKey RomancePrimaryKey,
+	historyEntry entity.VoteHistoryEntry,
+) (map[string]types.AttributeValue, error) {
+	item := VoteHistoryDocumentSchema{
+		RomanceKey:     voteHistoryRomanceKey(romanceKey),
+		SortKey:        fmt.Sprintf("%013d#%010d", historyEntry.RecordedAt.UnixMilli(), historyEntry.RomanceVersion),
+		ActorId:        historyEntry.ActorId.String(),
+		Action:         uint8(historyEntry.Action),
+		FromVoteType:   uint8(historyEntry.FromVoteType),
+		ToVoteType:     uint8(historyEntry.ToVoteType),
+		VotedAt:        unixOrNil(historyEntry.VotedAt),
+		RecordedAt:     historyEntry.RecordedAt.UnixMilli(),
+		RequestId:      historyEntry.RequestId,
+		RomanceVersion: historyEntry.RomanceVersion,
+		Ttl:            historyEntry.RecordedAt.Add(r.config.Voting.HistoryRetention).Unix(),
+	}
+	return attributevalue.MarshalMap(item)
+}
+
+func (r *RomancesRepository) GetVoteHistory(
+	ctx context.Context,
+	voteId sharedValueObject.VoteId,
+	limit int32,
+	cursor string,
+) (entity.VoteHistoryPage, error) {
+	romanceKey := voteHistoryRomanceKey(NewRomancePrimaryKey(voteId))
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(VoteHistoryTableName),
+		KeyConditionExpression: aws.String("#romanceKey = :romanceKey"),
+		ExpressionAttributeNames: map[string]string{
+			"#romanceKey": VoteHistoryRomanceAttrName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":romanceKey": &types.AttributeValueMemberS{Value: romanceKey},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(limit),
+	}
+	if cursor != "" {
+		sortKey, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(sortKey) == 0 {
+			return entity.VoteHistoryPage{}, romanceDomain.ErrInvalidHistoryCursor
+		}
+		input.ExclusiveStartKey = map[string]types.AttributeValue{
+			VoteHistoryRomanceAttrName: &types.AttributeValueMemberS{Value: romanceKey},
+			VoteHistorySortAttrName:    &types.AttributeValueMemberS{Value: string(sortKey)},
+		}
+	}
+
+	// the history is written next to the romance, only the region holding it returns entries
+	var out *dynamodb.QueryOutput
+	for _, region := range romanceRegions(voteId) {
+		var err error
+		out, err = r.dynamoDbClient.Query(ctx, input, func(o *dynamodb.Options) {
+			o.Region = region
+		})
+		if err != nil {
+			return entity.VoteHistoryPage{}, err
+		}
+		if len(out.Items) > 0 {
+			break
+		}
+	}
+
+	var items []VoteHistoryDocumentSchema
+	if err := attributevalue.UnmarshalListOfMaps(out.Items, &items); err != nil {
+		return entity.VoteHistoryPage{}, err
+	}
+
+	page := entity.VoteHistoryPage{Entries: make([]entity.VoteHistoryEntry, 0, len(items))}
+	for _, item := range items {
+		actorId, err := uuid.Parse(item.ActorId)
+		if err != nil {
+			return entity.VoteHistoryPage{}, err
+		}
+		page.Entries = append(page.Entries, entity.VoteHistoryEntry{
+			ActorId:        actorId,
+			Action:         valueobject.VoteAction(item.Action),
+			FromVoteType:   valueobject.VoteType(item.FromVoteType),
+			ToVoteType:     valueobject.VoteType(item.ToVoteType),
+			VotedAt:        timeOrNil(item.VotedAt),
+			RecordedAt:     time.UnixMilli(item.RecordedAt).UTC(),
+			RequestId:      item.RequestId,
+			RomanceVersion: item.RomanceVersion,
+		})
+	}
+
+	if lastKey, ok := out.LastEvaluatedKey[VoteHistorySortAttrName].(*types.AttributeValueMemberS); ok {
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(lastKey.Value))
+	}
+
+	return page, nil
+}
+
+func voteHistoryRomanceKey(romanceKey RomancePrimaryKey) string {
+	return romanceKey.Pk.String() + "#" + romanceKey.Sk.String()
+}
+
+// unixTimePtr keeps the second precision the romance times are stored with
+func unixTimePtr(t time.Time) *time.Time {
+	unix := time.Unix(t.Unix(), 0).UTC()
+	return &unix
+}
+
+func optionalUnixTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return unixTimePtr(*t)
+}
@@ -0,0 +1,129 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/message"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"github.com/google/uuid"
+)
+
+const (
+	RomanceEventsTopic = messaging.Topic("romance-events.fifo")
+	ttlPrincipalId     = "dynamodb.amazonaws.com"
+	ttlIdentityType    = "Service"
+)
+
+type RomancesStreamHandler struct {
+	publisher messaging.Publisher
+	logger    platform.Logger
+}
+
+func NewRomancesStreamHandler(
+	publisher messaging.Publisher,
+	logger platform.Logger,
+) *RomancesStreamHandler {
+	return &RomancesStreamHandler{
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// Handle publishes a message for every removed romance and skips other records.
+// A record which can not be parsed is logged and skipped, so it never blocks the shard.
+func (h *RomancesStreamHandler) Handle(ctx context.Context, record types.Record) error {
+	if record.EventName != types.OperationTypeRemove {
+		return nil
+	}
+
+	removal, err := newRomanceRemoval(record)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("Skipping malformed Romances stream record `%s`: %v", aws.ToString(record.EventID), err))
+		return nil
+	}
+
+	var m messaging.Message
+	if isTtlExpiry(record) {
+		m = message.NewRomanceExpiredMessage(removal)
+	} else {
+		m = message.NewRomanceDeletedMessage(removal)
+	}
+
+	return h.publisher.Publish(RomanceEventsTopic, m)
+}
+
+// isTtlExpiry relies on DynamoDB marking items deleted by TTL with its service principal.
+func isTtlExpiry(record types.Record) bool {
+	return record.UserIdentity != nil &&
+		aws.ToString(record.UserIdentity.PrincipalId) == ttlPrincipalId &&
+		aws.ToString(record.UserIdentity.Type) == ttlIdentityType
+}
+
+func newRomanceRemoval(record types.Record) (message.RomanceRemoval, error) {
+	if record.Dynamodb == nil {
+		return message.RomanceRemoval{}, errors.New("record has no data")
+	}
+
+	// The old image is missing when the stream is configured with keys only
+	image := record.Dynamodb.OldImage
+	if len(image) == 0 {
+		image = record.Dynamodb.Keys
+	}
+
+	item, err := attributevalue.FromDynamoDBStreamsMap(image)
+	if err != nil {
+		return message.RomanceRemoval{}, err
+	}
+
+	romanceItem := &persistence.RomanceDocumentSchema{}
+	if err = attributevalue.UnmarshalMap(item, romanceItem); err != nil {
+		return message.RomanceRemoval{}, err
+	}
+
+	minUserId, err := uuid.Parse(romanceItem.PkUserId)
+	if err != nil {
+		return message.RomanceRemoval{}, fmt.Errorf("invalid partition key %q: %w", romanceItem.PkUserId, err)
+	}
+	maxUserId, err := uuid.Parse(romanceItem.SkUserId)
+	if err != nil {
+		return message.RomanceRemoval{}, fmt.Errorf("invalid sort key %q: %w", romanceItem.SkUserId, err)
+	}
+
+	removedAt := time.Now().UTC()
+	if record.Dynamodb.ApproximateCreationDateTime != nil {
+		removedAt = record.Dynamodb.ApproximateCreationDateTime.UTC()
+	}
+
+	return message.RomanceRemoval{
+		StreamEventId:      aws.ToString(record.EventID),
+		MinUserId:          minUserId,
+		MaxUserId:          maxUserId,
+		MinUserVoteType:    romanceItem.PkUserVoteType,
+		MaxUserVoteType:    romanceItem.SkUserVoteType,
+		Version:            romanceItem.Version,
+		RemovedAt:          removedAt,
+		MinUserVoteContext: newVoteContextMessage(romanceItem.PkUserVoteContext),
+		MaxUserVoteContext: newVoteContextMessage(romanceItem.SkUserVoteContext),
+	}, nil
+}
+
+func newVoteContextMessage(voteContext *persistence.VoteContextDocumentSchema) *message.VoteContext {
+	if voteContext == nil {
+		return nil
+	}
+	return &message.VoteContext{
+		Surface:          valueobject.VoteSurface(voteContext.Surface).String(),
+		RecommendationId: voteContext.RecommendationId,
+		Position:         voteContext.Position,
+		ExperimentBucket: voteContext.ExperimentBucket,
+	}
+}
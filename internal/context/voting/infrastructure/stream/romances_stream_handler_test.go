@@ -0,0 +1,190 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/messaging/message"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type RomancesStreamHandlerUnitTestSuite struct {
+	suite.Suite
+	publisher *mocks.MockPublisher
+	logger    *mocks.MockLogger
+	handler   *RomancesStreamHandler
+	minUserId uuid.UUID
+	maxUserId uuid.UUID
+	removedAt time.Time
+}
+
+func TestRomancesStreamHandlerUnitSuite(t *testing.T) {
+	suite.Run(t, new(RomancesStreamHandlerUnitTestSuite))
+}
+
+func (s *RomancesStreamHandlerUnitTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.publisher = mocks.NewMockPublisher(ctrl)
+	s.logger = mocks.NewMockLogger(ctrl)
+	s.handler = NewRomancesStreamHandler(s.publisher, s.logger)
+
+	s.minUserId = uuidhelper.NewUUID(s.T())
+	s.maxUserId = uuidhelper.NewUUID(s.T())
+	s.removedAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+}
+
+func (s *RomancesStreamHandlerUnitTestSuite) TestTtlRemovalPublishesRomanceExpired() {
+	record := s.newRemoveRecord()
+	record.UserIdentity = &types.Identity{
+		PrincipalId: aws.String("dynamodb.amazonaws.com"),
+		Type:        aws.String("Service"),
+	}
+
+	s.publisher.EXPECT().
+		Publish(RomanceEventsTopic, gomock.Any()).
+		DoAndReturn(func(_ messaging.Topic, m messaging.Message) error {
+			expired, ok := m.(*message.RomanceExpiredMessage)
+			s.Require().True(ok)
+			s.Require().Equal(s.expectedRemoval(), expired.RomanceRemoval)
+			s.Require().Equal("event-1", expired.GetDeduplicationId())
+			return nil
+		})
+
+	s.Require().NoError(s.handler.Handle(context.Background(), record))
+}
+
+func (s *RomancesStreamHandlerUnitTestSuite) TestUserRemovalPublishesRomanceDeleted() {
+	record := s.newRemoveRecord()
+
+	s.publisher.EXPECT().
+		Publish(RomanceEventsTopic, gomock.Any()).
+		DoAndReturn(func(_ messaging.Topic, m messaging.Message) error {
+			deleted, ok := m.(*message.RomanceDeletedMessage)
+			s.Require().True(ok)
+			s.Require().Equal(s.expectedRemoval(), deleted.RomanceRemoval)
+			return nil
+		})
+
+	s.Require().NoError(s.handler.Handle(context.Background(), record))
+}
+
+func (s *RomancesStreamHandlerUnitTestSuite) TestRemovalWithKeysOnly() {
+	record := s.newRemoveRecord()
+	record.Dynamodb.OldImage = nil
+
+	s.publisher.EXPECT().
+		Publish(RomanceEventsTopic, gomock.Any()).
+		DoAndReturn(func(_ messaging.Topic, m messaging.Message) error {
+			deleted := m.(*message.RomanceDeletedMessage)
+			s.Require().Equal(s.minUserId, deleted.MinUserId)
+			s.Require().Equal(s.maxUserId, deleted.MaxUserId)
+			s.Require().Zero(deleted.Version)
+			return nil
+		})
+
+	s.Require().NoError(s.handler.Handle(context.Background(), record))
+}
+
+func (s *RomancesStreamHandlerUnitTestSuite) TestRemovalCarriesVoteContext() {
+	record := s.newRemoveRecord()
+	record.Dynamodb.OldImage["c"] = &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		"s": &types.AttributeValueMemberN{Value: "1"},
+		"r": &types.AttributeValueMemberS{Value: "rec-1"},
+		"p": &types.AttributeValueMemberN{Value: "7"},
+		"b": &types.AttributeValueMemberS{Value: "bucket-a"},
+	}}
+
+	s.publisher.EXPECT().
+		Publish(RomanceEventsTopic, gomock.Any()).
+		DoAndReturn(func(_ messaging.Topic, m messaging.Message) error {
+			deleted := m.(*message.RomanceDeletedMessage)
+			s.Require().Equal(&message.VoteContext{
+				Surface:          "deck",
+				RecommendationId: "rec-1",
+				Position:         aws.Uint32(7),
+				ExperimentBucket: "bucket-a",
+			}, deleted.MinUserVoteContext)
+			s.Require().Nil(deleted.MaxUserVoteContext)
+			return nil
+		})
+
+	s.Require().NoError(s.handler.Handle(context.Background(), record))
+}
+
+func (s *RomancesStreamHandlerUnitTestSuite) TestIgnoresNonRemoveRecords() {
+	record := s.newRemoveRecord()
+	record.EventName = types.OperationTypeModify
+
+	s.Require().NoError(s.handler.Handle(context.Background(), record))
+}
+
+func (s *RomancesStreamHandlerUnitTestSuite) TestSkipsMalformedRecord() {
+	record := s.newRemoveRecord()
+	record.Dynamodb.OldImage[persistence.PkUserIdAttrName] = &types.AttributeValueMemberS{Value: "not-uuid"}
+
+	s.logger.EXPECT().Error(gomock.Any())
+
+	s.Require().NoError(s.handler.Handle(context.Background(), record))
+}
+
+func (s *RomancesStreamHandlerUnitTestSuite) TestReturnsPublishError() {
+	expectedErr := errors.New("sns unavailable")
+
+	s.publisher.EXPECT().
+		Publish(RomanceEventsTopic, gomock.Any()).
+		Return(expectedErr)
+
+	err := s.handler.Handle(context.Background(), s.newRemoveRecord())
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *RomancesStreamHandlerUnitTestSuite) newRemoveRecord() types.Record {
+	keys := map[string]types.AttributeValue{
+		persistence.PkUserIdAttrName: &types.AttributeValueMemberS{Value: s.minUserId.String()},
+		persistence.SkUserIdAttrName: &types.AttributeValueMemberS{Value: s.maxUserId.String()},
+	}
+
+	oldImage := map[string]types.AttributeValue{
+		"e": &types.AttributeValueMemberN{Value: "2"},
+		"l": &types.AttributeValueMemberN{Value: "1"},
+		"v": &types.AttributeValueMemberN{Value: "3"},
+		"g": &types.AttributeValueMemberN{Value: strconv.FormatInt(s.removedAt.Unix(), 10)},
+	}
+	for name, value := range keys {
+		oldImage[name] = value
+	}
+
+	return types.Record{
+		EventID:   aws.String("event-1"),
+		EventName: types.OperationTypeRemove,
+		Dynamodb: &types.StreamRecord{
+			ApproximateCreationDateTime: aws.Time(s.removedAt),
+			Keys:                        keys,
+			OldImage:                    oldImage,
+			SequenceNumber:              aws.String("100"),
+		},
+	}
+}
+
+func (s *RomancesStreamHandlerUnitTestSuite) expectedRemoval() message.RomanceRemoval {
+	return message.RomanceRemoval{
+		StreamEventId:   "event-1",
+		MinUserId:       s.minUserId,
+		MaxUserId:       s.maxUserId,
+		MinUserVoteType: 2,
+		MaxUserVoteType: 1,
+		Version:         3,
+		RemovedAt:       s.removedAt,
+	}
+}
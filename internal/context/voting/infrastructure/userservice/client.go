@@ -0,0 +1,64 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+)
+
+const (
+	statusPathFormat       = "/v1/users/%d/%s/status"
+	entitlementsPathFormat = "/v1/users/%d/%s/entitlements"
+	timeZonePathFormat     = "/v1/users/%d/%s/time-zone"
+)
+
+// Client calls the user service, every request is bounded by the configured timeout
+type Client struct {
+	httpClient *http.Client
+	baseUrl    string
+}
+
+func NewClient(cfg config.Config) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: cfg.UserService.Timeout},
+		baseUrl:    strings.TrimRight(cfg.UserService.Url, "/"),
+	}
+}
+
+// getUserResource decodes the resource of the user into out, false is returned when the user is unknown
+func (c *Client) getUserResource(
+	ctx context.Context,
+	pathFormat string,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	out any,
+) (bool, error) {
+	url := c.baseUrl + fmt.Sprintf(pathFormat, activeUserKey.CountryId(), activeUserKey.ActiveUserId())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("user service responded with %d", resp.StatusCode)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("decode user service response: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,81 @@
+package userservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	userDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/ttlcache"
+)
+
+type entitlementsResponse struct {
+	VoteTypes []string `json:"vote_types"`
+}
+
+type entitlements map[romancesValueObject.VoteType]struct{}
+
+// HttpEntitlementProvider resolves the vote types a user is entitled to with the user service,
+// users unknown to the service are not entitled to any vote type.
+type HttpEntitlementProvider struct {
+	client   *Client
+	cache    *ttlcache.Cache[sharedValueObject.ActiveUserKey, entitlements]
+	failOpen bool
+	logger   platform.Logger
+}
+
+func NewHttpEntitlementProvider(client *Client, cfg config.Config, logger platform.Logger) *HttpEntitlementProvider {
+	return &HttpEntitlementProvider{
+		client:   client,
+		cache:    ttlcache.New[sharedValueObject.ActiveUserKey, entitlements](cfg.UserService.CacheTtl),
+		failOpen: cfg.UserService.EntitlementFailOpen,
+		logger:   logger,
+	}
+}
+
+func (p *HttpEntitlementProvider) IsEntitled(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	voteType romancesValueObject.VoteType,
+) (bool, error) {
+	userEntitlements, ok := p.cache.Get(activeUserKey, time.Now())
+	if !ok {
+		var err error
+		userEntitlements, err = p.fetchEntitlements(ctx, activeUserKey)
+		if err != nil {
+			p.logger.Error(fmt.Sprintf("IsEntitled error: %+v", err))
+			if p.failOpen {
+				return true, nil
+			}
+			return false, fmt.Errorf("%w: %v", userDomain.ErrUserCheckUnavailable, err)
+		}
+		p.cache.Set(activeUserKey, userEntitlements, time.Now())
+	}
+
+	_, entitled := userEntitlements[voteType]
+	return entitled, nil
+}
+
+func (p *HttpEntitlementProvider) fetchEntitlements(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+) (entitlements, error) {
+	var body entitlementsResponse
+	found, err := p.client.getUserResource(ctx, entitlementsPathFormat, activeUserKey, &body)
+	if err != nil || !found {
+		return entitlements{}, err
+	}
+
+	// vote types this service does not know yet are skipped rather than failing the check
+	userEntitlements := make(entitlements, len(body.VoteTypes))
+	for _, name := range body.VoteTypes {
+		if voteType, ok := romancesValueObject.VoteTypeFromString(name); ok {
+			userEntitlements[voteType] = struct{}{}
+		}
+	}
+	return userEntitlements, nil
+}
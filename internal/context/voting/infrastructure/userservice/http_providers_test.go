@@ -0,0 +1,194 @@
+package userservice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	userDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/valueobject"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpUserStatusProvider(t *testing.T) {
+	bannedKey := newActiveUserKey(t)
+	unknownKey := newActiveUserKey(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != fmt.Sprintf(statusPathFormat, bannedKey.CountryId(), bannedKey.ActiveUserId()) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, `{"status":"banned"}`)
+	}))
+	defer server.Close()
+
+	cfg := newUserServiceConfig(server.URL)
+	provider := NewHttpUserStatusProvider(NewClient(cfg), cfg, discardLogger())
+
+	status, err := provider.GetStatus(context.Background(), bannedKey)
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.UserStatusBanned, status)
+
+	status, err = provider.GetStatus(context.Background(), unknownKey)
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.UserStatusDeleted, status)
+
+	_, err = provider.GetStatus(context.Background(), bannedKey)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load(), "cached statuses are not requested again")
+}
+
+func TestHttpProvidersFailurePolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	key := newActiveUserKey(t)
+	cfg := newUserServiceConfig(server.URL)
+	cfg.UserService.Timeout = 10 * time.Millisecond
+
+	cfg.UserService.StatusFailOpen = true
+	cfg.UserService.EntitlementFailOpen = true
+	statusProvider := NewHttpUserStatusProvider(NewClient(cfg), cfg, discardLogger())
+	entitlementProvider := NewHttpEntitlementProvider(NewClient(cfg), cfg, discardLogger())
+
+	status, err := statusProvider.GetStatus(context.Background(), key)
+	require.NoError(t, err)
+	assert.Equal(t, valueobject.UserStatusActive, status)
+	entitled, err := entitlementProvider.IsEntitled(context.Background(), key, romancesValueObject.VoteTypeCrush)
+	require.NoError(t, err)
+	assert.True(t, entitled)
+
+	cfg.UserService.StatusFailOpen = false
+	cfg.UserService.EntitlementFailOpen = false
+	statusProvider = NewHttpUserStatusProvider(NewClient(cfg), cfg, discardLogger())
+	entitlementProvider = NewHttpEntitlementProvider(NewClient(cfg), cfg, discardLogger())
+
+	_, err = statusProvider.GetStatus(context.Background(), key)
+	assert.ErrorIs(t, err, userDomain.ErrUserCheckUnavailable)
+	_, err = entitlementProvider.IsEntitled(context.Background(), key, romancesValueObject.VoteTypeCrush)
+	assert.ErrorIs(t, err, userDomain.ErrUserCheckUnavailable)
+}
+
+func TestHttpEntitlementProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"vote_types":["compliment","superswipe"]}`)
+	}))
+	defer server.Close()
+
+	key := newActiveUserKey(t)
+	cfg := newUserServiceConfig(server.URL)
+	provider := NewHttpEntitlementProvider(NewClient(cfg), cfg, discardLogger())
+
+	entitled, err := provider.IsEntitled(context.Background(), key, romancesValueObject.VoteTypeCompliment)
+	require.NoError(t, err)
+	assert.True(t, entitled)
+
+	entitled, err = provider.IsEntitled(context.Background(), key, romancesValueObject.VoteTypeCrush)
+	require.NoError(t, err)
+	assert.False(t, entitled)
+}
+
+func TestHttpTimeZoneProvider(t *testing.T) {
+	kolkataKey := newActiveUserKey(t)
+	invalidKey := newActiveUserKey(t)
+	unknownKey := newActiveUserKey(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case fmt.Sprintf(timeZonePathFormat, kolkataKey.CountryId(), kolkataKey.ActiveUserId()):
+			_, _ = io.WriteString(w, `{"time_zone":"Asia/Kolkata"}`)
+		case fmt.Sprintf(timeZonePathFormat, invalidKey.CountryId(), invalidKey.ActiveUserId()):
+			_, _ = io.WriteString(w, `{"time_zone":"Mars/Olympus_Mons"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := newUserServiceConfig(server.URL)
+	provider := NewHttpTimeZoneProvider(NewClient(cfg), cfg, discardLogger())
+
+	location, err := provider.GetTimeZone(context.Background(), kolkataKey)
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Kolkata", location.String())
+
+	location, err = provider.GetTimeZone(context.Background(), invalidKey)
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, location)
+
+	location, err = provider.GetTimeZone(context.Background(), unknownKey)
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, location)
+
+	_, err = provider.GetTimeZone(context.Background(), kolkataKey)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load(), "cached time zones are not requested again")
+
+	server.Close()
+	_, err = provider.GetTimeZone(context.Background(), newActiveUserKey(t))
+	assert.ErrorIs(t, err, userDomain.ErrUserCheckUnavailable)
+}
+
+func TestStaticProviders(t *testing.T) {
+	key := newActiveUserKey(t)
+	statusProvider := NewStaticUserStatusProvider()
+	entitlementProvider := NewStaticEntitlementProvider()
+	timeZoneProvider := NewStaticTimeZoneProvider()
+
+	status, _ := statusProvider.GetStatus(context.Background(), key)
+	assert.Equal(t, valueobject.UserStatusActive, status)
+	entitled, _ := entitlementProvider.IsEntitled(context.Background(), key, romancesValueObject.VoteTypeCrush)
+	assert.True(t, entitled)
+	location, _ := timeZoneProvider.GetTimeZone(context.Background(), key)
+	assert.Equal(t, time.UTC, location)
+
+	statusProvider.SetStatus(key.ActiveUserId(), valueobject.UserStatusDeleted)
+	entitlementProvider.SetEntitlements(key.ActiveUserId(), romancesValueObject.VoteTypeCompliment)
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	timeZoneProvider.SetTimeZone(key.ActiveUserId(), kolkata)
+
+	status, _ = statusProvider.GetStatus(context.Background(), key)
+	assert.Equal(t, valueobject.UserStatusDeleted, status)
+	entitled, _ = entitlementProvider.IsEntitled(context.Background(), key, romancesValueObject.VoteTypeCrush)
+	assert.False(t, entitled)
+	location, _ = timeZoneProvider.GetTimeZone(context.Background(), key)
+	assert.Equal(t, kolkata, location)
+}
+
+func newActiveUserKey(t *testing.T) sharedValueObject.ActiveUserKey {
+	key, err := sharedValueObject.NewActiveUserKey(11, uuid.New())
+	require.NoError(t, err)
+	return key
+}
+
+func newUserServiceConfig(url string) config.Config {
+	return config.Config{
+		UserService: config.UserServiceConfig{
+			Url:      url,
+			Timeout:  time.Second,
+			CacheTtl: time.Minute,
+		},
+	}
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
@@ -0,0 +1,61 @@
+package userservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	userDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/ttlcache"
+)
+
+type timeZoneResponse struct {
+	TimeZone string `json:"time_zone"`
+}
+
+// HttpTimeZoneProvider resolves the profile time zone with the user service, unknown users and
+// profiles without a valid time zone fall back to UTC.
+type HttpTimeZoneProvider struct {
+	client *Client
+	cache  *ttlcache.Cache[sharedValueObject.ActiveUserKey, *time.Location]
+	logger platform.Logger
+}
+
+func NewHttpTimeZoneProvider(client *Client, cfg config.Config, logger platform.Logger) *HttpTimeZoneProvider {
+	return &HttpTimeZoneProvider{
+		client: client,
+		cache:  ttlcache.New[sharedValueObject.ActiveUserKey, *time.Location](cfg.UserService.CacheTtl),
+		logger: logger,
+	}
+}
+
+func (p *HttpTimeZoneProvider) GetTimeZone(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+) (*time.Location, error) {
+	if location, ok := p.cache.Get(activeUserKey, time.Now()); ok {
+		return location, nil
+	}
+
+	var body timeZoneResponse
+	found, err := p.client.getUserResource(ctx, timeZonePathFormat, activeUserKey, &body)
+	if err != nil {
+		p.logger.Error(fmt.Sprintf("GetTimeZone error: %+v", err))
+		return nil, fmt.Errorf("%w: %v", userDomain.ErrUserCheckUnavailable, err)
+	}
+
+	location := time.UTC
+	if found {
+		if location, err = quotaDomain.LoadTimeZone(body.TimeZone); err != nil {
+			p.logger.Error(fmt.Sprintf("GetTimeZone error: %+v", err))
+			location = time.UTC
+		}
+	}
+
+	p.cache.Set(activeUserKey, location, time.Now())
+	return location, nil
+}
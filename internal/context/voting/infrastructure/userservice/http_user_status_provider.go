@@ -0,0 +1,77 @@
+package userservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	userDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/ttlcache"
+)
+
+type statusResponse struct {
+	Status string `json:"status"`
+}
+
+// HttpUserStatusProvider resolves the user status with the user service, users unknown
+// to the service are treated as deleted.
+type HttpUserStatusProvider struct {
+	client   *Client
+	cache    *ttlcache.Cache[sharedValueObject.ActiveUserKey, valueobject.UserStatus]
+	failOpen bool
+	logger   platform.Logger
+}
+
+func NewHttpUserStatusProvider(client *Client, cfg config.Config, logger platform.Logger) *HttpUserStatusProvider {
+	return &HttpUserStatusProvider{
+		client:   client,
+		cache:    ttlcache.New[sharedValueObject.ActiveUserKey, valueobject.UserStatus](cfg.UserService.CacheTtl),
+		failOpen: cfg.UserService.StatusFailOpen,
+		logger:   logger,
+	}
+}
+
+func (p *HttpUserStatusProvider) GetStatus(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+) (valueobject.UserStatus, error) {
+	if status, ok := p.cache.Get(activeUserKey, time.Now()); ok {
+		return status, nil
+	}
+
+	status, err := p.fetchStatus(ctx, activeUserKey)
+	if err != nil {
+		p.logger.Error(fmt.Sprintf("GetStatus error: %+v", err))
+		if p.failOpen {
+			return valueobject.UserStatusActive, nil
+		}
+		return valueobject.UserStatusActive, fmt.Errorf("%w: %v", userDomain.ErrUserCheckUnavailable, err)
+	}
+
+	p.cache.Set(activeUserKey, status, time.Now())
+	return status, nil
+}
+
+func (p *HttpUserStatusProvider) fetchStatus(
+	ctx context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+) (valueobject.UserStatus, error) {
+	var body statusResponse
+	found, err := p.client.getUserResource(ctx, statusPathFormat, activeUserKey, &body)
+	if err != nil {
+		return valueobject.UserStatusActive, err
+	}
+	if !found {
+		return valueobject.UserStatusDeleted, nil
+	}
+
+	status, ok := valueobject.UserStatusFromString(body.Status)
+	if !ok {
+		return valueobject.UserStatusActive, fmt.Errorf("unknown user status %q", body.Status)
+	}
+	return status, nil
+}
@@ -0,0 +1,31 @@
+package userservice
+
+import (
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+)
+
+// NewUserStatusProvider uses the user service when it is configured and the static provider otherwise
+func NewUserStatusProvider(cfg config.Config, logger platform.Logger) provider.UserStatusProvider {
+	if cfg.UserService.Url == "" {
+		return NewStaticUserStatusProvider()
+	}
+	return NewHttpUserStatusProvider(NewClient(cfg), cfg, logger)
+}
+
+// NewEntitlementProvider uses the user service when it is configured and the static provider otherwise
+func NewEntitlementProvider(cfg config.Config, logger platform.Logger) provider.EntitlementProvider {
+	if cfg.UserService.Url == "" {
+		return NewStaticEntitlementProvider()
+	}
+	return NewHttpEntitlementProvider(NewClient(cfg), cfg, logger)
+}
+
+// NewTimeZoneProvider uses the user service when it is configured and the static provider otherwise
+func NewTimeZoneProvider(cfg config.Config, logger platform.Logger) provider.TimeZoneProvider {
+	if cfg.UserService.Url == "" {
+		return NewStaticTimeZoneProvider()
+	}
+	return NewHttpTimeZoneProvider(NewClient(cfg), cfg, logger)
+}
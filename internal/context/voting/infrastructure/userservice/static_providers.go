@@ -0,0 +1,112 @@
+package userservice
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/valueobject"
+	"github.com/google/uuid"
+)
+
+// StaticUserStatusProvider keeps user statuses in memory for local development and tests,
+// users without a status are active.
+type StaticUserStatusProvider struct {
+	mu       sync.RWMutex
+	statuses map[uuid.UUID]valueobject.UserStatus
+}
+
+func NewStaticUserStatusProvider() *StaticUserStatusProvider {
+	return &StaticUserStatusProvider{
+		statuses: map[uuid.UUID]valueobject.UserStatus{},
+	}
+}
+
+func (p *StaticUserStatusProvider) SetStatus(userId uuid.UUID, status valueobject.UserStatus) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.statuses[userId] = status
+}
+
+func (p *StaticUserStatusProvider) GetStatus(
+	_ context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+) (valueobject.UserStatus, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.statuses[activeUserKey.ActiveUserId()], nil
+}
+
+// StaticEntitlementProvider keeps entitlements in memory for local development and tests,
+// users without entitlements set are entitled to every vote type.
+type StaticEntitlementProvider struct {
+	mu           sync.RWMutex
+	entitlements map[uuid.UUID]entitlements
+}
+
+func NewStaticEntitlementProvider() *StaticEntitlementProvider {
+	return &StaticEntitlementProvider{
+		entitlements: map[uuid.UUID]entitlements{},
+	}
+}
+
+// SetEntitlements limits the user to voteTypes, no vote types revokes all entitlements
+func (p *StaticEntitlementProvider) SetEntitlements(userId uuid.UUID, voteTypes ...romancesValueObject.VoteType) {
+	userEntitlements := make(entitlements, len(voteTypes))
+	for _, voteType := range voteTypes {
+		userEntitlements[voteType] = struct{}{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entitlements[userId] = userEntitlements
+}
+
+func (p *StaticEntitlementProvider) IsEntitled(
+	_ context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+	voteType romancesValueObject.VoteType,
+) (bool, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	userEntitlements, ok := p.entitlements[activeUserKey.ActiveUserId()]
+	if !ok {
+		return true, nil
+	}
+	_, entitled := userEntitlements[voteType]
+	return entitled, nil
+}
+
+// StaticTimeZoneProvider keeps profile time zones in memory for local development and tests,
+// users without a time zone are in UTC.
+type StaticTimeZoneProvider struct {
+	mu        sync.RWMutex
+	locations map[uuid.UUID]*time.Location
+}
+
+func NewStaticTimeZoneProvider() *StaticTimeZoneProvider {
+	return &StaticTimeZoneProvider{
+		locations: map[uuid.UUID]*time.Location{},
+	}
+}
+
+func (p *StaticTimeZoneProvider) SetTimeZone(userId uuid.UUID, location *time.Location) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.locations[userId] = location
+}
+
+func (p *StaticTimeZoneProvider) GetTimeZone(
+	_ context.Context,
+	activeUserKey sharedValueObject.ActiveUserKey,
+) (*time.Location, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if location, ok := p.locations[activeUserKey.ActiveUserId()]; ok {
+		return location, nil
+	}
+	return time.UTC, nil
+}
@@ -0,0 +1,17 @@
+package command
+
+import "github.com/google/uuid"
+
+type BlockPeer struct {
+	CountryId     uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId  uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	PeerId        uuid.UUID `path:"peer_id" format:"uuid" doc:"Blocked peer user ID"`
+	PeerCountryId uint16    `query:"peer_country_id" required:"false" doc:"Peer user country ID, defaults to the active user country unless the deployment requires it"`
+}
+
+type UnblockPeer struct {
+	CountryId     uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId  uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	PeerId        uuid.UUID `path:"peer_id" format:"uuid" doc:"Blocked peer user ID"`
+	PeerCountryId uint16    `query:"peer_country_id" required:"false" doc:"Peer user country ID, defaults to the active user country unless the deployment requires it"`
+}
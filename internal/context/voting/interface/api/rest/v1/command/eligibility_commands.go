@@ -0,0 +1,11 @@
+package command
+
+import "github.com/google/uuid"
+
+type EligibilityCheck struct {
+	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	Body         struct {
+		PeerIds []uuid.UUID `json:"peer_ids" minItems:"1" maxItems:"100" doc:"Candidate peer user IDs"`
+	}
+}
@@ -0,0 +1,16 @@
+package command
+
+import (
+	"github.com/google/uuid"
+)
+
+type ModerateCompliment struct {
+	CountryId     uint16    `path:"country_id" doc:"Country ID of the romance"`
+	ActiveUserId  uuid.UUID `path:"active_user_id" format:"uuid" doc:"ID of the user who sent the compliment"`
+	PeerId        uuid.UUID `path:"peer_id" format:"uuid" doc:"ID of the user the compliment was sent to"`
+	PeerCountryId uint16    `query:"peer_country_id" required:"false" doc:"Country ID of the user the compliment was sent to, defaults to the sender country unless the deployment requires it"`
+	Body          struct {
+		ComplimentId uuid.UUID `json:"compliment_id" format:"uuid" doc:"ID of the moderated compliment, a replaced compliment is not found"`
+		Status       string    `json:"status" enum:"approved,rejected" doc:"Moderation decision"`
+	}
+}
@@ -0,0 +1,11 @@
+package command
+
+import "github.com/google/uuid"
+
+type QuotaOverridesSet struct {
+	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	Body         struct {
+		Overrides map[string]uint32 `json:"overrides" example:"{\"crush\":5}" doc:"Daily limits by premium vote type replacing the defaults for the user, omitted vote types use the defaults"`
+	}
+}
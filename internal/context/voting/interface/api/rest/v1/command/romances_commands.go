@@ -5,12 +5,23 @@ import (
 )
 
 type DeleteRomance struct {
-	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
-	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
-	PeerId       uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
+	CountryId     uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId  uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	PeerId        uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
+	PeerCountryId uint16    `query:"peer_country_id" required:"false" doc:"Peer user country ID, defaults to the active user country unless the deployment requires it"`
 }
 
 type DeleteRomances struct {
 	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
 	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
 }
+
+type UnmatchRomance struct {
+	CountryId     uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId  uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	PeerId        uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
+	PeerCountryId uint16    `query:"peer_country_id" required:"false" doc:"Peer user country ID, defaults to the active user country unless the deployment requires it"`
+	Body          struct {
+		Reason string `json:"reason,omitempty" example:"not_interested" doc:"Unmatch reason code: not_interested, no_response, inappropriate, fake_profile or met_someone"`
+	}
+}
@@ -7,26 +7,45 @@ import (
 )
 
 type VoteAdd struct {
-	CountryId uint16 `path:"country_id" doc:"Current active user country ID"`
-	Body      struct {
-		ActiveUserId uuid.UUID                `json:"active_user_id" format:"uuid" doc:"Active User Id"`
-		PeerId       uuid.UUID                `json:"peer_id" format:"uuid" doc:"Peer user ID"`
-		VoteType     contract.AddUserVoteType `json:"vote_type"`
-		VotedAt      time.Time                `json:"voted_at"`
+	CountryId      uint16 `path:"country_id" doc:"Current active user country ID"`
+	IdempotencyKey string `header:"Idempotency-Key" maxLength:"255" doc:"Client generated key, retries with the same key replay the first response"`
+	Body           struct {
+		ActiveUserId  uuid.UUID                `json:"active_user_id" format:"uuid" doc:"Active User Id"`
+		PeerId        uuid.UUID                `json:"peer_id" format:"uuid" doc:"Peer user ID"`
+		PeerCountryId uint16                   `json:"peer_country_id,omitempty" required:"false" doc:"Peer user country ID, defaults to the active user country unless the deployment requires it"`
+		VoteType      contract.AddUserVoteType `json:"vote_type"`
+		VotedAt       time.Time                `json:"voted_at"`
+		Context       *contract.VoteContext    `json:"context,omitempty" required:"false" doc:"Recommendation the vote was cast on"`
+		Compliment    string                   `json:"compliment,omitempty" required:"false" maxLength:"280" doc:"Message sent with a compliment vote, the peer sees it once it is approved by moderation"`
 	}
 }
 
 type ChangeVoteType struct {
-	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
-	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
-	PeerId       uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
-	Body         struct {
-		NewType contract.ChangeUserVoteType `json:"new_vote_type"`
+	CountryId      uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId   uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	PeerId         uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
+	PeerCountryId  uint16    `query:"peer_country_id" required:"false" doc:"Peer user country ID, defaults to the active user country unless the deployment requires it"`
+	IdempotencyKey string    `header:"Idempotency-Key" maxLength:"255" doc:"Client generated key, retries with the same key replay the first response"`
+	IfMatch        string    `header:"If-Match" doc:"Romance ETags, the change is rejected with 412 unless the romance is at one of them. Weak ETags never match"`
+	Body           struct {
+		NewType    contract.ChangeUserVoteType `json:"new_vote_type"`
+		VotedAt    time.Time                   `json:"voted_at,omitempty" required:"false" doc:"Vote time on the client, defaults to the server time"`
+		Context    *contract.VoteContext       `json:"context,omitempty" required:"false" doc:"Recommendation the new vote was cast on, the context of the previous vote is not kept"`
+		Compliment string                      `json:"compliment,omitempty" required:"false" maxLength:"280" doc:"Message sent with a compliment vote, the peer sees it once it is approved by moderation"`
 	}
 }
 
 type DeleteVote struct {
-	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
-	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
-	PeerId       uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
+	CountryId      uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId   uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	PeerId         uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
+	PeerCountryId  uint16    `query:"peer_country_id" required:"false" doc:"Peer user country ID, defaults to the active user country unless the deployment requires it"`
+	IdempotencyKey string    `header:"Idempotency-Key" maxLength:"255" doc:"Client generated key, retries with the same key replay the first response"`
+	IfMatch        string    `header:"If-Match" doc:"Romance ETags, the deletion is rejected with 412 unless the romance is at one of them. Weak ETags never match"`
+}
+
+type RewindVote struct {
+	CountryId      uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId   uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	IdempotencyKey string    `header:"Idempotency-Key" maxLength:"255" doc:"Client generated key, retries with the same key replay the first response"`
 }
@@ -35,13 +35,8 @@ func (v *AddUserVoteType) UnmarshalText(b []byte) error {
 }
 
 func (v *AddUserVoteType) Schema(r huma.Registry) *huma.Schema {
-	enums := make([]any, 0, len(addUserVoteTypeToString))
-	for voteType := range addUserVoteTypeToString {
-		enums = append(enums, addUserVoteTypeToString[voteType])
-	}
-
 	return &huma.Schema{
 		Type: huma.TypeString,
-		Enum: enums,
+		Enum: voteTypesEnum(addUserVoteTypeToString),
 	}
 }
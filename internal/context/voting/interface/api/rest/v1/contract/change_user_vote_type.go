@@ -8,14 +8,17 @@ import (
 
 type ChangeUserVoteType romancesValueObject.VoteType
 
+// the vote types a change can name, the transition policy decides which of them are accepted
 var changeUserVoteTypeToString = map[romancesValueObject.VoteType]string{
 	romancesValueObject.VoteTypeYes:        "yes",
+	romancesValueObject.VoteTypeNo:         "no",
 	romancesValueObject.VoteTypeCrush:      "crush",
 	romancesValueObject.VoteTypeCompliment: "compliment",
 }
 
 var changeUserVoteTypeFromString = map[string]romancesValueObject.VoteType{
 	"yes":        romancesValueObject.VoteTypeYes,
+	"no":         romancesValueObject.VoteTypeNo,
 	"crush":      romancesValueObject.VoteTypeCrush,
 	"compliment": romancesValueObject.VoteTypeCompliment,
 }
@@ -33,13 +36,8 @@ func (v *ChangeUserVoteType) UnmarshalText(b []byte) error {
 }
 
 func (v *ChangeUserVoteType) Schema(r huma.Registry) *huma.Schema {
-	enums := make([]any, 0, len(changeUserVoteTypeToString))
-	for voteType := range changeUserVoteTypeToString {
-		enums = append(enums, changeUserVoteTypeToString[voteType])
-	}
-
 	return &huma.Schema{
 		Type: huma.TypeString,
-		Enum: enums,
+		Enum: voteTypesEnum(changeUserVoteTypeToString),
 	}
 }
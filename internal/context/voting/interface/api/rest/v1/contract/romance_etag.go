@@ -0,0 +1,102 @@
+package contract
+
+import (
+	"strconv"
+	"strings"
+)
+
+const AnyETag = "*"
+
+// RomanceETag exposes the romance version used for optimistic locking as a strong ETag
+func RomanceETag(version uint32) string {
+	return strconv.Quote(strconv.FormatUint(uint64(version), 10))
+}
+
+func ParseRomanceETag(etag string) (uint32, bool) {
+	etag = strings.TrimSpace(etag)
+	unquoted, err := strconv.Unquote(etag)
+	if err != nil {
+		return 0, false
+	}
+	version, err := strconv.ParseUint(unquoted, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(version), true
+}
+
+// IfMatchVersions returns the romance versions an If-Match header accepts, unconditional is set for a missing
+// header and the "*" wildcard. If-Match uses the strong comparison of RFC 9110, so weak entity tags
+// and tags which are not romance versions never match.
+func IfMatchVersions(header string) (versions []uint32, unconditional bool) {
+	if strings.TrimSpace(header) == "" {
+		return nil, true
+	}
+	for _, etag := range splitETags(header) {
+		if etag.opaque == AnyETag {
+			return nil, true
+		}
+		if etag.weak {
+			continue
+		}
+		if version, ok := ParseRomanceETag(etag.opaque); ok {
+			versions = append(versions, version)
+		}
+	}
+	return versions, false
+}
+
+// MatchesETag reports whether an If-None-Match header lists the etag, it uses the weak comparison
+// of RFC 9110 so weak validators match too
+func MatchesETag(header string, etag string) bool {
+	for _, candidate := range splitETags(header) {
+		if candidate.opaque == AnyETag || candidate.opaque == etag {
+			return true
+		}
+	}
+	return false
+}
+
+type entityTag struct {
+	weak bool
+	// opaque keeps the quotes, except for the "*" wildcard
+	opaque string
+}
+
+// splitETags reads the comma separated entity tags of a conditional header, commas inside
+// the quotes of a tag do not split it and malformed tags are skipped
+func splitETags(header string) []entityTag {
+	var etags []entityTag
+	rest := header
+	for {
+		rest = strings.TrimLeft(rest, " \t,")
+		if rest == "" {
+			return etags
+		}
+		if strings.HasPrefix(rest, AnyETag) {
+			etags = append(etags, entityTag{opaque: AnyETag})
+			rest = rest[len(AnyETag):]
+			continue
+		}
+
+		weak := strings.HasPrefix(rest, "W/")
+		if weak {
+			rest = rest[len("W/"):]
+		}
+		if !strings.HasPrefix(rest, `"`) {
+			// skip the malformed tag up to the next one
+			next := strings.IndexByte(rest, ',')
+			if next < 0 {
+				return etags
+			}
+			rest = rest[next:]
+			continue
+		}
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			return etags
+		}
+		etags = append(etags, entityTag{weak: weak, opaque: rest[:end+2]})
+		rest = rest[end+2:]
+	}
+}
@@ -0,0 +1,42 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatchVersions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		header        string
+		versions      []uint32
+		unconditional bool
+	}{
+		{name: "missing", header: "", unconditional: true},
+		{name: "wildcard", header: "*", unconditional: true},
+		{name: "single", header: `"3"`, versions: []uint32{3}},
+		{name: "list", header: `"3", "4" ,"5"`, versions: []uint32{3, 4, 5}},
+		{name: "weak_never_matches", header: `W/"3"`},
+		{name: "weak_in_list", header: `W/"3", "4"`, versions: []uint32{4}},
+		{name: "foreign_tag", header: `"abc", "7"`, versions: []uint32{7}},
+		{name: "comma_inside_tag", header: `"1,2", "2"`, versions: []uint32{2}},
+		{name: "malformed_tag", header: `3, "4"`, versions: []uint32{4}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			versions, unconditional := IfMatchVersions(tc.header)
+			assert.Equal(t, tc.unconditional, unconditional)
+			assert.Equal(t, tc.versions, versions)
+		})
+	}
+}
+
+func TestMatchesETag(t *testing.T) {
+	etag := RomanceETag(3)
+
+	assert.True(t, MatchesETag(`"2", W/"3"`, etag))
+	assert.True(t, MatchesETag("*", etag))
+	assert.False(t, MatchesETag(`"2", "33"`, etag))
+}
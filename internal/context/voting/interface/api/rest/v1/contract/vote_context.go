@@ -0,0 +1,38 @@
+package contract
+
+import (
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+)
+
+// VoteContext limits keep the context of both users well below the romance item size limit
+type VoteContext struct {
+	Surface          string  `json:"surface" enum:"deck,profile,search" doc:"Part of the app the vote was cast from"`
+	RecommendationId string  `json:"recommendation_id,omitempty" required:"false" maxLength:"128" pattern:"^[A-Za-z0-9._:-]+$" doc:"ID of the recommendation the peer was shown in"`
+	Position         *uint32 `json:"position,omitempty" required:"false" maximum:"10000" doc:"Zero based position of the peer in the recommendation"`
+	ExperimentBucket string  `json:"experiment_bucket,omitempty" required:"false" maxLength:"64" pattern:"^[A-Za-z0-9._:-]+$" doc:"Experiment bucket of the active user"`
+}
+
+func (c *VoteContext) ToValueObject() *romancesValueObject.VoteContext {
+	if c == nil {
+		return nil
+	}
+	surface, _ := romancesValueObject.VoteSurfaceFromString(c.Surface)
+	return &romancesValueObject.VoteContext{
+		Surface:          surface,
+		RecommendationId: c.RecommendationId,
+		Position:         c.Position,
+		ExperimentBucket: c.ExperimentBucket,
+	}
+}
+
+func NewVoteContext(voteContext *romancesValueObject.VoteContext) *VoteContext {
+	if voteContext == nil {
+		return nil
+	}
+	return &VoteContext{
+		Surface:          voteContext.Surface.String(),
+		RecommendationId: voteContext.RecommendationId,
+		Position:         voteContext.Position,
+		ExperimentBucket: voteContext.ExperimentBucket,
+	}
+}
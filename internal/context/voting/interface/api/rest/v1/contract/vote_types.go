@@ -0,0 +1,46 @@
+package contract
+
+import (
+	"maps"
+	"slices"
+
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	huma "github.com/danielgtaylor/huma/v2"
+)
+
+// LimitVoteTypesEnum narrows the enum of a vote type property in the request body of op to voteTypes,
+// e.g. to the ones the transition policy allows. The body schema belongs to the registry of the API
+// op is registered with, so every API keeps its own enum and requests outside it fail validation.
+func LimitVoteTypesEnum(
+	registry huma.Registry,
+	op *huma.Operation,
+	property string,
+	voteTypes []romancesValueObject.VoteType,
+) {
+	if op.RequestBody == nil || op.RequestBody.Content["application/json"] == nil {
+		return
+	}
+	body := op.RequestBody.Content["application/json"].Schema
+	for body != nil && body.Ref != "" {
+		body = registry.SchemaFromRef(body.Ref)
+	}
+	if body == nil || body.Properties[property] == nil {
+		return
+	}
+
+	toString := make(map[romancesValueObject.VoteType]string, len(voteTypes))
+	for _, voteType := range voteTypes {
+		toString[voteType] = voteType.String()
+	}
+	schema := body.Properties[property]
+	schema.Enum = voteTypesEnum(toString)
+	schema.PrecomputeMessages()
+}
+
+func voteTypesEnum(toString map[romancesValueObject.VoteType]string) []any {
+	enums := make([]any, 0, len(toString))
+	for _, voteType := range slices.Sorted(maps.Keys(toString)) {
+		enums = append(enums, toString[voteType])
+	}
+	return enums
+}
@@ -0,0 +1,46 @@
+package contract
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type changeVoteInput struct {
+	Body struct {
+		NewType ChangeUserVoteType `json:"new_vote_type"`
+	}
+}
+
+func newChangeVoteApi(t *testing.T, voteTypes []romancesValueObject.VoteType) humatest.TestAPI {
+	_, api := humatest.New(t)
+	grp := huma.NewGroup(api)
+	grp.UseSimpleModifier(func(op *huma.Operation) {
+		LimitVoteTypesEnum(grp.OpenAPI().Components.Schemas, op, "new_vote_type", voteTypes)
+	})
+	huma.Register(grp, huma.Operation{
+		OperationID: "change-vote",
+		Method:      http.MethodPatch,
+		Path:        "/votes",
+	}, func(ctx context.Context, input *changeVoteInput) (*struct{}, error) {
+		return nil, nil
+	})
+	return api
+}
+
+func TestLimitVoteTypesEnumIsKeptPerApi(t *testing.T) {
+	narrow := newChangeVoteApi(t, []romancesValueObject.VoteType{romancesValueObject.VoteTypeYes})
+	wide := newChangeVoteApi(t, []romancesValueObject.VoteType{romancesValueObject.VoteTypeYes, romancesValueObject.VoteTypeNo})
+
+	assert.Equal(t, []any{"yes"}, narrow.OpenAPI().Components.Schemas.Map()["ChangeVoteInputBody"].Properties["new_vote_type"].Enum)
+	assert.Equal(t, []any{"yes", "no"}, wide.OpenAPI().Components.Schemas.Map()["ChangeVoteInputBody"].Properties["new_vote_type"].Enum)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, narrow.Patch("/votes", strings.NewReader(`{"new_vote_type":"no"}`)).Code)
+	assert.Equal(t, http.StatusNoContent, wide.Patch("/votes", strings.NewReader(`{"new_vote_type":"no"}`)).Code)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1/contract"
 	huma "github.com/danielgtaylor/huma/v2"
 	"github.com/google/uuid"
+	"time"
 )
 
 type LifetimeCountersGet struct {
@@ -20,6 +21,14 @@ type HourlyCountersGet struct {
 	HoursOffsetGroups    []uint8                      `json:"-"`
 }
 
+type CountersSeriesGet struct {
+	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	From         time.Time `query:"from" required:"true" doc:"Start of the range, moved to the start of its UTC bucket"`
+	To           time.Time `query:"to" required:"true" doc:"Exclusive end of the range, moved to the end of its UTC bucket"`
+	Granularity  string    `query:"granularity" enum:"hour,day,week" default:"hour" doc:"Length of the buckets of the series, weeks are ISO weeks starting on Monday"`
+}
+
 func (in *HourlyCountersGet) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
 	offsets := make([]uint8, len(in.HoursOffsetGroupsRaw))
 	location := prefix.With("query.hours_offset_groups")
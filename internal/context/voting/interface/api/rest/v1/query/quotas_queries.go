@@ -0,0 +1,8 @@
+package query
+
+import "github.com/google/uuid"
+
+type QuotasGet struct {
+	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+}
@@ -1,9 +1,44 @@
 package query
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type RomanceGet struct {
+	CountryId     uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId  uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	PeerId        uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
+	PeerCountryId uint16    `query:"peer_country_id" required:"false" doc:"Peer user country ID, defaults to the active user country unless the deployment requires it"`
+	IfNoneMatch   string    `header:"If-None-Match" doc:"Romance ETag, 304 is returned if the romance was not modified since"`
+}
+
+type RomanceHistoryGet struct {
+	CountryId     uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId  uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	PeerId        uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
+	PeerCountryId uint16    `query:"peer_country_id" required:"false" doc:"Peer user country ID, defaults to the active user country unless the deployment requires it"`
+	Limit         int32     `query:"limit" minimum:"1" maximum:"500" default:"50" doc:"Maximum number of history entries to return"`
+	Cursor        string    `query:"cursor" maxLength:"256" doc:"Cursor of the next page returned by the previous request"`
+}
+
+type RomancesList struct {
+	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	State        []string  `query:"state" enum:"empty,outgoing_pending,incoming_pending,matched,rejected,mutually_rejected,blocked,unmatched" doc:"Only list romances in these states, all states are listed when omitted"`
+	Limit        int32     `query:"limit" minimum:"1" maximum:"100" default:"50" doc:"Maximum number of romances to return"`
+	Cursor       string    `query:"cursor" maxLength:"256" doc:"Cursor of the next page returned by the previous request"`
+}
+
+type RomancesExport struct {
+	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	Since        time.Time `query:"since" doc:"Only export romances the active user voted on at or after this time, all romances are exported when omitted"`
+	Cursor       string    `query:"cursor" maxLength:"256" doc:"Last next_cursor line of an interrupted export to resume after"`
+}
+
+type ExclusionFilterGet struct {
 	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
 	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
-	PeerId       uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
 }
@@ -3,7 +3,9 @@ package query
 import "github.com/google/uuid"
 
 type VoteGet struct {
-	CountryId    uint16    `path:"country_id" doc:"Current active user country ID"`
-	ActiveUserId uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
-	PeerId       uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
+	CountryId     uint16    `path:"country_id" doc:"Current active user country ID"`
+	ActiveUserId  uuid.UUID `path:"active_user_id" format:"uuid" doc:"Active User Id"`
+	PeerId        uuid.UUID `path:"peer_id" format:"uuid" doc:"Peer user ID"`
+	PeerCountryId uint16    `query:"peer_country_id" required:"false" doc:"Peer user country ID, defaults to the active user country unless the deployment requires it"`
+	IfNoneMatch   string    `header:"If-None-Match" doc:"Romance ETag, 304 is returned if the romance was not modified since"`
 }
@@ -2,36 +2,62 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	apiResponse "github.com/bmbl-bumble2/recs-votes-storage/internal/app/api/response"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1/command"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1/contract"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1/query"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1/response"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/callerscope"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/idempotency"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
 	"net/http"
+	"strconv"
 )
 
 type VotesStorageRoutesRegister struct {
-	votesService *application.VotingService
+	votesService     *application.VotingService
+	idempotencyGuard *idempotency.Guard
+	transitionPolicy *romanceDomain.VoteTransitionPolicy
+	logger           platform.Logger
 }
 
 func NewVotesStorageRoutesRegister(
 	votesService *application.VotingService,
+	idempotencyGuard *idempotency.Guard,
+	transitionPolicy *romanceDomain.VoteTransitionPolicy,
+	logger platform.Logger,
 ) VotesStorageRoutesRegister {
 	return VotesStorageRoutesRegister{
-		votesService: votesService,
+		votesService:     votesService,
+		idempotencyGuard: idempotencyGuard,
+		transitionPolicy: transitionPolicy,
+		logger:           logger,
 	}
 }
 
 func (v VotesStorageRoutesRegister) RegisterV1Routes(grp *huma.Group) {
-	registerRomancesRoutes(grp, v.votesService)
-	registerVotesRoutes(grp, v.votesService)
-	registerCountersRoutes(grp, v.votesService)
+	registerRomancesRoutes(grp, v.votesService, v.logger)
+	registerVotesRoutes(grp, v.votesService, v.idempotencyGuard, v.transitionPolicy, v.logger)
+	registerCountersRoutes(grp, v.votesService, v.logger)
+	registerQuotasRoutes(grp, v.votesService, v.logger)
+	registerBlocksRoutes(grp, v.votesService, v.logger)
+	registerModerationRoutes(grp, v.votesService, v.logger)
+	registerEligibilityRoutes(grp, v.votesService, v.logger)
 }
 
 func registerRomancesRoutes(
 	grp *huma.Group,
 	votesService *application.VotingService,
+	logger platform.Logger,
 ) {
 	grp = huma.NewGroup(grp, "/romances")
 	grp.UseSimpleModifier(func(op *huma.Operation) {
@@ -46,16 +72,69 @@ func registerRomancesRoutes(
 		Summary:     "Get romance from the active user's perspective",
 		Description: "Each user in a pair can take the role of either the active user or the peer, " +
 			"and the order of users in the request determines how the romance object " +
-			"will be constructed and returned.",
+			"will be constructed and returned. Unless the " + callerscope.Header + " header grants " +
+			"the internal or admin scope, the peer vote is only shown in a mutual romance or for a crush or compliment.",
+		Responses: notModifiedResponses(),
 	}, func(reqCtx context.Context, get *query.RomanceGet) (*response.RomanceGetResponse, error) {
 		romance, err := votesService.GetRomance(reqCtx, *get)
 		if err != nil {
-			return nil, response.ToApiError(err)
+			return nil, response.ToApiError(err, logger)
 		}
 		resp := response.CreateRomanceGetResponseFromVoteEntity(romance)
+		if get.IfNoneMatch != "" && contract.MatchesETag(get.IfNoneMatch, resp.ETag) {
+			return nil, huma.Status304NotModified()
+		}
 		return resp, nil
 	})
 
+	// GET /v1/romances/{country_id}/{active_user_id}/exclusion-filter
+	exclusionFilterResponses := apiResponse.GenerateErrorResponsesGroup(grp, 422)
+	exclusionFilterResponses["200"] = &huma.Response{
+		Description: "Exclusion filter",
+		Content: map[string]*huma.MediaType{
+			response.ExclusionFilterContentType: {},
+		},
+	}
+	huma.Register(grp, huma.Operation{
+		OperationID: "get-exclusion-filter",
+		Method:      http.MethodGet,
+		Path:        "/{country_id}/{active_user_id}/exclusion-filter",
+		Summary:     "Get the exclusion filter of the active user",
+		Description: "Bloom filter of the peers which must not be recommended to the active user, the same peers " +
+			"as rejected by the eligibility check: peers the active user voted on, blocked peers and peers " +
+			"after an unmatch. Other peers are reported as excluded with the configured false positive rate. " +
+			"The binary filter starts with a format version byte, the hash count byte and the bit count as big " +
+			"endian uint32 followed by the bits, bit i is 1<<(i%8) of byte i/8. The bits of a peer are " +
+			"(h1 + j*h2) mod bit count for j below the hash count, h1 and h2 are the low and high 32 bits of the " +
+			"FNV-1a 64 hash of the 16 bytes of the peer id, h2 with its lowest bit set. " +
+			"Filters are cached for a short time and rebuilt once they report more false positives than configured.",
+		Responses: exclusionFilterResponses,
+	}, func(reqCtx context.Context, get *query.ExclusionFilterGet) (*response.ExclusionFilterGetResponse, error) {
+		filter, err := votesService.GetExclusionFilter(reqCtx, *get)
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return response.CreateExclusionFilterGetResponse(filter), nil
+	})
+
+	// GET /v1/romances/{country_id}/{active_user_id}/{peer_id}/history
+	huma.Register(grp, huma.Operation{
+		OperationID: "get-romance-history",
+		Method:      http.MethodGet,
+		Path:        "/{country_id}/{active_user_id}/{peer_id}/history",
+		Summary:     "Get vote history of a romance",
+		Description: "Lists every vote write of both users in the romance, newest first. " +
+			"Entries are kept for their own retention period regardless of the romance TTL. " +
+			"Restricted callers see the votes of the peer only as far as the romance shows them, hidden " +
+			"votes read as missing, and get an empty history while the peer blocks them.",
+	}, func(reqCtx context.Context, get *query.RomanceHistoryGet) (*response.RomanceHistoryGetResponse, error) {
+		page, err := votesService.GetRomanceHistory(reqCtx, *get)
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return response.CreateRomanceHistoryGetResponse(get.ActiveUserId, page), nil
+	})
+
 	// DELETE /v1/romances/{country_id}/{active_user_id}/{peer_id}
 	huma.Register(grp, huma.Operation{
 		OperationID: "delete-romance",
@@ -65,11 +144,74 @@ func registerRomancesRoutes(
 	}, func(reqCtx context.Context, command *command.DeleteRomance) (*struct{}, error) {
 		err := votesService.DeleteRomance(reqCtx, *command)
 		if err != nil {
-			return nil, response.ToApiError(err)
+			return nil, response.ToApiError(err, logger)
 		}
 		return nil, nil
 	})
 
+	// POST /v1/romances/{country_id}/{active_user_id}/{peer_id}/unmatch
+	huma.Register(grp, huma.Operation{
+		OperationID: "unmatch-romance",
+		Method:      http.MethodPost,
+		Path:        "/{country_id}/{active_user_id}/{peer_id}/unmatch",
+		Summary:     "Unmatch a mutual romance",
+		Description: "Ends the match for both users and removes their votes, which are kept in the history. " +
+			"Unlike deleting a vote it does not leave a pending like of the peer. Votes of both users are " +
+			"rejected until the re-vote ban ends and a new match needs new votes from both of them.",
+		Responses: apiResponse.GenerateErrorResponsesGroup(grp, 403, 409, 422),
+	}, func(reqCtx context.Context, command *command.UnmatchRomance) (*response.RomanceGetResponse, error) {
+		romance, err := votesService.UnmatchRomance(reqCtx, *command)
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return response.CreateRomanceGetResponseFromVoteEntity(romance), nil
+	})
+
+	// GET /v1/romances/{country_id}/{active_user_id}
+	huma.Register(grp, huma.Operation{
+		OperationID: "list-romances",
+		Method:      http.MethodGet,
+		Path:        "/{country_id}/{active_user_id}",
+		Summary:     "List active user romances",
+		Description: "Lists romances from the active user's perspective, optionally only those in the given states. " +
+			"Romances blocked by the peer are left out and the peer vote is projected like in get-romance. " +
+			"The listing ends when no next cursor is returned.",
+		Responses: apiResponse.GenerateErrorResponsesGroup(grp, 422),
+	}, func(reqCtx context.Context, list *query.RomancesList) (*response.RomancesListResponse, error) {
+		page, err := votesService.ListRomances(reqCtx, *list)
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return response.CreateRomancesListResponse(page), nil
+	})
+
+	// GET /v1/romances/{country_id}/{active_user_id}/export
+	huma.Register(grp, huma.Operation{
+		OperationID: "export-romances",
+		Method:      http.MethodGet,
+		Path:        "/{country_id}/{active_user_id}/export",
+		Summary:     "Stream all active user romances",
+		Description: "Streams the romances of list-romances as newline-delimited JSON while they are read, " +
+			"one line with peer_id and romance per romance. A line with next_cursor follows the romances of every " +
+			"read page, an interrupted export is resumed by passing the last received next_cursor as cursor. " +
+			"Errors after the first line end the stream with a line holding error instead of an error status. " +
+			"The export stops once the client disconnects.",
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Romances export",
+				Content: map[string]*huma.MediaType{
+					response.RomancesExportContentType: {},
+				},
+			},
+		},
+	}, func(reqCtx context.Context, export *query.RomancesExport) (*huma.StreamResponse, error) {
+		return &huma.StreamResponse{
+			Body: func(ctx huma.Context) {
+				writeRomancesExport(ctx, votesService, *export, logger)
+			},
+		}, nil
+	})
+
 	// DELETE /v1/romances/{country_id}/{active_user_id}
 	huma.Register(grp, huma.Operation{
 		OperationID: "delete-romances",
@@ -79,7 +221,7 @@ func registerRomancesRoutes(
 	}, func(reqCtx context.Context, command *command.DeleteRomances) (*struct{}, error) {
 		err := votesService.DeleteRomancesRequest(reqCtx, *command)
 		if err != nil {
-			return nil, response.ToApiError(err)
+			return nil, response.ToApiError(err, logger)
 		}
 		return nil, nil
 	})
@@ -88,11 +230,24 @@ func registerRomancesRoutes(
 func registerVotesRoutes(
 	grp *huma.Group,
 	votesService *application.VotingService,
+	idempotencyGuard *idempotency.Guard,
+	transitionPolicy *romanceDomain.VoteTransitionPolicy,
+	logger platform.Logger,
 ) {
 	grp = huma.NewGroup(grp, "/votes")
 	grp.UseSimpleModifier(func(op *huma.Operation) {
 		op.Tags = []string{"Votes"}
 	})
+	// the enums list the vote types the transition policy allows in at least one country
+	grp.UseSimpleModifier(func(op *huma.Operation) {
+		registry := grp.OpenAPI().Components.Schemas
+		switch op.OperationID {
+		case "add-vote":
+			contract.LimitVoteTypesEnum(registry, op, "vote_type", transitionPolicy.AddableVoteTypes())
+		case "change-vote":
+			contract.LimitVoteTypesEnum(registry, op, "new_vote_type", transitionPolicy.ChangeableVoteTypes())
+		}
+	})
 
 	// GET /v1/votes/{country_id}/{active_user_id}/{peer_id}
 	huma.Register(grp, huma.Operation{
@@ -100,12 +255,16 @@ func registerVotesRoutes(
 		Method:      http.MethodGet,
 		Path:        "/{country_id}/{active_user_id}/{peer_id}",
 		Summary:     "Get vote from the active user's perspective",
+		Responses:   notModifiedResponses(),
 	}, func(reqCtx context.Context, get *query.VoteGet) (*response.VoteGetResponse, error) {
-		vote, err := votesService.GetUserVote(reqCtx, *get)
+		vote, romanceVersion, err := votesService.GetUserVote(reqCtx, *get)
 		if err != nil {
-			return nil, response.ToApiError(err)
+			return nil, response.ToApiError(err, logger)
+		}
+		resp := response.CreateVoteGetResponseFromVoteEntity(vote, romanceVersion)
+		if get.IfNoneMatch != "" && contract.MatchesETag(get.IfNoneMatch, resp.ETag) {
+			return nil, huma.Status304NotModified()
 		}
-		resp := response.CreateVoteGetResponseFromVoteEntity(vote)
 		return resp, nil
 	})
 
@@ -115,12 +274,20 @@ func registerVotesRoutes(
 		Method:      http.MethodPost,
 		Path:        "/{country_id}",
 		Summary:     "Add new vote",
+		Responses:   apiResponse.GenerateErrorResponsesGroup(grp, 403, 409, 429),
 	}, func(reqCtx context.Context, command *command.VoteAdd) (*response.VoteAddResponse, error) {
-		vote, err := votesService.AddUserVote(reqCtx, *command)
+		scope := idempotencyScope("add-vote", command.CountryId, command.Body.ActiveUserId)
+		resp, err := idempotency.Execute(reqCtx, idempotencyGuard, scope, command.IdempotencyKey, command,
+			func(ctx context.Context) (*response.VoteAddResponse, error) {
+				vote, romanceVersion, err := votesService.AddUserVote(ctx, *command)
+				if err != nil {
+					return nil, response.ToApiError(err, logger)
+				}
+				return response.CreateVoteAddResponseFromVoteEntity(vote, romanceVersion), nil
+			})
 		if err != nil {
-			return nil, response.ToApiError(err)
+			return nil, response.ToApiError(err, logger)
 		}
-		resp := response.CreateVoteAddResponseFromVoteEntity(vote)
 		return resp, nil
 	})
 
@@ -130,13 +297,20 @@ func registerVotesRoutes(
 		Method:      http.MethodPatch,
 		Path:        "/{country_id}/{active_user_id}/{peer_id}/change-contract",
 		Summary:     "Change active user vote contract",
-		Responses:   apiResponse.GenerateErrorResponsesGroup(grp, 404),
+		Responses:   apiResponse.GenerateErrorResponsesGroup(grp, 403, 404, 409, 412, 429),
 	}, func(reqCtx context.Context, command *command.ChangeVoteType) (*response.ChangeVoteResponse, error) {
-		vote, err := votesService.ChangeUserVote(reqCtx, *command)
+		scope := idempotencyScope("change-vote", command.CountryId, command.ActiveUserId)
+		resp, err := idempotency.Execute(reqCtx, idempotencyGuard, scope, command.IdempotencyKey, command,
+			func(ctx context.Context) (*response.ChangeVoteResponse, error) {
+				vote, romanceVersion, err := votesService.ChangeUserVote(ctx, *command)
+				if err != nil {
+					return nil, response.ToApiError(err, logger)
+				}
+				return response.CreateChangeVoteResponseFromVoteEntity(vote, romanceVersion), nil
+			})
 		if err != nil {
-			return nil, response.ToApiError(err)
+			return nil, response.ToApiError(err, logger)
 		}
-		resp := response.CreateChangeVoteResponseFromVoteEntity(vote)
 		return resp, nil
 	})
 
@@ -146,18 +320,107 @@ func registerVotesRoutes(
 		Method:      http.MethodDelete,
 		Path:        "/{country_id}/{active_user_id}/{peer_id}",
 		Summary:     "Delete active user vote",
-	}, func(reqCtx context.Context, command *command.DeleteVote) (*struct{}, error) {
-		err := votesService.DeleteUserVote(reqCtx, *command)
+		Responses:   apiResponse.GenerateErrorResponsesGroup(grp, 403, 409, 412),
+	}, func(reqCtx context.Context, command *command.DeleteVote) (*response.DeleteVoteResponse, error) {
+		scope := idempotencyScope("delete-vote", command.CountryId, command.ActiveUserId)
+		resp, err := idempotency.Execute(reqCtx, idempotencyGuard, scope, command.IdempotencyKey, command,
+			func(ctx context.Context) (*response.DeleteVoteResponse, error) {
+				romanceVersion, err := votesService.DeleteUserVote(ctx, *command)
+				if err != nil {
+					return nil, response.ToApiError(err, logger)
+				}
+				return response.CreateDeleteVoteResponse(romanceVersion), nil
+			})
 		if err != nil {
-			return nil, response.ToApiError(err)
+			return nil, response.ToApiError(err, logger)
 		}
-		return nil, nil
+		return resp, nil
 	})
+
+	// POST /v1/votes/{country_id}/{active_user_id}/rewind
+	huma.Register(grp, huma.Operation{
+		OperationID: "rewind-vote",
+		Method:      http.MethodPost,
+		Path:        "/{country_id}/{active_user_id}/rewind",
+		Summary:     "Undo the most recent vote of the active user",
+		Description: "Restores the previous vote of the romance and takes the vote back from the counters, " +
+			"only votes accepted within the rewind window can be undone.",
+		Responses: apiResponse.GenerateErrorResponsesGroup(grp, 403, 404, 409),
+	}, func(reqCtx context.Context, command *command.RewindVote) (*response.RewindVoteResponse, error) {
+		scope := idempotencyScope("rewind-vote", command.CountryId, command.ActiveUserId)
+		resp, err := idempotency.Execute(reqCtx, idempotencyGuard, scope, command.IdempotencyKey, command,
+			func(ctx context.Context) (*response.RewindVoteResponse, error) {
+				vote, romanceVersion, err := votesService.RewindVote(ctx, *command)
+				if err != nil {
+					return nil, response.ToApiError(err, logger)
+				}
+				return response.CreateRewindVoteResponseFromVoteEntity(vote, romanceVersion), nil
+			})
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return resp, nil
+	})
+}
+
+func notModifiedResponses() map[string]*huma.Response {
+	return map[string]*huma.Response{
+		strconv.Itoa(http.StatusNotModified): {Description: http.StatusText(http.StatusNotModified)},
+	}
+}
+
+// writeRomancesExport sends the status with the first page, so errors before it still get an error status
+func writeRomancesExport(
+	ctx huma.Context,
+	votesService *application.VotingService,
+	export query.RomancesExport,
+	logger platform.Logger,
+) {
+	writer := ctx.BodyWriter()
+	encoder := json.NewEncoder(writer)
+	started := false
+	err := votesService.ExportRomances(ctx.Context(), export, func(page romanceEntity.RomancesPage) error {
+		if !started {
+			ctx.SetHeader("Content-Type", response.RomancesExportContentType)
+			ctx.SetStatus(http.StatusOK)
+			started = true
+		}
+		for _, line := range response.NewRomancesExportLines(page) {
+			if err := encoder.Encode(line); err != nil {
+				return err
+			}
+		}
+		if flusher, ok := writer.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err == nil || ctx.Context().Err() != nil {
+		return
+	}
+	if started {
+		_ = encoder.Encode(response.RomancesExportLine{Error: response.ToApiError(err, logger).Error()})
+		return
+	}
+
+	var statusErr huma.StatusError
+	if !errors.As(response.ToApiError(err, logger), &statusErr) {
+		return
+	}
+	ctx.SetHeader("Content-Type", apiResponse.ProblemJsonContentType)
+	ctx.SetStatus(statusErr.GetStatus())
+	_ = encoder.Encode(statusErr)
+}
+
+// idempotencyScope keeps the same client key used by different users or operations apart.
+func idempotencyScope(operationId string, countryId uint16, activeUserId uuid.UUID) string {
+	return fmt.Sprintf("%s#%d#%s", operationId, countryId, activeUserId)
 }
 
 func registerCountersRoutes(
 	grp *huma.Group,
 	votesService *application.VotingService,
+	logger platform.Logger,
 ) {
 	grp = huma.NewGroup(grp, "/counters")
 	grp.UseSimpleModifier(func(op *huma.Operation) {
@@ -173,7 +436,7 @@ func registerCountersRoutes(
 	}, func(reqCtx context.Context, query *query.LifetimeCountersGet) (*response.LifetimeCountersGetResponse, error) {
 		countersGroup, err := votesService.GetLifetimeCounters(reqCtx, *query)
 		if err != nil {
-			return nil, response.ToApiError(err)
+			return nil, response.ToApiError(err, logger)
 		}
 		resp := response.CreateLifetimeCountersGetResponseFromCountersGroup(countersGroup)
 		return resp, nil
@@ -188,9 +451,185 @@ func registerCountersRoutes(
 	}, func(reqCtx context.Context, query *query.HourlyCountersGet) (*response.HourlyCountersGetResponse, error) {
 		countersGroup, err := votesService.GetHourlyCounters(reqCtx, *query)
 		if err != nil {
-			return nil, response.ToApiError(err)
+			return nil, response.ToApiError(err, logger)
 		}
 		resp := response.CreateHourlyCountersGetResponseFromCountersGroup(countersGroup)
 		return resp, nil
 	})
+
+	// GET /v1/counters/{country_id}/{active_user_id}/series
+	huma.Register(grp, huma.Operation{
+		OperationID: "get-counters-series",
+		Method:      http.MethodGet,
+		Path:        "/{country_id}/{active_user_id}/series",
+		Summary:     "Get counters of the active user as a time series",
+		Description: "Returns one point per UTC hour, day or ISO week between from and to, buckets without votes are zero. " +
+			"Hourly counters expire after " + strconv.Itoa(config.CountersTtlHours) + " hours, an hourly range starting " +
+			"earlier is rejected. Daily and weekly buckets are read from rollups kept for the configured retention, " +
+			"buckets older than that are zero.",
+		Responses: apiResponse.GenerateErrorResponsesGroup(grp, 422),
+	}, func(reqCtx context.Context, query *query.CountersSeriesGet) (*response.CountersSeriesGetResponse, error) {
+		series, err := votesService.GetCountersSeries(reqCtx, *query)
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return response.CreateCountersSeriesGetResponse(query.Granularity, series), nil
+	})
+}
+
+func registerQuotasRoutes(
+	grp *huma.Group,
+	votesService *application.VotingService,
+	logger platform.Logger,
+) {
+	grp = huma.NewGroup(grp, "/quotas")
+	grp.UseSimpleModifier(func(op *huma.Operation) {
+		op.Tags = []string{"Quotas"}
+	})
+
+	// GET /v1/quotas/{country_id}/{active_user_id}
+	huma.Register(grp, huma.Operation{
+		OperationID: "get-quotas",
+		Method:      http.MethodGet,
+		Path:        "/{country_id}/{active_user_id}",
+		Summary:     "Get remaining daily quotas of premium votes for the active user",
+	}, func(reqCtx context.Context, query *query.QuotasGet) (*response.QuotasGetResponse, error) {
+		quotas, location, err := votesService.GetVoteQuotas(reqCtx, *query)
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return response.CreateQuotasGetResponseFromQuotaEntities(quotas, location), nil
+	})
+
+	// PUT /v1/quotas/{country_id}/{active_user_id}/overrides
+	huma.Register(grp, huma.Operation{
+		OperationID: "set-quota-overrides",
+		Method:      http.MethodPut,
+		Path:        "/{country_id}/{active_user_id}/overrides",
+		Summary:     "Replace the daily quota overrides of the active user",
+		Description: "Unless the " + callerscope.Header + " header grants the internal or admin scope " +
+			"the request is rejected with 403.",
+		Responses: apiResponse.GenerateErrorResponsesGroup(grp, 403, 422),
+	}, func(reqCtx context.Context, command *command.QuotaOverridesSet) (*struct{}, error) {
+		if err := requireUnrestrictedScope(reqCtx); err != nil {
+			return nil, err
+		}
+		err := votesService.SetVoteQuotaOverrides(reqCtx, *command)
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return nil, nil
+	})
+}
+
+func registerBlocksRoutes(
+	grp *huma.Group,
+	votesService *application.VotingService,
+	logger platform.Logger,
+) {
+	grp = huma.NewGroup(grp, "/blocks")
+	grp.UseSimpleModifier(func(op *huma.Operation) {
+		op.Tags = []string{"Blocks"}
+	})
+
+	// PUT /v1/blocks/{country_id}/{active_user_id}/{peer_id}
+	huma.Register(grp, huma.Operation{
+		OperationID: "block-peer",
+		Method:      http.MethodPut,
+		Path:        "/{country_id}/{active_user_id}/{peer_id}",
+		Summary:     "Block the peer for the active user",
+		Description: "While either user blocks the other one no votes are accepted in both directions " +
+			"and the romance is returned as if nobody voted, counters are left as they are.",
+	}, func(reqCtx context.Context, command *command.BlockPeer) (*struct{}, error) {
+		err := votesService.BlockPeer(reqCtx, *command)
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return nil, nil
+	})
+
+	// DELETE /v1/blocks/{country_id}/{active_user_id}/{peer_id}
+	huma.Register(grp, huma.Operation{
+		OperationID: "unblock-peer",
+		Method:      http.MethodDelete,
+		Path:        "/{country_id}/{active_user_id}/{peer_id}",
+		Summary:     "Remove the block of the peer set by the active user",
+	}, func(reqCtx context.Context, command *command.UnblockPeer) (*struct{}, error) {
+		err := votesService.UnblockPeer(reqCtx, *command)
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return nil, nil
+	})
+}
+
+func registerModerationRoutes(
+	grp *huma.Group,
+	votesService *application.VotingService,
+	logger platform.Logger,
+) {
+	grp = huma.NewGroup(grp, "/moderation")
+	grp.UseSimpleModifier(func(op *huma.Operation) {
+		op.Tags = []string{"Moderation"}
+	})
+
+	// POST /v1/moderation/compliments/{country_id}/{active_user_id}/{peer_id}
+	huma.Register(grp, huma.Operation{
+		OperationID: "moderate-compliment",
+		Method:      http.MethodPost,
+		Path:        "/compliments/{country_id}/{active_user_id}/{peer_id}",
+		Summary:     "Set the moderation status of a compliment",
+		Description: "Callback of the moderation for compliments sent by the active user to the peer. " +
+			"The peer only sees approved compliments, a decision on a compliment which was " +
+			"replaced or removed in the meantime is rejected with 404. Unless the " + callerscope.Header +
+			" header grants the internal or admin scope the request is rejected with 403.",
+		Responses: apiResponse.GenerateErrorResponsesGroup(grp, 403, 404, 409, 422),
+	}, func(reqCtx context.Context, command *command.ModerateCompliment) (*response.ModerateComplimentResponse, error) {
+		if err := requireUnrestrictedScope(reqCtx); err != nil {
+			return nil, err
+		}
+		vote, err := votesService.ModerateCompliment(reqCtx, *command)
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return response.CreateModerateComplimentResponseFromVoteEntity(vote), nil
+	})
+}
+
+func registerEligibilityRoutes(
+	grp *huma.Group,
+	votesService *application.VotingService,
+	logger platform.Logger,
+) {
+	grp = huma.NewGroup(grp, "/eligibility")
+	grp.UseSimpleModifier(func(op *huma.Operation) {
+		op.Tags = []string{"Eligibility"}
+	})
+
+	// POST /v1/eligibility/{country_id}/{active_user_id}
+	huma.Register(grp, huma.Operation{
+		OperationID: "check-eligibility",
+		Method:      http.MethodPost,
+		Path:        "/{country_id}/{active_user_id}",
+		Summary:     "Check which candidates can be recommended to the active user",
+		Description: "A candidate is eligible unless the active user voted on them, blocks between the users " +
+			"and re-vote bans after an unmatch exclude a candidate too. A no only excludes the candidate " +
+			"for the no-vote cooling period, afterwards the no is kept and counted but the candidate " +
+			"can be shown again. The votes of the candidates are not taken into account.",
+		Responses: apiResponse.GenerateErrorResponsesGroup(grp, 422),
+	}, func(reqCtx context.Context, command *command.EligibilityCheck) (*response.EligibilityCheckResponse, error) {
+		eligiblePeerIds, err := votesService.CheckEligibility(reqCtx, *command)
+		if err != nil {
+			return nil, response.ToApiError(err, logger)
+		}
+		return response.CreateEligibilityCheckResponse(eligiblePeerIds), nil
+	})
+}
+
+// requireUnrestrictedScope keeps the routes called by other services and the back office away from users
+func requireUnrestrictedScope(ctx context.Context) error {
+	if !callerscope.FromContext(ctx).IsUnrestricted() {
+		return response.NewErr403Forbidden(response.CodeScopeNotAllowed, "the internal or admin scope is required")
+	}
+	return nil
 }
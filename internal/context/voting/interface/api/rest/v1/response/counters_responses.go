@@ -1,12 +1,16 @@
 package response
 
-import "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/entity"
+import (
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/entity"
+	"time"
+)
 
 type CountersGroup struct {
 	IncomingYes uint32 `json:"incoming_yes" doc:"Incoming yes votes count"`
 	IncomingNo  uint32 `json:"incoming_no" doc:"Incoming no votes count"`
 	OutgoingYes uint32 `json:"outgoing_yes" doc:"Outgoing yes votes count"`
 	OutgoingNo  uint32 `json:"outgoing_no" doc:"Outgoing no votes count"`
+	Matches     uint32 `json:"matches" doc:"Matches count, unmatched romances are excluded from the lifetime count"`
 }
 
 func NewCountersGroupFromEntity(counters *entity.CountersGroup) CountersGroup {
@@ -15,6 +19,7 @@ func NewCountersGroupFromEntity(counters *entity.CountersGroup) CountersGroup {
 		IncomingNo:  counters.IncomingNo,
 		OutgoingYes: counters.OutgoingYes,
 		OutgoingNo:  counters.OutgoingNo,
+		Matches:     counters.Matches,
 	}
 }
 
@@ -44,3 +49,33 @@ func CreateHourlyCountersGetResponseFromCountersGroup(counters map[uint8]*entity
 
 	return resp
 }
+
+type CountersSeriesPoint struct {
+	Start time.Time `json:"start" doc:"Start of the bucket"`
+	CountersGroup
+}
+
+type CountersSeries struct {
+	Granularity string                `json:"granularity" enum:"hour,day,week" doc:"Length of the buckets"`
+	Points      []CountersSeriesPoint `json:"points" doc:"One point per bucket of the range, oldest first, buckets without votes are zero"`
+}
+
+type CountersSeriesGetResponse struct {
+	Body CountersSeries
+}
+
+func CreateCountersSeriesGetResponse(granularity string, series []entity.CountersGroup) *CountersSeriesGetResponse {
+	points := make([]CountersSeriesPoint, 0, len(series))
+	for _, counters := range series {
+		points = append(points, CountersSeriesPoint{
+			Start:         time.Unix(int64(counters.HourUnixTimestamp), 0).UTC(),
+			CountersGroup: NewCountersGroupFromEntity(&counters),
+		})
+	}
+	return &CountersSeriesGetResponse{
+		Body: CountersSeries{
+			Granularity: granularity,
+			Points:      points,
+		},
+	}
+}
@@ -0,0 +1,15 @@
+package response
+
+import "github.com/google/uuid"
+
+type EligibilityCheckResponse struct {
+	Body struct {
+		EligiblePeerIds []uuid.UUID `json:"eligible_peer_ids" doc:"Candidates which can be shown to the active user, in the order of the request"`
+	}
+}
+
+func CreateEligibilityCheckResponse(eligiblePeerIds []uuid.UUID) *EligibilityCheckResponse {
+	resp := &EligibilityCheckResponse{}
+	resp.Body.EligiblePeerIds = eligiblePeerIds
+	return resp
+}
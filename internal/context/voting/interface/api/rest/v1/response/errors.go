@@ -2,41 +2,187 @@ package response
 
 import (
 	"errors"
+	"fmt"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/app/api/response"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
+	countersValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/idempotency"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	huma "github.com/danielgtaylor/huma/v2"
 	"net/http"
+	"time"
 )
 
-func ToApiError(err error) error {
+const (
+	CodeVoteNotFound          = "vote_not_found"
+	CodeVoteDuplicate         = "vote_duplicate"
+	CodeWrongVote             = "wrong_vote"
+	CodeVersionConflict       = "version_conflict"
+	CodeVersionMismatch       = "version_mismatch"
+	CodeStaleVote             = "stale_vote"
+	CodeVotedAtInFuture       = "voted_at_in_future"
+	CodeVotedAtTooOld         = "voted_at_too_old"
+	CodeRomanceBlocked        = "romance_blocked"
+	CodeNotMatched            = "not_matched"
+	CodeUnmatched             = "unmatched"
+	CodeInvalidUnmatchReason  = "invalid_unmatch_reason"
+	CodeNothingToRewind       = "nothing_to_rewind"
+	CodeInvalidHistoryCursor  = "invalid_history_cursor"
+	CodeInvalidRomancesCursor = "invalid_romances_cursor"
+	CodeInvalidRomanceState   = "invalid_romance_state"
+	CodeComplimentNotAllowed  = "compliment_not_allowed"
+	CodeComplimentNotFound    = "compliment_not_found"
+	CodeModerationStatus      = "invalid_moderation_status"
+	CodeInvalidIdentity       = "invalid_identity"
+	CodeInvalidHoursOffsets   = "invalid_hours_offsets"
+	CodeInvalidCountersRange  = "invalid_counters_range"
+	CodeIdempotencyKeyReused  = "idempotency_key_reused"
+	CodeRequestInProgress     = "request_in_progress"
+	CodeThrottled             = "throttled"
+	CodeVoteRateLimited       = "vote_rate_limited"
+	CodeQuotaExhausted        = "quota_exhausted"
+	CodeInvalidQuotas         = "invalid_quotas"
+	CodeUserBanned            = "user_banned"
+	CodeUserDeleted           = "user_deleted"
+	CodeVoteTypeNotEntitled   = "vote_type_not_entitled"
+	CodeUserCheckUnavailable  = "user_check_unavailable"
+	CodeScopeNotAllowed       = "scope_not_allowed"
+)
+
+const (
+	throttledRetryAfter   = time.Second
+	unavailableRetryAfter = 5 * time.Second
+)
+
+const internalErrorMessage = "internal server error"
+
+// ToApiError never lets raw infrastructure messages reach the client, errors which are
+// already mapped to a status pass through untouched.
+func ToApiError(err error, logger platform.Logger) error {
+	var statusErr huma.StatusError
+	var voteLimitErr *counter.VoteRateLimitError
+	var quotaErr *quota.QuotaExhaustedError
 	switch {
+	case errors.As(err, &statusErr):
+		return err
 	case errors.Is(err, romance.ErrVoteNotFound):
-		return NewErr404NotFound(err.Error())
+		return NewErr404NotFound(CodeVoteNotFound, err.Error())
 	case errors.Is(err, romance.ErrVoteDuplicate):
-		return NewErr400BadRequest(err.Error())
+		return NewErr400BadRequest(CodeVoteDuplicate, err.Error())
 	case errors.Is(err, romance.ErrWrongVote):
-		return NewErr400BadRequest(err.Error())
+		return NewErr400BadRequest(CodeWrongVote, err.Error())
+	case errors.Is(err, romance.ErrVersionConflict):
+		return NewErr409Conflict(CodeVersionConflict, err.Error())
+	case errors.Is(err, romance.ErrVersionMismatch):
+		return NewErr412PreconditionFailed(CodeVersionMismatch, err.Error())
+	case errors.Is(err, romance.ErrStaleVote):
+		return NewErr409Conflict(CodeStaleVote, err.Error())
+	case errors.Is(err, romance.ErrVotedAtInFuture):
+		return NewErr422UnprocessableEntity(CodeVotedAtInFuture, err.Error())
+	case errors.Is(err, romance.ErrVotedAtTooOld):
+		return NewErr422UnprocessableEntity(CodeVotedAtTooOld, err.Error())
+	case errors.Is(err, romance.ErrRomanceBlocked):
+		return NewErr403Forbidden(CodeRomanceBlocked, err.Error())
+	case errors.Is(err, romance.ErrNotMatched):
+		return NewErr409Conflict(CodeNotMatched, err.Error())
+	case errors.Is(err, romance.ErrUnmatched):
+		return NewErr403Forbidden(CodeUnmatched, err.Error())
+	case errors.Is(err, romance.ErrUnknownUnmatchReason):
+		return NewErr422UnprocessableEntity(CodeInvalidUnmatchReason, err.Error())
+	case errors.Is(err, romance.ErrInvalidHistoryCursor):
+		return NewErr422UnprocessableEntity(CodeInvalidHistoryCursor, err.Error())
+	case errors.Is(err, romance.ErrInvalidRomancesCursor):
+		return NewErr422UnprocessableEntity(CodeInvalidRomancesCursor, err.Error())
+	case errors.Is(err, romance.ErrUnknownRomanceState):
+		return NewErr422UnprocessableEntity(CodeInvalidRomanceState, err.Error())
+	case errors.Is(err, romance.ErrComplimentNotAllowed):
+		return NewErr422UnprocessableEntity(CodeComplimentNotAllowed, err.Error())
+	case errors.Is(err, romance.ErrComplimentNotFound):
+		return NewErr404NotFound(CodeComplimentNotFound, err.Error())
+	case errors.Is(err, romance.ErrModerationStatus):
+		return NewErr422UnprocessableEntity(CodeModerationStatus, err.Error())
+	case errors.Is(err, rewind.ErrNothingToRewind):
+		return NewErr404NotFound(CodeNothingToRewind, err.Error())
+	case errors.Is(err, rewind.ErrVersionConflict):
+		return NewErr409Conflict(CodeVersionConflict, err.Error())
+	case errors.As(err, &voteLimitErr):
+		return NewErr429TooManyRequests(CodeVoteRateLimited, err.Error()).
+			WithRetryAfter(time.Until(voteLimitErr.ResetAt))
+	case errors.As(err, &quotaErr):
+		return NewErr429TooManyRequests(CodeQuotaExhausted, err.Error()).
+			WithRetryAfter(time.Until(quotaErr.ResetAt))
+	case errors.Is(err, quota.ErrInvalidQuotas):
+		return NewErr422UnprocessableEntity(CodeInvalidQuotas, err.Error())
+	case errors.Is(err, user.ErrUserBanned):
+		return NewErr403Forbidden(CodeUserBanned, err.Error())
+	case errors.Is(err, user.ErrUserDeleted):
+		return NewErr403Forbidden(CodeUserDeleted, err.Error())
+	case errors.Is(err, user.ErrVoteTypeNotEntitled):
+		return NewErr403Forbidden(CodeVoteTypeNotEntitled, err.Error())
+	case errors.Is(err, user.ErrUserCheckUnavailable):
+		return NewErr503ServiceUnavailable(CodeUserCheckUnavailable, "user checks are temporarily unavailable").
+			WithRetryAfter(unavailableRetryAfter)
+	case errors.Is(err, sharedValueObject.ErrInvalidIdentity):
+		return NewErr422UnprocessableEntity(CodeInvalidIdentity, err.Error())
+	case errors.Is(err, countersValueObject.ErrInvalidHoursOffsets):
+		return NewErr422UnprocessableEntity(CodeInvalidHoursOffsets, err.Error())
+	case errors.Is(err, countersValueObject.ErrInvalidCountersRange):
+		return NewErr422UnprocessableEntity(CodeInvalidCountersRange, err.Error())
+	case errors.Is(err, idempotency.ErrKeyReused):
+		return NewErr422UnprocessableEntity(CodeIdempotencyKeyReused, err.Error())
+	case errors.Is(err, idempotency.ErrRequestInProgress):
+		return NewErr409Conflict(CodeRequestInProgress, err.Error())
+	case dynamodb.IsThrottlingError(err):
+		return NewErr429TooManyRequests(CodeThrottled, "request rate is too high, retry later").
+			WithRetryAfter(throttledRetryAfter)
+	case dynamodb.IsUnavailableError(err):
+		return NewErr503ServiceUnavailable(response.CodeServiceUnavailable, "storage is temporarily unavailable").
+			WithRetryAfter(unavailableRetryAfter)
 	default:
-		return err
+		// the response hides the cause, so it is only known from the log
+		logger.Error(fmt.Sprintf("Unexpected API error: %+v", err))
+		return NewErr500InternalServerError(response.CodeInternalError, internalErrorMessage)
 	}
 }
 
-func NewErr404NotFound(msg string) *response.HumaApiError {
-	return &response.HumaApiError{
-		Message: msg,
-		Status:  http.StatusNotFound,
-	}
+func NewErr404NotFound(code string, msg string) *response.HumaApiError {
+	return response.NewHumaApiError(http.StatusNotFound, code, msg)
 }
 
-func NewErr400BadRequest(msg string) *response.HumaApiError {
-	return &response.HumaApiError{
-		Message: msg,
-		Status:  http.StatusBadRequest,
-	}
+func NewErr400BadRequest(code string, msg string) *response.HumaApiError {
+	return response.NewHumaApiError(http.StatusBadRequest, code, msg)
 }
 
-func NewErr500InternalServerError(msg string) *response.HumaApiError {
-	return &response.HumaApiError{
-		Message: msg,
-		Status:  http.StatusInternalServerError,
-	}
+func NewErr403Forbidden(code string, msg string) *response.HumaApiError {
+	return response.NewHumaApiError(http.StatusForbidden, code, msg)
+}
+
+func NewErr409Conflict(code string, msg string) *response.HumaApiError {
+	return response.NewHumaApiError(http.StatusConflict, code, msg)
+}
+
+func NewErr412PreconditionFailed(code string, msg string) *response.HumaApiError {
+	return response.NewHumaApiError(http.StatusPreconditionFailed, code, msg)
+}
+
+func NewErr422UnprocessableEntity(code string, msg string) *response.HumaApiError {
+	return response.NewHumaApiError(http.StatusUnprocessableEntity, code, msg)
+}
+
+func NewErr429TooManyRequests(code string, msg string) *response.HumaApiError {
+	return response.NewHumaApiError(http.StatusTooManyRequests, code, msg)
+}
+
+func NewErr500InternalServerError(code string, msg string) *response.HumaApiError {
+	return response.NewHumaApiError(http.StatusInternalServerError, code, msg)
+}
+
+func NewErr503ServiceUnavailable(code string, msg string) *response.HumaApiError {
+	return response.NewHumaApiError(http.StatusServiceUnavailable, code, msg)
 }
@@ -0,0 +1,142 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	apiResponse "github.com/bmbl-bumble2/recs-votes-storage/internal/app/api/response"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
+	romanceValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/idempotency"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestToApiError(t *testing.T) {
+	_, invalidIdentityErr := sharedValueObject.NewVoteId(1, uuid.Nil, uuid.New())
+
+	testCases := []struct {
+		name       string
+		err        error
+		status     int
+		code       string
+		retryAfter string
+	}{
+		{name: "vote_not_found", err: romance.ErrVoteNotFound, status: http.StatusNotFound, code: CodeVoteNotFound},
+		{name: "vote_duplicate", err: romance.ErrVoteDuplicate, status: http.StatusBadRequest, code: CodeVoteDuplicate},
+		{name: "wrong_vote", err: romance.NewChangingVoteTypeError(1, 2), status: http.StatusBadRequest, code: CodeWrongVote},
+		{name: "version_conflict", err: romance.ErrVersionConflict, status: http.StatusConflict, code: CodeVersionConflict},
+		{name: "version_mismatch", err: romance.ErrVersionMismatch, status: http.StatusPreconditionFailed, code: CodeVersionMismatch},
+		{name: "stale_vote", err: romance.ErrStaleVote, status: http.StatusConflict, code: CodeStaleVote},
+		{name: "romance_blocked", err: romance.ErrRomanceBlocked, status: http.StatusForbidden, code: CodeRomanceBlocked},
+		{name: "not_matched", err: romance.ErrNotMatched, status: http.StatusConflict, code: CodeNotMatched},
+		{name: "unmatched", err: romance.ErrUnmatched, status: http.StatusForbidden, code: CodeUnmatched},
+		{name: "invalid_unmatch_reason", err: romance.ErrUnknownUnmatchReason, status: http.StatusUnprocessableEntity, code: CodeInvalidUnmatchReason},
+		{name: "invalid_history_cursor", err: romance.ErrInvalidHistoryCursor, status: http.StatusUnprocessableEntity, code: CodeInvalidHistoryCursor},
+		{name: "invalid_romances_cursor", err: romance.ErrInvalidRomancesCursor, status: http.StatusUnprocessableEntity, code: CodeInvalidRomancesCursor},
+		{name: "invalid_romance_state", err: romance.ErrUnknownRomanceState, status: http.StatusUnprocessableEntity, code: CodeInvalidRomanceState},
+		{name: "compliment_not_allowed", err: romance.ErrComplimentNotAllowed, status: http.StatusUnprocessableEntity, code: CodeComplimentNotAllowed},
+		{name: "compliment_not_found", err: romance.ErrComplimentNotFound, status: http.StatusNotFound, code: CodeComplimentNotFound},
+		{name: "invalid_moderation_status", err: romance.ErrModerationStatus, status: http.StatusUnprocessableEntity, code: CodeModerationStatus},
+		{name: "nothing_to_rewind", err: rewind.ErrNothingToRewind, status: http.StatusNotFound, code: CodeNothingToRewind},
+		{name: "last_votes_version_conflict", err: rewind.ErrVersionConflict, status: http.StatusConflict, code: CodeVersionConflict},
+		{name: "voted_at_in_future", err: fmt.Errorf("%w: ahead", romance.ErrVotedAtInFuture), status: http.StatusUnprocessableEntity, code: CodeVotedAtInFuture},
+		{name: "voted_at_too_old", err: fmt.Errorf("%w: behind", romance.ErrVotedAtTooOld), status: http.StatusUnprocessableEntity, code: CodeVotedAtTooOld},
+		{
+			name:       "vote_rate_limited",
+			err:        &counter.VoteRateLimitError{Counter: "yes", Window: counter.LimitWindowHour, Limit: 10, ResetAt: time.Now().Add(time.Minute)},
+			status:     http.StatusTooManyRequests,
+			code:       CodeVoteRateLimited,
+			retryAfter: "60",
+		},
+		{
+			name:       "quota_exhausted",
+			err:        &quota.QuotaExhaustedError{VoteType: romanceValueObject.VoteTypeCrush, Limit: 1, ResetAt: time.Now().Add(time.Hour)},
+			status:     http.StatusTooManyRequests,
+			code:       CodeQuotaExhausted,
+			retryAfter: "3600",
+		},
+		{
+			name:       "quota_reset_passed",
+			err:        &quota.QuotaExhaustedError{VoteType: romanceValueObject.VoteTypeCrush, Limit: 1, ResetAt: time.Now().Add(-time.Second)},
+			status:     http.StatusTooManyRequests,
+			code:       CodeQuotaExhausted,
+			retryAfter: "1",
+		},
+		{name: "invalid_quotas", err: quota.ErrInvalidQuotas, status: http.StatusUnprocessableEntity, code: CodeInvalidQuotas},
+		{name: "user_banned", err: user.ErrUserBanned, status: http.StatusForbidden, code: CodeUserBanned},
+		{name: "user_deleted", err: user.ErrUserDeleted, status: http.StatusForbidden, code: CodeUserDeleted},
+		{name: "vote_type_not_entitled", err: fmt.Errorf("%w: `crush`", user.ErrVoteTypeNotEntitled), status: http.StatusForbidden, code: CodeVoteTypeNotEntitled},
+		{
+			name:       "user_check_unavailable",
+			err:        fmt.Errorf("%w: timeout", user.ErrUserCheckUnavailable),
+			status:     http.StatusServiceUnavailable,
+			code:       CodeUserCheckUnavailable,
+			retryAfter: "5",
+		},
+		{name: "invalid_identity", err: invalidIdentityErr, status: http.StatusUnprocessableEntity, code: CodeInvalidIdentity},
+		{name: "idempotency_key_reused", err: idempotency.ErrKeyReused, status: http.StatusUnprocessableEntity, code: CodeIdempotencyKeyReused},
+		{name: "request_in_progress", err: idempotency.ErrRequestInProgress, status: http.StatusConflict, code: CodeRequestInProgress},
+		{
+			name:       "throttled",
+			err:        fmt.Errorf("put item: %w", &types.ProvisionedThroughputExceededException{}),
+			status:     http.StatusTooManyRequests,
+			code:       CodeThrottled,
+			retryAfter: "1",
+		},
+		{
+			name:       "unavailable",
+			err:        context.DeadlineExceeded,
+			status:     http.StatusServiceUnavailable,
+			code:       apiResponse.CodeServiceUnavailable,
+			retryAfter: "5",
+		},
+		{name: "unknown", err: errors.New("dial tcp 10.0.0.1: refused"), status: http.StatusInternalServerError, code: apiResponse.CodeInternalError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var apiErr *apiResponse.HumaApiError
+			require.ErrorAs(t, ToApiError(tc.err, discardLogger()), &apiErr)
+			assert.Equal(t, tc.status, apiErr.GetStatus())
+			assert.Equal(t, tc.code, apiErr.Code)
+			assert.Equal(t, http.StatusText(tc.status), apiErr.Title)
+			assert.Equal(t, tc.retryAfter, apiErr.GetHeaders().Get("Retry-After"))
+		})
+	}
+}
+
+func TestToApiErrorHidesInternalMessages(t *testing.T) {
+	logger := mocks.NewMockLogger(gomock.NewController(t))
+	logger.EXPECT().Error(gomock.Cond(func(msg string) bool {
+		return strings.Contains(msg, "dial tcp 10.0.0.1: refused")
+	}))
+
+	err := ToApiError(errors.New("dial tcp 10.0.0.1: refused"), logger)
+	assert.Equal(t, internalErrorMessage, err.Error())
+}
+
+func TestToApiErrorKeepsStatusErrors(t *testing.T) {
+	replayed := &idempotency.ReplayedError{Status: http.StatusBadRequest}
+	assert.Same(t, replayed, ToApiError(replayed, discardLogger()))
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
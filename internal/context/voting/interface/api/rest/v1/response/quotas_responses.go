@@ -0,0 +1,38 @@
+package response
+
+import (
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+)
+
+type Quota struct {
+	VoteType  string    `json:"vote_type" doc:"Premium vote type"`
+	Limit     *uint32   `json:"limit" doc:"Votes allowed per day, null when the vote type is not limited"`
+	Used      uint32    `json:"used" doc:"Votes used today"`
+	Remaining *uint32   `json:"remaining" doc:"Votes left today, null when the vote type is not limited"`
+	ResetAt   time.Time `json:"reset_at" doc:"Next midnight in the user time zone"`
+}
+
+type QuotasGetResponse struct {
+	Body struct {
+		TimeZone string  `json:"time_zone" doc:"Time zone of the user profile the quota day is taken in"`
+		Quotas   []Quota `json:"quotas"`
+	}
+}
+
+func CreateQuotasGetResponseFromQuotaEntities(quotas []entity.Quota, location *time.Location) *QuotasGetResponse {
+	resp := &QuotasGetResponse{}
+	resp.Body.TimeZone = location.String()
+	resp.Body.Quotas = make([]Quota, 0, len(quotas))
+	for _, quota := range quotas {
+		resp.Body.Quotas = append(resp.Body.Quotas, Quota{
+			VoteType:  quota.VoteType.String(),
+			Limit:     quota.Limit,
+			Used:      quota.Used,
+			Remaining: quota.Remaining(),
+			ResetAt:   quota.ResetAt.In(location),
+		})
+	}
+	return resp
+}
@@ -2,23 +2,165 @@ package response
 
 import (
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1/contract"
+	"github.com/google/uuid"
+	"time"
 )
 
 type Romance struct {
-	ActiveUserVote Vote `json:"active_user_vote" doc:"Active user vote"`
-	PeerUserVote   Vote `json:"peer_vote" doc:"Peer user vote"`
+	ActiveUserVote Vote     `json:"active_user_vote" doc:"Active user vote"`
+	PeerUserVote   Vote     `json:"peer_vote" doc:"Peer user vote"`
+	State          string   `json:"state" enum:"empty,outgoing_pending,incoming_pending,matched,rejected,mutually_rejected,blocked,unmatched" doc:"Romance state from the active user's perspective"`
+	Unmatch        *Unmatch `json:"unmatch,omitempty" doc:"Set when the match was ended by one of the users"`
+}
+
+func NewRomanceFromEntity(romance entity.Romance) Romance {
+	return Romance{
+		ActiveUserVote: NewVoteFromEntity(romance.ActiveUserVote),
+		PeerUserVote:   NewVoteFromEntity(romance.PeerUserVote),
+		State:          romance.State().String(),
+		Unmatch:        NewUnmatchFromEntity(romance.Unmatch),
+	}
+}
+
+type Unmatch struct {
+	UnmatchedBy       uuid.UUID `json:"unmatched_by" doc:"User who unmatched"`
+	UnmatchedAt       time.Time `json:"unmatched_at" doc:"Unmatch time"`
+	Reason            string    `json:"reason" doc:"Unmatch reason code"`
+	RevoteBannedUntil time.Time `json:"revote_banned_until" doc:"Votes of both users are rejected until this time"`
+}
+
+func NewUnmatchFromEntity(unmatch *entity.Unmatch) *Unmatch {
+	if unmatch == nil {
+		return nil
+	}
+	return &Unmatch{
+		UnmatchedBy:       unmatch.UnmatchedBy,
+		UnmatchedAt:       unmatch.UnmatchedAt,
+		Reason:            unmatch.Reason.String(),
+		RevoteBannedUntil: unmatch.RevoteBannedUntil,
+	}
 }
 
 type RomanceGetResponse struct {
+	ETag string `header:"ETag" doc:"Romance version, send it in If-Match to make vote changes conditional"`
 	Body Romance
 }
 
 func CreateRomanceGetResponseFromVoteEntity(vote entity.Romance) *RomanceGetResponse {
 	resp := &RomanceGetResponse{
-		Body: Romance{
-			ActiveUserVote: NewVoteFromEntity(vote.ActiveUserVote),
-			PeerUserVote:   NewVoteFromEntity(vote.PeerUserVote),
-		},
+		ETag: contract.RomanceETag(vote.Version),
+		Body: NewRomanceFromEntity(vote),
 	}
 	return resp
 }
+
+type RomancesList struct {
+	Romances   []Romance `json:"romances" doc:"Romances of the active user"`
+	NextCursor string    `json:"next_cursor,omitempty" doc:"Cursor of the next page, absent on the last page"`
+}
+
+type RomancesListResponse struct {
+	Body RomancesList
+}
+
+func CreateRomancesListResponse(page entity.RomancesPage) *RomancesListResponse {
+	romances := make([]Romance, 0, len(page.Romances))
+	for _, romance := range page.Romances {
+		romances = append(romances, NewRomanceFromEntity(romance))
+	}
+	return &RomancesListResponse{
+		Body: RomancesList{
+			Romances:   romances,
+			NextCursor: page.NextCursor,
+		},
+	}
+}
+
+type VoteHistoryEntry struct {
+	Actor          string                    `json:"actor" enum:"active_user,peer" doc:"User who wrote the vote, from the active user's perspective"`
+	ActorId        uuid.UUID                 `json:"actor_id" doc:"User who wrote the vote"`
+	Action         string                    `json:"action" enum:"add,change,delete,rewind,moderate,unmatch" doc:"What happened to the vote, an unmatch removes the votes of both users"`
+	FromVoteType   contract.ReadUserVoteType `json:"from_vote_type" doc:"Vote type before the write"`
+	ToVoteType     contract.ReadUserVoteType `json:"to_vote_type" doc:"Vote type after the write"`
+	VotedAt        *time.Time                `json:"voted_at,omitempty" doc:"Client time of the written vote"`
+	RecordedAt     time.Time                 `json:"recorded_at" doc:"Time the write was stored"`
+	RequestId      string                    `json:"request_id,omitempty" doc:"Request ID of the write"`
+	RomanceVersion uint32                    `json:"romance_version" doc:"Romance version produced by the write"`
+}
+
+type RomanceHistory struct {
+	Entries    []VoteHistoryEntry `json:"entries" doc:"History entries, newest first"`
+	NextCursor string             `json:"next_cursor,omitempty" doc:"Cursor of the next page, absent on the last page"`
+}
+
+type RomanceHistoryGetResponse struct {
+	Body RomanceHistory
+}
+
+func CreateRomanceHistoryGetResponse(activeUserId uuid.UUID, page entity.VoteHistoryPage) *RomanceHistoryGetResponse {
+	entries := make([]VoteHistoryEntry, 0, len(page.Entries))
+	for _, historyEntry := range page.Entries {
+		actor := "peer"
+		if historyEntry.ActorId == activeUserId {
+			actor = "active_user"
+		}
+		entries = append(entries, VoteHistoryEntry{
+			Actor:          actor,
+			ActorId:        historyEntry.ActorId,
+			Action:         historyEntry.Action.String(),
+			FromVoteType:   contract.ReadUserVoteType(historyEntry.FromVoteType),
+			ToVoteType:     contract.ReadUserVoteType(historyEntry.ToVoteType),
+			VotedAt:        historyEntry.VotedAt,
+			RecordedAt:     historyEntry.RecordedAt,
+			RequestId:      historyEntry.RequestId,
+			RomanceVersion: historyEntry.RomanceVersion,
+		})
+	}
+	return &RomanceHistoryGetResponse{
+		Body: RomanceHistory{
+			Entries:    entries,
+			NextCursor: page.NextCursor,
+		},
+	}
+}
+
+const ExclusionFilterContentType = "application/octet-stream"
+
+type ExclusionFilterGetResponse struct {
+	ContentType string `header:"Content-Type"`
+	Body        []byte
+}
+
+func CreateExclusionFilterGetResponse(filter []byte) *ExclusionFilterGetResponse {
+	return &ExclusionFilterGetResponse{
+		ContentType: ExclusionFilterContentType,
+		Body:        filter,
+	}
+}
+
+const RomancesExportContentType = "application/x-ndjson"
+
+// RomancesExportLine is one line of the export, it either holds a romance, the cursor to resume after
+// the romances written so far or the error which ended the export early
+type RomancesExportLine struct {
+	PeerId     *uuid.UUID `json:"peer_id,omitempty"`
+	Romance    *Romance   `json:"romance,omitempty"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// NewRomancesExportLines writes the romances of the page first and its cursor last, the last page
+// has no cursor line
+func NewRomancesExportLines(page entity.RomancesPage) []RomancesExportLine {
+	lines := make([]RomancesExportLine, 0, len(page.Romances)+1)
+	for _, romance := range page.Romances {
+		peerId := romance.ActiveUserVote.Id.PeerUserId()
+		exported := NewRomanceFromEntity(romance)
+		lines = append(lines, RomancesExportLine{PeerId: &peerId, Romance: &exported})
+	}
+	if page.NextCursor != "" {
+		lines = append(lines, RomancesExportLine{NextCursor: page.NextCursor})
+	}
+	return lines
+}
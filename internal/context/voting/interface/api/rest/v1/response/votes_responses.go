@@ -2,52 +2,123 @@ package response
 
 import (
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/interface/api/rest/v1/contract"
+	"github.com/google/uuid"
 	"time"
 )
 
 type Vote struct {
-	VoteType  contract.ReadUserVoteType `json:"vote_type"`
-	VotedAt   *time.Time                `json:"voted_at" doc:"Vote time"`
-	CreatedAt *time.Time                `json:"created_at" doc:"Vote creation time"`
-	UpdatedAt *time.Time                `json:"updated_at" doc:"Vote update time"`
+	VoteType   contract.ReadUserVoteType `json:"vote_type"`
+	VotedAt    *time.Time                `json:"voted_at" doc:"Vote time"`
+	CreatedAt  *time.Time                `json:"created_at" doc:"Vote creation time"`
+	UpdatedAt  *time.Time                `json:"updated_at" doc:"Vote update time"`
+	Context    *contract.VoteContext     `json:"context,omitempty" doc:"Recommendation the vote was cast on"`
+	Compliment *Compliment               `json:"compliment,omitempty" doc:"Message sent with the compliment vote, a peer's one is only returned once approved"`
+}
+
+type Compliment struct {
+	Id          uuid.UUID  `json:"id" format:"uuid" doc:"Compliment ID, moderation callbacks refer to it"`
+	Text        string     `json:"text"`
+	Status      string     `json:"status" enum:"pending,approved,rejected" doc:"Moderation status"`
+	ModeratedAt *time.Time `json:"moderated_at,omitempty" doc:"Time the moderation status was decided"`
+}
+
+func NewComplimentFromValueObject(compliment *valueobject.Compliment) *Compliment {
+	if compliment == nil {
+		return nil
+	}
+	return &Compliment{
+		Id:          compliment.Id,
+		Text:        compliment.Text,
+		Status:      compliment.Status.String(),
+		ModeratedAt: compliment.ModeratedAt,
+	}
 }
 
 func NewVoteFromEntity(vote entity.Vote) Vote {
 	return Vote{
-		VoteType:  contract.ReadUserVoteType(vote.VoteType),
-		VotedAt:   vote.VotedAt,
-		CreatedAt: vote.CreatedAt,
-		UpdatedAt: vote.UpdatedAt,
+		VoteType:   contract.ReadUserVoteType(vote.VoteType),
+		VotedAt:    vote.VotedAt,
+		CreatedAt:  vote.CreatedAt,
+		UpdatedAt:  vote.UpdatedAt,
+		Context:    contract.NewVoteContext(vote.Context),
+		Compliment: NewComplimentFromValueObject(vote.Compliment),
 	}
 }
 
 type VoteGetResponse struct {
+	ETag string `header:"ETag" doc:"Romance version, send it in If-Match to make vote changes conditional"`
 	Body Vote
 }
 
 type VoteAddResponse struct {
+	ETag string `header:"ETag" doc:"Romance version after the vote, send it in If-Match to make vote changes conditional"`
 	Body Vote
 }
 
 type ChangeVoteResponse struct {
+	ETag string `header:"ETag" doc:"Romance version after the change, send it in If-Match to make vote changes conditional"`
 	Body Vote
 }
 
-func CreateVoteGetResponseFromVoteEntity(vote entity.Vote) *VoteGetResponse {
+type DeleteVoteResponse struct {
+	ETag string `header:"ETag" doc:"Romance version after the deletion"`
+}
+
+type RewoundVote struct {
+	PeerId uuid.UUID `json:"peer_id" format:"uuid" doc:"Peer user ID of the rewound vote"`
+	Vote   Vote      `json:"vote" doc:"Restored vote, the vote type is empty when the rewound vote was the first one"`
+}
+
+type RewindVoteResponse struct {
+	ETag string `header:"ETag" doc:"Romance version after the rewind, send it in If-Match to make vote changes conditional"`
+	Body RewoundVote
+}
+
+type ModerateComplimentResponse struct {
+	Body Vote
+}
+
+func CreateVoteGetResponseFromVoteEntity(vote entity.Vote, romanceVersion uint32) *VoteGetResponse {
 	return &VoteGetResponse{
+		ETag: contract.RomanceETag(romanceVersion),
 		Body: NewVoteFromEntity(vote),
 	}
 }
 
-func CreateVoteAddResponseFromVoteEntity(vote entity.Vote) *VoteAddResponse {
+func CreateVoteAddResponseFromVoteEntity(vote entity.Vote, romanceVersion uint32) *VoteAddResponse {
 	return &VoteAddResponse{
+		ETag: contract.RomanceETag(romanceVersion),
 		Body: NewVoteFromEntity(vote),
 	}
 }
 
-func CreateChangeVoteResponseFromVoteEntity(vote entity.Vote) *ChangeVoteResponse {
+func CreateChangeVoteResponseFromVoteEntity(vote entity.Vote, romanceVersion uint32) *ChangeVoteResponse {
 	return &ChangeVoteResponse{
+		ETag: contract.RomanceETag(romanceVersion),
+		Body: NewVoteFromEntity(vote),
+	}
+}
+
+func CreateDeleteVoteResponse(romanceVersion uint32) *DeleteVoteResponse {
+	return &DeleteVoteResponse{
+		ETag: contract.RomanceETag(romanceVersion),
+	}
+}
+
+func CreateModerateComplimentResponseFromVoteEntity(vote entity.Vote) *ModerateComplimentResponse {
+	return &ModerateComplimentResponse{
 		Body: NewVoteFromEntity(vote),
 	}
 }
+
+func CreateRewindVoteResponseFromVoteEntity(vote entity.Vote, romanceVersion uint32) *RewindVoteResponse {
+	return &RewindVoteResponse{
+		ETag: contract.RomanceETag(romanceVersion),
+		Body: RewoundVote{
+			PeerId: vote.Id.PeerUserId(),
+			Vote:   NewVoteFromEntity(vote),
+		},
+	}
+}
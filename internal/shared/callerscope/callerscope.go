@@ -0,0 +1,54 @@
+package callerscope
+
+import (
+	"context"
+	"strings"
+)
+
+// Header is set by the gateway in front of the service, clients can not choose their scope
+const Header = "X-Caller-Scope"
+
+type Scope uint8
+
+const (
+	ScopeUser Scope = iota
+	ScopeInternal
+	ScopeAdmin
+)
+
+var ScopeToString = map[Scope]string{
+	ScopeUser:     "user",
+	ScopeInternal: "internal",
+	ScopeAdmin:    "admin",
+}
+
+type scopeKey struct{}
+
+// FromHeader falls back to the most restricted scope for a missing or unknown header
+func FromHeader(header string) Scope {
+	name := strings.ToLower(strings.TrimSpace(header))
+	for scope, scopeName := range ScopeToString {
+		if scopeName == name {
+			return scope
+		}
+	}
+	return ScopeUser
+}
+
+func ContextWithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+func FromContext(ctx context.Context) Scope {
+	scope, _ := ctx.Value(scopeKey{}).(Scope)
+	return scope
+}
+
+// IsUnrestricted tells if the caller may see the votes of both users as they are stored
+func (s Scope) IsUnrestricted() bool {
+	return s == ScopeInternal || s == ScopeAdmin
+}
+
+func (s Scope) String() string {
+	return ScopeToString[s]
+}
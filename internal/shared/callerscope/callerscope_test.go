@@ -0,0 +1,27 @@
+package callerscope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromHeader(t *testing.T) {
+	assert.Equal(t, ScopeAdmin, FromHeader("admin"))
+	assert.Equal(t, ScopeInternal, FromHeader(" Internal "))
+	for _, header := range []string{"", "user", "root"} {
+		assert.Equal(t, ScopeUser, FromHeader(header), header)
+	}
+}
+
+func TestContextWithScope(t *testing.T) {
+	assert.Equal(t, ScopeUser, FromContext(context.Background()))
+	assert.Equal(t, ScopeAdmin, FromContext(ContextWithScope(context.Background(), ScopeAdmin)))
+}
+
+func TestIsUnrestricted(t *testing.T) {
+	assert.False(t, ScopeUser.IsUnrestricted())
+	assert.True(t, ScopeInternal.IsUnrestricted())
+	assert.True(t, ScopeAdmin.IsUnrestricted())
+}
@@ -0,0 +1,153 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	platformDynamoDb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+)
+
+const (
+	KeysTableName       = "IdempotencyKeys"
+	KeyAttrName         = "k"
+	fingerprintAttrName = "f"
+	completedAttrName   = "c"
+	statusAttrName      = "s"
+	bodyAttrName        = "r"
+	lockedUntilAttrName = "l"
+)
+
+type idempotencyKeyDocumentSchema struct {
+	Key         string `dynamodbav:"k"`
+	Fingerprint string `dynamodbav:"f"`
+	Completed   bool   `dynamodbav:"c"`
+	Status      int    `dynamodbav:"s"`
+	Body        []byte `dynamodbav:"r"`
+	LockedUntil int64  `dynamodbav:"l"`
+}
+
+type DynamoDbStore struct {
+	dynamoDbClient platformDynamoDb.Client
+	logger         platform.Logger
+}
+
+func NewDynamoDbStore(
+	dynamoDbClient platformDynamoDb.Client,
+	logger platform.Logger,
+) *DynamoDbStore {
+	return &DynamoDbStore{
+		dynamoDbClient: dynamoDbClient,
+		logger:         logger,
+	}
+}
+
+func (s *DynamoDbStore) getKeysTableKey(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		KeyAttrName: &types.AttributeValueMemberS{Value: key},
+	}
+}
+
+// Reserve takes over keys whose lock is stale, so a crashed request does not block retries
+// until the record expires. TTL deletion is lazy, expired records are treated as missing.
+func (s *DynamoDbStore) Reserve(
+	ctx context.Context,
+	key string,
+	fingerprint string,
+	lockedUntil time.Time,
+	expiresAt time.Time,
+) (Record, bool, error) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	item := s.getKeysTableKey(key)
+	item[fingerprintAttrName] = &types.AttributeValueMemberS{Value: fingerprint}
+	item[completedAttrName] = &types.AttributeValueMemberBOOL{Value: false}
+	item[lockedUntilAttrName] = &types.AttributeValueMemberN{Value: strconv.FormatInt(lockedUntil.Unix(), 10)}
+	item[platformDynamoDb.TtlAttrName] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)}
+
+	_, err := s.dynamoDbClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(KeysTableName),
+		Item:      item,
+		ConditionExpression: aws.String(
+			"attribute_not_exists(#key) OR #ttl < :now OR (#completed = :false AND #lockedUntil < :now)",
+		),
+		ExpressionAttributeNames: map[string]string{
+			"#key":         KeyAttrName,
+			"#ttl":         platformDynamoDb.TtlAttrName,
+			"#completed":   completedAttrName,
+			"#lockedUntil": lockedUntilAttrName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now":   &types.AttributeValueMemberN{Value: now},
+			":false": &types.AttributeValueMemberBOOL{Value: false},
+		},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err == nil {
+		return Record{Fingerprint: fingerprint}, true, nil
+	}
+
+	var condCheckErr *types.ConditionalCheckFailedException
+	if !errors.As(err, &condCheckErr) {
+		return Record{}, false, err
+	}
+
+	existing := condCheckErr.Item
+	if len(existing) == 0 {
+		out, err := s.dynamoDbClient.GetItem(ctx, &dynamodb.GetItemInput{
+			Key:            s.getKeysTableKey(key),
+			TableName:      aws.String(KeysTableName),
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return Record{}, false, err
+		}
+		existing = out.Item
+	}
+
+	keyItem := &idempotencyKeyDocumentSchema{}
+	if err = attributevalue.UnmarshalMap(existing, keyItem); err != nil {
+		return Record{}, false, err
+	}
+
+	return Record{
+		Fingerprint: keyItem.Fingerprint,
+		Completed:   keyItem.Completed,
+		Status:      keyItem.Status,
+		Body:        keyItem.Body,
+	}, false, nil
+}
+
+func (s *DynamoDbStore) Complete(ctx context.Context, key string, record Record, expiresAt time.Time) error {
+	keyItem, err := attributevalue.MarshalMap(idempotencyKeyDocumentSchema{
+		Key:         key,
+		Fingerprint: record.Fingerprint,
+		Completed:   true,
+		Status:      record.Status,
+		Body:        record.Body,
+	})
+	if err != nil {
+		return err
+	}
+	keyItem[platformDynamoDb.TtlAttrName] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)}
+
+	_, err = s.dynamoDbClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(KeysTableName),
+		Item:      keyItem,
+	})
+	return err
+}
+
+func (s *DynamoDbStore) Release(ctx context.Context, key string) error {
+	_, err := s.dynamoDbClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		Key:       s.getKeysTableKey(key),
+		TableName: aws.String(KeysTableName),
+	})
+	return err
+}
@@ -0,0 +1,133 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/idempotency"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type DynamoDbStoreUnitTestSuite struct {
+	suite.Suite
+	dynamoDbClient *mocks.MockClient
+	store          *idempotency.DynamoDbStore
+}
+
+func TestDynamoDbStoreUnitSuite(t *testing.T) {
+	suite.Run(t, new(DynamoDbStoreUnitTestSuite))
+}
+
+func (s *DynamoDbStoreUnitTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.dynamoDbClient = mocks.NewMockClient(ctrl)
+	s.store = idempotency.NewDynamoDbStore(s.dynamoDbClient, mocks.NewMockLogger(ctrl))
+}
+
+func (s *DynamoDbStoreUnitTestSuite) TestReserveNewKey() {
+	ctx := context.Background()
+
+	s.dynamoDbClient.EXPECT().
+		PutItem(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, in *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+			s.Require().Equal(idempotency.KeysTableName, *in.TableName)
+			s.Require().Equal(&types.AttributeValueMemberS{Value: storeKey}, in.Item[idempotency.KeyAttrName])
+			s.Require().Equal(&types.AttributeValueMemberS{Value: "fp"}, in.Item["f"])
+			s.Require().NotNil(in.ConditionExpression)
+			return &dynamodb.PutItemOutput{}, nil
+		})
+
+	record, reserved, err := s.store.Reserve(ctx, storeKey, "fp", time.Now(), time.Now().Add(time.Hour))
+	s.Require().NoError(err)
+	s.Require().True(reserved)
+	s.Require().Equal(idempotency.Record{Fingerprint: "fp"}, record)
+}
+
+func (s *DynamoDbStoreUnitTestSuite) TestReserveTakenKeyReturnsExistingRecord() {
+	ctx := context.Background()
+
+	s.dynamoDbClient.EXPECT().
+		PutItem(ctx, gomock.Any()).
+		Return(nil, &types.ConditionalCheckFailedException{
+			Item: map[string]types.AttributeValue{
+				idempotency.KeyAttrName: &types.AttributeValueMemberS{Value: storeKey},
+				"f":                     &types.AttributeValueMemberS{Value: "fp"},
+				"c":                     &types.AttributeValueMemberBOOL{Value: true},
+				"s":                     &types.AttributeValueMemberN{Value: "400"},
+				"r":                     &types.AttributeValueMemberB{Value: []byte(`{"status":400}`)},
+			},
+		})
+
+	record, reserved, err := s.store.Reserve(ctx, storeKey, "fp", time.Now(), time.Now().Add(time.Hour))
+	s.Require().NoError(err)
+	s.Require().False(reserved)
+	s.Require().Equal("fp", record.Fingerprint)
+	s.Require().True(record.Completed)
+	s.Require().Equal(400, record.Status)
+	s.Require().JSONEq(`{"status":400}`, string(record.Body))
+}
+
+func (s *DynamoDbStoreUnitTestSuite) TestReserveTakenKeyWithoutOldItemReadsIt() {
+	ctx := context.Background()
+
+	s.dynamoDbClient.EXPECT().
+		PutItem(ctx, gomock.Any()).
+		Return(nil, &types.ConditionalCheckFailedException{})
+	s.dynamoDbClient.EXPECT().
+		GetItem(ctx, gomock.Any()).
+		Return(&dynamodb.GetItemOutput{
+			Item: map[string]types.AttributeValue{
+				"f": &types.AttributeValueMemberS{Value: "other"},
+				"c": &types.AttributeValueMemberBOOL{Value: false},
+			},
+		}, nil)
+
+	record, reserved, err := s.store.Reserve(ctx, storeKey, "fp", time.Now(), time.Now().Add(time.Hour))
+	s.Require().NoError(err)
+	s.Require().False(reserved)
+	s.Require().Equal(idempotency.Record{Fingerprint: "other"}, record)
+}
+
+func (s *DynamoDbStoreUnitTestSuite) TestReserveWithDbException() {
+	ctx := context.Background()
+	expectedErr := errors.New("throttled")
+
+	s.dynamoDbClient.EXPECT().
+		PutItem(ctx, gomock.Any()).
+		Return(nil, expectedErr)
+
+	_, _, err := s.store.Reserve(ctx, storeKey, "fp", time.Now(), time.Now().Add(time.Hour))
+	s.Require().ErrorIs(err, expectedErr)
+}
+
+func (s *DynamoDbStoreUnitTestSuite) TestComplete() {
+	ctx := context.Background()
+
+	s.dynamoDbClient.EXPECT().
+		PutItem(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, in *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+			s.Require().Equal(&types.AttributeValueMemberBOOL{Value: true}, in.Item["c"])
+			s.Require().Equal(&types.AttributeValueMemberB{Value: []byte(`{}`)}, in.Item["r"])
+			s.Require().Contains(in.Item, "ttl")
+			return &dynamodb.PutItemOutput{}, nil
+		})
+
+	err := s.store.Complete(ctx, storeKey, idempotency.Record{Fingerprint: "fp", Completed: true, Body: []byte(`{}`)}, time.Now())
+	s.Require().NoError(err)
+}
+
+func (s *DynamoDbStoreUnitTestSuite) TestRelease() {
+	ctx := context.Background()
+
+	s.dynamoDbClient.EXPECT().
+		DeleteItem(ctx, gomock.Any()).
+		Return(&dynamodb.DeleteItemOutput{}, nil)
+
+	s.Require().NoError(s.store.Release(ctx, storeKey))
+}
@@ -0,0 +1,169 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+)
+
+const HeaderName = "Idempotency-Key"
+
+const problemJsonContentType = "application/problem+json"
+
+type statusError interface {
+	error
+	GetStatus() int
+}
+
+// ReplayedError is the stored client error of the first request, it is written as it was.
+type ReplayedError struct {
+	Status int
+	Body   json.RawMessage
+}
+
+func (e *ReplayedError) Error() string {
+	return fmt.Sprintf("replayed response with status %d", e.Status)
+}
+
+func (e *ReplayedError) GetStatus() int {
+	return e.Status
+}
+
+func (e *ReplayedError) MarshalJSON() ([]byte, error) {
+	return e.Body, nil
+}
+
+func (e *ReplayedError) ContentType(ct string) string {
+	if ct == "application/json" {
+		return problemJsonContentType
+	}
+	return ct
+}
+
+type Guard struct {
+	store  Store
+	config config.IdempotencyConfig
+	logger platform.Logger
+}
+
+func NewGuard(store Store, config config.Config, logger platform.Logger) *Guard {
+	return &Guard{
+		store:  store,
+		config: config.Idempotency,
+		logger: logger,
+	}
+}
+
+// Execute runs fn once per key. Successful and client error responses are stored and replayed
+// for retries with the same request, server errors release the key so a retry runs fn again.
+// The scope keeps keys of different operations and users apart, request is the fingerprint source.
+func Execute[O any](
+	ctx context.Context,
+	g *Guard,
+	scope string,
+	key string,
+	request any,
+	fn func(ctx context.Context) (*O, error),
+) (*O, error) {
+	if key == "" {
+		return fn(ctx)
+	}
+
+	fingerprint, err := fingerprintOf(request)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	storeKey := strings.Join([]string{scope, key}, "#")
+	record, reserved, err := g.store.Reserve(ctx, storeKey, fingerprint, now.Add(g.config.LockTimeout), now.Add(g.config.Ttl))
+	if err != nil {
+		return nil, err
+	}
+
+	if !reserved {
+		return replay[O](record, fingerprint)
+	}
+
+	out, fnErr := fn(ctx)
+
+	completed, ok := completedRecord(fingerprint, out, fnErr)
+	if !ok {
+		if err := g.store.Release(ctx, storeKey); err != nil {
+			g.logger.Error(fmt.Sprintf("Unable to release idempotency key `%s`, %v", storeKey, err))
+		}
+		return out, fnErr
+	}
+
+	if err := g.store.Complete(ctx, storeKey, completed, now.Add(g.config.Ttl)); err != nil {
+		g.logger.Error(fmt.Sprintf("Unable to store response for idempotency key `%s`, %v", storeKey, err))
+	}
+	return out, fnErr
+}
+
+func replay[O any](record Record, fingerprint string) (*O, error) {
+	if record.Fingerprint != fingerprint {
+		return nil, ErrKeyReused
+	}
+
+	if !record.Completed {
+		return nil, ErrRequestInProgress
+	}
+
+	if record.Status != 0 {
+		return nil, &ReplayedError{Status: record.Status, Body: record.Body}
+	}
+
+	var out *O
+	if err := json.Unmarshal(record.Body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func completedRecord[O any](fingerprint string, out *O, fnErr error) (Record, bool) {
+	if fnErr == nil {
+		body, err := json.Marshal(out)
+		if err != nil {
+			return Record{}, false
+		}
+		return Record{Fingerprint: fingerprint, Completed: true, Body: body}, true
+	}
+
+	var statusErr statusError
+	if !errors.As(fnErr, &statusErr) || isTransientStatus(statusErr.GetStatus()) {
+		return Record{}, false
+	}
+
+	body, err := json.Marshal(statusErr)
+	if err != nil {
+		return Record{}, false
+	}
+	return Record{Fingerprint: fingerprint, Completed: true, Status: statusErr.GetStatus(), Body: body}, true
+}
+
+// isTransientStatus marks responses a retry may change, they release the key instead of being replayed
+func isTransientStatus(status int) bool {
+	return status >= http.StatusInternalServerError ||
+		status == http.StatusConflict ||
+		status == http.StatusTooManyRequests
+}
+
+func fingerprintOf(request any) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
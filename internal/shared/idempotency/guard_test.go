@@ -0,0 +1,263 @@
+package idempotency_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/idempotency"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testScope = "add-vote#11#user"
+	testKey   = "key-1"
+	storeKey  = testScope + "#" + testKey
+)
+
+type testRequest struct {
+	VoteType int `json:"vote_type"`
+}
+
+type testResponse struct {
+	Body struct {
+		VoteType int `json:"vote_type"`
+	}
+}
+
+type testStatusError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+func (e *testStatusError) Error() string {
+	return e.Message
+}
+
+func (e *testStatusError) GetStatus() int {
+	return e.Status
+}
+
+type GuardUnitTestSuite struct {
+	suite.Suite
+	store  *mocks.MockStore
+	logger *mocks.MockLogger
+	guard  *idempotency.Guard
+}
+
+func TestGuardUnitSuite(t *testing.T) {
+	suite.Run(t, new(GuardUnitTestSuite))
+}
+
+func (s *GuardUnitTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.store = mocks.NewMockStore(ctrl)
+	s.logger = mocks.NewMockLogger(ctrl)
+	s.guard = idempotency.NewGuard(s.store, config.Config{
+		Idempotency: config.IdempotencyConfig{Ttl: time.Hour, LockTimeout: time.Minute},
+	}, s.logger)
+}
+
+func (s *GuardUnitTestSuite) TestWithoutKeyRunsHandler() {
+	calls := 0
+	out, err := idempotency.Execute(context.Background(), s.guard, testScope, "", testRequest{}, s.handler(&calls, nil))
+
+	s.Require().NoError(err)
+	s.Require().Equal(2, out.Body.VoteType)
+	s.Require().Equal(1, calls)
+}
+
+func (s *GuardUnitTestSuite) TestFirstRequestStoresResponse() {
+	ctx := context.Background()
+	fingerprint := s.fingerprint(testRequest{VoteType: 2})
+
+	s.store.EXPECT().
+		Reserve(ctx, storeKey, fingerprint, gomock.Any(), gomock.Any()).
+		Return(idempotency.Record{Fingerprint: fingerprint}, true, nil)
+	s.store.EXPECT().
+		Complete(ctx, storeKey, idempotency.Record{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Body:        json.RawMessage(`{"Body":{"vote_type":2}}`),
+		}, gomock.Any()).
+		Return(nil)
+
+	calls := 0
+	out, err := idempotency.Execute(ctx, s.guard, testScope, testKey, testRequest{VoteType: 2}, s.handler(&calls, nil))
+
+	s.Require().NoError(err)
+	s.Require().Equal(2, out.Body.VoteType)
+	s.Require().Equal(1, calls)
+}
+
+func (s *GuardUnitTestSuite) TestRetryReplaysStoredResponse() {
+	ctx := context.Background()
+	fingerprint := s.fingerprint(testRequest{VoteType: 2})
+
+	s.store.EXPECT().
+		Reserve(ctx, storeKey, fingerprint, gomock.Any(), gomock.Any()).
+		Return(idempotency.Record{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Body:        json.RawMessage(`{"Body":{"vote_type":2}}`),
+		}, false, nil)
+
+	calls := 0
+	out, err := idempotency.Execute(ctx, s.guard, testScope, testKey, testRequest{VoteType: 2}, s.handler(&calls, nil))
+
+	s.Require().NoError(err)
+	s.Require().Equal(2, out.Body.VoteType)
+	s.Require().Zero(calls)
+}
+
+func (s *GuardUnitTestSuite) TestRetryReplaysStoredClientError() {
+	ctx := context.Background()
+	fingerprint := s.fingerprint(testRequest{VoteType: 2})
+	body := json.RawMessage(`{"status":400,"message":"vote already exists"}`)
+
+	s.store.EXPECT().
+		Reserve(ctx, storeKey, fingerprint, gomock.Any(), gomock.Any()).
+		Return(idempotency.Record{Fingerprint: fingerprint, Completed: true, Status: http.StatusBadRequest, Body: body}, false, nil)
+
+	calls := 0
+	_, err := idempotency.Execute(ctx, s.guard, testScope, testKey, testRequest{VoteType: 2}, s.handler(&calls, nil))
+
+	var replayed *idempotency.ReplayedError
+	s.Require().ErrorAs(err, &replayed)
+	s.Require().Equal(http.StatusBadRequest, replayed.GetStatus())
+	marshaled, marshalErr := json.Marshal(replayed)
+	s.Require().NoError(marshalErr)
+	s.Require().JSONEq(string(body), string(marshaled))
+	s.Require().Zero(calls)
+}
+
+func (s *GuardUnitTestSuite) TestKeyReusedWithDifferentPayload() {
+	ctx := context.Background()
+
+	s.store.EXPECT().
+		Reserve(ctx, storeKey, s.fingerprint(testRequest{VoteType: 3}), gomock.Any(), gomock.Any()).
+		Return(idempotency.Record{Fingerprint: s.fingerprint(testRequest{VoteType: 2}), Completed: true}, false, nil)
+
+	calls := 0
+	_, err := idempotency.Execute(ctx, s.guard, testScope, testKey, testRequest{VoteType: 3}, s.handler(&calls, nil))
+
+	s.Require().ErrorIs(err, idempotency.ErrKeyReused)
+	s.Require().Zero(calls)
+}
+
+func (s *GuardUnitTestSuite) TestConcurrentRequestInProgress() {
+	ctx := context.Background()
+	fingerprint := s.fingerprint(testRequest{VoteType: 2})
+
+	s.store.EXPECT().
+		Reserve(ctx, storeKey, fingerprint, gomock.Any(), gomock.Any()).
+		Return(idempotency.Record{Fingerprint: fingerprint}, false, nil)
+
+	calls := 0
+	_, err := idempotency.Execute(ctx, s.guard, testScope, testKey, testRequest{VoteType: 2}, s.handler(&calls, nil))
+
+	s.Require().ErrorIs(err, idempotency.ErrRequestInProgress)
+	s.Require().Zero(calls)
+}
+
+func (s *GuardUnitTestSuite) TestClientErrorIsStored() {
+	ctx := context.Background()
+	fingerprint := s.fingerprint(testRequest{VoteType: 2})
+	handlerErr := &testStatusError{Status: http.StatusNotFound, Message: "vote not found"}
+
+	s.store.EXPECT().
+		Reserve(ctx, storeKey, fingerprint, gomock.Any(), gomock.Any()).
+		Return(idempotency.Record{Fingerprint: fingerprint}, true, nil)
+	s.store.EXPECT().
+		Complete(ctx, storeKey, idempotency.Record{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      http.StatusNotFound,
+			Body:        json.RawMessage(`{"status":404,"message":"vote not found"}`),
+		}, gomock.Any()).
+		Return(nil)
+
+	calls := 0
+	_, err := idempotency.Execute(ctx, s.guard, testScope, testKey, testRequest{VoteType: 2}, s.handler(&calls, handlerErr))
+
+	s.Require().ErrorIs(err, handlerErr)
+}
+
+func (s *GuardUnitTestSuite) TestServerErrorReleasesKey() {
+	ctx := context.Background()
+	fingerprint := s.fingerprint(testRequest{VoteType: 2})
+	handlerErr := errors.New("dynamodb unavailable")
+
+	s.store.EXPECT().
+		Reserve(ctx, storeKey, fingerprint, gomock.Any(), gomock.Any()).
+		Return(idempotency.Record{Fingerprint: fingerprint}, true, nil)
+	s.store.EXPECT().Release(ctx, storeKey).Return(nil)
+
+	calls := 0
+	_, err := idempotency.Execute(ctx, s.guard, testScope, testKey, testRequest{VoteType: 2}, s.handler(&calls, handlerErr))
+
+	s.Require().ErrorIs(err, handlerErr)
+}
+
+func (s *GuardUnitTestSuite) TestStoreFailureKeepsResponse() {
+	ctx := context.Background()
+	fingerprint := s.fingerprint(testRequest{VoteType: 2})
+
+	s.store.EXPECT().
+		Reserve(ctx, storeKey, fingerprint, gomock.Any(), gomock.Any()).
+		Return(idempotency.Record{Fingerprint: fingerprint}, true, nil)
+	s.store.EXPECT().
+		Complete(ctx, storeKey, gomock.Any(), gomock.Any()).
+		Return(errors.New("throttled"))
+	s.logger.EXPECT().Error(gomock.Any())
+
+	calls := 0
+	out, err := idempotency.Execute(ctx, s.guard, testScope, testKey, testRequest{VoteType: 2}, s.handler(&calls, nil))
+
+	s.Require().NoError(err)
+	s.Require().Equal(2, out.Body.VoteType)
+}
+
+func (s *GuardUnitTestSuite) handler(calls *int, err error) func(ctx context.Context) (*testResponse, error) {
+	return func(ctx context.Context) (*testResponse, error) {
+		*calls++
+		if err != nil {
+			return nil, err
+		}
+		out := &testResponse{}
+		out.Body.VoteType = 2
+		return out, nil
+	}
+}
+
+func (s *GuardUnitTestSuite) fingerprint(request any) string {
+	data, err := json.Marshal(request)
+	s.Require().NoError(err)
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *GuardUnitTestSuite) TestThrottledErrorReleasesKey() {
+	ctx := context.Background()
+	fingerprint := s.fingerprint(testRequest{VoteType: 2})
+	handlerErr := &testStatusError{Status: http.StatusTooManyRequests, Message: "throttled"}
+
+	s.store.EXPECT().
+		Reserve(ctx, storeKey, fingerprint, gomock.Any(), gomock.Any()).
+		Return(idempotency.Record{Fingerprint: fingerprint}, true, nil)
+	s.store.EXPECT().Release(ctx, storeKey).Return(nil)
+
+	calls := 0
+	_, err := idempotency.Execute(ctx, s.guard, testScope, testKey, testRequest{VoteType: 2}, s.handler(&calls, handlerErr))
+
+	s.Require().ErrorIs(err, handlerErr)
+}
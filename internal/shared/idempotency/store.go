@@ -0,0 +1,30 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrKeyReused         = errors.New("idempotency key was already used with a different request")
+	ErrRequestInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+type Record struct {
+	Fingerprint string
+	Completed   bool
+	// Status is zero for successful responses, the handler default status is used on replay
+	Status int
+	Body   json.RawMessage
+}
+
+//go:generate mockgen -destination=../../testlib/mocks/idempotency_store_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/shared/idempotency Store
+type Store interface {
+	// Reserve locks the key for the request. When the key is already taken it returns
+	// the existing record and false.
+	Reserve(ctx context.Context, key string, fingerprint string, lockedUntil time.Time, expiresAt time.Time) (Record, bool, error)
+	Complete(ctx context.Context, key string, record Record, expiresAt time.Time) error
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,108 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+)
+
+const (
+	CloudEventsSpecVersion = "1.0"
+	CloudEventsTypePrefix  = "com.bumble.recs."
+)
+
+// cloudEvent is a CloudEvents 1.0 event in the structured JSON mode,
+// the trace context uses the distributed tracing extension attribute names.
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	Id              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            *time.Time      `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype"`
+	SchemaVersion   uint16          `json:"schemaversion,omitempty"`
+	TraceParent     string          `json:"traceparent,omitempty"`
+	TraceState      string          `json:"tracestate,omitempty"`
+	Data            json.RawMessage `json:"data"`
+}
+
+type cloudEventsCodec struct{}
+
+func (c cloudEventsCodec) Encoding() Encoding {
+	return EncodingCloudEvents
+}
+
+func (c cloudEventsCodec) ContentType() string {
+	return "application/cloudevents+json"
+}
+
+func (c cloudEventsCodec) MarshalBody(body any) ([]byte, error) {
+	return json.Marshal(body)
+}
+
+func (c cloudEventsCodec) UnmarshalBody(data []byte, body any) error {
+	return json.Unmarshal(data, body)
+}
+
+func (c cloudEventsCodec) Encode(envelope Envelope) (messaging.Payload, error) {
+	if envelope.Id == "" || envelope.Producer == "" || envelope.Name == "" {
+		return nil, errors.New("cloudevents require id, producer and name")
+	}
+
+	return json.Marshal(cloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		Id:              envelope.Id,
+		Source:          envelope.Producer,
+		Type:            CloudEventsTypePrefix + envelope.Name,
+		Time:            envelope.ProducedAt,
+		DataContentType: Json.ContentType(),
+		SchemaVersion:   envelope.SchemaVersion,
+		TraceParent:     envelope.TraceParent,
+		TraceState:      envelope.TraceState,
+		Data:            envelope.Body,
+	})
+}
+
+func (c cloudEventsCodec) Decode(payload messaging.Payload) (Envelope, error) {
+	var event cloudEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Envelope{}, err
+	}
+
+	if event.SpecVersion != CloudEventsSpecVersion {
+		return Envelope{}, fmt.Errorf("unsupported cloudevents specversion %q", event.SpecVersion)
+	}
+
+	name, ok := strings.CutPrefix(event.Type, CloudEventsTypePrefix)
+	if !ok {
+		return Envelope{}, fmt.Errorf("unexpected cloudevents type %q", event.Type)
+	}
+
+	if isEmptyJson(event.Data) {
+		return Envelope{}, ErrEmptyBody
+	}
+
+	return Envelope{
+		Metadata: messaging.Metadata{
+			Id:            event.Id,
+			ProducedAt:    event.Time,
+			Producer:      event.Source,
+			SchemaVersion: event.SchemaVersion,
+			TraceContext: messaging.TraceContext{
+				TraceParent: event.TraceParent,
+				TraceState:  event.TraceState,
+			},
+		},
+		Name: name,
+		Body: event.Data,
+	}, nil
+}
+
+func (c cloudEventsCodec) Recognizes(payload messaging.Payload) bool {
+	probe, ok := probeJson(payload)
+	return ok && probe.SpecVersion != nil
+}
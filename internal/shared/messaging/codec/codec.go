@@ -0,0 +1,127 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"google.golang.org/protobuf/proto"
+)
+
+type Encoding string
+
+const (
+	EncodingJson        Encoding = "json"
+	EncodingCloudEvents Encoding = "cloudevents"
+	EncodingProtobuf    Encoding = "protobuf"
+)
+
+var (
+	ErrEmptyBody       = errors.New("empty message body")
+	ErrUnknownEncoding = errors.New("unknown encoding")
+	ErrUnknownPayload  = errors.New("payload does not match any supported encoding")
+)
+
+var (
+	Json        Codec = jsonCodec{}
+	CloudEvents Codec = cloudEventsCodec{}
+	Protobuf    Codec = protobufCodec{}
+)
+
+// detectionOrder matters: CloudEvents and JSON envelopes are both JSON objects,
+// protobuf payloads are checked last as the most expensive to recognize.
+var detectionOrder = []Codec{CloudEvents, Json, Protobuf}
+
+// Envelope is the encoding independent representation of a message.
+// Body holds the message itself already marshaled with the codec's MarshalBody.
+type Envelope struct {
+	messaging.Metadata
+	Name string
+	Body []byte
+}
+
+// Codec turns an Envelope into a transport payload and back.
+type Codec interface {
+	Encoding() Encoding
+	ContentType() string
+	MarshalBody(body any) ([]byte, error)
+	UnmarshalBody(data []byte, body any) error
+	Encode(envelope Envelope) (messaging.Payload, error)
+	Decode(payload messaging.Payload) (Envelope, error)
+	Recognizes(payload messaging.Payload) bool
+}
+
+// ProtoBody is implemented by messages which can be published with the protobuf encoding.
+type ProtoBody interface {
+	MarshalProto() ([]byte, error)
+	UnmarshalProto(data []byte) error
+}
+
+// Encodable is implemented by messages which can be published with any codec.
+type Encodable interface {
+	messaging.Message
+	Encode(codec Codec) (messaging.Payload, error)
+}
+
+func ByEncoding(encoding Encoding) (Codec, error) {
+	for _, c := range detectionOrder {
+		if c.Encoding() == encoding {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownEncoding, encoding)
+}
+
+// Detect finds the codec a payload was encoded with, so consumers keep working
+// while a topic is being migrated from one encoding to another.
+func Detect(payload messaging.Payload) (Codec, error) {
+	for _, c := range detectionOrder {
+		if c.Recognizes(payload) {
+			return c, nil
+		}
+	}
+	return nil, ErrUnknownPayload
+}
+
+func DecodeMetadata(payload messaging.Payload) (messaging.Metadata, error) {
+	c, err := Detect(payload)
+	if err != nil {
+		return messaging.Metadata{}, err
+	}
+
+	envelope, err := c.Decode(payload)
+	if err != nil {
+		return messaging.Metadata{}, err
+	}
+
+	return envelope.Metadata.WithDefaults(), nil
+}
+
+func MarshalProto(m proto.Message) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(m)
+}
+
+type TopicCodecs struct {
+	byTopic map[messaging.Topic]Codec
+}
+
+// NewTopicCodecs maps topic names to encoding names, topics without an explicit encoding use JSON.
+func NewTopicCodecs(topicEncodings map[string]string) (TopicCodecs, error) {
+	byTopic := make(map[messaging.Topic]Codec, len(topicEncodings))
+	for topic, encoding := range topicEncodings {
+		c, err := ByEncoding(Encoding(encoding))
+		if err != nil {
+			return TopicCodecs{}, fmt.Errorf("topic %q: %w", topic, err)
+		}
+		byTopic[messaging.Topic(topic)] = c
+	}
+
+	return TopicCodecs{byTopic: byTopic}, nil
+}
+
+func (t TopicCodecs) ForTopic(topic messaging.Topic) Codec {
+	if c, ok := t.byTopic[topic]; ok {
+		return c
+	}
+	return Json
+}
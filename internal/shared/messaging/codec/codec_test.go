@@ -0,0 +1,166 @@
+package codec
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func testEnvelope(body []byte) Envelope {
+	producedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return Envelope{
+		Metadata: messaging.Metadata{
+			Id:            "b7b7d3c4-4f4e-4d8c-9d6c-0b6f6f0e2a11",
+			ProducedAt:    &producedAt,
+			Producer:      "recs-votes-storage",
+			SchemaVersion: 2,
+			TraceContext:  messaging.NewTraceContext(validTraceParent, "vendor=value"),
+		},
+		Name: "del_romances",
+		Body: body,
+	}
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	testCases := []struct {
+		codec Codec
+		body  []byte
+	}{
+		{codec: Json, body: []byte(`{"country_id":1}`)},
+		{codec: CloudEvents, body: []byte(`{"country_id":1}`)},
+		{codec: Protobuf, body: []byte{0x10, 0x01}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.codec.Encoding()), func(t *testing.T) {
+			envelope := testEnvelope(tc.body)
+
+			payload, err := tc.codec.Encode(envelope)
+			require.NoError(t, err)
+
+			detected, err := Detect(payload)
+			require.NoError(t, err)
+			assert.Equal(t, tc.codec.Encoding(), detected.Encoding())
+
+			decoded, err := tc.codec.Decode(payload)
+			require.NoError(t, err)
+			assert.Equal(t, envelope, decoded)
+		})
+	}
+}
+
+func TestCloudEvents_EncodesRequiredAttributes(t *testing.T) {
+	payload, err := CloudEvents.Encode(testEnvelope([]byte(`{}`)))
+	require.NoError(t, err)
+
+	var event map[string]any
+	require.NoError(t, json.Unmarshal(payload, &event))
+	assert.Equal(t, "1.0", event["specversion"])
+	assert.Equal(t, "b7b7d3c4-4f4e-4d8c-9d6c-0b6f6f0e2a11", event["id"])
+	assert.Equal(t, "recs-votes-storage", event["source"])
+	assert.Equal(t, CloudEventsTypePrefix+"del_romances", event["type"])
+	assert.Equal(t, "2025-01-02T03:04:05Z", event["time"])
+	assert.Equal(t, "application/json", event["datacontenttype"])
+	assert.Equal(t, float64(2), event["schemaversion"])
+	assert.Equal(t, validTraceParent, event["traceparent"])
+}
+
+func TestCloudEvents_RejectsMissingAttributes(t *testing.T) {
+	envelope := testEnvelope([]byte(`{}`))
+	envelope.Producer = ""
+
+	_, err := CloudEvents.Encode(envelope)
+	assert.Error(t, err)
+}
+
+func TestCloudEvents_RejectsForeignEvents(t *testing.T) {
+	_, err := CloudEvents.Decode(messaging.Payload(`{"specversion":"0.3","type":"com.bumble.recs.del_romances","data":{}}`))
+	assert.ErrorContains(t, err, "specversion")
+
+	_, err = CloudEvents.Decode(messaging.Payload(`{"specversion":"1.0","type":"com.example.del_romances","data":{}}`))
+	assert.ErrorContains(t, err, "type")
+}
+
+func TestCodecs_EmptyBody(t *testing.T) {
+	for _, c := range []Codec{Json, CloudEvents, Protobuf} {
+		t.Run(string(c.Encoding()), func(t *testing.T) {
+			payload, err := c.Encode(testEnvelope(nil))
+			require.NoError(t, err)
+
+			_, err = c.Decode(payload)
+			assert.ErrorIs(t, err, ErrEmptyBody)
+		})
+	}
+}
+
+func TestProtobuf_RequiresProtoBody(t *testing.T) {
+	_, err := Protobuf.MarshalBody(struct{}{})
+	assert.Error(t, err)
+
+	assert.Error(t, Protobuf.UnmarshalBody([]byte{}, &struct{}{}))
+}
+
+func TestDetect_UnknownPayload(t *testing.T) {
+	for _, payload := range []string{"", "{", "Data", `{"country_id":1}`} {
+		_, err := Detect(messaging.Payload(payload))
+		assert.ErrorIs(t, err, ErrUnknownPayload, payload)
+	}
+}
+
+func TestDecodeMetadata_LegacyPayload(t *testing.T) {
+	payload := messaging.Payload(`{"name":"del_romances","message":{"country_id":1}}`)
+
+	metadata, err := DecodeMetadata(payload)
+
+	require.NoError(t, err)
+	assert.Equal(t, messaging.Metadata{SchemaVersion: messaging.DefaultSchemaVersion}, metadata)
+	assert.Equal(t, time.Duration(0), metadata.Age(time.Now()))
+}
+
+func TestDecodeMetadata_AllEncodings(t *testing.T) {
+	for _, c := range []Codec{Json, CloudEvents, Protobuf} {
+		t.Run(string(c.Encoding()), func(t *testing.T) {
+			envelope := testEnvelope([]byte{0x10, 0x01})
+			if c != Protobuf {
+				envelope.Body = []byte(`{}`)
+			}
+			payload, err := c.Encode(envelope)
+			require.NoError(t, err)
+
+			metadata, err := DecodeMetadata(payload)
+
+			require.NoError(t, err)
+			assert.Equal(t, envelope.Metadata, metadata)
+			assert.Equal(t, time.Hour, metadata.Age(envelope.ProducedAt.Add(time.Hour)))
+		})
+	}
+}
+
+func TestDecodeMetadata_InvalidPayload(t *testing.T) {
+	_, err := DecodeMetadata(messaging.Payload("{"))
+	assert.Error(t, err)
+}
+
+func TestNewTopicCodecs(t *testing.T) {
+	topicCodecs, err := NewTopicCodecs(map[string]string{
+		"a.fifo": "protobuf",
+		"b.fifo": "cloudevents",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, Protobuf, topicCodecs.ForTopic("a.fifo"))
+	assert.Equal(t, CloudEvents, topicCodecs.ForTopic("b.fifo"))
+	assert.Equal(t, Json, topicCodecs.ForTopic("c.fifo"))
+}
+
+func TestNewTopicCodecs_UnknownEncoding(t *testing.T) {
+	_, err := NewTopicCodecs(map[string]string{"a.fifo": "avro"})
+	assert.ErrorIs(t, err, ErrUnknownEncoding)
+	assert.ErrorContains(t, err, "a.fifo")
+}
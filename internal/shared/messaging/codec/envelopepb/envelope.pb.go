@@ -0,0 +1,193 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: internal/shared/messaging/codec/envelopepb/envelope.proto
+
+package envelopepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Envelope wraps every message published with the protobuf encoding.
+type Envelope struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProducedAt    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=produced_at,json=producedAt,proto3" json:"produced_at,omitempty"`
+	Producer      string                 `protobuf:"bytes,3,opt,name=producer,proto3" json:"producer,omitempty"`
+	SchemaVersion uint32                 `protobuf:"varint,4,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Traceparent   string                 `protobuf:"bytes,5,opt,name=traceparent,proto3" json:"traceparent,omitempty"`
+	Tracestate    string                 `protobuf:"bytes,6,opt,name=tracestate,proto3" json:"tracestate,omitempty"`
+	Name          string                 `protobuf:"bytes,7,opt,name=name,proto3" json:"name,omitempty"`
+	// Protobuf encoded message, its type is defined by name and schema_version.
+	Message       []byte `protobuf:"bytes,8,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Envelope) Reset() {
+	*x = Envelope{}
+	mi := &file_internal_shared_messaging_codec_envelopepb_envelope_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Envelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Envelope) ProtoMessage() {}
+
+func (x *Envelope) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_shared_messaging_codec_envelopepb_envelope_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Envelope.ProtoReflect.Descriptor instead.
+func (*Envelope) Descriptor() ([]byte, []int) {
+	return file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Envelope) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Envelope) GetProducedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProducedAt
+	}
+	return nil
+}
+
+func (x *Envelope) GetProducer() string {
+	if x != nil {
+		return x.Producer
+	}
+	return ""
+}
+
+func (x *Envelope) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *Envelope) GetTraceparent() string {
+	if x != nil {
+		return x.Traceparent
+	}
+	return ""
+}
+
+func (x *Envelope) GetTracestate() string {
+	if x != nil {
+		return x.Tracestate
+	}
+	return ""
+}
+
+func (x *Envelope) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Envelope) GetMessage() []byte {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+var File_internal_shared_messaging_codec_envelopepb_envelope_proto protoreflect.FileDescriptor
+
+const file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDesc = "" +
+	"\n" +
+	"9internal/shared/messaging/codec/envelopepb/envelope.proto\x12\x1frecs.votes_storage.messaging.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8a\x02\n" +
+	"\bEnvelope\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12;\n" +
+	"\vproduced_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"producedAt\x12\x1a\n" +
+	"\bproducer\x18\x03 \x01(\tR\bproducer\x12%\n" +
+	"\x0eschema_version\x18\x04 \x01(\rR\rschemaVersion\x12 \n" +
+	"\vtraceparent\x18\x05 \x01(\tR\vtraceparent\x12\x1e\n" +
+	"\n" +
+	"tracestate\x18\x06 \x01(\tR\n" +
+	"tracestate\x12\x12\n" +
+	"\x04name\x18\a \x01(\tR\x04name\x12\x18\n" +
+	"\amessage\x18\b \x01(\fR\amessageBWZUgithub.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec/envelopepbb\x06proto3"
+
+var (
+	file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDescOnce sync.Once
+	file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDescData []byte
+)
+
+func file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDescGZIP() []byte {
+	file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDescOnce.Do(func() {
+		file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDesc), len(file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDesc)))
+	})
+	return file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDescData
+}
+
+var file_internal_shared_messaging_codec_envelopepb_envelope_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_internal_shared_messaging_codec_envelopepb_envelope_proto_goTypes = []any{
+	(*Envelope)(nil),              // 0: recs.votes_storage.messaging.v1.Envelope
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_internal_shared_messaging_codec_envelopepb_envelope_proto_depIdxs = []int32{
+	1, // 0: recs.votes_storage.messaging.v1.Envelope.produced_at:type_name -> google.protobuf.Timestamp
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_internal_shared_messaging_codec_envelopepb_envelope_proto_init() }
+func file_internal_shared_messaging_codec_envelopepb_envelope_proto_init() {
+	if File_internal_shared_messaging_codec_envelopepb_envelope_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDesc), len(file_internal_shared_messaging_codec_envelopepb_envelope_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_internal_shared_messaging_codec_envelopepb_envelope_proto_goTypes,
+		DependencyIndexes: file_internal_shared_messaging_codec_envelopepb_envelope_proto_depIdxs,
+		MessageInfos:      file_internal_shared_messaging_codec_envelopepb_envelope_proto_msgTypes,
+	}.Build()
+	File_internal_shared_messaging_codec_envelopepb_envelope_proto = out.File
+	file_internal_shared_messaging_codec_envelopepb_envelope_proto_goTypes = nil
+	file_internal_shared_messaging_codec_envelopepb_envelope_proto_depIdxs = nil
+}
@@ -0,0 +1,86 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+)
+
+type jsonEnvelope struct {
+	messaging.Metadata
+	Name    string          `json:"name"`
+	Message json.RawMessage `json:"message"`
+}
+
+type jsonProbe struct {
+	SpecVersion *string `json:"specversion"`
+	Name        *string `json:"name"`
+}
+
+// jsonCodec is the original envelope format: metadata, name and the message under the `message` key.
+type jsonCodec struct{}
+
+func (c jsonCodec) Encoding() Encoding {
+	return EncodingJson
+}
+
+func (c jsonCodec) ContentType() string {
+	return "application/json"
+}
+
+func (c jsonCodec) MarshalBody(body any) ([]byte, error) {
+	return json.Marshal(body)
+}
+
+func (c jsonCodec) UnmarshalBody(data []byte, body any) error {
+	return json.Unmarshal(data, body)
+}
+
+func (c jsonCodec) Encode(envelope Envelope) (messaging.Payload, error) {
+	return json.Marshal(jsonEnvelope{
+		Metadata: envelope.Metadata,
+		Name:     envelope.Name,
+		Message:  envelope.Body,
+	})
+}
+
+func (c jsonCodec) Decode(payload messaging.Payload) (Envelope, error) {
+	var env jsonEnvelope
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return Envelope{}, err
+	}
+
+	if isEmptyJson(env.Message) {
+		return Envelope{}, ErrEmptyBody
+	}
+
+	return Envelope{
+		Metadata: env.Metadata,
+		Name:     env.Name,
+		Body:     env.Message,
+	}, nil
+}
+
+func (c jsonCodec) Recognizes(payload messaging.Payload) bool {
+	probe, ok := probeJson(payload)
+	return ok && probe.SpecVersion == nil && probe.Name != nil
+}
+
+func probeJson(payload messaging.Payload) (jsonProbe, bool) {
+	trimmed := bytes.TrimSpace(payload)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return jsonProbe{}, false
+	}
+
+	var probe jsonProbe
+	if err := json.Unmarshal(trimmed, &probe); err != nil {
+		return jsonProbe{}, false
+	}
+	return probe, true
+}
+
+func isEmptyJson(data json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
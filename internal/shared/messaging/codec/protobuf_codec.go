@@ -0,0 +1,118 @@
+package codec
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec/envelopepb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// protobufCodec encodes envelopepb.Envelope. SNS and SQS only carry text,
+// so the binary envelope is sent base64 encoded.
+type protobufCodec struct{}
+
+func (c protobufCodec) Encoding() Encoding {
+	return EncodingProtobuf
+}
+
+func (c protobufCodec) ContentType() string {
+	return "application/x-protobuf;encoding=base64"
+}
+
+func (c protobufCodec) MarshalBody(body any) ([]byte, error) {
+	protoBody, ok := body.(ProtoBody)
+	if !ok {
+		return nil, fmt.Errorf("%T does not support protobuf encoding", body)
+	}
+	return protoBody.MarshalProto()
+}
+
+func (c protobufCodec) UnmarshalBody(data []byte, body any) error {
+	protoBody, ok := body.(ProtoBody)
+	if !ok {
+		return fmt.Errorf("%T does not support protobuf encoding", body)
+	}
+	return protoBody.UnmarshalProto(data)
+}
+
+func (c protobufCodec) Encode(envelope Envelope) (messaging.Payload, error) {
+	pb := &envelopepb.Envelope{
+		Id:            envelope.Id,
+		Producer:      envelope.Producer,
+		SchemaVersion: uint32(envelope.SchemaVersion),
+		Traceparent:   envelope.TraceParent,
+		Tracestate:    envelope.TraceState,
+		Name:          envelope.Name,
+		Message:       envelope.Body,
+	}
+	if envelope.ProducedAt != nil {
+		pb.ProducedAt = timestamppb.New(*envelope.ProducedAt)
+	}
+
+	data, err := MarshalProto(pb)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(payload, data)
+	return payload, nil
+}
+
+func (c protobufCodec) Decode(payload messaging.Payload) (Envelope, error) {
+	pb, err := decodeProtoEnvelope(payload)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	if pb.SchemaVersion > math.MaxUint16 {
+		return Envelope{}, fmt.Errorf("schema version %d out of range", pb.SchemaVersion)
+	}
+
+	if len(pb.Message) == 0 {
+		return Envelope{}, ErrEmptyBody
+	}
+
+	envelope := Envelope{
+		Metadata: messaging.Metadata{
+			Id:            pb.Id,
+			Producer:      pb.Producer,
+			SchemaVersion: uint16(pb.SchemaVersion),
+			TraceContext: messaging.TraceContext{
+				TraceParent: pb.Traceparent,
+				TraceState:  pb.Tracestate,
+			},
+		},
+		Name: pb.Name,
+		Body: pb.Message,
+	}
+	if pb.ProducedAt != nil {
+		producedAt := pb.ProducedAt.AsTime()
+		envelope.ProducedAt = &producedAt
+	}
+
+	return envelope, nil
+}
+
+func (c protobufCodec) Recognizes(payload messaging.Payload) bool {
+	pb, err := decodeProtoEnvelope(payload)
+	return err == nil && pb.Name != ""
+}
+
+func decodeProtoEnvelope(payload messaging.Payload) (*envelopepb.Envelope, error) {
+	data := make([]byte, base64.StdEncoding.DecodedLen(len(payload)))
+	n, err := base64.StdEncoding.Decode(data, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	pb := &envelopepb.Envelope{}
+	if err := proto.Unmarshal(data[:n], pb); err != nil {
+		return nil, err
+	}
+	return pb, nil
+}
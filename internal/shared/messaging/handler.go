@@ -33,6 +33,8 @@ func (r *TopicHandler) Dispatch(ctx context.Context, topic Topic, msg BackMessag
 		return fmt.Errorf("no handlers registered for topic %q", topic)
 	}
 
+	ctx = ContextWithMetadata(ctx, msg.GetMetadata())
+
 	var errs []error
 	for _, h := range hs {
 		if !h.canHandle(msg) {
@@ -0,0 +1,105 @@
+package messaging
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+const (
+	TraceParentHeader    = "traceparent"
+	TraceStateHeader     = "tracestate"
+	DefaultSchemaVersion = 1
+)
+
+var traceParentRegexp = regexp.MustCompile(`^[0-9a-f]{2}-[0-9a-f]{32}-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+type traceContextKey struct{}
+type metadataKey struct{}
+
+// TraceContext is the W3C trace context (https://www.w3.org/TR/trace-context/)
+// propagated from the originating HTTP request to every message it produces.
+type TraceContext struct {
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
+}
+
+// Metadata is the envelope information shared by all messages regardless of their type.
+// Payloads produced before it was introduced have no metadata and decode with zero values
+// and the default schema version.
+type Metadata struct {
+	Id            string     `json:"id,omitempty"`
+	ProducedAt    *time.Time `json:"produced_at,omitempty"`
+	Producer      string     `json:"producer,omitempty"`
+	SchemaVersion uint16     `json:"schema_version,omitempty"`
+	TraceContext
+}
+
+func NewTraceContext(traceParent string, traceState string) TraceContext {
+	if !isValidTraceParent(traceParent) {
+		return TraceContext{}
+	}
+
+	return TraceContext{
+		TraceParent: traceParent,
+		TraceState:  traceState,
+	}
+}
+
+func (t TraceContext) IsEmpty() bool {
+	return t.TraceParent == ""
+}
+
+func (m Metadata) Age(now time.Time) time.Duration {
+	if m.ProducedAt == nil {
+		return 0
+	}
+	return now.Sub(*m.ProducedAt)
+}
+
+// WithDefaults fills the values missing in legacy payloads and drops a malformed trace context.
+func (m Metadata) WithDefaults() Metadata {
+	if m.SchemaVersion == 0 {
+		m.SchemaVersion = DefaultSchemaVersion
+	}
+	m.TraceContext = NewTraceContext(m.TraceParent, m.TraceState)
+	return m
+}
+
+func ContextWithTraceContext(ctx context.Context, traceContext TraceContext) context.Context {
+	if traceContext.IsEmpty() {
+		return ctx
+	}
+	return context.WithValue(ctx, traceContextKey{}, traceContext)
+}
+
+func TraceContextFromContext(ctx context.Context) TraceContext {
+	traceContext, _ := ctx.Value(traceContextKey{}).(TraceContext)
+	return traceContext
+}
+
+func ContextWithMetadata(ctx context.Context, metadata Metadata) context.Context {
+	ctx = context.WithValue(ctx, metadataKey{}, metadata)
+	return ContextWithTraceContext(ctx, metadata.TraceContext)
+}
+
+func MetadataFromContext(ctx context.Context) (Metadata, bool) {
+	metadata, ok := ctx.Value(metadataKey{}).(Metadata)
+	return metadata, ok
+}
+
+func isValidTraceParent(traceParent string) bool {
+	if !traceParentRegexp.MatchString(traceParent) {
+		return false
+	}
+
+	// Version ff is forbidden, all-zero trace and parent ids are invalid
+	if traceParent[:2] == "ff" {
+		return false
+	}
+	if traceParent[3:35] == "00000000000000000000000000000000" || traceParent[36:52] == "0000000000000000" {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,63 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestNewTraceContext_AcceptsValidTraceParent(t *testing.T) {
+	traceContext := NewTraceContext(validTraceParent, "vendor=value")
+
+	assert.False(t, traceContext.IsEmpty())
+	assert.Equal(t, validTraceParent, traceContext.TraceParent)
+	assert.Equal(t, "vendor=value", traceContext.TraceState)
+}
+
+func TestNewTraceContext_DropsInvalidTraceParent(t *testing.T) {
+	testCases := []struct {
+		name        string
+		traceParent string
+	}{
+		{name: "empty", traceParent: ""},
+		{name: "garbage", traceParent: "not-a-trace"},
+		{name: "uppercase hex", traceParent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-01"},
+		{name: "forbidden version", traceParent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "zero trace id", traceParent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "zero parent id", traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			traceContext := NewTraceContext(tc.traceParent, "vendor=value")
+			assert.True(t, traceContext.IsEmpty())
+			assert.Equal(t, TraceContext{}, traceContext)
+		})
+	}
+}
+
+func TestContextWithMetadata_PropagatesTraceContext(t *testing.T) {
+	metadata := Metadata{
+		Id:           "id",
+		TraceContext: NewTraceContext(validTraceParent, ""),
+	}
+
+	ctx := ContextWithMetadata(context.Background(), metadata)
+
+	got, ok := MetadataFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, metadata, got)
+	assert.Equal(t, metadata.TraceContext, TraceContextFromContext(ctx))
+}
+
+func TestTraceContextFromContext_Empty(t *testing.T) {
+	ctx := ContextWithTraceContext(context.Background(), TraceContext{})
+
+	assert.True(t, TraceContextFromContext(ctx).IsEmpty())
+	_, ok := MetadataFromContext(ctx)
+	assert.False(t, ok)
+}
@@ -13,6 +13,7 @@ func (t Topic) IsFifo() bool {
 
 type BackMessage interface {
 	GetPayload() Payload
+	GetMetadata() Metadata
 	Nack() bool
 	Ack() bool
 }
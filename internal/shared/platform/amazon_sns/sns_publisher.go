@@ -7,15 +7,19 @@ import (
 	watermillMessage "github.com/ThreeDotsLabs/watermill/message"
 	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
 	"github.com/google/uuid"
 	"os"
 	"reflect"
 )
 
+const ContentTypeMetadataField = "content-type"
+
 type SnsPublisher struct {
-	pub    *sns.Publisher
-	logger platform.Logger
+	pub         *sns.Publisher
+	topicCodecs codec.TopicCodecs
+	logger      platform.Logger
 }
 
 func NewSnsPublisher(config config.Config, logger platform.Logger) *SnsPublisher {
@@ -33,14 +37,27 @@ func NewSnsPublisher(config config.Config, logger platform.Logger) *SnsPublisher
 		os.Exit(1)
 	}
 
+	topicCodecs, err := codec.NewTopicCodecs(config.Messaging.TopicEncodings)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Invalid topic encodings, %v", err))
+		os.Exit(1)
+	}
+
 	return &SnsPublisher{
-		pub:    pub,
-		logger: logger,
+		pub:         pub,
+		topicCodecs: topicCodecs,
+		logger:      logger,
 	}
 }
 
 func (p SnsPublisher) Publish(topic messaging.Topic, m messaging.Message) error {
-	wm := watermillMessage.NewMessage(uuid.NewString(), watermillMessage.Payload(m.GetPayload()))
+	payload, c, err := p.encode(topic, m)
+	if err != nil {
+		return err
+	}
+
+	wm := watermillMessage.NewMessage(uuid.NewString(), watermillMessage.Payload(payload))
+	wm.Metadata.Set(ContentTypeMetadataField, c.ContentType())
 
 	if topic.IsFifo() {
 		t := reflect.Indirect(reflect.ValueOf(m)).Type()
@@ -48,10 +65,26 @@ func (p SnsPublisher) Publish(topic messaging.Topic, m messaging.Message) error
 		wm.Metadata.Set(sns.MessageDeduplicationIdMetadataField, m.GetDeduplicationId())
 	}
 
-	err := p.pub.Publish(string(topic), wm)
+	err = p.pub.Publish(string(topic), wm)
 	if err != nil {
 		return err
 	}
-	p.logger.Debug(fmt.Sprintf("Topic `%s`: published new SNS message with ID `%s`", topic, wm.UUID))
+	p.logger.Debug(fmt.Sprintf("Topic `%s`: published new %s SNS message with ID `%s`", topic, c.Encoding(), wm.UUID))
 	return nil
 }
+
+// encode uses the codec configured for the topic, messages which can not choose
+// their encoding are published as they are, in the JSON envelope.
+func (p SnsPublisher) encode(topic messaging.Topic, m messaging.Message) (messaging.Payload, codec.Codec, error) {
+	encodable, ok := m.(codec.Encodable)
+	if !ok {
+		return m.GetPayload(), codec.Json, nil
+	}
+
+	c := p.topicCodecs.ForTopic(topic)
+	payload, err := encodable.Encode(c)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode message for topic `%s` as %s: %w", topic, c.Encoding(), err)
+	}
+	return payload, c, nil
+}
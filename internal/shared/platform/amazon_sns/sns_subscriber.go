@@ -9,6 +9,7 @@ import (
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/messaging/codec"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
 	"io"
 	"os"
@@ -131,6 +132,13 @@ func newSnsBackMessage(message *message.Message) *SnsBackMessage {
 func (bm *SnsBackMessage) GetPayload() messaging.Payload {
 	return messaging.Payload(bm.wrappedMessage.Payload)
 }
+func (bm *SnsBackMessage) GetMetadata() messaging.Metadata {
+	metadata, err := codec.DecodeMetadata(bm.GetPayload())
+	if err != nil {
+		return messaging.Metadata{SchemaVersion: messaging.DefaultSchemaVersion}
+	}
+	return metadata
+}
 func (bm *SnsBackMessage) Nack() bool {
 	return bm.wrappedMessage.Nack()
 }
@@ -23,6 +23,7 @@ type Client interface {
 	DeleteItem(ctx context.Context, in *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
 	Query(ctx context.Context, in *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
 	TransactWriteItems(ctx context.Context, in *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+	BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(options *dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
 	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(options *dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
 }
 
@@ -52,3 +53,9 @@ func NewDynamoDbClient(conf appConfig.Config, logger platform.Logger) Client {
 func GetDynamodbRegionByCountry(countryId uint16) string {
 	return "us-east-2"
 }
+
+// GetDynamodbRegions lists each region serving a country once, items whose country is not known
+// are looked up in all of them
+func GetDynamodbRegions() []string {
+	return []string{"us-east-2"}
+}
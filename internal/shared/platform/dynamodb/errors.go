@@ -0,0 +1,42 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/smithy-go"
+)
+
+var throttlingErrorCodes = map[string]struct{}{
+	"ProvisionedThroughputExceededException": {},
+	"RequestLimitExceeded":                   {},
+	"ThrottlingException":                    {},
+	"LimitExceededException":                 {},
+}
+
+var unavailableErrorCodes = map[string]struct{}{
+	"InternalServerError": {},
+	"ServiceUnavailable":  {},
+}
+
+// IsThrottlingError reports whether DynamoDB rejected the request because of capacity limits,
+// errors returned after the SDK retries are exhausted still wrap the original API error.
+func IsThrottlingError(err error) bool {
+	return hasApiErrorCode(err, throttlingErrorCodes)
+}
+
+func IsUnavailableError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return hasApiErrorCode(err, unavailableErrorCodes)
+}
+
+func hasApiErrorCode(err error, codes map[string]struct{}) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	_, ok := codes[apiErr.ErrorCode()]
+	return ok
+}
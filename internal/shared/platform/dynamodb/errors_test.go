@@ -0,0 +1,29 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsThrottlingError(t *testing.T) {
+	assert.True(t, IsThrottlingError(&types.ProvisionedThroughputExceededException{}))
+	assert.True(t, IsThrottlingError(&types.RequestLimitExceeded{}))
+	assert.True(t, IsThrottlingError(&retry.MaxAttemptsError{Attempt: 3, Err: &smithy.GenericAPIError{Code: "ThrottlingException"}}))
+	assert.True(t, IsThrottlingError(fmt.Errorf("put item: %w", &types.ProvisionedThroughputExceededException{})))
+	assert.False(t, IsThrottlingError(&types.ConditionalCheckFailedException{}))
+	assert.False(t, IsThrottlingError(errors.New("throttled")))
+}
+
+func TestIsUnavailableError(t *testing.T) {
+	assert.True(t, IsUnavailableError(&types.InternalServerError{}))
+	assert.True(t, IsUnavailableError(fmt.Errorf("get item: %w", context.DeadlineExceeded)))
+	assert.False(t, IsUnavailableError(&types.ProvisionedThroughputExceededException{}))
+	assert.False(t, IsUnavailableError(context.Canceled))
+}
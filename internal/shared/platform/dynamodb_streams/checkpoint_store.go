@@ -0,0 +1,171 @@
+package dynamodb_streams
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	platformDynamoDb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/timeutil"
+	"github.com/google/uuid"
+)
+
+const (
+	CheckpointsTableName        = "StreamCheckpoints"
+	StreamArnAttrName           = "s"
+	ShardIdAttrName             = "h"
+	sequenceNumberAttrName      = "q"
+	checkpointUpdatedAtAttrName = "u"
+	leaseOwnerAttrName          = "o"
+	leaseExpiresAtAttrName      = "l"
+	// Stream records are kept for 24 hours, checkpoints of closed shards are useless after that.
+	checkpointTtlSeconds = 2 * timeutil.DaySeconds
+)
+
+// ErrLeaseLost is returned when another owner took the lease of the shard over
+var ErrLeaseLost = errors.New("shard lease is held by another owner")
+
+//go:generate mockgen -destination=../../../testlib/mocks/checkpoint_store_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb_streams CheckpointStore
+type CheckpointStore interface {
+	// Lease takes or renews the lease of the shard for duration, false is returned while another
+	// owner holds an unexpired lease
+	Lease(ctx context.Context, streamArn string, shardId string, duration time.Duration) (bool, error)
+	// Release lets other owners take the shard over without waiting for the lease to expire
+	Release(ctx context.Context, streamArn string, shardId string) error
+	// Load returns the sequence number of the last processed record or an empty string
+	Load(ctx context.Context, streamArn string, shardId string) (string, error)
+	// Save returns ErrLeaseLost unless the shard is leased by the store owner
+	Save(ctx context.Context, streamArn string, shardId string, sequenceNumber string) error
+}
+
+// DynamoDbCheckpointStore keeps the lease of a shard on its checkpoint row, every store is
+// an owner of its own so each instance of the service is.
+type DynamoDbCheckpointStore struct {
+	dynamoDbClient platformDynamoDb.Client
+	owner          string
+	logger         platform.Logger
+}
+
+func NewDynamoDbCheckpointStore(
+	dynamoDbClient platformDynamoDb.Client,
+	logger platform.Logger,
+) *DynamoDbCheckpointStore {
+	return &DynamoDbCheckpointStore{
+		dynamoDbClient: dynamoDbClient,
+		owner:          uuid.NewString(),
+		logger:         logger,
+	}
+}
+
+func (c *DynamoDbCheckpointStore) getCheckpointsTableKey(streamArn string, shardId string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		StreamArnAttrName: &types.AttributeValueMemberS{Value: streamArn},
+		ShardIdAttrName:   &types.AttributeValueMemberS{Value: shardId},
+	}
+}
+
+func (c *DynamoDbCheckpointStore) Load(ctx context.Context, streamArn string, shardId string) (string, error) {
+	out, err := c.dynamoDbClient.GetItem(ctx, &dynamodb.GetItemInput{
+		Key:            c.getCheckpointsTableKey(streamArn, shardId),
+		TableName:      aws.String(CheckpointsTableName),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if out == nil || len(out.Item) == 0 {
+		return "", nil
+	}
+
+	sequenceNumber, ok := out.Item[sequenceNumberAttrName].(*types.AttributeValueMemberS)
+	if !ok {
+		return "", nil
+	}
+	return sequenceNumber.Value, nil
+}
+
+func (c *DynamoDbCheckpointStore) Save(ctx context.Context, streamArn string, shardId string, sequenceNumber string) error {
+	now := time.Now()
+
+	_, err := c.dynamoDbClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(CheckpointsTableName),
+		Key:                 c.getCheckpointsTableKey(streamArn, shardId),
+		UpdateExpression:    aws.String("SET #sequenceNumber = :sequenceNumber, #updatedAt = :now, #ttl = :ttl"),
+		ConditionExpression: aws.String("#owner = :owner"),
+		ExpressionAttributeNames: map[string]string{
+			"#sequenceNumber": sequenceNumberAttrName,
+			"#updatedAt":      checkpointUpdatedAtAttrName,
+			"#ttl":            platformDynamoDb.TtlAttrName,
+			"#owner":          leaseOwnerAttrName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":sequenceNumber": &types.AttributeValueMemberS{Value: sequenceNumber},
+			":now":            &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+			":ttl":            &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix()+checkpointTtlSeconds, 10)},
+			":owner":          &types.AttributeValueMemberS{Value: c.owner},
+		},
+	})
+
+	var condCheckErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condCheckErr) {
+		return ErrLeaseLost
+	}
+	return err
+}
+
+func (c *DynamoDbCheckpointStore) Lease(ctx context.Context, streamArn string, shardId string, duration time.Duration) (bool, error) {
+	now := time.Now()
+
+	_, err := c.dynamoDbClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(CheckpointsTableName),
+		Key:                 c.getCheckpointsTableKey(streamArn, shardId),
+		UpdateExpression:    aws.String("SET #owner = :owner, #expiresAt = :expiresAt, #ttl = :ttl"),
+		ConditionExpression: aws.String("attribute_not_exists(#owner) OR #owner = :owner OR #expiresAt < :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#owner":     leaseOwnerAttrName,
+			"#expiresAt": leaseExpiresAtAttrName,
+			"#ttl":       platformDynamoDb.TtlAttrName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":owner":     &types.AttributeValueMemberS{Value: c.owner},
+			":expiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(duration).UnixMilli(), 10)},
+			":now":       &types.AttributeValueMemberN{Value: strconv.FormatInt(now.UnixMilli(), 10)},
+			":ttl":       &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix()+checkpointTtlSeconds, 10)},
+		},
+	})
+
+	var condCheckErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condCheckErr) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *DynamoDbCheckpointStore) Release(ctx context.Context, streamArn string, shardId string) error {
+	_, err := c.dynamoDbClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(CheckpointsTableName),
+		Key:                 c.getCheckpointsTableKey(streamArn, shardId),
+		UpdateExpression:    aws.String("REMOVE #owner, #expiresAt"),
+		ConditionExpression: aws.String("#owner = :owner"),
+		ExpressionAttributeNames: map[string]string{
+			"#owner":     leaseOwnerAttrName,
+			"#expiresAt": leaseExpiresAtAttrName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":owner": &types.AttributeValueMemberS{Value: c.owner},
+		},
+	})
+
+	// the lease already expired and was taken over
+	var condCheckErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condCheckErr) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package dynamodb_streams
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type CheckpointStoreUnitTestSuite struct {
+	suite.Suite
+	dynamoDbClient *mocks.MockClient
+	store          *DynamoDbCheckpointStore
+}
+
+func TestCheckpointStoreUnitSuite(t *testing.T) {
+	suite.Run(t, new(CheckpointStoreUnitTestSuite))
+}
+
+func (s *CheckpointStoreUnitTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.dynamoDbClient = mocks.NewMockClient(ctrl)
+	s.store = NewDynamoDbCheckpointStore(s.dynamoDbClient, mocks.NewMockLogger(ctrl))
+}
+
+func (s *CheckpointStoreUnitTestSuite) TestLoadMissingCheckpoint() {
+	ctx := context.Background()
+
+	s.dynamoDbClient.EXPECT().
+		GetItem(ctx, gomock.Any()).
+		Return(&dynamodb.GetItemOutput{}, nil)
+
+	checkpoint, err := s.store.Load(ctx, testStreamArn, "shard-1")
+	s.Require().NoError(err)
+	s.Require().Empty(checkpoint)
+}
+
+func (s *CheckpointStoreUnitTestSuite) TestLoadCheckpoint() {
+	ctx := context.Background()
+
+	s.dynamoDbClient.EXPECT().
+		GetItem(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, in *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+			s.Require().Equal(CheckpointsTableName, *in.TableName)
+			s.Require().Equal(&types.AttributeValueMemberS{Value: testStreamArn}, in.Key[StreamArnAttrName])
+			s.Require().Equal(&types.AttributeValueMemberS{Value: "shard-1"}, in.Key[ShardIdAttrName])
+			return &dynamodb.GetItemOutput{
+				Item: map[string]types.AttributeValue{
+					sequenceNumberAttrName: &types.AttributeValueMemberS{Value: "100"},
+				},
+			}, nil
+		})
+
+	checkpoint, err := s.store.Load(ctx, testStreamArn, "shard-1")
+	s.Require().NoError(err)
+	s.Require().Equal("100", checkpoint)
+}
+
+func (s *CheckpointStoreUnitTestSuite) TestSaveCheckpoint() {
+	ctx := context.Background()
+
+	s.dynamoDbClient.EXPECT().
+		UpdateItem(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, in *dynamodb.UpdateItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+			s.Require().Equal(CheckpointsTableName, *in.TableName)
+			s.Require().Equal(&types.AttributeValueMemberS{Value: "100"}, in.ExpressionAttributeValues[":sequenceNumber"])
+			s.Require().Equal(&types.AttributeValueMemberS{Value: s.store.owner}, in.ExpressionAttributeValues[":owner"])
+			s.Require().Contains(in.ExpressionAttributeValues, ":ttl")
+			return &dynamodb.UpdateItemOutput{}, nil
+		})
+
+	s.Require().NoError(s.store.Save(ctx, testStreamArn, "shard-1", "100"))
+}
+
+func (s *CheckpointStoreUnitTestSuite) TestSaveCheckpointOfShardLeasedByAnotherOwner() {
+	ctx := context.Background()
+
+	s.dynamoDbClient.EXPECT().
+		UpdateItem(ctx, gomock.Any()).
+		Return(nil, &types.ConditionalCheckFailedException{})
+
+	s.Require().ErrorIs(s.store.Save(ctx, testStreamArn, "shard-1", "100"), ErrLeaseLost)
+}
+
+func (s *CheckpointStoreUnitTestSuite) TestLease() {
+	ctx := context.Background()
+	before := time.Now()
+
+	gomock.InOrder(
+		s.dynamoDbClient.EXPECT().
+			UpdateItem(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *dynamodb.UpdateItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				s.Require().Equal(&types.AttributeValueMemberS{Value: s.store.owner}, in.ExpressionAttributeValues[":owner"])
+				expiresAt, err := strconv.ParseInt(in.ExpressionAttributeValues[":expiresAt"].(*types.AttributeValueMemberN).Value, 10, 64)
+				s.Require().NoError(err)
+				s.Require().GreaterOrEqual(expiresAt, before.Add(30*time.Second).UnixMilli())
+				return &dynamodb.UpdateItemOutput{}, nil
+			}),
+		s.dynamoDbClient.EXPECT().
+			UpdateItem(ctx, gomock.Any()).
+			Return(nil, &types.ConditionalCheckFailedException{}),
+	)
+
+	leased, err := s.store.Lease(ctx, testStreamArn, "shard-1", 30*time.Second)
+	s.Require().NoError(err)
+	s.Require().True(leased)
+
+	leased, err = s.store.Lease(ctx, testStreamArn, "shard-1", 30*time.Second)
+	s.Require().NoError(err)
+	s.Require().False(leased, "the shard is leased by another owner")
+}
+
+func (s *CheckpointStoreUnitTestSuite) TestReleaseOfLeaseTakenOver() {
+	ctx := context.Background()
+
+	s.dynamoDbClient.EXPECT().
+		UpdateItem(ctx, gomock.Any()).
+		Return(nil, &types.ConditionalCheckFailedException{})
+
+	s.Require().NoError(s.store.Release(ctx, testStreamArn, "shard-1"))
+}
@@ -0,0 +1,44 @@
+package dynamodb_streams
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	appConfig "github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+)
+
+//go:generate mockgen -destination=../../../testlib/mocks/dynamodb_streams_client_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb_streams StreamsClient
+type StreamsClient interface {
+	DescribeStream(ctx context.Context, params *dynamodbstreams.DescribeStreamInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.DescribeStreamOutput, error)
+	GetShardIterator(ctx context.Context, params *dynamodbstreams.GetShardIteratorInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.GetShardIteratorOutput, error)
+	GetRecords(ctx context.Context, params *dynamodbstreams.GetRecordsInput, optFns ...func(*dynamodbstreams.Options)) (*dynamodbstreams.GetRecordsOutput, error)
+}
+
+// NewDynamoDbStreamsClient shares the DynamoDB endpoint, LocalStack serves both APIs on it.
+func NewDynamoDbStreamsClient(conf appConfig.Config, logger platform.Logger) StreamsClient {
+
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(conf.Aws.Region),
+	}
+
+	if conf.Aws.AccessKeyId != "" && conf.Aws.SecretAccessKey != "" {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(conf.Aws.AccessKeyId, conf.Aws.SecretAccessKey, "")))
+	}
+
+	if conf.Aws.DynamoDbEndpoint != "" {
+		opts = append(opts, config.WithBaseEndpoint(conf.Aws.DynamoDbEndpoint))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(context.TODO(), opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Unable to load SDK config, %v", err))
+		os.Exit(1)
+	}
+	return dynamodbstreams.NewFromConfig(awsCfg)
+}
@@ -0,0 +1,376 @@
+package dynamodb_streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform"
+	platformDynamoDb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+)
+
+var ErrStreamNotEnabled = errors.New("stream is not enabled for the table")
+
+//go:generate mockgen -destination=../../../testlib/mocks/record_handler_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb_streams RecordHandler
+type RecordHandler interface {
+	Handle(ctx context.Context, record types.Record) error
+}
+
+type shardResult struct {
+	shardId string
+	drained bool
+}
+
+type StreamReader struct {
+	streamsClient  StreamsClient
+	dynamoDbClient platformDynamoDb.Client
+	checkpoints    CheckpointStore
+	config         config.StreamsConfig
+	logger         platform.Logger
+}
+
+func NewStreamReader(
+	streamsClient StreamsClient,
+	dynamoDbClient platformDynamoDb.Client,
+	checkpoints CheckpointStore,
+	config config.Config,
+	logger platform.Logger,
+) *StreamReader {
+	return &StreamReader{
+		streamsClient:  streamsClient,
+		dynamoDbClient: dynamoDbClient,
+		checkpoints:    checkpoints,
+		config:         config.Streams,
+		logger:         logger,
+	}
+}
+
+// Read follows all shards of the table stream until ctx is done. A child shard is started
+// only after its parent is drained, so the records of one item are handled in order.
+// Records are delivered at least once. Every shard is read by the reader holding its lease, so
+// readers of all instances share the shards, a shard leased by another reader is tried again later.
+func (r *StreamReader) Read(ctx context.Context, tableName string, handler RecordHandler) error {
+	streamArn, err := r.getStreamArn(ctx, tableName)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Debug(fmt.Sprintf("Starting reading stream `%s` of table `%s`", streamArn, tableName))
+
+	wg := sync.WaitGroup{}
+	defer wg.Wait()
+
+	started := map[string]bool{}
+	finished := map[string]bool{}
+	// shards which stopped without being drained are tried again on the next shards refresh
+	stopped := map[string]bool{}
+	done := make(chan shardResult)
+
+	ticker := time.NewTicker(r.config.ShardsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		shards, err := r.listShards(ctx, streamArn)
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error(fmt.Sprintf("Stream `%s`: unable to list shards, %v", streamArn, err))
+		}
+
+		known := make(map[string]bool, len(shards))
+		for _, shard := range shards {
+			known[aws.ToString(shard.ShardId)] = true
+		}
+
+		for _, shard := range shards {
+			shardId := aws.ToString(shard.ShardId)
+			parentShardId := aws.ToString(shard.ParentShardId)
+			if started[shardId] || stopped[shardId] || (known[parentShardId] && !finished[parentShardId]) {
+				continue
+			}
+
+			started[shardId] = true
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				drained := r.readShard(ctx, streamArn, shardId, handler)
+				select {
+				case done <- shardResult{shardId: shardId, drained: drained}:
+				case <-ctx.Done():
+				}
+			}()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case result := <-done:
+			finished[result.shardId] = result.drained
+			started[result.shardId] = result.drained
+			stopped[result.shardId] = !result.drained
+		case <-ticker.C:
+			clear(stopped)
+		}
+	}
+}
+
+func (r *StreamReader) getStreamArn(ctx context.Context, tableName string) (string, error) {
+	out, err := r.dynamoDbClient.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if out.Table == nil || out.Table.LatestStreamArn == nil ||
+		out.Table.StreamSpecification == nil || !aws.ToBool(out.Table.StreamSpecification.StreamEnabled) {
+		return "", fmt.Errorf("%w: %s", ErrStreamNotEnabled, tableName)
+	}
+
+	return *out.Table.LatestStreamArn, nil
+}
+
+func (r *StreamReader) listShards(ctx context.Context, streamArn string) ([]types.Shard, error) {
+	var (
+		shards                []types.Shard
+		exclusiveStartShardId *string
+	)
+
+	for {
+		out, err := r.streamsClient.DescribeStream(ctx, &dynamodbstreams.DescribeStreamInput{
+			StreamArn:             aws.String(streamArn),
+			ExclusiveStartShardId: exclusiveStartShardId,
+		})
+		if err != nil {
+			return shards, err
+		}
+
+		if out.StreamDescription == nil {
+			return shards, nil
+		}
+
+		shards = append(shards, out.StreamDescription.Shards...)
+
+		if out.StreamDescription.LastEvaluatedShardId == nil {
+			return shards, nil
+		}
+		exclusiveStartShardId = out.StreamDescription.LastEvaluatedShardId
+	}
+}
+
+// readShard returns true once the shard is drained or no longer exists, false when it stopped
+// without holding the lease of the shard or ctx is done.
+func (r *StreamReader) readShard(ctx context.Context, streamArn string, shardId string, handler RecordHandler) bool {
+	lease, ok := r.acquireLease(ctx, streamArn, shardId)
+	if !ok {
+		return false
+	}
+	defer r.releaseLease(ctx, streamArn, shardId)
+
+	r.logger.Debug(fmt.Sprintf("Shard `%s`: started reading", shardId))
+
+	checkpoint, ok := r.loadCheckpoint(ctx, streamArn, shardId)
+	if !ok {
+		return false
+	}
+
+	var iterator *string
+	for {
+		if !lease.renew(ctx) {
+			return false
+		}
+
+		if iterator == nil {
+			out, err := r.streamsClient.GetShardIterator(ctx, r.getShardIteratorInput(streamArn, shardId, checkpoint))
+			if err != nil {
+				var notFound *types.ResourceNotFoundException
+				if errors.As(err, &notFound) {
+					r.logger.Debug(fmt.Sprintf("Shard `%s`: no longer exists", shardId))
+					return true
+				}
+
+				checkpoint = r.handleReadError(shardId, err, checkpoint)
+				if !sleep(ctx, r.config.RetryInterval) {
+					return false
+				}
+				continue
+			}
+			iterator = out.ShardIterator
+		}
+
+		out, err := r.streamsClient.GetRecords(ctx, &dynamodbstreams.GetRecordsInput{
+			ShardIterator: iterator,
+			Limit:         aws.Int32(r.config.BatchSize),
+		})
+		if err != nil {
+			iterator = nil
+			checkpoint = r.handleReadError(shardId, err, checkpoint)
+			if !sleep(ctx, r.config.RetryInterval) {
+				return false
+			}
+			continue
+		}
+
+		lastSequenceNumber, err := r.handleRecords(ctx, out.Records, handler)
+		if lastSequenceNumber != "" {
+			checkpoint = lastSequenceNumber
+			saveErr := r.checkpoints.Save(ctx, streamArn, shardId, checkpoint)
+			if errors.Is(saveErr, ErrLeaseLost) {
+				r.logger.Error(fmt.Sprintf("Shard `%s`: lease lost before saving checkpoint `%s`", shardId, checkpoint))
+				return false
+			}
+			if saveErr != nil {
+				r.logger.Error(fmt.Sprintf("Shard `%s`: unable to save checkpoint `%s`, %v", shardId, checkpoint, saveErr))
+			}
+		}
+
+		if err != nil {
+			// The iterator has already moved past the failed record, resume right after the checkpoint
+			iterator = nil
+			r.logger.Error(fmt.Sprintf("Shard `%s`: unable to handle record, %v", shardId, err))
+			if !sleep(ctx, r.config.RetryInterval) {
+				return false
+			}
+			continue
+		}
+
+		if out.NextShardIterator == nil {
+			r.logger.Debug(fmt.Sprintf("Shard `%s`: closed and drained", shardId))
+			return true
+		}
+		iterator = out.NextShardIterator
+
+		if len(out.Records) == 0 && !sleep(ctx, r.config.PollInterval) {
+			return false
+		}
+	}
+}
+
+// shardLease is renewed once a third of its duration passed, a lease which could not be renewed
+// is given up when it expires
+type shardLease struct {
+	reader    *StreamReader
+	streamArn string
+	shardId   string
+	renewAt   time.Time
+	expiresAt time.Time
+}
+
+func (r *StreamReader) acquireLease(ctx context.Context, streamArn string, shardId string) (*shardLease, bool) {
+	lease := &shardLease{reader: r, streamArn: streamArn, shardId: shardId}
+	if !lease.renew(ctx) {
+		r.logger.Debug(fmt.Sprintf("Shard `%s`: leased by another reader", shardId))
+		return nil, false
+	}
+	return lease, true
+}
+
+func (r *StreamReader) releaseLease(ctx context.Context, streamArn string, shardId string) {
+	// the lease is released on shutdown as well
+	releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.config.RetryInterval)
+	defer cancel()
+	if err := r.checkpoints.Release(releaseCtx, streamArn, shardId); err != nil {
+		r.logger.Error(fmt.Sprintf("Shard `%s`: unable to release lease, %v", shardId, err))
+	}
+}
+
+// renew returns false once the lease is held by another reader or expired
+func (l *shardLease) renew(ctx context.Context) bool {
+	now := time.Now()
+	if now.Before(l.renewAt) {
+		return true
+	}
+
+	duration := l.reader.config.LeaseDuration
+	leased, err := l.reader.checkpoints.Lease(ctx, l.streamArn, l.shardId, duration)
+	if err != nil {
+		l.reader.logger.Error(fmt.Sprintf("Shard `%s`: unable to renew lease, %v", l.shardId, err))
+		return now.Before(l.expiresAt)
+	}
+	if !leased {
+		return false
+	}
+	l.renewAt = now.Add(duration / 3)
+	l.expiresAt = now.Add(duration)
+	return true
+}
+
+func (r *StreamReader) loadCheckpoint(ctx context.Context, streamArn string, shardId string) (string, bool) {
+	for {
+		checkpoint, err := r.checkpoints.Load(ctx, streamArn, shardId)
+		if err == nil {
+			return checkpoint, true
+		}
+
+		r.logger.Error(fmt.Sprintf("Shard `%s`: unable to load checkpoint, %v", shardId, err))
+		if !sleep(ctx, r.config.RetryInterval) {
+			return "", false
+		}
+	}
+}
+
+func (r *StreamReader) getShardIteratorInput(streamArn string, shardId string, checkpoint string) *dynamodbstreams.GetShardIteratorInput {
+	input := &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         aws.String(streamArn),
+		ShardId:           aws.String(shardId),
+		ShardIteratorType: types.ShardIteratorTypeTrimHorizon,
+	}
+
+	if checkpoint != "" {
+		input.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		input.SequenceNumber = aws.String(checkpoint)
+	}
+	return input
+}
+
+// handleReadError logs the error and returns the checkpoint to resume from.
+func (r *StreamReader) handleReadError(shardId string, err error, checkpoint string) string {
+	var (
+		expired *types.ExpiredIteratorException
+		trimmed *types.TrimmedDataAccessException
+	)
+
+	switch {
+	case errors.As(err, &expired):
+		r.logger.Debug(fmt.Sprintf("Shard `%s`: iterator expired, resuming from checkpoint", shardId))
+		return checkpoint
+	case errors.As(err, &trimmed):
+		r.logger.Error(fmt.Sprintf("Shard `%s`: records after checkpoint `%s` are trimmed, resuming from the oldest record", shardId, checkpoint))
+		return ""
+	default:
+		r.logger.Error(fmt.Sprintf("Shard `%s`: unable to read records, %v", shardId, err))
+		return checkpoint
+	}
+}
+
+// handleRecords returns the sequence number of the last successfully handled record.
+func (r *StreamReader) handleRecords(ctx context.Context, records []types.Record, handler RecordHandler) (string, error) {
+	var lastSequenceNumber string
+	for _, record := range records {
+		if err := handler.Handle(ctx, record); err != nil {
+			return lastSequenceNumber, err
+		}
+
+		if record.Dynamodb != nil {
+			lastSequenceNumber = aws.ToString(record.Dynamodb.SequenceNumber)
+		}
+	}
+	return lastSequenceNumber, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,339 @@
+package dynamodb_streams
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamoDbTypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testTableName = "Romances"
+	testStreamArn = "arn:aws:dynamodb:us-east-2:000000000000:table/Romances/stream/2025-01-01T00:00:00.000"
+)
+
+type StreamReaderUnitTestSuite struct {
+	suite.Suite
+	ctrl           *gomock.Controller
+	streamsClient  *mocks.MockStreamsClient
+	dynamoDbClient *mocks.MockClient
+	checkpoints    *mocks.MockCheckpointStore
+	handler        *mocks.MockRecordHandler
+	reader         *StreamReader
+}
+
+func TestStreamReaderUnitSuite(t *testing.T) {
+	suite.Run(t, new(StreamReaderUnitTestSuite))
+}
+
+func (s *StreamReaderUnitTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.streamsClient = mocks.NewMockStreamsClient(s.ctrl)
+	s.dynamoDbClient = mocks.NewMockClient(s.ctrl)
+	s.checkpoints = mocks.NewMockCheckpointStore(s.ctrl)
+	s.handler = mocks.NewMockRecordHandler(s.ctrl)
+
+	logger := mocks.NewMockLogger(s.ctrl)
+	logger.EXPECT().Debug(gomock.Any()).AnyTimes()
+	logger.EXPECT().Error(gomock.Any()).AnyTimes()
+
+	s.reader = NewStreamReader(
+		s.streamsClient,
+		s.dynamoDbClient,
+		s.checkpoints,
+		config.Config{
+			Streams: config.StreamsConfig{
+				PollInterval:          time.Millisecond,
+				ShardsRefreshInterval: time.Hour,
+				RetryInterval:         time.Millisecond,
+				BatchSize:             10,
+				LeaseDuration:         time.Hour,
+			},
+		},
+		logger,
+	)
+}
+
+func (s *StreamReaderUnitTestSuite) TestReadWithDisabledStream() {
+	ctx := context.Background()
+
+	s.dynamoDbClient.EXPECT().
+		DescribeTable(ctx, gomock.Any()).
+		Return(&dynamodb.DescribeTableOutput{Table: &dynamoDbTypes.TableDescription{}}, nil)
+
+	err := s.reader.Read(ctx, testTableName, s.handler)
+	s.Require().ErrorIs(err, ErrStreamNotEnabled)
+}
+
+func (s *StreamReaderUnitTestSuite) TestReadShardFromCheckpoint() {
+	ctx := context.Background()
+	s.expectLease(ctx, "shard-1")
+	records := []types.Record{newRecord("101"), newRecord("102")}
+
+	s.checkpoints.EXPECT().Load(ctx, testStreamArn, "shard-1").Return("100", nil)
+	s.streamsClient.EXPECT().
+		GetShardIterator(ctx, &dynamodbstreams.GetShardIteratorInput{
+			StreamArn:         aws.String(testStreamArn),
+			ShardId:           aws.String("shard-1"),
+			ShardIteratorType: types.ShardIteratorTypeAfterSequenceNumber,
+			SequenceNumber:    aws.String("100"),
+		}).
+		Return(&dynamodbstreams.GetShardIteratorOutput{ShardIterator: aws.String("it-1")}, nil)
+	s.streamsClient.EXPECT().
+		GetRecords(ctx, &dynamodbstreams.GetRecordsInput{ShardIterator: aws.String("it-1"), Limit: aws.Int32(10)}).
+		Return(&dynamodbstreams.GetRecordsOutput{Records: records}, nil)
+
+	gomock.InOrder(
+		s.handler.EXPECT().Handle(ctx, records[0]).Return(nil),
+		s.handler.EXPECT().Handle(ctx, records[1]).Return(nil),
+	)
+	s.checkpoints.EXPECT().Save(ctx, testStreamArn, "shard-1", "102").Return(nil)
+
+	s.reader.readShard(ctx, testStreamArn, "shard-1", s.handler)
+}
+
+func (s *StreamReaderUnitTestSuite) TestReadShardFromTrimHorizonWithoutCheckpoint() {
+	ctx := context.Background()
+	s.expectLease(ctx, "shard-1")
+
+	s.checkpoints.EXPECT().Load(ctx, testStreamArn, "shard-1").Return("", nil)
+	s.streamsClient.EXPECT().
+		GetShardIterator(ctx, &dynamodbstreams.GetShardIteratorInput{
+			StreamArn:         aws.String(testStreamArn),
+			ShardId:           aws.String("shard-1"),
+			ShardIteratorType: types.ShardIteratorTypeTrimHorizon,
+		}).
+		Return(&dynamodbstreams.GetShardIteratorOutput{ShardIterator: aws.String("it-1")}, nil)
+	gomock.InOrder(
+		s.streamsClient.EXPECT().
+			GetRecords(ctx, gomock.Any()).
+			Return(&dynamodbstreams.GetRecordsOutput{NextShardIterator: aws.String("it-2")}, nil),
+		s.streamsClient.EXPECT().
+			GetRecords(ctx, &dynamodbstreams.GetRecordsInput{ShardIterator: aws.String("it-2"), Limit: aws.Int32(10)}).
+			Return(&dynamodbstreams.GetRecordsOutput{}, nil),
+	)
+
+	s.reader.readShard(ctx, testStreamArn, "shard-1", s.handler)
+}
+
+func (s *StreamReaderUnitTestSuite) TestReadShardResumesAfterHandlerError() {
+	ctx := context.Background()
+	s.expectLease(ctx, "shard-1")
+	records := []types.Record{newRecord("101"), newRecord("102")}
+
+	s.checkpoints.EXPECT().Load(ctx, testStreamArn, "shard-1").Return("", nil)
+	gomock.InOrder(
+		s.streamsClient.EXPECT().
+			GetShardIterator(ctx, gomock.Any()).
+			Return(&dynamodbstreams.GetShardIteratorOutput{ShardIterator: aws.String("it-1")}, nil),
+		s.streamsClient.EXPECT().
+			GetShardIterator(ctx, &dynamodbstreams.GetShardIteratorInput{
+				StreamArn:         aws.String(testStreamArn),
+				ShardId:           aws.String("shard-1"),
+				ShardIteratorType: types.ShardIteratorTypeAfterSequenceNumber,
+				SequenceNumber:    aws.String("101"),
+			}).
+			Return(&dynamodbstreams.GetShardIteratorOutput{ShardIterator: aws.String("it-2")}, nil),
+	)
+	gomock.InOrder(
+		s.streamsClient.EXPECT().
+			GetRecords(ctx, gomock.Any()).
+			Return(&dynamodbstreams.GetRecordsOutput{Records: records, NextShardIterator: aws.String("it-3")}, nil),
+		s.streamsClient.EXPECT().
+			GetRecords(ctx, &dynamodbstreams.GetRecordsInput{ShardIterator: aws.String("it-2"), Limit: aws.Int32(10)}).
+			Return(&dynamodbstreams.GetRecordsOutput{Records: records[1:]}, nil),
+	)
+	gomock.InOrder(
+		s.handler.EXPECT().Handle(ctx, records[0]).Return(nil),
+		s.handler.EXPECT().Handle(ctx, records[1]).Return(errors.New("publish failed")),
+		s.handler.EXPECT().Handle(ctx, records[1]).Return(nil),
+	)
+	gomock.InOrder(
+		s.checkpoints.EXPECT().Save(ctx, testStreamArn, "shard-1", "101").Return(nil),
+		s.checkpoints.EXPECT().Save(ctx, testStreamArn, "shard-1", "102").Return(nil),
+	)
+
+	s.reader.readShard(ctx, testStreamArn, "shard-1", s.handler)
+}
+
+func (s *StreamReaderUnitTestSuite) TestReadShardRenewsExpiredIterator() {
+	ctx := context.Background()
+	s.expectLease(ctx, "shard-1")
+
+	s.checkpoints.EXPECT().Load(ctx, testStreamArn, "shard-1").Return("100", nil)
+	s.streamsClient.EXPECT().
+		GetShardIterator(ctx, gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			in *dynamodbstreams.GetShardIteratorInput,
+			_ ...func(*dynamodbstreams.Options),
+		) (*dynamodbstreams.GetShardIteratorOutput, error) {
+			s.Require().Equal("100", aws.ToString(in.SequenceNumber))
+			return &dynamodbstreams.GetShardIteratorOutput{ShardIterator: aws.String("it")}, nil
+		}).
+		Times(2)
+	gomock.InOrder(
+		s.streamsClient.EXPECT().
+			GetRecords(ctx, gomock.Any()).
+			Return(nil, &types.ExpiredIteratorException{}),
+		s.streamsClient.EXPECT().
+			GetRecords(ctx, gomock.Any()).
+			Return(&dynamodbstreams.GetRecordsOutput{}, nil),
+	)
+
+	s.reader.readShard(ctx, testStreamArn, "shard-1", s.handler)
+}
+
+func (s *StreamReaderUnitTestSuite) TestReadShardRestartsFromTrimHorizonWhenCheckpointIsTrimmed() {
+	ctx := context.Background()
+	s.expectLease(ctx, "shard-1")
+
+	s.checkpoints.EXPECT().Load(ctx, testStreamArn, "shard-1").Return("100", nil)
+	gomock.InOrder(
+		s.streamsClient.EXPECT().
+			GetShardIterator(ctx, gomock.Any()).
+			Return(nil, &types.TrimmedDataAccessException{}),
+		s.streamsClient.EXPECT().
+			GetShardIterator(ctx, &dynamodbstreams.GetShardIteratorInput{
+				StreamArn:         aws.String(testStreamArn),
+				ShardId:           aws.String("shard-1"),
+				ShardIteratorType: types.ShardIteratorTypeTrimHorizon,
+			}).
+			Return(&dynamodbstreams.GetShardIteratorOutput{ShardIterator: aws.String("it")}, nil),
+	)
+	s.streamsClient.EXPECT().
+		GetRecords(ctx, gomock.Any()).
+		Return(&dynamodbstreams.GetRecordsOutput{}, nil)
+
+	s.reader.readShard(ctx, testStreamArn, "shard-1", s.handler)
+}
+
+func (s *StreamReaderUnitTestSuite) TestReadShardStopsOnMissingShard() {
+	ctx := context.Background()
+	s.expectLease(ctx, "shard-1")
+
+	s.checkpoints.EXPECT().Load(ctx, testStreamArn, "shard-1").Return("", nil)
+	s.streamsClient.EXPECT().
+		GetShardIterator(ctx, gomock.Any()).
+		Return(nil, &types.ResourceNotFoundException{})
+
+	s.reader.readShard(ctx, testStreamArn, "shard-1", s.handler)
+}
+
+func (s *StreamReaderUnitTestSuite) TestReadStartsChildShardAfterParent() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.dynamoDbClient.EXPECT().
+		DescribeTable(ctx, gomock.Any()).
+		Return(&dynamodb.DescribeTableOutput{
+			Table: &dynamoDbTypes.TableDescription{
+				LatestStreamArn:     aws.String(testStreamArn),
+				StreamSpecification: &dynamoDbTypes.StreamSpecification{StreamEnabled: aws.Bool(true)},
+			},
+		}, nil)
+
+	s.streamsClient.EXPECT().
+		DescribeStream(ctx, gomock.Any()).
+		Return(&dynamodbstreams.DescribeStreamOutput{
+			StreamDescription: &types.StreamDescription{
+				Shards: []types.Shard{
+					{ShardId: aws.String("child"), ParentShardId: aws.String("parent")},
+					{ShardId: aws.String("parent")},
+				},
+			},
+		}, nil).
+		AnyTimes()
+
+	s.checkpoints.EXPECT().Lease(ctx, testStreamArn, gomock.Any(), time.Hour).Return(true, nil).Times(2)
+	s.checkpoints.EXPECT().Release(gomock.Any(), testStreamArn, gomock.Any()).Return(nil).Times(2)
+	s.checkpoints.EXPECT().Load(ctx, testStreamArn, gomock.Any()).Return("", nil).Times(2)
+	s.checkpoints.EXPECT().Save(ctx, testStreamArn, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	s.streamsClient.EXPECT().
+		GetShardIterator(ctx, gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			in *dynamodbstreams.GetShardIteratorInput,
+			_ ...func(*dynamodbstreams.Options),
+		) (*dynamodbstreams.GetShardIteratorOutput, error) {
+			return &dynamodbstreams.GetShardIteratorOutput{ShardIterator: in.ShardId}, nil
+		}).
+		Times(2)
+
+	parentRecord := newRecord("1")
+	childRecord := newRecord("2")
+	s.streamsClient.EXPECT().
+		GetRecords(ctx, gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			in *dynamodbstreams.GetRecordsInput,
+			_ ...func(*dynamodbstreams.Options),
+		) (*dynamodbstreams.GetRecordsOutput, error) {
+			if aws.ToString(in.ShardIterator) == "parent" {
+				return &dynamodbstreams.GetRecordsOutput{Records: []types.Record{parentRecord}}, nil
+			}
+			return &dynamodbstreams.GetRecordsOutput{Records: []types.Record{childRecord}}, nil
+		}).
+		Times(2)
+
+	gomock.InOrder(
+		s.handler.EXPECT().Handle(ctx, parentRecord).Return(nil),
+		s.handler.EXPECT().Handle(ctx, childRecord).DoAndReturn(func(context.Context, types.Record) error {
+			cancel()
+			return nil
+		}),
+	)
+
+	err := s.reader.Read(ctx, testTableName, s.handler)
+	s.Require().NoError(err)
+}
+
+func (s *StreamReaderUnitTestSuite) TestReadShardSkipsShardLeasedByAnotherReader() {
+	ctx := context.Background()
+
+	s.checkpoints.EXPECT().Lease(ctx, testStreamArn, "shard-1", time.Hour).Return(false, nil)
+
+	s.Require().False(s.reader.readShard(ctx, testStreamArn, "shard-1", s.handler))
+}
+
+func (s *StreamReaderUnitTestSuite) TestReadShardStopsWhenLeaseIsLost() {
+	ctx := context.Background()
+	s.expectLease(ctx, "shard-1")
+	record := newRecord("101")
+
+	s.checkpoints.EXPECT().Load(ctx, testStreamArn, "shard-1").Return("", nil)
+	s.streamsClient.EXPECT().
+		GetShardIterator(ctx, gomock.Any()).
+		Return(&dynamodbstreams.GetShardIteratorOutput{ShardIterator: aws.String("it-1")}, nil)
+	s.streamsClient.EXPECT().
+		GetRecords(ctx, gomock.Any()).
+		Return(&dynamodbstreams.GetRecordsOutput{Records: []types.Record{record}, NextShardIterator: aws.String("it-2")}, nil)
+	s.handler.EXPECT().Handle(ctx, record).Return(nil)
+	s.checkpoints.EXPECT().Save(ctx, testStreamArn, "shard-1", "101").Return(ErrLeaseLost)
+
+	s.Require().False(s.reader.readShard(ctx, testStreamArn, "shard-1", s.handler))
+}
+
+func (s *StreamReaderUnitTestSuite) expectLease(ctx context.Context, shardId string) {
+	s.checkpoints.EXPECT().Lease(ctx, testStreamArn, shardId, time.Hour).Return(true, nil)
+	s.checkpoints.EXPECT().Release(gomock.Any(), testStreamArn, shardId).Return(nil)
+}
+
+func newRecord(sequenceNumber string) types.Record {
+	return types.Record{
+		EventID:   aws.String("event-" + sequenceNumber),
+		EventName: types.OperationTypeRemove,
+		Dynamodb:  &types.StreamRecord{SequenceNumber: aws.String(sequenceNumber)},
+	}
+}
@@ -0,0 +1,31 @@
+package requestid
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+const Header = "X-Request-Id"
+
+var requestIdRegexp = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type requestIdKey struct{}
+
+// FromHeader keeps a well-formed id sent by the client and generates one otherwise
+func FromHeader(header string) string {
+	if requestIdRegexp.MatchString(header) {
+		return header
+	}
+	return uuid.NewString()
+}
+
+func ContextWithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+func FromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey{}).(string)
+	return requestId
+}
@@ -0,0 +1,26 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromHeaderKeepsWellFormedIds(t *testing.T) {
+	assert.Equal(t, "req-42:retry.1", FromHeader("req-42:retry.1"))
+}
+
+func TestFromHeaderGeneratesIdForMissingOrMalformedIds(t *testing.T) {
+	for _, header := range []string{"", "has space", strings.Repeat("a", 129)} {
+		_, err := uuid.Parse(FromHeader(header))
+		assert.NoError(t, err, header)
+	}
+}
+
+func TestContextWithRequestId(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+	assert.Equal(t, "req-1", FromContext(ContextWithRequestId(context.Background(), "req-1")))
+}
@@ -19,3 +19,7 @@ func UnixToTimePtr(from *int32) *time.Time {
 	to := time.Unix(int64(*from), 0).UTC()
 	return &to
 }
+
+func DayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
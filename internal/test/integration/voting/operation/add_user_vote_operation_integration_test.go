@@ -15,7 +15,9 @@ import (
 	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/helper"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/mocks"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
 )
 
 type AddUserVoteOperationIntegrationTestSuite struct {
@@ -45,13 +47,28 @@ func (s *AddUserVoteOperationIntegrationTestSuite) SetupSuite() {
 	err = s.romancesTableHelper.CreateRomancesTable()
 	s.Require().NoError(err)
 
+	voteHistoryTableHelper, err := helper.NewVoteHistoryTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = voteHistoryTableHelper.CreateVoteHistoryTable()
+	s.Require().NoError(err)
+
 	err = s.countersTableHelper.CreateCountersTable()
 	s.Require().NoError(err)
 
+	quotasTableHelper, err := helper.NewQuotasTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = quotasTableHelper.CreateQuotasTable()
+	s.Require().NoError(err)
+
+	lastVotesTableHelper, err := helper.NewLastVotesTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = lastVotesTableHelper.CreateLastVotesTable()
+	s.Require().NoError(err)
+
 	s.ctx = context.Background()
 	s.romancesRepo = newRomancesRepository(ddbClient)
 	s.countersRepo = newCountersRepository(ddbClient)
-	s.op = operation.NewAddUserVoteOperation(s.romancesRepo, s.countersRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.op = operation.NewAddUserVoteOperation(s.romancesRepo, newExclusionFiltersRepository(), s.countersRepo, newVoteTransitionPolicy(), romanceDomain.NewVotedAtPolicy(appConfig), romanceDomain.NewEligibilityPolicy(appConfig), newVotePolicy(), newCheckVoterOperation(), newConsumeVoteQuotaOperation(ddbClient), newRecordLastVoteOperation(ddbClient), newSubmitComplimentOperation(), mocks.NewMockPublisher(gomock.NewController(s.T())), appConfig, slog.New(slog.NewTextHandler(io.Discard, nil)))
 }
 
 func (s *AddUserVoteOperationIntegrationTestSuite) SetupTest() {
@@ -73,7 +90,7 @@ func (s *AddUserVoteOperationIntegrationTestSuite) TearDownTest() {
 
 func (s *AddUserVoteOperationIntegrationTestSuite) TestAddFirstYesVote() {
 	votedAt := time.Now().UTC()
-	vote, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt)
+	vote, _, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt, nil, "")
 
 	s.Require().NoError(err)
 	s.Require().Equal(romancesValueObject.VoteTypeYes, vote.VoteType)
@@ -97,7 +114,7 @@ func (s *AddUserVoteOperationIntegrationTestSuite) TestAddFirstYesVote() {
 
 func (s *AddUserVoteOperationIntegrationTestSuite) TestAddFirstNoVote() {
 	votedAt := time.Now().UTC()
-	vote, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeNo, votedAt)
+	vote, _, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeNo, votedAt, nil, "")
 
 	s.Require().NoError(err)
 	s.Require().Equal(romancesValueObject.VoteTypeNo, vote.VoteType)
@@ -122,11 +139,11 @@ func (s *AddUserVoteOperationIntegrationTestSuite) TestAddFirstNoVote() {
 func (s *AddUserVoteOperationIntegrationTestSuite) TestAddInvalidVoteTransition() {
 	// Setup: Add a CRUSH vote (terminal state)
 	votedAt := time.Now().UTC()
-	_, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, votedAt)
+	_, _, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeCrush, votedAt, nil, "")
 	s.Require().NoError(err)
 
 	// Test: Try to add a YES vote (invalid transition from Crush)
-	_, err = s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt)
+	_, _, err = s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt, nil, "")
 
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, romanceDomain.ErrWrongVote)
@@ -136,11 +153,11 @@ func (s *AddUserVoteOperationIntegrationTestSuite) TestAddInvalidVoteTransition(
 func (s *AddUserVoteOperationIntegrationTestSuite) TestValidVoteTransition() {
 	// Setup: Add a NO vote
 	votedAt := time.Now().UTC()
-	_, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeNo, votedAt)
+	_, _, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeNo, votedAt, nil, "")
 	s.Require().NoError(err)
 
 	// Test: Change to YES vote (valid transition)
-	vote, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt)
+	vote, _, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, votedAt, nil, "")
 
 	s.Require().NoError(err)
 	s.Require().Equal(romancesValueObject.VoteTypeYes, vote.VoteType)
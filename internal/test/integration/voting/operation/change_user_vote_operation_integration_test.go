@@ -46,14 +46,29 @@ func (s *ChangeUserVoteOperationIntegrationTestSuite) SetupSuite() {
 	err = s.romancesTableHelper.CreateRomancesTable()
 	s.Require().NoError(err)
 
+	voteHistoryTableHelper, err := helper.NewVoteHistoryTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = voteHistoryTableHelper.CreateVoteHistoryTable()
+	s.Require().NoError(err)
+
 	err = s.countersTableHelper.CreateCountersTable()
 	s.Require().NoError(err)
 
+	quotasTableHelper, err := helper.NewQuotasTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = quotasTableHelper.CreateQuotasTable()
+	s.Require().NoError(err)
+
+	lastVotesTableHelper, err := helper.NewLastVotesTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = lastVotesTableHelper.CreateLastVotesTable()
+	s.Require().NoError(err)
+
 	s.ctx = context.Background()
 	s.romancesRepo = newRomancesRepository(ddbClient)
 	s.countersRepo = newCountersRepository(ddbClient)
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
-	s.op = operation.NewChangeUserVoteOperation(s.romancesRepo, s.countersRepo, logger)
+	s.op = operation.NewChangeUserVoteOperation(s.romancesRepo, newExclusionFiltersRepository(), s.countersRepo, newVoteTransitionPolicy(), romanceDomain.NewVotedAtPolicy(appConfig), newCheckVoterOperation(), newConsumeVoteQuotaOperation(ddbClient), newRecordLastVoteOperation(ddbClient), newSubmitComplimentOperation(), logger)
 }
 
 func (s *ChangeUserVoteOperationIntegrationTestSuite) SetupTest() {
@@ -77,11 +92,11 @@ func (s *ChangeUserVoteOperationIntegrationTestSuite) TestChangeInvalidVoteTrans
 	// Setup: Add a CRUSH vote (terminal state) directly via repository
 	romance := romanceEntity.CreateEmptyRomance(s.voteId)
 	votedAt := time.Now().UTC()
-	_, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeCrush, votedAt)
+	_, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeCrush, votedAt, nil, nil)
 	s.Require().NoError(err)
 
 	// Test: Try to change to YES vote (invalid transition from Crush)
-	_, err = s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes)
+	_, _, err = s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, time.Now(), nil, "")
 
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, romanceDomain.ErrWrongVote)
@@ -92,7 +107,7 @@ func (s *ChangeUserVoteOperationIntegrationTestSuite) TestValidVoteTransition()
 	// Setup: Add a NO vote directly via repository (without incrementing counters)
 	romance := romanceEntity.CreateEmptyRomance(s.voteId)
 	votedAt := time.Now().UTC()
-	_, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeNo, votedAt)
+	_, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeNo, votedAt, nil, nil)
 	s.Require().NoError(err)
 
 	// Verify initial counters are all zero (repository doesn't increment counters)
@@ -106,7 +121,7 @@ func (s *ChangeUserVoteOperationIntegrationTestSuite) TestValidVoteTransition()
 	s.Require().Equal(uint32(0), countersBefore.IncomingNo)
 
 	// Test: Change to YES vote (valid transition)
-	vote, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes)
+	vote, _, err := s.op.Run(s.ctx, s.voteId, romancesValueObject.VoteTypeYes, time.Now(), nil, "")
 
 	s.Require().NoError(err)
 	s.Require().Equal(romancesValueObject.VoteTypeYes, vote.VoteType)
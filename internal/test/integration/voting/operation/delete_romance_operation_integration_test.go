@@ -36,9 +36,14 @@ func (s *DeleteRomanceOperationIntegrationTestSuite) SetupSuite() {
 	err = s.romancesTableHelper.CreateRomancesTable()
 	s.Require().NoError(err)
 
+	voteHistoryTableHelper, err := helper.NewVoteHistoryTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = voteHistoryTableHelper.CreateVoteHistoryTable()
+	s.Require().NoError(err)
+
 	s.ctx = context.Background()
 	s.romancesRepo = newRomancesRepository(ddbClient)
-	s.op = operation.NewDeleteRomanceOperation(s.romancesRepo)
+	s.op = operation.NewDeleteRomanceOperation(s.romancesRepo, newExclusionFiltersRepository())
 }
 
 func (s *DeleteRomanceOperationIntegrationTestSuite) SetupTest() {
@@ -62,7 +67,7 @@ func (s *DeleteRomanceOperationIntegrationTestSuite) TestDeleteRomanceWhenRomanc
 	// Setup: Create a romance with votes
 	romance := romanceEntity.CreateEmptyRomance(s.voteId)
 	votedAt := time.Now().UTC()
-	_, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt)
+	_, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt, nil, nil)
 	s.Require().NoError(err)
 
 	// Test: Delete the romance
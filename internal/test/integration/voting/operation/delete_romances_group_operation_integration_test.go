@@ -37,6 +37,11 @@ func (s *DeleteRomancesGroupOperationIntegrationTestSuite) SetupSuite() {
 	err = s.romancesTableHelper.CreateRomancesTable()
 	s.Require().NoError(err)
 
+	voteHistoryTableHelper, err := helper.NewVoteHistoryTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = voteHistoryTableHelper.CreateVoteHistoryTable()
+	s.Require().NoError(err)
+
 	s.countryId = uint16(11)
 	s.ctx = context.Background()
 }
@@ -62,7 +67,7 @@ func (s *DeleteRomancesGroupOperationIntegrationTestSuite) TestDeleteRomancesGro
 
 		romance := romanceEntity.CreateEmptyRomance(voteId)
 		votedAt := time.Now().UTC()
-		_, err = repo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt)
+		_, err = repo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt, nil, nil)
 		s.Require().NoError(err)
 	}
 
@@ -40,10 +40,15 @@ func (s *DeleteUserVoteOperationIntegrationTestSuite) SetupSuite() {
 	err = s.romancesTableHelper.CreateRomancesTable()
 	s.Require().NoError(err)
 
+	voteHistoryTableHelper, err := helper.NewVoteHistoryTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = voteHistoryTableHelper.CreateVoteHistoryTable()
+	s.Require().NoError(err)
+
 	s.ctx = context.Background()
 	s.romancesRepo = newRomancesRepository(ddbClient)
 	s.countersRepo = newCountersRepository(ddbClient)
-	s.op = operation.NewDeleteUserVoteOperation(s.romancesRepo, s.countersRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.op = operation.NewDeleteUserVoteOperation(s.romancesRepo, newExclusionFiltersRepository(), s.countersRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
 }
 
 func (s *DeleteUserVoteOperationIntegrationTestSuite) SetupTest() {
@@ -67,11 +72,11 @@ func (s *DeleteUserVoteOperationIntegrationTestSuite) TestDeleteVoteWhenVoteExis
 	// Setup: Create a vote
 	romance := romanceEntity.CreateEmptyRomance(s.voteId)
 	votedAt := time.Now().UTC()
-	_, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt)
+	_, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt, nil, nil)
 	s.Require().NoError(err)
 
 	// Test: Delete the vote
-	err = s.op.Run(s.ctx, s.voteId)
+	_, err = s.op.Run(s.ctx, s.voteId)
 
 	s.Require().NoError(err)
 
@@ -91,7 +96,7 @@ func (s *DeleteUserVoteOperationIntegrationTestSuite) TestDeleteVoteWhenVoteExis
 
 func (s *DeleteUserVoteOperationIntegrationTestSuite) TestDeleteVoteWhenVoteDoesNotExist() {
 	// Test: Delete vote when no vote exists (should succeed without error)
-	err := s.op.Run(s.ctx, s.voteId)
+	_, err := s.op.Run(s.ctx, s.voteId)
 
 	s.Require().NoError(err)
 }
@@ -36,6 +36,11 @@ func (s *GetRomanceOperationIntegrationTestSuite) SetupSuite() {
 	err = s.romancesTableHelper.CreateRomancesTable()
 	s.Require().NoError(err)
 
+	voteHistoryTableHelper, err := helper.NewVoteHistoryTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = voteHistoryTableHelper.CreateVoteHistoryTable()
+	s.Require().NoError(err)
+
 	s.ctx = context.Background()
 	s.romancesRepo = newRomancesRepository(ddbClient)
 	s.op = operation.NewGetRomanceOperation(s.romancesRepo)
@@ -72,14 +77,14 @@ func (s *GetRomanceOperationIntegrationTestSuite) TestGetRomanceWhenRomanceExist
 	// Setup: Create a romance with votes from both sides
 	activeRomance := romanceEntity.CreateEmptyRomance(s.voteId)
 	votedAt := time.Now().UTC()
-	_, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, activeRomance, romancesValueObject.VoteTypeYes, votedAt)
+	_, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, activeRomance, romancesValueObject.VoteTypeYes, votedAt, nil, nil)
 	s.Require().NoError(err)
 
 	// Add peer vote
 	peerVoteId := s.voteId.ToPeerVoteId()
 	peerRomance, err := s.romancesRepo.GetRomance(s.ctx, peerVoteId)
 	s.Require().NoError(err)
-	_, err = s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, peerRomance, romancesValueObject.VoteTypeNo, votedAt)
+	_, err = s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, peerRomance, romancesValueObject.VoteTypeNo, votedAt, nil, nil)
 	s.Require().NoError(err)
 
 	// Test: Get the romance from active user perspective
@@ -36,6 +36,11 @@ func (s *GetUserVoteOperationIntegrationTestSuite) SetupSuite() {
 	err = s.romancesTableHelper.CreateRomancesTable()
 	s.Require().NoError(err)
 
+	voteHistoryTableHelper, err := helper.NewVoteHistoryTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = voteHistoryTableHelper.CreateVoteHistoryTable()
+	s.Require().NoError(err)
+
 	s.ctx = context.Background()
 	s.romancesRepo = newRomancesRepository(ddbClient)
 	s.op = operation.NewGetUserVoteOperation(s.romancesRepo)
@@ -77,7 +82,7 @@ func (s *GetUserVoteOperationIntegrationTestSuite) TestGetVoteWhenVoteExists() {
 	// Setup: Create a romance with a vote
 	romance := romanceEntity.CreateEmptyRomance(s.voteId)
 	votedAt := time.Now().UTC()
-	updatedRomance, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt)
+	updatedRomance, err := s.romancesRepo.AddActiveUserVoteToRomance(s.ctx, romance, romancesValueObject.VoteTypeYes, votedAt, nil, nil)
 	s.Require().NoError(err)
 
 	// Test: Get the vote
@@ -9,10 +9,18 @@ import (
 	"testing"
 
 	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/application/operation"
+	counterDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
 	counterRepository "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/repository"
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	rewindDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
+	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
 	romanceRepository "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/moderation"
 	infraDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/userservice"
 	platformDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/helper"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/testcontainer"
 )
 
@@ -46,7 +54,63 @@ func newRomancesRepository(client platformDynamodb.Client) romanceRepository.Rom
 	return infraDynamodb.NewRomancesRepository(client, appConfig, logger)
 }
 
+// newExclusionFiltersRepository creates the table of the filters when it does not exist yet
+func newExclusionFiltersRepository() romanceRepository.ExclusionFiltersRepository {
+	exclusionFiltersTableHelper, err := helper.NewExclusionFiltersTableHelper(ddbClient)
+	if err != nil {
+		log.Fatalf("failed to create exclusion filters table helper: %v", err)
+	}
+	if err = exclusionFiltersTableHelper.CreateExclusionFiltersTable(); err != nil {
+		log.Fatalf("failed to create exclusion filters table: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return infraDynamodb.NewExclusionFiltersRepository(ddbClient, appConfig, logger)
+}
+
 func newCountersRepository(client platformDynamodb.Client) counterRepository.CountersRepository {
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	return infraDynamodb.NewCountersRepository(client, appConfig, logger)
 }
+
+func newConsumeVoteQuotaOperation(client platformDynamodb.Client) *operation.ConsumeVoteQuotaOperation {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	quotaPolicy, err := quotaDomain.NewQuotaPolicy(appConfig)
+	if err != nil {
+		log.Fatalf("failed to load vote quotas: %v", err)
+	}
+	return operation.NewConsumeVoteQuotaOperation(infraDynamodb.NewQuotasRepository(client, appConfig, logger), quotaPolicy, userservice.NewStaticTimeZoneProvider(), logger)
+}
+
+func newRecordLastVoteOperation(client platformDynamodb.Client) *operation.RecordLastVoteOperation {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return operation.NewRecordLastVoteOperation(infraDynamodb.NewLastVotesRepository(client, appConfig, logger), rewindDomain.NewRewindPolicy(appConfig), logger)
+}
+
+// newSubmitComplimentOperation approves every compliment
+func newSubmitComplimentOperation() *operation.SubmitComplimentOperation {
+	return operation.NewSubmitComplimentOperation(
+		moderation.NewKeywordModerationProvider(nil, true),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+}
+
+// newCheckVoterOperation treats every user as active and entitled
+func newCheckVoterOperation() *operation.CheckVoterOperation {
+	return operation.NewCheckVoterOperation(userservice.NewStaticUserStatusProvider(), userservice.NewStaticEntitlementProvider())
+}
+
+func newVoteTransitionPolicy() *romanceDomain.VoteTransitionPolicy {
+	policy, err := romanceDomain.NewVoteTransitionPolicy(appConfig)
+	if err != nil {
+		log.Fatalf("failed to load vote transitions: %v", err)
+	}
+	return policy
+}
+
+func newVotePolicy() *counterDomain.VotePolicy {
+	policy, err := counterDomain.NewVotePolicy(appConfig)
+	if err != nil {
+		log.Fatalf("failed to load vote limits: %v", err)
+	}
+	return policy
+}
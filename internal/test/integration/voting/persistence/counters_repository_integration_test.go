@@ -5,11 +5,15 @@ import (
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
 	"io"
 	"log/slog"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	counterDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
 	counterEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/entity"
 	countersRepository "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/repository"
+	countersValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	infraDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
 	platformDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
@@ -61,6 +65,110 @@ func (s *CountersRepositoryTestSuite) TestGetEmptyLifetimeCounter() {
 	s.assertEmptyCountersGroup(s.activeUserKey, countersGroup)
 }
 
+func (s *CountersRepositoryTestSuite) TestMatchCountersAreNotDecrementedBelowZero() {
+	ctx := context.Background()
+	repo := newCountersRepository(ddbClient)
+
+	voteId, err := sharedValueObject.NewVoteId(s.activeUserKey.CountryId(), s.activeUserKey.ActiveUserId(), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	counterUpdateGroup, err := countersValueObject.NewCounterUpdateGroup(time.Now())
+	s.Require().NoError(err)
+
+	repo.IncrMatchCounters(ctx, voteId, counterUpdateGroup)
+	repo.IncrMatchCounters(ctx, voteId, counterUpdateGroup)
+	repo.DecrMatchCounters(ctx, voteId)
+
+	countersGroup, err := repo.GetLifetimeCounter(ctx, s.activeUserKey)
+	s.Require().NoError(err)
+	s.Require().Equal(uint32(1), countersGroup.Matches)
+
+	repo.DecrMatchCounters(ctx, voteId)
+	repo.DecrMatchCounters(ctx, voteId)
+
+	countersGroup, err = repo.GetLifetimeCounter(ctx, s.activeUserKey)
+	s.Require().NoError(err)
+	s.Require().Equal(uint32(0), countersGroup.Matches)
+
+	hourlyCounters, err := repo.GetHourlyCountersSince(ctx, s.activeUserKey, counterUpdateGroup.HourStartTime())
+	s.Require().NoError(err)
+	s.Require().Len(hourlyCounters, 1)
+	s.Require().Equal(uint32(2), hourlyCounters[0].Matches)
+}
+
+func (s *CountersRepositoryTestSuite) TestVoteLimitsAreReservedUpToTheLimit() {
+	ctx := context.Background()
+	repo := newCountersRepository(ddbClient)
+
+	now := time.Now().UTC()
+	windows := []counterDomain.VoteLimitWindow{
+		{Counter: "yes", Window: counterDomain.LimitWindowHour, Start: now.Truncate(time.Hour), Limit: 3},
+		{Counter: "yes", Window: counterDomain.LimitWindowDay, Start: now.Truncate(24 * time.Hour), Limit: 2},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.ReserveVoteLimits(ctx, s.activeUserKey, windows)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	reserved := 0
+	for err := range errs {
+		if err == nil {
+			reserved++
+			continue
+		}
+		var limitErr *counterDomain.VoteRateLimitError
+		if s.ErrorAs(err, &limitErr) {
+			s.Equal(counterDomain.LimitWindowDay, limitErr.Window)
+		}
+	}
+	s.Require().Equal(2, reserved)
+
+	s.Require().NoError(repo.ReleaseVoteLimits(ctx, s.activeUserKey, windows))
+	s.Require().NoError(repo.ReserveVoteLimits(ctx, s.activeUserKey, windows))
+
+	// the reservations are kept apart from the counters
+	hourlyCounters, err := repo.GetHourlyCountersSince(ctx, s.activeUserKey, now.Add(-time.Hour))
+	s.Require().NoError(err)
+	s.Require().Empty(hourlyCounters)
+}
+
+func (s *CountersRepositoryTestSuite) TestRollupsFollowIncrementsAndDecrements() {
+	ctx := context.Background()
+	repo := newCountersRepository(ddbClient)
+
+	voteId, err := sharedValueObject.NewVoteId(s.activeUserKey.CountryId(), s.activeUserKey.ActiveUserId(), uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	now := time.Now()
+	counterUpdateGroup, err := countersValueObject.NewCounterUpdateGroup(now)
+	s.Require().NoError(err)
+
+	repo.IncrYesCounters(ctx, voteId, counterUpdateGroup)
+	repo.IncrYesCounters(ctx, voteId, counterUpdateGroup)
+	repo.DecrYesCounters(ctx, voteId, counterUpdateGroup)
+
+	for _, granularity := range []countersValueObject.CountersGranularity{
+		countersValueObject.CountersGranularityHour,
+		countersValueObject.CountersGranularityDay,
+		countersValueObject.CountersGranularityWeek,
+	} {
+		countersRange, err := countersValueObject.NewCountersRange(now, now.Add(time.Second), granularity)
+		s.Require().NoError(err)
+
+		counters, err := repo.GetCountersRange(ctx, s.activeUserKey, countersRange)
+		s.Require().NoError(err)
+		s.Require().Len(counters, 1, granularity.String())
+		s.Require().Equal(int32(countersRange.From().Unix()), counters[0].HourUnixTimestamp, granularity.String())
+		s.Require().Equal(uint32(1), counters[0].OutgoingYes, granularity.String())
+	}
+}
+
 func newCountersRepository(client platformDynamodb.Client) countersRepository.CountersRepository {
 	appConfig := config.Load()
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
@@ -0,0 +1,113 @@
+package persistence
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	romanceRepository "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	infraDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/helper"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExclusionFiltersRepositoryTestSuite struct {
+	suite.Suite
+	userKey sharedValueObject.ActiveUserKey
+	ctx     context.Context
+}
+
+func TestExclusionFiltersRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ExclusionFiltersRepositoryTestSuite))
+}
+
+func (s *ExclusionFiltersRepositoryTestSuite) SetupSuite() {
+	exclusionFiltersTableHelper, err := helper.NewExclusionFiltersTableHelper(ddbClient)
+	s.Require().NoError(err)
+	s.Require().NoError(exclusionFiltersTableHelper.CreateExclusionFiltersTable())
+	s.ctx = context.Background()
+}
+
+func (s *ExclusionFiltersRepositoryTestSuite) SetupTest() {
+	userKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.userKey = userKey
+}
+
+func (s *ExclusionFiltersRepositoryTestSuite) TestFilterIsSharedByInstances() {
+	// each repository stands for another instance of the service
+	first, second := newExclusionFiltersRepository(), newExclusionFiltersRepository()
+	builtPeerId, addedPeerId := uuidhelper.NewUUID(s.T()), uuidhelper.NewUUID(s.T())
+
+	second.AddToExclusionFilter(s.ctx, s.userKey, addedPeerId)
+	_, ok, err := first.GetExclusionFilter(s.ctx, s.userKey)
+	s.Require().NoError(err)
+	s.Require().False(ok, "nothing is added without a built filter")
+
+	filter, err := romancesValueObject.NewExclusionFilter(10, 0.01)
+	s.Require().NoError(err)
+	filter.Add(builtPeerId)
+	s.Require().NoError(first.SaveExclusionFilter(s.ctx, s.userKey, filter))
+	second.AddToExclusionFilter(s.ctx, s.userKey, addedPeerId)
+
+	kept, ok, err := first.GetExclusionFilter(s.ctx, s.userKey)
+	s.Require().NoError(err)
+	s.Require().True(ok)
+	s.Require().True(kept.MayContain(builtPeerId))
+	s.Require().True(kept.MayContain(addedPeerId))
+
+	second.DeleteExclusionFilter(s.ctx, s.userKey)
+	_, ok, err = first.GetExclusionFilter(s.ctx, s.userKey)
+	s.Require().NoError(err)
+	s.Require().False(ok)
+}
+
+func (s *ExclusionFiltersRepositoryTestSuite) TestFilterLargerThanAnItemIsKept() {
+	repo := newExclusionFiltersRepository()
+	peerIds := make([]uuid.UUID, 0, 1000)
+
+	// about 1.2 MB of bits, three times the item limit
+	filter, err := romancesValueObject.NewExclusionFilter(1_000_000, 0.01)
+	s.Require().NoError(err)
+	for range cap(peerIds) {
+		peerId := uuidhelper.NewUUID(s.T())
+		peerIds = append(peerIds, peerId)
+		filter.Add(peerId)
+	}
+	s.Require().NoError(repo.SaveExclusionFilter(s.ctx, s.userKey, filter))
+
+	kept, ok, err := repo.GetExclusionFilter(s.ctx, s.userKey)
+	s.Require().NoError(err)
+	s.Require().True(ok)
+	s.Require().Equal(filter, kept)
+	for _, peerId := range peerIds {
+		s.Require().True(kept.MayContain(peerId))
+	}
+}
+
+func (s *ExclusionFiltersRepositoryTestSuite) TestFiltersOfCountriesAreKeptApart() {
+	repo := newExclusionFiltersRepository()
+	otherCountryKey, err := sharedValueObject.NewActiveUserKey(22, s.userKey.ActiveUserId())
+	s.Require().NoError(err)
+
+	filter, err := romancesValueObject.NewExclusionFilter(10, 0.01)
+	s.Require().NoError(err)
+	s.Require().NoError(repo.SaveExclusionFilter(s.ctx, s.userKey, filter))
+
+	_, ok, err := repo.GetExclusionFilter(s.ctx, otherCountryKey)
+	s.Require().NoError(err)
+	s.Require().False(ok)
+	_, ok, err = repo.GetExclusionFilter(s.ctx, s.userKey)
+	s.Require().NoError(err)
+	s.Require().True(ok)
+}
+
+func newExclusionFiltersRepository() romanceRepository.ExclusionFiltersRepository {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return infraDynamodb.NewExclusionFiltersRepository(ddbClient, appConfig, logger)
+}
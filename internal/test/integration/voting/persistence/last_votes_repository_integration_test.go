@@ -0,0 +1,123 @@
+package persistence
+
+import (
+	"context"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	quotaEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	rewindDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind"
+	rewindEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
+	lastVotesRepository "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/repository"
+	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	infraDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
+	platformDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/helper"
+	"github.com/stretchr/testify/suite"
+)
+
+type LastVotesRepositoryTestSuite struct {
+	suite.Suite
+	repo          lastVotesRepository.LastVotesRepository
+	activeUserKey sharedValueObject.ActiveUserKey
+	ctx           context.Context
+}
+
+func TestLastVotesRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(LastVotesRepositoryTestSuite))
+}
+
+func (s *LastVotesRepositoryTestSuite) SetupSuite() {
+	lastVotesTableHelper, err := helper.NewLastVotesTableHelper(ddbClient)
+	s.Require().NoError(err)
+	s.Require().NoError(lastVotesTableHelper.CreateLastVotesTable())
+
+	s.repo = newLastVotesRepository(ddbClient)
+	s.ctx = context.Background()
+}
+
+func (s *LastVotesRepositoryTestSuite) SetupTest() {
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.activeUserKey = activeUserKey
+}
+
+func (s *LastVotesRepositoryTestSuite) TestSaveAndGetLastVotes() {
+	lastVotes, err := s.repo.GetLastVotes(s.ctx, s.activeUserKey)
+	s.Require().NoError(err)
+	s.Require().Empty(lastVotes.Votes)
+
+	peerId := uuidhelper.NewUUID(s.T())
+	voteId, err := sharedValueObject.NewCrossCountryVoteId(s.activeUserKey.CountryId(), s.activeUserKey.ActiveUserId(), 22, peerId)
+	s.Require().NoError(err)
+	votedAt := time.Unix(time.Now().Unix(), 0)
+	previousVotedAt := votedAt.Add(-time.Hour)
+	recordedAt := time.UnixMilli(time.Now().UnixMilli())
+	vote := rewindEntity.LastVote{
+		PeerId:        peerId,
+		PeerCountryId: 22,
+		VoteType:      romancesValueObject.VoteTypeYes,
+		VotedAt:       votedAt,
+		PreviousVote: romanceEntity.Vote{
+			Id:        voteId,
+			VoteType:  romancesValueObject.VoteTypeNo,
+			VotedAt:   &previousVotedAt,
+			CreatedAt: &previousVotedAt,
+		},
+		RecordedAt: recordedAt,
+		CountedYes: true,
+		PreviousUnmatch: &romanceEntity.Unmatch{
+			UnmatchedBy:       peerId,
+			UnmatchedAt:       previousVotedAt.UTC(),
+			Reason:            romancesValueObject.UnmatchReasonInappropriate,
+			RevoteBannedUntil: votedAt.Add(-time.Minute).UTC(),
+		},
+		Consumption: &quotaEntity.Consumption{
+			ActiveUserKey: s.activeUserKey,
+			VoteType:      romancesValueObject.VoteTypeYes,
+			Day:           votedAt.Truncate(24 * time.Hour).UTC(),
+		},
+	}
+	lastVotes.Push(vote, 5)
+	s.Require().NoError(s.repo.SaveLastVotes(s.ctx, lastVotes, recordedAt.Add(time.Minute)))
+
+	stored, err := s.repo.GetLastVotes(s.ctx, s.activeUserKey)
+	s.Require().NoError(err)
+	s.Require().Equal(uint32(1), stored.Version)
+	s.Require().Len(stored.Votes, 1)
+	s.Require().Equal(vote.PeerId, stored.Votes[0].PeerId)
+	s.Require().Equal(vote.PeerCountryId, stored.Votes[0].PeerCountryId)
+	s.Require().Equal(voteId, stored.Votes[0].PreviousVote.Id)
+	s.Require().Equal(vote.VoteType, stored.Votes[0].VoteType)
+	s.Require().True(vote.VotedAt.Equal(stored.Votes[0].VotedAt))
+	s.Require().True(vote.RecordedAt.Equal(stored.Votes[0].RecordedAt))
+	s.Require().Equal(romancesValueObject.VoteTypeNo, stored.Votes[0].PreviousVote.VoteType)
+	s.Require().True(previousVotedAt.Equal(*stored.Votes[0].PreviousVote.VotedAt))
+	s.Require().Nil(stored.Votes[0].PreviousVote.UpdatedAt)
+	s.Require().True(stored.Votes[0].CountedYes)
+	s.Require().Equal(vote.PreviousUnmatch, stored.Votes[0].PreviousUnmatch)
+	s.Require().Equal(vote.Consumption, stored.Votes[0].Consumption)
+}
+
+func (s *LastVotesRepositoryTestSuite) TestSaveWithOutdatedVersionIsRejected() {
+	lastVotes, err := s.repo.GetLastVotes(s.ctx, s.activeUserKey)
+	s.Require().NoError(err)
+	expiresAt := time.Now().Add(time.Minute)
+
+	s.Require().NoError(s.repo.SaveLastVotes(s.ctx, lastVotes, expiresAt))
+	s.Require().ErrorIs(s.repo.SaveLastVotes(s.ctx, lastVotes, expiresAt), rewindDomain.ErrVersionConflict)
+
+	lastVotes, err = s.repo.GetLastVotes(s.ctx, s.activeUserKey)
+	s.Require().NoError(err)
+	s.Require().NoError(s.repo.SaveLastVotes(s.ctx, lastVotes, expiresAt))
+}
+
+func newLastVotesRepository(client platformDynamodb.Client) lastVotesRepository.LastVotesRepository {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return infraDynamodb.NewLastVotesRepository(client, appConfig, logger)
+}
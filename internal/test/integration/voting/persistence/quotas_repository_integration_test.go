@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"context"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/uuidhelper"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/bmbl-bumble2/recs-votes-storage/config"
+	quotaDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota"
+	quotaEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	quotasRepository "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/repository"
+	romancesValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	infraDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
+	platformDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/helper"
+	"github.com/stretchr/testify/suite"
+)
+
+type QuotasRepositoryTestSuite struct {
+	suite.Suite
+	repo          quotasRepository.QuotasRepository
+	activeUserKey sharedValueObject.ActiveUserKey
+	ctx           context.Context
+}
+
+func TestQuotasRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(QuotasRepositoryTestSuite))
+}
+
+func (s *QuotasRepositoryTestSuite) SetupSuite() {
+	quotasTableHelper, err := helper.NewQuotasTableHelper(ddbClient)
+	s.Require().NoError(err)
+	s.Require().NoError(quotasTableHelper.CreateQuotasTable())
+
+	s.repo = newQuotasRepository(ddbClient)
+	s.ctx = context.Background()
+}
+
+func (s *QuotasRepositoryTestSuite) SetupTest() {
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	s.activeUserKey = activeUserKey
+}
+
+func (s *QuotasRepositoryTestSuite) TestConsumeUntilExhaustedAndRefund() {
+	day, resetAt := quotaDomain.Day(time.Now(), time.UTC)
+	consumption := quotaEntity.Consumption{
+		ActiveUserKey: s.activeUserKey,
+		VoteType:      romancesValueObject.VoteTypeCrush,
+		Day:           day,
+	}
+
+	s.Require().NoError(s.repo.Consume(s.ctx, consumption, 2, resetAt))
+	s.Require().NoError(s.repo.Consume(s.ctx, consumption, 2, resetAt))
+	s.Require().ErrorIs(s.repo.Consume(s.ctx, consumption, 2, resetAt), quotaDomain.ErrQuotaExhausted)
+
+	s.Require().NoError(s.repo.Refund(s.ctx, consumption))
+	usage, err := s.repo.GetUsage(s.ctx, s.activeUserKey, day)
+	s.Require().NoError(err)
+	s.Require().Equal(map[romancesValueObject.VoteType]uint32{romancesValueObject.VoteTypeCrush: 1}, usage)
+
+	s.Require().NoError(s.repo.Consume(s.ctx, consumption, 2, resetAt))
+}
+
+func (s *QuotasRepositoryTestSuite) TestRefundWithoutConsumptionIsIgnored() {
+	day, _ := quotaDomain.Day(time.Now(), time.UTC)
+	consumption := quotaEntity.Consumption{
+		ActiveUserKey: s.activeUserKey,
+		VoteType:      romancesValueObject.VoteTypeCompliment,
+		Day:           day,
+	}
+
+	s.Require().NoError(s.repo.Refund(s.ctx, consumption))
+	usage, err := s.repo.GetUsage(s.ctx, s.activeUserKey, day)
+	s.Require().NoError(err)
+	s.Require().Empty(usage)
+}
+
+func (s *QuotasRepositoryTestSuite) TestSetAndGetOverrides() {
+	overrides, err := s.repo.GetOverrides(s.ctx, s.activeUserKey)
+	s.Require().NoError(err)
+	s.Require().Empty(overrides)
+
+	expected := map[romancesValueObject.VoteType]uint32{
+		romancesValueObject.VoteTypeCrush:      5,
+		romancesValueObject.VoteTypeCompliment: 0,
+	}
+	s.Require().NoError(s.repo.SetOverrides(s.ctx, s.activeUserKey, expected))
+
+	overrides, err = s.repo.GetOverrides(s.ctx, s.activeUserKey)
+	s.Require().NoError(err)
+	s.Require().Equal(expected, overrides)
+}
+
+func newQuotasRepository(client platformDynamodb.Client) quotasRepository.QuotasRepository {
+	appConfig := config.Load()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return infraDynamodb.NewQuotasRepository(client, appConfig, logger)
+}
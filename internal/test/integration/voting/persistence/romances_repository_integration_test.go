@@ -1,7 +1,9 @@
 package persistence
 
 import (
+	"bytes"
 	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/bmbl-bumble2/recs-votes-storage/config"
 	romanceDomain "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance"
 	romanceEntity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/entity"
@@ -10,6 +12,7 @@ import (
 	sharedValueObject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
 	infraDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/infrastructure/persistence"
 	platformDynamodb "github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb"
+	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/requestid"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/shared/timeutil"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib"
 	"github.com/bmbl-bumble2/recs-votes-storage/internal/testlib/helper"
@@ -19,6 +22,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"io"
 	"log/slog"
+	"slices"
 	"testing"
 	"time"
 )
@@ -41,6 +45,11 @@ func (s *RomancesRepositoryTestSuite) SetupSuite() {
 	err = s.romancesTableHelper.CreateRomancesTable()
 	s.Require().NoError(err)
 
+	voteHistoryTableHelper, err := helper.NewVoteHistoryTableHelper(ddbClient)
+	s.Require().NoError(err)
+	err = voteHistoryTableHelper.CreateVoteHistoryTable()
+	s.Require().NoError(err)
+
 	activeUserId := uuidhelper.NewUUID(s.T())
 	peerUserId := uuidhelper.NewUUID(s.T())
 	countryId := uint16(11)
@@ -79,7 +88,7 @@ func (s *RomancesRepositoryTestSuite) TestAddVoteToEmptyRomance() {
 	romance := romanceEntity.CreateEmptyRomance(s.voteId)
 
 	// Adding a YES vote for the active user
-	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now())
+	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now(), nil, nil)
 	s.Require().NoError(err)
 	s.assertRomanceInDbMatchesExpected(
 		newExpectedRomanceParams(s.voteId, rvo.VoteTypeYes, rvo.VoteTypeEmpty, 1),
@@ -100,7 +109,7 @@ func (s *RomancesRepositoryTestSuite) TestGetAndAddRomanceVote() {
 	)
 
 	// step 2: Adding a YES vote for the active user
-	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now())
+	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now(), nil, nil)
 	s.Require().NoError(err)
 	s.assertRomanceInDbMatchesExpected(
 		newExpectedRomanceParams(s.voteId, rvo.VoteTypeYes, rvo.VoteTypeEmpty, 1),
@@ -122,7 +131,7 @@ func (s *RomancesRepositoryTestSuite) TestGetAndAddRomanceVote() {
 	)
 
 	// step 5: Adding a new NO vote from the peer side
-	newPeerRomance, err := repo.AddActiveUserVoteToRomance(ctx, peerRomance, rvo.VoteTypeNo, time.Now())
+	newPeerRomance, err := repo.AddActiveUserVoteToRomance(ctx, peerRomance, rvo.VoteTypeNo, time.Now(), nil, nil)
 	s.Require().NoError(err)
 	s.assertRomanceInDbMatchesExpected(
 		newExpectedRomanceParams(peerVoteId, rvo.VoteTypeNo, rvo.VoteTypeYes, 2),
@@ -146,7 +155,7 @@ func (s *RomancesRepositoryTestSuite) TestAddVoteAndRewrite() {
 
 	// step 1: Adding a NO vote for the peer user
 	peerRomance := romanceEntity.CreateEmptyRomance(peerVoteId)
-	newPeerRomance, err := repo.AddActiveUserVoteToRomance(ctx, peerRomance, rvo.VoteTypeYes, now)
+	newPeerRomance, err := repo.AddActiveUserVoteToRomance(ctx, peerRomance, rvo.VoteTypeYes, now, nil, nil)
 	s.Require().NoError(err)
 	s.assertRomanceInDbMatchesExpected(
 		newExpectedRomanceParams(peerVoteId, rvo.VoteTypeYes, rvo.VoteTypeEmpty, 1),
@@ -156,7 +165,7 @@ func (s *RomancesRepositoryTestSuite) TestAddVoteAndRewrite() {
 	// step 2: Rewriting peer vote
 	// There is no check at the persistence level for which vote we are inserting,
 	// so there may be a NO after YES
-	newPeerRomance, err = repo.AddActiveUserVoteToRomance(ctx, newPeerRomance, rvo.VoteTypeNo, now)
+	newPeerRomance, err = repo.AddActiveUserVoteToRomance(ctx, newPeerRomance, rvo.VoteTypeNo, now, nil, nil)
 	s.Require().NoError(err)
 	s.assertRomanceInDbMatchesExpected(
 		newExpectedRomanceParams(peerVoteId, rvo.VoteTypeNo, rvo.VoteTypeEmpty, 2),
@@ -172,7 +181,7 @@ func (s *RomancesRepositoryTestSuite) TestAddVoteAndRewrite() {
 	)
 
 	// step 4: Adding a YES vote to the active user romance
-	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, now)
+	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, now, nil, nil)
 	s.Require().NoError(err)
 	s.assertRomanceInDbMatchesExpected(
 		newExpectedRomanceParams(activeUserVoteId, rvo.VoteTypeYes, rvo.VoteTypeNo, 3),
@@ -180,7 +189,7 @@ func (s *RomancesRepositoryTestSuite) TestAddVoteAndRewrite() {
 	)
 
 	// step 5: Adding a NO vote for the active user
-	newRomance, err = repo.AddActiveUserVoteToRomance(ctx, newRomance, rvo.VoteTypeNo, now)
+	newRomance, err = repo.AddActiveUserVoteToRomance(ctx, newRomance, rvo.VoteTypeNo, now, nil, nil)
 	s.Require().NoError(err)
 	s.assertRomanceInDbMatchesExpected(
 		newExpectedRomanceParams(activeUserVoteId, rvo.VoteTypeNo, rvo.VoteTypeNo, 4),
@@ -199,7 +208,7 @@ func (s *RomancesRepositoryTestSuite) TestAddWithVersionConflictError() {
 	romance.Version = 10
 
 	// step 2: Adding vote
-	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, now)
+	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, now, nil, nil)
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, romanceDomain.ErrVersionConflict)
 	s.assertNilRomance(newRomance)
@@ -217,7 +226,7 @@ func (s *RomancesRepositoryTestSuite) TestAddActiveUserVoteWithWrongPeerVotePart
 	romance.PeerUserVote.VotedAt = &now
 
 	// step 2: Adding vote
-	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, now)
+	newRomance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, now, nil, nil)
 	s.Require().NoError(err)
 	// The `AddActiveUserVoteToRomance` method returns a synchronized romance
 	s.assertRomanceInDbMatchesExpected(
@@ -232,7 +241,7 @@ func (s *RomancesRepositoryTestSuite) TestDeleteRomance() {
 
 	// step 1: Adding new Romance
 	romance := romanceEntity.CreateEmptyRomance(s.voteId)
-	_, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now())
+	_, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now(), nil, nil)
 	s.Require().NoError(err)
 
 	// step 2: Deleting this romance
@@ -255,7 +264,7 @@ func (s *RomancesRepositoryTestSuite) TestDeleteRomanceFromPeerSide() {
 
 	// step 1: Adding new Romance
 	romance := romanceEntity.CreateEmptyRomance(s.voteId)
-	_, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now())
+	_, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now(), nil, nil)
 	s.Require().NoError(err)
 
 	// step 2: Deleting this romance from peer side
@@ -289,13 +298,13 @@ func (s *RomancesRepositoryTestSuite) TestDeleteActiveUserVoteFromRomance() {
 
 	// step 1: Adding new Romance and active user vote
 	emptyActiveUserRomance := romanceEntity.CreateEmptyRomance(activeUserVoteId)
-	_, err := repo.AddActiveUserVoteToRomance(ctx, emptyActiveUserRomance, rvo.VoteTypeYes, time.Now())
+	_, err := repo.AddActiveUserVoteToRomance(ctx, emptyActiveUserRomance, rvo.VoteTypeYes, time.Now(), nil, nil)
 	s.Require().NoError(err)
 
 	// step 2: Add vote to peer user side
 	peerRomance, err := repo.GetRomance(ctx, peerUserVoteId)
 	s.Require().NoError(err)
-	peerRomance, err = repo.AddActiveUserVoteToRomance(ctx, peerRomance, rvo.VoteTypeNo, time.Now())
+	peerRomance, err = repo.AddActiveUserVoteToRomance(ctx, peerRomance, rvo.VoteTypeNo, time.Now(), nil, nil)
 	s.Require().NoError(err)
 
 	s.assertRomanceInDbMatchesExpected(
@@ -304,7 +313,7 @@ func (s *RomancesRepositoryTestSuite) TestDeleteActiveUserVoteFromRomance() {
 	)
 
 	// step 3: Deleting vote from peer user side
-	err = repo.DeleteActiveUserVoteFromRomance(ctx, peerRomance)
+	_, err = repo.DeleteActiveUserVoteFromRomance(ctx, peerRomance)
 	s.Require().NoError(err)
 
 	peerRomance, err = repo.GetRomance(ctx, peerUserVoteId)
@@ -318,7 +327,7 @@ func (s *RomancesRepositoryTestSuite) TestDeleteActiveUserVoteFromRomance() {
 	activeUserRomance, err := repo.GetRomance(ctx, activeUserVoteId)
 	s.Require().NoError(err)
 
-	err = repo.DeleteActiveUserVoteFromRomance(ctx, activeUserRomance)
+	_, err = repo.DeleteActiveUserVoteFromRomance(ctx, activeUserRomance)
 	s.Require().NoError(err)
 
 	activeUserRomance, err = repo.GetRomance(ctx, activeUserVoteId)
@@ -336,7 +345,7 @@ func (s *RomancesRepositoryTestSuite) TestDeleteActiveUserVoteFromEmptyRomance()
 	// step 1: Adding new Romance and active user vote
 	emptyActiveUserRomance := romanceEntity.CreateEmptyRomance(s.voteId)
 
-	err := repo.DeleteActiveUserVoteFromRomance(ctx, emptyActiveUserRomance)
+	_, err := repo.DeleteActiveUserVoteFromRomance(ctx, emptyActiveUserRomance)
 	s.Require().NoError(err)
 
 	activeUserRomance, err := repo.GetRomance(ctx, s.voteId)
@@ -356,11 +365,11 @@ func (s *RomancesRepositoryTestSuite) TestChangeActiveUserVoteTypeInRomance() {
 
 	// step 1: Adding new Romance
 	romance := romanceEntity.CreateEmptyRomance(activeUserVoteId)
-	romance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeNo, time.Now())
+	romance, err := repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeNo, time.Now(), nil, nil)
 	s.Require().NoError(err)
 
 	// step 2: Changing active user vote type
-	newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeYes)
+	newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeYes, time.Now(), nil, nil)
 	s.Require().NoError(err)
 	s.assertRomanceInDbMatchesExpected(
 		newExpectedRomanceParams(activeUserVoteId, rvo.VoteTypeYes, rvo.VoteTypeEmpty, 2),
@@ -370,11 +379,11 @@ func (s *RomancesRepositoryTestSuite) TestChangeActiveUserVoteTypeInRomance() {
 	// step 3: Adding peer vote
 	peerRomance, err := repo.GetRomance(ctx, peerUserVoteId)
 	s.Require().NoError(err)
-	peerRomance, err = repo.AddActiveUserVoteToRomance(ctx, peerRomance, rvo.VoteTypeYes, time.Now())
+	peerRomance, err = repo.AddActiveUserVoteToRomance(ctx, peerRomance, rvo.VoteTypeYes, time.Now(), nil, nil)
 	s.Require().NoError(err)
 
 	// step 4: Changing peer user vote type
-	newPeerRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, peerRomance, rvo.VoteTypeCrush)
+	newPeerRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, peerRomance, rvo.VoteTypeCrush, time.Now(), nil, nil)
 	s.Require().NoError(err)
 	s.assertRomanceInDbMatchesExpected(
 		newExpectedRomanceParams(peerUserVoteId, rvo.VoteTypeCrush, rvo.VoteTypeYes, 4),
@@ -387,7 +396,7 @@ func (s *RomancesRepositoryTestSuite) TestChangeActiveUserVoteTypeInEmptyRomance
 	repo := newRomancesRepository(ddbClient)
 
 	romance := romanceEntity.CreateEmptyRomance(s.voteId)
-	newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeNo)
+	newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeNo, time.Now(), nil, nil)
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, romanceDomain.ErrVoteNotFound)
 	s.assertNilRomance(newRomance)
@@ -418,11 +427,11 @@ func (s *RomancesRepositoryTestSuite) TestChangeActiveUserVoteTypeInRomanceVaria
 		err := repo.DeleteRomance(ctx, activeUserVoteId)
 		s.Require().NoError(err)
 		romance := romanceEntity.CreateEmptyRomance(activeUserVoteId)
-		romance, err = repo.AddActiveUserVoteToRomance(ctx, romance, c.fromVote, time.Now())
+		romance, err = repo.AddActiveUserVoteToRomance(ctx, romance, c.fromVote, time.Now(), nil, nil)
 		s.Require().NoError(err)
 
 		// step 2: Changing active user vote type
-		newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, c.toVote)
+		newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, c.toVote, time.Now(), nil, nil)
 		s.Require().NoError(err)
 		s.assertRomanceInDbMatchesExpected(
 			newExpectedRomanceParams(activeUserVoteId, c.toVote, rvo.VoteTypeEmpty, 2),
@@ -441,11 +450,11 @@ func (s *RomancesRepositoryTestSuite) TestChangeActiveUserVoteTypeInRomanceToEmp
 	err := repo.DeleteRomance(ctx, activeUserVoteId)
 	s.Require().NoError(err)
 	romance := romanceEntity.CreateEmptyRomance(activeUserVoteId)
-	romance, err = repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now())
+	romance, err = repo.AddActiveUserVoteToRomance(ctx, romance, rvo.VoteTypeYes, time.Now(), nil, nil)
 	s.Require().NoError(err)
 
 	// step 2: Changing active user vote type to empty
-	newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeEmpty)
+	newRomance, err := repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeEmpty, time.Now(), nil, nil)
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, romanceDomain.ErrWrongVote)
 	s.assertNilRomance(newRomance)
@@ -617,3 +626,371 @@ func newExpectedRomanceParams(
 		Version:            version,
 	}
 }
+
+func (s *RomancesRepositoryTestSuite) TestChangeActiveUserVoteTypeInRomanceKeepsNewerVote() {
+	ctx := context.Background()
+	repo := newRomancesRepository(ddbClient)
+
+	err := repo.DeleteRomance(ctx, s.voteId)
+	s.Require().NoError(err)
+	votedAt := time.Now()
+	romance, err := repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(s.voteId), rvo.VoteTypeYes, votedAt, nil, nil)
+	s.Require().NoError(err)
+
+	// a delayed change voted before the stored vote must not override it
+	_, err = repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeCrush, votedAt.Add(-time.Minute), nil, nil)
+	s.Require().ErrorIs(err, romanceDomain.ErrStaleVote)
+
+	stored, err := repo.GetRomance(ctx, s.voteId)
+	s.Require().NoError(err)
+	s.Require().Equal(rvo.VoteTypeYes, stored.ActiveUserVote.VoteType)
+}
+
+func (s *RomancesRepositoryTestSuite) TestBlockAndUnblockPeerInRomance() {
+	ctx := context.Background()
+	repo := newRomancesRepository(ddbClient)
+
+	// step 1: Blocking the peer creates the romance item
+	blockedAt := time.Now().Truncate(time.Second)
+	romance, err := repo.BlockPeerInRomance(ctx, romanceEntity.CreateEmptyRomance(s.voteId), blockedAt)
+	s.Require().NoError(err)
+	s.Require().Equal(uint32(1), romance.Version)
+
+	// step 2: The block is visible from both sides
+	romance, err = repo.GetRomance(ctx, s.voteId)
+	s.Require().NoError(err)
+	s.Require().NotNil(romance.ActiveUserBlockedAt)
+	s.Require().True(blockedAt.Equal(*romance.ActiveUserBlockedAt))
+	s.Require().Nil(romance.PeerUserBlockedAt)
+
+	peerRomance, err := repo.GetRomance(ctx, s.voteId.ToPeerVoteId())
+	s.Require().NoError(err)
+	s.Require().NotNil(peerRomance.PeerUserBlockedAt)
+	s.Require().True(peerRomance.IsBlocked())
+
+	// step 3: A stale version is rejected
+	_, err = repo.UnblockPeerInRomance(ctx, romanceEntity.CreateEmptyRomance(s.voteId))
+	s.Require().ErrorIs(err, romanceDomain.ErrVersionConflict)
+
+	// step 4: Unblocking removes the block
+	romance, err = repo.UnblockPeerInRomance(ctx, romance)
+	s.Require().NoError(err)
+	s.Require().False(romance.IsBlocked())
+
+	romance, err = repo.GetRomance(ctx, s.voteId)
+	s.Require().NoError(err)
+	s.Require().False(romance.IsBlocked())
+	s.Require().Equal(uint32(2), romance.Version)
+}
+
+func (s *RomancesRepositoryTestSuite) TestUnmatchRomanceUntilNextVote() {
+	ctx := context.Background()
+	repo := newRomancesRepository(ddbClient)
+
+	// step 1: Creating a match
+	romance, err := repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(s.voteId), rvo.VoteTypeYes, time.Now(), nil, nil)
+	s.Require().NoError(err)
+	peerRomance, err := repo.GetRomance(ctx, s.voteId.ToPeerVoteId())
+	s.Require().NoError(err)
+	peerRomance, err = repo.AddActiveUserVoteToRomance(ctx, peerRomance, rvo.VoteTypeYes, time.Now(), nil, nil)
+	s.Require().NoError(err)
+
+	// step 2: Unmatching removes the votes of both users and is visible from both sides
+	unmatchedAt := time.Now().Truncate(time.Second).UTC()
+	unmatch := romanceEntity.Unmatch{
+		UnmatchedBy:       s.voteId.ActiveUserId(),
+		UnmatchedAt:       unmatchedAt,
+		Reason:            rvo.UnmatchReasonInappropriate,
+		RevoteBannedUntil: unmatchedAt.Add(time.Hour),
+	}
+	romance, err = repo.GetRomance(ctx, s.voteId)
+	s.Require().NoError(err)
+	romance, err = repo.UnmatchRomance(ctx, romance, unmatch)
+	s.Require().NoError(err)
+	s.Require().Equal(&unmatch, romance.Unmatch)
+	s.Require().True(romance.IsEmpty())
+
+	peerRomance, err = repo.GetRomance(ctx, s.voteId.ToPeerVoteId())
+	s.Require().NoError(err)
+	s.Require().Equal(&unmatch, peerRomance.Unmatch)
+	s.Require().True(peerRomance.IsEmpty())
+	s.Require().Equal(romance.Version, peerRomance.Version)
+
+	history, err := repo.GetVoteHistory(ctx, s.voteId, 1, "")
+	s.Require().NoError(err)
+	s.Require().Len(history.Entries, 1)
+	s.Require().Equal(rvo.VoteActionUnmatch, history.Entries[0].Action)
+	s.Require().Equal(rvo.VoteTypeYes, history.Entries[0].FromVoteType)
+	s.Require().Equal(romance.Version, history.Entries[0].RomanceVersion)
+
+	// step 3: The next vote ends the unmatched state, the old vote of the other user does not match it
+	peerRomance, err = repo.AddActiveUserVoteToRomance(ctx, peerRomance, rvo.VoteTypeYes, time.Now(), nil, nil)
+	s.Require().NoError(err)
+	s.Require().Nil(peerRomance.Unmatch)
+	s.Require().False(peerRomance.IsMatched())
+
+	romance, err = repo.GetRomance(ctx, s.voteId)
+	s.Require().NoError(err)
+	s.Require().False(romance.IsMatched())
+	s.Require().Equal(rvo.RomanceStateIncomingPending, romance.State())
+}
+
+func (s *RomancesRepositoryTestSuite) TestDeletingVoteKeepsRevoteBan() {
+	ctx := context.Background()
+	repo := newRomancesRepository(ddbClient)
+
+	romance, err := repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(s.voteId), rvo.VoteTypeYes, time.Now(), nil, nil)
+	s.Require().NoError(err)
+	unmatchedAt := time.Now().Truncate(time.Second).UTC()
+	unmatch := romanceEntity.Unmatch{
+		UnmatchedBy:       s.voteId.PeerUserId(),
+		UnmatchedAt:       unmatchedAt,
+		Reason:            rvo.UnmatchReasonInappropriate,
+		RevoteBannedUntil: unmatchedAt.Add(time.Hour),
+	}
+	romance, err = repo.UnmatchRomance(ctx, romance, unmatch)
+	s.Require().NoError(err)
+
+	romance, err = repo.DeleteActiveUserVoteFromRomance(ctx, romance)
+	s.Require().NoError(err)
+	s.Require().Equal(&unmatch, romance.Unmatch)
+
+	romance, err = repo.GetRomance(ctx, s.voteId)
+	s.Require().NoError(err)
+	s.Require().True(romance.ActiveUserVote.VoteType.IsEmpty())
+	s.Require().True(romance.IsRevoteBanned(time.Now()))
+}
+
+func (s *RomancesRepositoryTestSuite) TestVoteHistoryIsPagedNewestFirst() {
+	ctx := requestid.ContextWithRequestId(context.Background(), "history-req")
+	repo := newRomancesRepository(ddbClient)
+
+	votedAt := time.Now().Truncate(time.Second).UTC()
+	romance, err := repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(s.voteId), rvo.VoteTypeYes, votedAt, nil, nil)
+	s.Require().NoError(err)
+	peerRomance, err := repo.GetRomance(ctx, s.voteId.ToPeerVoteId())
+	s.Require().NoError(err)
+	_, err = repo.AddActiveUserVoteToRomance(ctx, peerRomance, rvo.VoteTypeNo, votedAt, nil, nil)
+	s.Require().NoError(err)
+	romance, err = repo.GetRomance(ctx, s.voteId)
+	s.Require().NoError(err)
+	_, err = repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeCrush, votedAt.Add(time.Second), nil, nil)
+	s.Require().NoError(err)
+
+	page, err := repo.GetVoteHistory(ctx, s.voteId, 2, "")
+	s.Require().NoError(err)
+	s.Require().Len(page.Entries, 2)
+	s.Require().NotEmpty(page.NextCursor)
+	s.Require().Equal(s.voteId.ActiveUserId(), page.Entries[0].ActorId)
+	s.Require().Equal(rvo.VoteActionChange, page.Entries[0].Action)
+	s.Require().Equal(rvo.VoteTypeYes, page.Entries[0].FromVoteType)
+	s.Require().Equal(rvo.VoteTypeCrush, page.Entries[0].ToVoteType)
+	s.Require().Equal("history-req", page.Entries[0].RequestId)
+	s.Require().Equal(uint32(3), page.Entries[0].RomanceVersion)
+	s.Require().Equal(s.voteId.PeerUserId(), page.Entries[1].ActorId)
+
+	// the peer reads the same history
+	page, err = repo.GetVoteHistory(ctx, s.voteId.ToPeerVoteId(), 2, page.NextCursor)
+	s.Require().NoError(err)
+	s.Require().Len(page.Entries, 1)
+	s.Require().Equal(rvo.VoteActionAdd, page.Entries[0].Action)
+	s.Require().Equal(rvo.VoteTypeEmpty, page.Entries[0].FromVoteType)
+	s.Require().True(votedAt.Equal(*page.Entries[0].VotedAt))
+
+	_, err = repo.GetVoteHistory(ctx, s.voteId, 2, "%%%")
+	s.Require().ErrorIs(err, romanceDomain.ErrInvalidHistoryCursor)
+}
+
+func (s *RomancesRepositoryTestSuite) TestVoteContextIsStoredWithTheVote() {
+	ctx := context.Background()
+	repo := newRomancesRepository(ddbClient)
+
+	voteContext := &rvo.VoteContext{
+		Surface:          rvo.VoteSurfaceSearch,
+		RecommendationId: "rec-7",
+		Position:         aws.Uint32(0),
+		ExperimentBucket: "treatment",
+	}
+	_, err := repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(s.voteId), rvo.VoteTypeYes, time.Now(), voteContext, nil)
+	s.Require().NoError(err)
+
+	romance, err := repo.GetRomance(ctx, s.voteId)
+	s.Require().NoError(err)
+	s.Require().Equal(voteContext, romance.ActiveUserVote.Context)
+
+	peerRomance, err := repo.GetRomance(ctx, s.voteId.ToPeerVoteId())
+	s.Require().NoError(err)
+	s.Require().Equal(voteContext, peerRomance.PeerUserVote.Context)
+	s.Require().Nil(peerRomance.ActiveUserVote.Context)
+
+	_, err = repo.ChangeActiveUserVoteTypeInRomance(ctx, romance, rvo.VoteTypeCrush, time.Now(), nil, nil)
+	s.Require().NoError(err)
+
+	romance, err = repo.GetRomance(ctx, s.voteId)
+	s.Require().NoError(err)
+	s.Require().Nil(romance.ActiveUserVote.Context)
+}
+
+func (s *RomancesRepositoryTestSuite) TestComplimentModerationIsStored() {
+	ctx := context.Background()
+	repo := newRomancesRepository(ddbClient)
+
+	compliment := &rvo.Compliment{
+		Id:     uuidhelper.NewUUID(s.T()),
+		Text:   "Lovely smile",
+		Status: rvo.ModerationStatusPending,
+	}
+	romance, err := repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(s.voteId), rvo.VoteTypeCompliment, time.Now(), nil, compliment)
+	s.Require().NoError(err)
+
+	moderatedAt := time.Unix(time.Now().Unix(), 0)
+	moderated, err := repo.ModerateComplimentInRomance(ctx, romance, rvo.ModerationStatusApproved, moderatedAt)
+	s.Require().NoError(err)
+	s.Require().Equal(romance.Version+1, moderated.Version)
+	s.Require().True(moderated.ActiveUserVote.Compliment.IsApproved())
+
+	history, err := repo.GetVoteHistory(ctx, s.voteId, 1, "")
+	s.Require().NoError(err)
+	s.Require().Len(history.Entries, 1)
+	s.Require().Equal(rvo.VoteActionModerate, history.Entries[0].Action)
+	s.Require().Equal(moderated.Version, history.Entries[0].RomanceVersion)
+
+	peerRomance, err := repo.GetRomance(ctx, s.voteId.ToPeerVoteId())
+	s.Require().NoError(err)
+	s.Require().Equal(compliment.Id, peerRomance.PeerUserVote.Compliment.Id)
+	s.Require().Equal("Lovely smile", peerRomance.PeerUserVote.Compliment.Text)
+	s.Require().True(peerRomance.PeerUserVote.Compliment.IsApproved())
+	s.Require().True(moderatedAt.Equal(*peerRomance.PeerUserVote.Compliment.ModeratedAt))
+
+	_, err = repo.ModerateComplimentInRomance(ctx, romance, rvo.ModerationStatusRejected, moderatedAt)
+	s.Require().ErrorIs(err, romanceDomain.ErrVersionConflict)
+}
+
+func (s *RomancesRepositoryTestSuite) TestListRomancesForActiveUserPagesThroughBothKeys() {
+	ctx := context.Background()
+	repo := newRomancesRepository(ddbClient)
+
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(11, uuidhelper.NewUUID(s.T()))
+	s.Require().NoError(err)
+	expectedVoteTypes := map[uuid.UUID]rvo.VoteType{}
+	for _, voteType := range []rvo.VoteType{rvo.VoteTypeYes, rvo.VoteTypeNo, rvo.VoteTypeCrush, rvo.VoteTypeYes, rvo.VoteTypeNo} {
+		voteId, err := sharedValueObject.NewVoteId(activeUserKey.CountryId(), activeUserKey.ActiveUserId(), uuidhelper.NewUUID(s.T()))
+		s.Require().NoError(err)
+		_, err = repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(voteId), voteType, time.Now(), nil, nil)
+		s.Require().NoError(err)
+		expectedVoteTypes[voteId.PeerUserId()] = voteType
+		s.T().Cleanup(func() {
+			s.Require().NoError(repo.DeleteRomance(ctx, voteId))
+		})
+	}
+
+	listedVoteTypes := map[uuid.UUID]rvo.VoteType{}
+	cursor := ""
+	for pages := 0; ; pages++ {
+		s.Require().Less(pages, 5)
+		page, err := repo.ListRomancesForActiveUser(ctx, activeUserKey, 2, cursor)
+		s.Require().NoError(err)
+		s.Require().LessOrEqual(len(page.Romances), 2)
+		for _, romance := range page.Romances {
+			s.Require().Equal(activeUserKey.ActiveUserId(), romance.ActiveUserVote.Id.ActiveUserId())
+			listedVoteTypes[romance.ActiveUserVote.Id.PeerUserId()] = romance.ActiveUserVote.VoteType
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	s.Require().Equal(expectedVoteTypes, listedVoteTypes)
+
+	_, err = repo.ListRomancesForActiveUser(ctx, activeUserKey, 2, "%%%")
+	s.Require().ErrorIs(err, romanceDomain.ErrInvalidRomancesCursor)
+}
+
+func (s *RomancesRepositoryTestSuite) TestGetRomancesGroupKeepsThePeersOrder() {
+	ctx := context.Background()
+	repo := newRomancesRepository(ddbClient)
+
+	activeUserKey := s.voteId.ActiveUserKey()
+	_, err := repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(s.voteId), rvo.VoteTypeNo, time.Now(), nil, nil)
+	s.Require().NoError(err)
+	notVotedPeerId := uuidhelper.NewUUID(s.T())
+
+	romances, err := repo.GetRomancesGroup(ctx, activeUserKey, []uuid.UUID{notVotedPeerId, s.voteId.PeerUserId()})
+	s.Require().NoError(err)
+	s.Require().Len(romances, 2)
+	s.Require().Equal(notVotedPeerId, romances[0].ActiveUserVote.Id.PeerUserId())
+	s.Require().True(romances[0].IsEmpty())
+	s.Require().Equal(s.voteId, romances[1].ActiveUserVote.Id)
+	s.Require().Equal(rvo.VoteTypeNo, romances[1].ActiveUserVote.VoteType)
+}
+
+func (s *RomancesRepositoryTestSuite) TestCrossCountryRomancesAreFoundFromBothSides() {
+	ctx := context.Background()
+	repo := newRomancesRepository(ddbClient)
+
+	// the active user is in the middle so romances are stored with either user as the partition key
+	userIds := make([]uuid.UUID, 5)
+	for i := range userIds {
+		userIds[i] = uuidhelper.NewUUID(s.T())
+	}
+	slices.SortFunc(userIds, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	activeUserKey, err := sharedValueObject.NewActiveUserKey(11, userIds[2])
+	s.Require().NoError(err)
+	peerIds := []uuid.UUID{userIds[0], userIds[1], userIds[3], userIds[4]}
+
+	for _, peerId := range peerIds {
+		voteId, err := sharedValueObject.NewCrossCountryVoteId(activeUserKey.CountryId(), activeUserKey.ActiveUserId(), 22, peerId)
+		s.Require().NoError(err)
+		_, err = repo.AddActiveUserVoteToRomance(ctx, romanceEntity.CreateEmptyRomance(voteId), rvo.VoteTypeYes, time.Now(), nil, nil)
+		s.Require().NoError(err)
+		s.T().Cleanup(func() {
+			s.Require().NoError(repo.DeleteRomance(ctx, voteId))
+		})
+	}
+
+	var listedPeerIds []uuid.UUID
+	cursor := ""
+	for pages := 0; ; pages++ {
+		s.Require().Less(pages, 5)
+		page, err := repo.ListRomancesForActiveUser(ctx, activeUserKey, 3, cursor)
+		s.Require().NoError(err)
+		for _, romance := range page.Romances {
+			s.Require().Equal(uint16(22), romance.ActiveUserVote.Id.PeerCountryId())
+			listedPeerIds = append(listedPeerIds, romance.ActiveUserVote.Id.PeerUserId())
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	s.Require().ElementsMatch(peerIds, listedPeerIds)
+
+	romances, err := repo.GetRomancesGroup(ctx, activeUserKey, peerIds)
+	s.Require().NoError(err)
+	for i, romance := range romances {
+		s.Require().Equal(peerIds[i], romance.ActiveUserVote.Id.PeerUserId())
+		s.Require().Equal(rvo.VoteTypeYes, romance.ActiveUserVote.VoteType)
+	}
+
+	peerUserKey, err := sharedValueObject.NewActiveUserKey(22, userIds[0])
+	s.Require().NoError(err)
+	peerRomances, err := repo.GetRomancesGroup(ctx, peerUserKey, []uuid.UUID{activeUserKey.ActiveUserId()})
+	s.Require().NoError(err)
+	s.Require().Equal(rvo.VoteTypeYes, peerRomances[0].PeerUserVote.VoteType)
+	s.Require().Equal(uint16(11), peerRomances[0].ActiveUserVote.Id.PeerCountryId())
+
+	peersChan, err := repo.GetAllPeersForActiveUser(ctx, activeUserKey)
+	s.Require().NoError(err)
+	var streamedPeerIds []uuid.UUID
+	for peerId := range peersChan {
+		streamedPeerIds = append(streamedPeerIds, peerId)
+	}
+	s.Require().ElementsMatch(peerIds, streamedPeerIds)
+
+	s.Require().NoError(repo.DeleteRomancesGroup(ctx, activeUserKey, peerIds))
+	romances, err = repo.GetRomancesGroup(ctx, activeUserKey, peerIds)
+	s.Require().NoError(err)
+	for _, romance := range romances {
+		s.Require().True(romance.IsEmpty())
+	}
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb_streams (interfaces: CheckpointStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../../testlib/mocks/checkpoint_store_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb_streams CheckpointStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCheckpointStore is a mock of CheckpointStore interface.
+type MockCheckpointStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCheckpointStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockCheckpointStoreMockRecorder is the mock recorder for MockCheckpointStore.
+type MockCheckpointStoreMockRecorder struct {
+	mock *MockCheckpointStore
+}
+
+// NewMockCheckpointStore creates a new mock instance.
+func NewMockCheckpointStore(ctrl *gomock.Controller) *MockCheckpointStore {
+	mock := &MockCheckpointStore{ctrl: ctrl}
+	mock.recorder = &MockCheckpointStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCheckpointStore) EXPECT() *MockCheckpointStoreMockRecorder {
+	return m.recorder
+}
+
+// Lease mocks base method.
+func (m *MockCheckpointStore) Lease(ctx context.Context, streamArn, shardId string, duration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lease", ctx, streamArn, shardId, duration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lease indicates an expected call of Lease.
+func (mr *MockCheckpointStoreMockRecorder) Lease(ctx, streamArn, shardId, duration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lease", reflect.TypeOf((*MockCheckpointStore)(nil).Lease), ctx, streamArn, shardId, duration)
+}
+
+// Load mocks base method.
+func (m *MockCheckpointStore) Load(ctx context.Context, streamArn, shardId string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx, streamArn, shardId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockCheckpointStoreMockRecorder) Load(ctx, streamArn, shardId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockCheckpointStore)(nil).Load), ctx, streamArn, shardId)
+}
+
+// Release mocks base method.
+func (m *MockCheckpointStore) Release(ctx context.Context, streamArn, shardId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, streamArn, shardId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockCheckpointStoreMockRecorder) Release(ctx, streamArn, shardId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockCheckpointStore)(nil).Release), ctx, streamArn, shardId)
+}
+
+// Save mocks base method.
+func (m *MockCheckpointStore) Save(ctx context.Context, streamArn, shardId, sequenceNumber string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, streamArn, shardId, sequenceNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockCheckpointStoreMockRecorder) Save(ctx, streamArn, shardId, sequenceNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCheckpointStore)(nil).Save), ctx, streamArn, shardId, sequenceNumber)
+}
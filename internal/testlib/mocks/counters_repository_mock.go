@@ -12,7 +12,9 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	counter "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter"
 	entity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/entity"
 	valueobject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/counter/valueobject"
 	valueobject0 "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
@@ -43,6 +45,57 @@ func (m *MockCountersRepository) EXPECT() *MockCountersRepositoryMockRecorder {
 	return m.recorder
 }
 
+// DecrMatchCounters mocks base method.
+func (m *MockCountersRepository) DecrMatchCounters(ctx context.Context, voteId valueobject0.VoteId) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DecrMatchCounters", ctx, voteId)
+}
+
+// DecrMatchCounters indicates an expected call of DecrMatchCounters.
+func (mr *MockCountersRepositoryMockRecorder) DecrMatchCounters(ctx, voteId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrMatchCounters", reflect.TypeOf((*MockCountersRepository)(nil).DecrMatchCounters), ctx, voteId)
+}
+
+// DecrNoCounters mocks base method.
+func (m *MockCountersRepository) DecrNoCounters(ctx context.Context, voteId valueobject0.VoteId, counterGroup valueobject.CounterUpdateGroup) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DecrNoCounters", ctx, voteId, counterGroup)
+}
+
+// DecrNoCounters indicates an expected call of DecrNoCounters.
+func (mr *MockCountersRepositoryMockRecorder) DecrNoCounters(ctx, voteId, counterGroup any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrNoCounters", reflect.TypeOf((*MockCountersRepository)(nil).DecrNoCounters), ctx, voteId, counterGroup)
+}
+
+// DecrYesCounters mocks base method.
+func (m *MockCountersRepository) DecrYesCounters(ctx context.Context, voteId valueobject0.VoteId, counterGroup valueobject.CounterUpdateGroup) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DecrYesCounters", ctx, voteId, counterGroup)
+}
+
+// DecrYesCounters indicates an expected call of DecrYesCounters.
+func (mr *MockCountersRepositoryMockRecorder) DecrYesCounters(ctx, voteId, counterGroup any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrYesCounters", reflect.TypeOf((*MockCountersRepository)(nil).DecrYesCounters), ctx, voteId, counterGroup)
+}
+
+// GetCountersRange mocks base method.
+func (m *MockCountersRepository) GetCountersRange(ctx context.Context, activeUserKey valueobject0.ActiveUserKey, countersRange valueobject.CountersRange) ([]entity.CountersGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountersRange", ctx, activeUserKey, countersRange)
+	ret0, _ := ret[0].([]entity.CountersGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountersRange indicates an expected call of GetCountersRange.
+func (mr *MockCountersRepositoryMockRecorder) GetCountersRange(ctx, activeUserKey, countersRange any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountersRange", reflect.TypeOf((*MockCountersRepository)(nil).GetCountersRange), ctx, activeUserKey, countersRange)
+}
+
 // GetHourlyCounters mocks base method.
 func (m *MockCountersRepository) GetHourlyCounters(ctx context.Context, activeUserKey valueobject0.ActiveUserKey, hoursOffsetGroups valueobject.HoursOffsetGroups) (map[uint8]*entity.CountersGroup, error) {
 	m.ctrl.T.Helper()
@@ -58,6 +111,21 @@ func (mr *MockCountersRepositoryMockRecorder) GetHourlyCounters(ctx, activeUserK
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHourlyCounters", reflect.TypeOf((*MockCountersRepository)(nil).GetHourlyCounters), ctx, activeUserKey, hoursOffsetGroups)
 }
 
+// GetHourlyCountersSince mocks base method.
+func (m *MockCountersRepository) GetHourlyCountersSince(ctx context.Context, activeUserKey valueobject0.ActiveUserKey, since time.Time) ([]entity.CountersGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHourlyCountersSince", ctx, activeUserKey, since)
+	ret0, _ := ret[0].([]entity.CountersGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHourlyCountersSince indicates an expected call of GetHourlyCountersSince.
+func (mr *MockCountersRepositoryMockRecorder) GetHourlyCountersSince(ctx, activeUserKey, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHourlyCountersSince", reflect.TypeOf((*MockCountersRepository)(nil).GetHourlyCountersSince), ctx, activeUserKey, since)
+}
+
 // GetLifetimeCounter mocks base method.
 func (m *MockCountersRepository) GetLifetimeCounter(ctx context.Context, activeUserKey valueobject0.ActiveUserKey) (entity.CountersGroup, error) {
 	m.ctrl.T.Helper()
@@ -73,6 +141,18 @@ func (mr *MockCountersRepositoryMockRecorder) GetLifetimeCounter(ctx, activeUser
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLifetimeCounter", reflect.TypeOf((*MockCountersRepository)(nil).GetLifetimeCounter), ctx, activeUserKey)
 }
 
+// IncrMatchCounters mocks base method.
+func (m *MockCountersRepository) IncrMatchCounters(ctx context.Context, voteId valueobject0.VoteId, counterGroup valueobject.CounterUpdateGroup) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncrMatchCounters", ctx, voteId, counterGroup)
+}
+
+// IncrMatchCounters indicates an expected call of IncrMatchCounters.
+func (mr *MockCountersRepositoryMockRecorder) IncrMatchCounters(ctx, voteId, counterGroup any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrMatchCounters", reflect.TypeOf((*MockCountersRepository)(nil).IncrMatchCounters), ctx, voteId, counterGroup)
+}
+
 // IncrNoCounters mocks base method.
 func (m *MockCountersRepository) IncrNoCounters(ctx context.Context, voteId valueobject0.VoteId, counterGroup valueobject.CounterUpdateGroup) {
 	m.ctrl.T.Helper()
@@ -96,3 +176,31 @@ func (mr *MockCountersRepositoryMockRecorder) IncrYesCounters(ctx, voteId, count
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrYesCounters", reflect.TypeOf((*MockCountersRepository)(nil).IncrYesCounters), ctx, voteId, counterGroup)
 }
+
+// ReleaseVoteLimits mocks base method.
+func (m *MockCountersRepository) ReleaseVoteLimits(ctx context.Context, activeUserKey valueobject0.ActiveUserKey, windows []counter.VoteLimitWindow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseVoteLimits", ctx, activeUserKey, windows)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseVoteLimits indicates an expected call of ReleaseVoteLimits.
+func (mr *MockCountersRepositoryMockRecorder) ReleaseVoteLimits(ctx, activeUserKey, windows any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseVoteLimits", reflect.TypeOf((*MockCountersRepository)(nil).ReleaseVoteLimits), ctx, activeUserKey, windows)
+}
+
+// ReserveVoteLimits mocks base method.
+func (m *MockCountersRepository) ReserveVoteLimits(ctx context.Context, activeUserKey valueobject0.ActiveUserKey, windows []counter.VoteLimitWindow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveVoteLimits", ctx, activeUserKey, windows)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReserveVoteLimits indicates an expected call of ReserveVoteLimits.
+func (mr *MockCountersRepositoryMockRecorder) ReserveVoteLimits(ctx, activeUserKey, windows any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveVoteLimits", reflect.TypeOf((*MockCountersRepository)(nil).ReserveVoteLimits), ctx, activeUserKey, windows)
+}
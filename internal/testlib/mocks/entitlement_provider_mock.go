@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider (interfaces: EntitlementProvider)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../../../../testlib/mocks/entitlement_provider_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider EntitlementProvider
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	valueobject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	valueobject0 "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEntitlementProvider is a mock of EntitlementProvider interface.
+type MockEntitlementProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntitlementProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockEntitlementProviderMockRecorder is the mock recorder for MockEntitlementProvider.
+type MockEntitlementProviderMockRecorder struct {
+	mock *MockEntitlementProvider
+}
+
+// NewMockEntitlementProvider creates a new mock instance.
+func NewMockEntitlementProvider(ctrl *gomock.Controller) *MockEntitlementProvider {
+	mock := &MockEntitlementProvider{ctrl: ctrl}
+	mock.recorder = &MockEntitlementProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntitlementProvider) EXPECT() *MockEntitlementProviderMockRecorder {
+	return m.recorder
+}
+
+// IsEntitled mocks base method.
+func (m *MockEntitlementProvider) IsEntitled(ctx context.Context, activeUserKey valueobject0.ActiveUserKey, voteType valueobject.VoteType) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEntitled", ctx, activeUserKey, voteType)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEntitled indicates an expected call of IsEntitled.
+func (mr *MockEntitlementProviderMockRecorder) IsEntitled(ctx, activeUserKey, voteType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEntitled", reflect.TypeOf((*MockEntitlementProvider)(nil).IsEntitled), ctx, activeUserKey, voteType)
+}
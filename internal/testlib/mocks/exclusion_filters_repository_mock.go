@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository (interfaces: ExclusionFiltersRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../../../../testlib/mocks/exclusion_filters_repository_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/repository ExclusionFiltersRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	valueobject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	valueobject0 "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExclusionFiltersRepository is a mock of ExclusionFiltersRepository interface.
+type MockExclusionFiltersRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExclusionFiltersRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockExclusionFiltersRepositoryMockRecorder is the mock recorder for MockExclusionFiltersRepository.
+type MockExclusionFiltersRepositoryMockRecorder struct {
+	mock *MockExclusionFiltersRepository
+}
+
+// NewMockExclusionFiltersRepository creates a new mock instance.
+func NewMockExclusionFiltersRepository(ctrl *gomock.Controller) *MockExclusionFiltersRepository {
+	mock := &MockExclusionFiltersRepository{ctrl: ctrl}
+	mock.recorder = &MockExclusionFiltersRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExclusionFiltersRepository) EXPECT() *MockExclusionFiltersRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddToExclusionFilter mocks base method.
+func (m *MockExclusionFiltersRepository) AddToExclusionFilter(ctx context.Context, userKey valueobject0.ActiveUserKey, peerId uuid.UUID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddToExclusionFilter", ctx, userKey, peerId)
+}
+
+// AddToExclusionFilter indicates an expected call of AddToExclusionFilter.
+func (mr *MockExclusionFiltersRepositoryMockRecorder) AddToExclusionFilter(ctx, userKey, peerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToExclusionFilter", reflect.TypeOf((*MockExclusionFiltersRepository)(nil).AddToExclusionFilter), ctx, userKey, peerId)
+}
+
+// DeleteExclusionFilter mocks base method.
+func (m *MockExclusionFiltersRepository) DeleteExclusionFilter(ctx context.Context, userKey valueobject0.ActiveUserKey) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteExclusionFilter", ctx, userKey)
+}
+
+// DeleteExclusionFilter indicates an expected call of DeleteExclusionFilter.
+func (mr *MockExclusionFiltersRepositoryMockRecorder) DeleteExclusionFilter(ctx, userKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExclusionFilter", reflect.TypeOf((*MockExclusionFiltersRepository)(nil).DeleteExclusionFilter), ctx, userKey)
+}
+
+// GetExclusionFilter mocks base method.
+func (m *MockExclusionFiltersRepository) GetExclusionFilter(ctx context.Context, userKey valueobject0.ActiveUserKey) (valueobject.ExclusionFilter, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExclusionFilter", ctx, userKey)
+	ret0, _ := ret[0].(valueobject.ExclusionFilter)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetExclusionFilter indicates an expected call of GetExclusionFilter.
+func (mr *MockExclusionFiltersRepositoryMockRecorder) GetExclusionFilter(ctx, userKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExclusionFilter", reflect.TypeOf((*MockExclusionFiltersRepository)(nil).GetExclusionFilter), ctx, userKey)
+}
+
+// SaveExclusionFilter mocks base method.
+func (m *MockExclusionFiltersRepository) SaveExclusionFilter(ctx context.Context, userKey valueobject0.ActiveUserKey, filter valueobject.ExclusionFilter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveExclusionFilter", ctx, userKey, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveExclusionFilter indicates an expected call of SaveExclusionFilter.
+func (mr *MockExclusionFiltersRepositoryMockRecorder) SaveExclusionFilter(ctx, userKey, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveExclusionFilter", reflect.TypeOf((*MockExclusionFiltersRepository)(nil).SaveExclusionFilter), ctx, userKey, filter)
+}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/repository (interfaces: LastVotesRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../../../../testlib/mocks/last_votes_repository_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/repository LastVotesRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/rewind/entity"
+	valueobject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLastVotesRepository is a mock of LastVotesRepository interface.
+type MockLastVotesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLastVotesRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLastVotesRepositoryMockRecorder is the mock recorder for MockLastVotesRepository.
+type MockLastVotesRepositoryMockRecorder struct {
+	mock *MockLastVotesRepository
+}
+
+// NewMockLastVotesRepository creates a new mock instance.
+func NewMockLastVotesRepository(ctrl *gomock.Controller) *MockLastVotesRepository {
+	mock := &MockLastVotesRepository{ctrl: ctrl}
+	mock.recorder = &MockLastVotesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLastVotesRepository) EXPECT() *MockLastVotesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetLastVotes mocks base method.
+func (m *MockLastVotesRepository) GetLastVotes(ctx context.Context, activeUserKey valueobject.ActiveUserKey) (entity.LastVotes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastVotes", ctx, activeUserKey)
+	ret0, _ := ret[0].(entity.LastVotes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastVotes indicates an expected call of GetLastVotes.
+func (mr *MockLastVotesRepositoryMockRecorder) GetLastVotes(ctx, activeUserKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastVotes", reflect.TypeOf((*MockLastVotesRepository)(nil).GetLastVotes), ctx, activeUserKey)
+}
+
+// SaveLastVotes mocks base method.
+func (m *MockLastVotesRepository) SaveLastVotes(ctx context.Context, lastVotes entity.LastVotes, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLastVotes", ctx, lastVotes, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLastVotes indicates an expected call of SaveLastVotes.
+func (mr *MockLastVotesRepositoryMockRecorder) SaveLastVotes(ctx, lastVotes, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLastVotes", reflect.TypeOf((*MockLastVotesRepository)(nil).SaveLastVotes), ctx, lastVotes, expiresAt)
+}
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/provider (interfaces: ModerationProvider)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../../../../testlib/mocks/moderation_provider_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/provider ModerationProvider
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	valueobject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	valueobject0 "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockModerationProvider is a mock of ModerationProvider interface.
+type MockModerationProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockModerationProviderMockRecorder is the mock recorder for MockModerationProvider.
+type MockModerationProviderMockRecorder struct {
+	mock *MockModerationProvider
+}
+
+// NewMockModerationProvider creates a new mock instance.
+func NewMockModerationProvider(ctrl *gomock.Controller) *MockModerationProvider {
+	mock := &MockModerationProvider{ctrl: ctrl}
+	mock.recorder = &MockModerationProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerationProvider) EXPECT() *MockModerationProviderMockRecorder {
+	return m.recorder
+}
+
+// Moderate mocks base method.
+func (m *MockModerationProvider) Moderate(ctx context.Context, voteId valueobject0.VoteId, text string) (valueobject.ModerationStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Moderate", ctx, voteId, text)
+	ret0, _ := ret[0].(valueobject.ModerationStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Moderate indicates an expected call of Moderate.
+func (mr *MockModerationProviderMockRecorder) Moderate(ctx, voteId, text any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Moderate", reflect.TypeOf((*MockModerationProvider)(nil).Moderate), ctx, voteId, text)
+}
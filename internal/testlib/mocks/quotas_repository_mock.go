@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/repository (interfaces: QuotasRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../../../../testlib/mocks/quotas_repository_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/repository QuotasRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/quota/entity"
+	valueobject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/romance/valueobject"
+	valueobject0 "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQuotasRepository is a mock of QuotasRepository interface.
+type MockQuotasRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotasRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockQuotasRepositoryMockRecorder is the mock recorder for MockQuotasRepository.
+type MockQuotasRepositoryMockRecorder struct {
+	mock *MockQuotasRepository
+}
+
+// NewMockQuotasRepository creates a new mock instance.
+func NewMockQuotasRepository(ctrl *gomock.Controller) *MockQuotasRepository {
+	mock := &MockQuotasRepository{ctrl: ctrl}
+	mock.recorder = &MockQuotasRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuotasRepository) EXPECT() *MockQuotasRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockQuotasRepository) Consume(ctx context.Context, consumption entity.Consumption, limit uint32, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, consumption, limit, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockQuotasRepositoryMockRecorder) Consume(ctx, consumption, limit, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockQuotasRepository)(nil).Consume), ctx, consumption, limit, expiresAt)
+}
+
+// GetOverrides mocks base method.
+func (m *MockQuotasRepository) GetOverrides(ctx context.Context, activeUserKey valueobject0.ActiveUserKey) (map[valueobject.VoteType]uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverrides", ctx, activeUserKey)
+	ret0, _ := ret[0].(map[valueobject.VoteType]uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverrides indicates an expected call of GetOverrides.
+func (mr *MockQuotasRepositoryMockRecorder) GetOverrides(ctx, activeUserKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverrides", reflect.TypeOf((*MockQuotasRepository)(nil).GetOverrides), ctx, activeUserKey)
+}
+
+// GetUsage mocks base method.
+func (m *MockQuotasRepository) GetUsage(ctx context.Context, activeUserKey valueobject0.ActiveUserKey, day time.Time) (map[valueobject.VoteType]uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage", ctx, activeUserKey, day)
+	ret0, _ := ret[0].(map[valueobject.VoteType]uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockQuotasRepositoryMockRecorder) GetUsage(ctx, activeUserKey, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockQuotasRepository)(nil).GetUsage), ctx, activeUserKey, day)
+}
+
+// Refund mocks base method.
+func (m *MockQuotasRepository) Refund(ctx context.Context, consumption entity.Consumption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, consumption)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockQuotasRepositoryMockRecorder) Refund(ctx, consumption any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockQuotasRepository)(nil).Refund), ctx, consumption)
+}
+
+// SetOverrides mocks base method.
+func (m *MockQuotasRepository) SetOverrides(ctx context.Context, activeUserKey valueobject0.ActiveUserKey, overrides map[valueobject.VoteType]uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOverrides", ctx, activeUserKey, overrides)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOverrides indicates an expected call of SetOverrides.
+func (mr *MockQuotasRepositoryMockRecorder) SetOverrides(ctx, activeUserKey, overrides any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverrides", reflect.TypeOf((*MockQuotasRepository)(nil).SetOverrides), ctx, activeUserKey, overrides)
+}
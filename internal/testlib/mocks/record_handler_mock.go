@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb_streams (interfaces: RecordHandler)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../../testlib/mocks/record_handler_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/shared/platform/dynamodb_streams RecordHandler
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecordHandler is a mock of RecordHandler interface.
+type MockRecordHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecordHandlerMockRecorder
+	isgomock struct{}
+}
+
+// MockRecordHandlerMockRecorder is the mock recorder for MockRecordHandler.
+type MockRecordHandlerMockRecorder struct {
+	mock *MockRecordHandler
+}
+
+// NewMockRecordHandler creates a new mock instance.
+func NewMockRecordHandler(ctrl *gomock.Controller) *MockRecordHandler {
+	mock := &MockRecordHandler{ctrl: ctrl}
+	mock.recorder = &MockRecordHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecordHandler) EXPECT() *MockRecordHandlerMockRecorder {
+	return m.recorder
+}
+
+// Handle mocks base method.
+func (m *MockRecordHandler) Handle(ctx context.Context, record types.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Handle", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Handle indicates an expected call of Handle.
+func (mr *MockRecordHandlerMockRecorder) Handle(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handle", reflect.TypeOf((*MockRecordHandler)(nil).Handle), ctx, record)
+}
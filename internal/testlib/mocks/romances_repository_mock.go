@@ -46,41 +46,57 @@ func (m *MockRomancesRepository) EXPECT() *MockRomancesRepositoryMockRecorder {
 }
 
 // AddActiveUserVoteToRomance mocks base method.
-func (m *MockRomancesRepository) AddActiveUserVoteToRomance(ctx context.Context, romance entity.Romance, voteType valueobject.VoteType, votedAt time.Time) (entity.Romance, error) {
+func (m *MockRomancesRepository) AddActiveUserVoteToRomance(ctx context.Context, romance entity.Romance, voteType valueobject.VoteType, votedAt time.Time, voteContext *valueobject.VoteContext, compliment *valueobject.Compliment) (entity.Romance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddActiveUserVoteToRomance", ctx, romance, voteType, votedAt)
+	ret := m.ctrl.Call(m, "AddActiveUserVoteToRomance", ctx, romance, voteType, votedAt, voteContext, compliment)
 	ret0, _ := ret[0].(entity.Romance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddActiveUserVoteToRomance indicates an expected call of AddActiveUserVoteToRomance.
-func (mr *MockRomancesRepositoryMockRecorder) AddActiveUserVoteToRomance(ctx, romance, voteType, votedAt any) *gomock.Call {
+func (mr *MockRomancesRepositoryMockRecorder) AddActiveUserVoteToRomance(ctx, romance, voteType, votedAt, voteContext, compliment any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActiveUserVoteToRomance", reflect.TypeOf((*MockRomancesRepository)(nil).AddActiveUserVoteToRomance), ctx, romance, voteType, votedAt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActiveUserVoteToRomance", reflect.TypeOf((*MockRomancesRepository)(nil).AddActiveUserVoteToRomance), ctx, romance, voteType, votedAt, voteContext, compliment)
+}
+
+// BlockPeerInRomance mocks base method.
+func (m *MockRomancesRepository) BlockPeerInRomance(ctx context.Context, romance entity.Romance, blockedAt time.Time) (entity.Romance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockPeerInRomance", ctx, romance, blockedAt)
+	ret0, _ := ret[0].(entity.Romance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockPeerInRomance indicates an expected call of BlockPeerInRomance.
+func (mr *MockRomancesRepositoryMockRecorder) BlockPeerInRomance(ctx, romance, blockedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockPeerInRomance", reflect.TypeOf((*MockRomancesRepository)(nil).BlockPeerInRomance), ctx, romance, blockedAt)
 }
 
 // ChangeActiveUserVoteTypeInRomance mocks base method.
-func (m *MockRomancesRepository) ChangeActiveUserVoteTypeInRomance(ctx context.Context, romance entity.Romance, newVoteType valueobject.VoteType) (entity.Romance, error) {
+func (m *MockRomancesRepository) ChangeActiveUserVoteTypeInRomance(ctx context.Context, romance entity.Romance, newVoteType valueobject.VoteType, votedAt time.Time, voteContext *valueobject.VoteContext, compliment *valueobject.Compliment) (entity.Romance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ChangeActiveUserVoteTypeInRomance", ctx, romance, newVoteType)
+	ret := m.ctrl.Call(m, "ChangeActiveUserVoteTypeInRomance", ctx, romance, newVoteType, votedAt, voteContext, compliment)
 	ret0, _ := ret[0].(entity.Romance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ChangeActiveUserVoteTypeInRomance indicates an expected call of ChangeActiveUserVoteTypeInRomance.
-func (mr *MockRomancesRepositoryMockRecorder) ChangeActiveUserVoteTypeInRomance(ctx, romance, newVoteType any) *gomock.Call {
+func (mr *MockRomancesRepositoryMockRecorder) ChangeActiveUserVoteTypeInRomance(ctx, romance, newVoteType, votedAt, voteContext, compliment any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeActiveUserVoteTypeInRomance", reflect.TypeOf((*MockRomancesRepository)(nil).ChangeActiveUserVoteTypeInRomance), ctx, romance, newVoteType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeActiveUserVoteTypeInRomance", reflect.TypeOf((*MockRomancesRepository)(nil).ChangeActiveUserVoteTypeInRomance), ctx, romance, newVoteType, votedAt, voteContext, compliment)
 }
 
 // DeleteActiveUserVoteFromRomance mocks base method.
-func (m *MockRomancesRepository) DeleteActiveUserVoteFromRomance(ctx context.Context, romance entity.Romance) error {
+func (m *MockRomancesRepository) DeleteActiveUserVoteFromRomance(ctx context.Context, romance entity.Romance) (entity.Romance, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteActiveUserVoteFromRomance", ctx, romance)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(entity.Romance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteActiveUserVoteFromRomance indicates an expected call of DeleteActiveUserVoteFromRomance.
@@ -146,3 +162,108 @@ func (mr *MockRomancesRepositoryMockRecorder) GetRomance(ctx, voteId any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRomance", reflect.TypeOf((*MockRomancesRepository)(nil).GetRomance), ctx, voteId)
 }
+
+// GetRomancesGroup mocks base method.
+func (m *MockRomancesRepository) GetRomancesGroup(ctx context.Context, userKey valueobject0.ActiveUserKey, peerIds []uuid.UUID) ([]entity.Romance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRomancesGroup", ctx, userKey, peerIds)
+	ret0, _ := ret[0].([]entity.Romance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRomancesGroup indicates an expected call of GetRomancesGroup.
+func (mr *MockRomancesRepositoryMockRecorder) GetRomancesGroup(ctx, userKey, peerIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRomancesGroup", reflect.TypeOf((*MockRomancesRepository)(nil).GetRomancesGroup), ctx, userKey, peerIds)
+}
+
+// GetVoteHistory mocks base method.
+func (m *MockRomancesRepository) GetVoteHistory(ctx context.Context, voteId valueobject0.VoteId, limit int32, cursor string) (entity.VoteHistoryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVoteHistory", ctx, voteId, limit, cursor)
+	ret0, _ := ret[0].(entity.VoteHistoryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVoteHistory indicates an expected call of GetVoteHistory.
+func (mr *MockRomancesRepositoryMockRecorder) GetVoteHistory(ctx, voteId, limit, cursor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVoteHistory", reflect.TypeOf((*MockRomancesRepository)(nil).GetVoteHistory), ctx, voteId, limit, cursor)
+}
+
+// ListRomancesForActiveUser mocks base method.
+func (m *MockRomancesRepository) ListRomancesForActiveUser(ctx context.Context, activeUserKey valueobject0.ActiveUserKey, limit int32, cursor string) (entity.RomancesPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRomancesForActiveUser", ctx, activeUserKey, limit, cursor)
+	ret0, _ := ret[0].(entity.RomancesPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRomancesForActiveUser indicates an expected call of ListRomancesForActiveUser.
+func (mr *MockRomancesRepositoryMockRecorder) ListRomancesForActiveUser(ctx, activeUserKey, limit, cursor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRomancesForActiveUser", reflect.TypeOf((*MockRomancesRepository)(nil).ListRomancesForActiveUser), ctx, activeUserKey, limit, cursor)
+}
+
+// ModerateComplimentInRomance mocks base method.
+func (m *MockRomancesRepository) ModerateComplimentInRomance(ctx context.Context, romance entity.Romance, status valueobject.ModerationStatus, moderatedAt time.Time) (entity.Romance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateComplimentInRomance", ctx, romance, status, moderatedAt)
+	ret0, _ := ret[0].(entity.Romance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModerateComplimentInRomance indicates an expected call of ModerateComplimentInRomance.
+func (mr *MockRomancesRepositoryMockRecorder) ModerateComplimentInRomance(ctx, romance, status, moderatedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateComplimentInRomance", reflect.TypeOf((*MockRomancesRepository)(nil).ModerateComplimentInRomance), ctx, romance, status, moderatedAt)
+}
+
+// RestoreActiveUserVoteInRomance mocks base method.
+func (m *MockRomancesRepository) RestoreActiveUserVoteInRomance(ctx context.Context, romance entity.Romance, vote entity.Vote, unmatch *entity.Unmatch) (entity.Romance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreActiveUserVoteInRomance", ctx, romance, vote, unmatch)
+	ret0, _ := ret[0].(entity.Romance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreActiveUserVoteInRomance indicates an expected call of RestoreActiveUserVoteInRomance.
+func (mr *MockRomancesRepositoryMockRecorder) RestoreActiveUserVoteInRomance(ctx, romance, vote, unmatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreActiveUserVoteInRomance", reflect.TypeOf((*MockRomancesRepository)(nil).RestoreActiveUserVoteInRomance), ctx, romance, vote, unmatch)
+}
+
+// UnblockPeerInRomance mocks base method.
+func (m *MockRomancesRepository) UnblockPeerInRomance(ctx context.Context, romance entity.Romance) (entity.Romance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockPeerInRomance", ctx, romance)
+	ret0, _ := ret[0].(entity.Romance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnblockPeerInRomance indicates an expected call of UnblockPeerInRomance.
+func (mr *MockRomancesRepositoryMockRecorder) UnblockPeerInRomance(ctx, romance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockPeerInRomance", reflect.TypeOf((*MockRomancesRepository)(nil).UnblockPeerInRomance), ctx, romance)
+}
+
+// UnmatchRomance mocks base method.
+func (m *MockRomancesRepository) UnmatchRomance(ctx context.Context, romance entity.Romance, unmatch entity.Unmatch) (entity.Romance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmatchRomance", ctx, romance, unmatch)
+	ret0, _ := ret[0].(entity.Romance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnmatchRomance indicates an expected call of UnmatchRomance.
+func (mr *MockRomancesRepositoryMockRecorder) UnmatchRomance(ctx, romance, unmatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmatchRomance", reflect.TypeOf((*MockRomancesRepository)(nil).UnmatchRomance), ctx, romance, unmatch)
+}
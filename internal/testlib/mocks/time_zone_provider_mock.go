@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider (interfaces: TimeZoneProvider)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../../../../testlib/mocks/time_zone_provider_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider TimeZoneProvider
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	valueobject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTimeZoneProvider is a mock of TimeZoneProvider interface.
+type MockTimeZoneProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockTimeZoneProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockTimeZoneProviderMockRecorder is the mock recorder for MockTimeZoneProvider.
+type MockTimeZoneProviderMockRecorder struct {
+	mock *MockTimeZoneProvider
+}
+
+// NewMockTimeZoneProvider creates a new mock instance.
+func NewMockTimeZoneProvider(ctrl *gomock.Controller) *MockTimeZoneProvider {
+	mock := &MockTimeZoneProvider{ctrl: ctrl}
+	mock.recorder = &MockTimeZoneProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTimeZoneProvider) EXPECT() *MockTimeZoneProviderMockRecorder {
+	return m.recorder
+}
+
+// GetTimeZone mocks base method.
+func (m *MockTimeZoneProvider) GetTimeZone(ctx context.Context, activeUserKey valueobject.ActiveUserKey) (*time.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeZone", ctx, activeUserKey)
+	ret0, _ := ret[0].(*time.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimeZone indicates an expected call of GetTimeZone.
+func (mr *MockTimeZoneProviderMockRecorder) GetTimeZone(ctx, activeUserKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeZone", reflect.TypeOf((*MockTimeZoneProvider)(nil).GetTimeZone), ctx, activeUserKey)
+}
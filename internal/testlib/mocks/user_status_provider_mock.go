@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider (interfaces: UserStatusProvider)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../../../../testlib/mocks/user_status_provider_mock.go -package=mocks github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/provider UserStatusProvider
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	valueobject "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/sharedkernel/valueobject"
+	valueobject0 "github.com/bmbl-bumble2/recs-votes-storage/internal/context/voting/domain/user/valueobject"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserStatusProvider is a mock of UserStatusProvider interface.
+type MockUserStatusProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserStatusProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockUserStatusProviderMockRecorder is the mock recorder for MockUserStatusProvider.
+type MockUserStatusProviderMockRecorder struct {
+	mock *MockUserStatusProvider
+}
+
+// NewMockUserStatusProvider creates a new mock instance.
+func NewMockUserStatusProvider(ctrl *gomock.Controller) *MockUserStatusProvider {
+	mock := &MockUserStatusProvider{ctrl: ctrl}
+	mock.recorder = &MockUserStatusProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserStatusProvider) EXPECT() *MockUserStatusProviderMockRecorder {
+	return m.recorder
+}
+
+// GetStatus mocks base method.
+func (m *MockUserStatusProvider) GetStatus(ctx context.Context, activeUserKey valueobject.ActiveUserKey) (valueobject0.UserStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", ctx, activeUserKey)
+	ret0, _ := ret[0].(valueobject0.UserStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockUserStatusProviderMockRecorder) GetStatus(ctx, activeUserKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockUserStatusProvider)(nil).GetStatus), ctx, activeUserKey)
+}